@@ -0,0 +1,219 @@
+package app_error
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code is a stable, machine readable error code returned to clients
+type Code string
+
+const (
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeNotFound           Code = "not_found"
+	CodeConflict           Code = "conflict"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeRateLimited        Code = "rate_limited"
+	CodeCanceled           Code = "canceled"
+	CodeTimeout            Code = "timeout"
+	CodeUnavailable        Code = "unavailable"
+	CodeUnimplemented      Code = "unimplemented"
+	CodeInternal           Code = "internal"
+)
+
+type codeMapping struct {
+	httpStatus int
+	grpcCode   codes.Code
+	message    string
+}
+
+var codeMappings = map[Code]codeMapping{
+	CodeInvalidArgument:    {http.StatusBadRequest, codes.InvalidArgument, "Invalid request"},
+	CodeUnauthenticated:    {http.StatusUnauthorized, codes.Unauthenticated, "Unauthorized"},
+	CodePermissionDenied:   {http.StatusForbidden, codes.PermissionDenied, "Forbidden"},
+	CodeNotFound:           {http.StatusNotFound, codes.NotFound, "Not found"},
+	CodeConflict:           {http.StatusConflict, codes.AlreadyExists, "Conflict"},
+	CodeFailedPrecondition: {http.StatusPreconditionFailed, codes.FailedPrecondition, "Precondition failed"},
+	CodeRateLimited:        {http.StatusTooManyRequests, codes.ResourceExhausted, "Too many requests"},
+	CodeCanceled:           {499, codes.Canceled, "Request canceled"},
+	CodeTimeout:            {http.StatusGatewayTimeout, codes.DeadlineExceeded, "Request timed out"},
+	CodeUnavailable:        {http.StatusServiceUnavailable, codes.Unavailable, "Service unavailable"},
+	CodeUnimplemented:      {http.StatusNotImplemented, codes.Unimplemented, "Not implemented"},
+	CodeInternal:           {http.StatusInternalServerError, codes.Internal, "Internal server error"},
+}
+
+// AppError is an error with a client facing code and message. The wrapped cause is kept
+// for logs only and never sent to clients.
+type AppError struct {
+	Code       Code
+	HTTPStatus int
+	GRPCCode   codes.Code
+	// Message is safe to show to clients
+	Message string
+	// Details are returned to clients alongside Message, e.g. field errors
+	Details map[string]interface{}
+	// cause is internal detail for logs
+	cause error
+}
+
+// New creates an error with code, taking the HTTP status and gRPC code from the code's defaults.
+// An empty message uses the code's default message.
+func New(code Code, message string) *AppError {
+	mapping, ok := codeMappings[code]
+	if !ok {
+		mapping = codeMappings[CodeInternal]
+	}
+	if message == "" {
+		message = mapping.message
+	}
+	return &AppError{
+		Code:       code,
+		HTTPStatus: mapping.httpStatus,
+		GRPCCode:   mapping.grpcCode,
+		Message:    message,
+	}
+}
+
+// Newf creates an error with a formatted message
+func Newf(code Code, format string, args ...interface{}) *AppError {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wrap creates an error with code and message that keeps err as its internal cause
+func Wrap(err error, code Code, message string) *AppError {
+	return New(code, message).WithCause(err)
+}
+
+func InvalidArgument(message string) *AppError  { return New(CodeInvalidArgument, message) }
+func Unauthenticated(message string) *AppError  { return New(CodeUnauthenticated, message) }
+func PermissionDenied(message string) *AppError { return New(CodePermissionDenied, message) }
+func NotFound(message string) *AppError         { return New(CodeNotFound, message) }
+func Conflict(message string) *AppError         { return New(CodeConflict, message) }
+func RateLimited(message string) *AppError      { return New(CodeRateLimited, message) }
+func Unavailable(message string) *AppError      { return New(CodeUnavailable, message) }
+
+// Internal wraps err as an internal error with the generic public message
+func Internal(err error) *AppError {
+	return New(CodeInternal, "").WithCause(err)
+}
+
+func (e *AppError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *AppError) Unwrap() error {
+	return e.cause
+}
+
+// Is matches another AppError with the same code, so errors.Is(err, app_error.NotFound("")) works
+func (e *AppError) Is(target error) bool {
+	other, ok := target.(*AppError)
+	return ok && other.Code == e.Code
+}
+
+// Cause returns the internal error, if any
+func (e *AppError) Cause() error {
+	return e.cause
+}
+
+// WithCause returns a copy of the error wrapping cause
+func (e *AppError) WithCause(cause error) *AppError {
+	copied := *e
+	copied.cause = cause
+	return &copied
+}
+
+// WithDetail returns a copy of the error with key set in its public details
+func (e *AppError) WithDetail(key string, value interface{}) *AppError {
+	copied := *e
+	copied.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		copied.Details[k] = v
+	}
+	copied.Details[key] = value
+	return &copied
+}
+
+// WithStatus returns a copy of the error with a different HTTP status
+func (e *AppError) WithStatus(httpStatus int) *AppError {
+	copied := *e
+	copied.HTTPStatus = httpStatus
+	return &copied
+}
+
+// GRPCStatus lets gRPC send the code and public message, so handlers can return an AppError directly
+func (e *AppError) GRPCStatus() *status.Status {
+	return status.New(e.GRPCCode, e.Message)
+}
+
+// From converts any error into an AppError. AppErrors and gRPC status errors keep their code;
+// context and sql.ErrNoRows errors map to their natural codes; anything else is internal.
+func From(err error) *AppError {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Wrap(err, CodeCanceled, "")
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, CodeTimeout, "")
+	case errors.Is(err, sql.ErrNoRows):
+		return Wrap(err, CodeNotFound, "")
+	}
+
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		for code, mapping := range codeMappings {
+			if mapping.grpcCode == st.Code() {
+				return Wrap(err, code, st.Message())
+			}
+		}
+	}
+	return Internal(err)
+}
+
+// ErrorBody is the JSON body of an error response
+type ErrorBody struct {
+	Code    Code                   `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	XID     string                 `json:"xid,omitempty"`
+	TraceID string                 `json:"trace_id,omitempty"`
+}
+
+// ErrorResponse is the envelope every error response uses: {"error": {...}}
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// Response converts err into its HTTP status and response envelope, tagged with the request's XID and trace ID
+func Response(err error, xid uuid.UUID, traceID string) (int, ErrorResponse) {
+	appErr := From(err)
+	body := ErrorBody{
+		Code:    appErr.Code,
+		Message: appErr.Message,
+		Details: appErr.Details,
+		TraceID: traceID,
+	}
+	if xid != uuid.Nil {
+		body.XID = xid.String()
+	}
+	return appErr.HTTPStatus, ErrorResponse{Error: body}
+}
@@ -0,0 +1,121 @@
+package app_error
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew_UsesCodeDefaults(t *testing.T) {
+	err := NotFound("")
+	assert.Equal(t, CodeNotFound, err.Code)
+	assert.Equal(t, http.StatusNotFound, err.HTTPStatus)
+	assert.Equal(t, codes.NotFound, err.GRPCCode)
+	assert.Equal(t, "Not found", err.Message)
+
+	err = InvalidArgument("name is required")
+	assert.Equal(t, http.StatusBadRequest, err.HTTPStatus)
+	assert.Equal(t, "invalid_argument: name is required", err.Error())
+}
+
+func TestWrap_KeepsCauseInternal(t *testing.T) {
+	cause := errors.New("pq: duplicate key value violates unique constraint")
+	err := Wrap(cause, CodeConflict, "Email already registered")
+
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, cause, err.Cause())
+	assert.Contains(t, err.Error(), "duplicate key")
+
+	status, body := Response(err, uuid.Nil, "")
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "Email already registered", body.Error.Message)
+	assert.Empty(t, body.Error.XID)
+}
+
+func TestWithDetail_CopiesError(t *testing.T) {
+	base := InvalidArgument("Validation failed")
+	detailed := base.WithDetail("fields", map[string]string{"email": "required"})
+
+	assert.Nil(t, base.Details)
+	assert.Equal(t, map[string]string{"email": "required"}, detailed.Details["fields"])
+}
+
+func TestIs_MatchesCode(t *testing.T) {
+	err := fmt.Errorf("loading user: %w", NotFound("User not found"))
+	assert.ErrorIs(t, err, NotFound(""))
+	assert.NotErrorIs(t, err, Conflict(""))
+}
+
+func TestFrom(t *testing.T) {
+	wrapped := fmt.Errorf("service: %w", PermissionDenied("Not your order"))
+
+	tests := []struct {
+		name    string
+		err     error
+		code    Code
+		message string
+	}{
+		{"app error", wrapped, CodePermissionDenied, "Not your order"},
+		{"no rows", fmt.Errorf("find: %w", sql.ErrNoRows), CodeNotFound, "Not found"},
+		{"deadline", context.DeadlineExceeded, CodeTimeout, "Request timed out"},
+		{"canceled", context.Canceled, CodeCanceled, "Request canceled"},
+		{"grpc status", status.Error(codes.AlreadyExists, "exists"), CodeConflict, "exists"},
+		{"unknown", errors.New("connection refused to 10.0.0.5"), CodeInternal, "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := From(tt.err)
+			assert.Equal(t, tt.code, appErr.Code)
+			assert.Equal(t, tt.message, appErr.Message)
+		})
+	}
+
+	assert.Nil(t, From(nil))
+}
+
+func TestResponse_Envelope(t *testing.T) {
+	xid := uuid.New()
+	status, body := Response(errors.New("secret internal detail"), xid, "trace-1")
+
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, ErrorBody{Code: CodeInternal, Message: "Internal server error", XID: xid.String(), TraceID: "trace-1"}, body.Error)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}
+
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{"app error", fmt.Errorf("get: %w", NotFound("Order not found")), codes.NotFound, "Order not found"},
+		{"status error", status.Error(codes.ResourceExhausted, "rate limit exceeded"), codes.ResourceExhausted, "rate limit exceeded"},
+		{"plain error", errors.New("dial tcp 10.0.0.5: refused"), codes.Internal, "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tt.err
+			})
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+		})
+	}
+}
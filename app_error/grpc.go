@@ -0,0 +1,43 @@
+package app_error
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor converts handler errors into gRPC statuses carrying only the public message,
+// so internal error text never reaches clients
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, toStatusError(info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor converts stream handler errors like UnaryServerInterceptor
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return toStatusError(info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+func toStatusError(method string, err error) error {
+	// Status errors created by gRPC or other interceptors are already client safe
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+	appErr := From(err)
+	if appErr.Code == CodeInternal {
+		log.Printf("[Error] %s: %v", method, err)
+	}
+	return appErr.GRPCStatus().Err()
+}
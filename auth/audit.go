@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/peer"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/store/analytics/clickhouse"
+)
+
+// Audit event types
+const (
+	AuditLogin              = "login"
+	AuditLoginFailed        = "login_failed"
+	AuditLoginLocked        = "login_locked"
+	AuditLoginBlocked       = "login_blocked"
+	AuditSessionCreated     = "session_created"
+	AuditTokenRefresh       = "token_refresh"
+	AuditRefreshFailed      = "token_refresh_failed"
+	AuditTokenRejected      = "token_rejected"
+	AuditRevocation         = "revocation"
+	AuditServiceToken       = "service_token"
+	AuditServiceTokenFailed = "service_token_failed"
+	AuditMFAChallenge       = "mfa_challenge"
+	AuditMFAVerified        = "mfa_verified"
+	AuditMFAFailed          = "mfa_failed"
+)
+
+// AuditEventsDDL creates the ClickHouse table AuditEvent is written to, for use with clickhouse.Migrator
+const AuditEventsDDL = `CREATE TABLE IF NOT EXISTS auth_audit_events {on_cluster} (
+	id UUID,
+	event_type LowCardinality(String),
+	actor_id String,
+	identifier String,
+	ip_address String,
+	user_agent String,
+	client_device_id String,
+	reason String,
+	xid UUID,
+	trace_id String,
+	occurred_at DateTime64(3)
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (event_type, occurred_at)`
+
+// AuditEventsPostgresDDL creates the Postgres table AuditEvent is written to by PostgresAuditSink
+const AuditEventsPostgresDDL = `CREATE TABLE IF NOT EXISTS auth_audit_events (
+	id UUID PRIMARY KEY,
+	event_type VARCHAR(50) NOT NULL,
+	actor_id VARCHAR(255) NOT NULL DEFAULT '',
+	identifier VARCHAR(255) NOT NULL DEFAULT '',
+	ip_address VARCHAR(64) NOT NULL DEFAULT '',
+	user_agent TEXT NOT NULL DEFAULT '',
+	client_device_id VARCHAR(255) NOT NULL DEFAULT '',
+	reason TEXT NOT NULL DEFAULT '',
+	xid UUID,
+	trace_id VARCHAR(255) NOT NULL DEFAULT '',
+	occurred_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_events_actor ON auth_audit_events (actor_id, occurred_at)`
+
+// AuditEvent records a security relevant auth action
+type AuditEvent struct {
+	ID             uuid.UUID `json:"id" orm:"column:id;pk"`
+	Type           string    `json:"event_type" orm:"column:event_type"`
+	ActorID        string    `json:"actor_id,omitempty" orm:"column:actor_id"`
+	Identifier     string    `json:"identifier,omitempty" orm:"column:identifier"`
+	IPAddress      string    `json:"ip_address,omitempty" orm:"column:ip_address"`
+	UserAgent      string    `json:"user_agent,omitempty" orm:"column:user_agent"`
+	ClientDeviceID string    `json:"client_device_id,omitempty" orm:"column:client_device_id"`
+	Reason         string    `json:"reason,omitempty" orm:"column:reason"`
+	XID            uuid.UUID `json:"xid" orm:"column:xid"`
+	TraceID        string    `json:"trace_id,omitempty" orm:"column:trace_id"`
+	OccurredAt     time.Time `json:"occurred_at" orm:"column:occurred_at"`
+}
+
+func (AuditEvent) TableName() string {
+	return "auth_audit_events"
+}
+
+// AuditSink persists audit events
+type AuditSink interface {
+	Record(ctx context.Context, events ...*AuditEvent) error
+}
+
+// LogAuditSink writes each event as a JSON line to the standard logger
+type LogAuditSink struct{}
+
+func (LogAuditSink) Record(ctx context.Context, events ...*AuditEvent) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		log.Printf("[Audit] %s", data)
+	}
+	return nil
+}
+
+// PostgresAuditSink inserts events into auth_audit_events; create it with AuditEventsPostgresDDL
+type PostgresAuditSink struct {
+	db *orm.DB[AuditEvent]
+}
+
+func NewPostgresAuditSink(db *sql.DB) *PostgresAuditSink {
+	if orm.GetMetadata[AuditEvent]() == nil {
+		orm.RegisterModel[AuditEvent]()
+	}
+	return &PostgresAuditSink{db: orm.NewDB[AuditEvent](db)}
+}
+
+func (s *PostgresAuditSink) Record(ctx context.Context, events ...*AuditEvent) error {
+	return s.db.CreateMultiple(ctx, events)
+}
+
+// ClickHouseAuditSink buffers events and writes them to ClickHouse in batches
+type ClickHouseAuditSink struct {
+	writer *clickhouse.BatchWriter[AuditEvent]
+}
+
+// NewClickHouseAuditSink creates a sink backed by a clickhouse.BatchWriter
+func NewClickHouseAuditSink(client *clickhouse.Client, cfg clickhouse.BatchWriterConfig) (*ClickHouseAuditSink, error) {
+	writer, err := clickhouse.NewBatchWriter[AuditEvent](client, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ClickHouseAuditSink{writer: writer}, nil
+}
+
+// Record buffers events; they are flushed by size, interval or clickhouse.Shutdown
+func (s *ClickHouseAuditSink) Record(ctx context.Context, events ...*AuditEvent) error {
+	return s.writer.Write(ctx, events...)
+}
+
+// MultiAuditSink sends every event to each of its sinks
+type MultiAuditSink []AuditSink
+
+func (m MultiAuditSink) Record(ctx context.Context, events ...*AuditEvent) error {
+	var firstErr error
+	for _, sink := range m {
+		if err := sink.Record(ctx, events...); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+var (
+	_ AuditSink = LogAuditSink{}
+	_ AuditSink = (*PostgresAuditSink)(nil)
+	_ AuditSink = (*ClickHouseAuditSink)(nil)
+	_ AuditSink = MultiAuditSink(nil)
+)
+
+// correlationKey stores request correlation IDs in a context.Context
+type correlationKey struct{}
+
+type correlation struct {
+	xid     uuid.UUID
+	traceID string
+}
+
+// ContextWithCorrelation attaches the request XID and trace ID so audit events can be joined with request logs
+func ContextWithCorrelation(ctx context.Context, xid uuid.UUID, traceID string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation{xid: xid, traceID: traceID})
+}
+
+// SetAuditSink enables audit events; nil disables them
+func (a *AuthService) SetAuditSink(sink AuditSink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.auditSink = sink
+}
+
+func (a *AuthService) auditSinkOrNil() AuditSink {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.auditSink
+}
+
+// Audit records event, filling in its ID, time and correlation IDs from ctx.
+// Sink errors are logged rather than returned so auditing never fails an auth flow.
+func (a *AuthService) Audit(ctx context.Context, event *AuditEvent) {
+	sink := a.auditSinkOrNil()
+	if sink == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	if c, ok := ctx.Value(correlationKey{}).(correlation); ok {
+		if event.XID == uuid.Nil {
+			event.XID = c.xid
+		}
+		if event.TraceID == "" {
+			event.TraceID = c.traceID
+		}
+	}
+	if event.ActorID == "" {
+		if claims, ok := ClaimsFromContext(ctx); ok {
+			event.ActorID = claims.UserID.String()
+			if event.ClientDeviceID == "" {
+				event.ClientDeviceID = claims.ClientDeviceID
+			}
+		}
+	}
+
+	if err := sink.Record(ctx, event); err != nil {
+		log.Printf("[Auth] failed to record audit event %s: %v", event.Type, err)
+	}
+}
+
+// auditRequest records an event for an HTTP request, adding the client IP and user agent
+func (a *AuthService) auditRequest(c *gin.Context, event *AuditEvent) {
+	if c.Request == nil {
+		a.Audit(c, event)
+		return
+	}
+	event.IPAddress = c.ClientIP()
+	event.UserAgent = c.Request.UserAgent()
+	a.Audit(c.Request.Context(), event)
+}
+
+// auditGRPC records a rejected token for a gRPC call, adding the peer address
+func (a *AuthService) auditGRPC(ctx context.Context, fullMethod string, err error) {
+	event := &AuditEvent{Type: AuditTokenRejected, Reason: fullMethod + ": " + err.Error()}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.IPAddress = p.Addr.String()
+	}
+	a.Audit(ctx, event)
+}
+
+// auditRevocation records a revocation of refresh tokens or sessions by actor
+func (a *AuthService) auditRevocation(actor *uuid.UUID, reason string) {
+	event := &AuditEvent{Type: AuditRevocation, Reason: reason}
+	if actor != nil {
+		event.ActorID = actor.String()
+	}
+	a.Audit(context.Background(), event)
+}
+
+// LoginEventAuditor adapts LoginLimiter events to audit events, for LoginLimiterConfig.OnEvent
+func (a *AuthService) LoginEventAuditor() func(LoginEvent) {
+	types := map[string]string{
+		LoginEventFailed:    AuditLoginFailed,
+		LoginEventSucceeded: AuditLogin,
+		LoginEventLocked:    AuditLoginLocked,
+		LoginEventBlocked:   AuditLoginBlocked,
+	}
+	return func(event LoginEvent) {
+		a.Audit(context.Background(), &AuditEvent{
+			Type:       types[event.Type],
+			Identifier: event.Identifier,
+			IPAddress:  event.IP,
+			OccurredAt: event.Time.UTC(),
+		})
+	}
+}
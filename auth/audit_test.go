@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+type memoryAuditSink struct {
+	mu     sync.Mutex
+	events []*AuditEvent
+}
+
+func (s *memoryAuditSink) Record(ctx context.Context, events ...*AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func TestAudit_FillsCorrelation(t *testing.T) {
+	authService := newTestAuthService(t)
+	sink := &memoryAuditSink{}
+	authService.SetAuditSink(sink)
+
+	xid := uuid.New()
+	claims := &UserClaims{UserID: uuid.New(), ClientDeviceID: "device-1"}
+	ctx := ContextWithCorrelation(ContextWithClaims(context.Background(), claims), xid, "trace-1")
+	authService.Audit(ctx, &AuditEvent{Type: AuditMFAChallenge})
+
+	require.Len(t, sink.events, 1)
+	event := sink.events[0]
+	assert.NotEqual(t, uuid.Nil, event.ID)
+	assert.False(t, event.OccurredAt.IsZero())
+	assert.Equal(t, xid, event.XID)
+	assert.Equal(t, "trace-1", event.TraceID)
+	assert.Equal(t, claims.UserID.String(), event.ActorID)
+	assert.Equal(t, "device-1", event.ClientDeviceID)
+}
+
+func TestAudit_RejectedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	sink := &memoryAuditSink{}
+	authService.SetAuditSink(sink)
+
+	engine := gin.New()
+	engine.Use(authService.HTTPMiddleware())
+	engine.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	req.Header.Set("User-Agent", "test-agent")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, AuditTokenRejected, sink.events[0].Type)
+	assert.Equal(t, "test-agent", sink.events[0].UserAgent)
+	assert.NotEmpty(t, sink.events[0].Reason)
+}
+
+func TestAudit_Revocation(t *testing.T) {
+	authService := newTestAuthService(t)
+	authService.SetRevocationStore(NewRevocationStore(local.NewLocalCache(nil)))
+	sink := &memoryAuditSink{}
+	authService.SetAuditSink(sink)
+
+	claims := &UserClaims{UserID: uuid.New()}
+	claims.ID = uuid.NewString()
+	require.NoError(t, authService.RevokeClaims(context.Background(), claims))
+
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, AuditRevocation, sink.events[0].Type)
+	assert.Equal(t, claims.UserID.String(), sink.events[0].ActorID)
+}
+
+func TestLoginEventAuditor(t *testing.T) {
+	authService := newTestAuthService(t)
+	sink := &memoryAuditSink{}
+	authService.SetAuditSink(sink)
+
+	authService.LoginEventAuditor()(LoginEvent{Type: LoginEventLocked, Identifier: "user@example.com", IP: "10.0.0.1", Time: time.Now()})
+
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, AuditLoginLocked, sink.events[0].Type)
+	assert.Equal(t, "user@example.com", sink.events[0].Identifier)
+	assert.Equal(t, "10.0.0.1", sink.events[0].IPAddress)
+}
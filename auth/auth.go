@@ -2,22 +2,20 @@ package auth
 
 import (
 	"context"
+	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/sha256"
-	"crypto/x509"
 	"database/sql"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/pem"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
-	"net/http"
-
 	"github.com/google/uuid"
 
+	"github.com/yadunandan004/scaffold/app_error"
 	"github.com/yadunandan004/scaffold/config"
 
 	"github.com/gin-gonic/gin"
@@ -30,8 +28,21 @@ import (
 
 type AuthService struct {
 	cfg        *config.AuthConfig
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+
+	// mu guards the key set, revocation store, verifiers, cookie settings, skip lists, audit sink and client store
+	mu              sync.RWMutex
+	keys            map[string]*SigningKey
+	currentKID      string
+	revocations     *RevocationStore
+	verifiers       []TokenVerifier
+	cookies         *CookieConfig
+	skipPaths       []string
+	skipRoutes      map[string]bool
+	skipGRPCMethods map[string]bool
+	auditSink       AuditSink
+	clients         ClientStore
 }
 
 // NewAuthService creates a new AuthService with the given configuration.
@@ -41,62 +52,47 @@ func NewAuthService(cfg *config.AuthConfig) (*AuthService, error) {
 		return nil, fmt.Errorf("auth config is nil")
 	}
 
-	// Parse RSA private key
-	privateKeyBlock, _ := pem.Decode([]byte(cfg.PrivateKey))
-	if privateKeyBlock == nil {
-		return nil, fmt.Errorf("failed to parse private key: invalid PEM block")
+	privateKey, err := ParsePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
 	}
-	var privateKey *rsa.PrivateKey
 
-	// Try PKCS8 first
-	privateKeyInterface, err := x509.ParsePKCS8PrivateKey(privateKeyBlock.Bytes)
-	if err == nil {
-		rsaKey, ok := privateKeyInterface.(*rsa.PrivateKey)
-		if !ok {
-			return nil, fmt.Errorf("failed to parse private key: key is not RSA type")
-		}
-		privateKey = rsaKey
-	} else {
-		// Try PKCS1 format (for RSA)
-		parsedKey, pkcs1Err := x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes)
-		if pkcs1Err != nil {
-			return nil, fmt.Errorf("failed to parse private key: not PKCS8 (%v) or PKCS1 (%v)", err, pkcs1Err)
-		}
-		privateKey = parsedKey
+	publicKey, err := ParsePublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse RSA public key
-	publicKeyBlock, _ := pem.Decode([]byte(cfg.PublicKey))
-	if publicKeyBlock == nil {
-		return nil, fmt.Errorf("failed to parse public key: invalid PEM block")
-	}
-	publicKeyInterface, err := x509.ParsePKIXPublicKey(publicKeyBlock.Bytes)
+	key, err := newSigningKey(cfg.KeyID, cfg.Algorithm, privateKey, publicKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key: %w", err)
-	}
-	publicKey, ok := publicKeyInterface.(*rsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("failed to parse public key: key is not RSA type")
+		return nil, err
 	}
 
-	return &AuthService{
+	a := &AuthService{
 		cfg:        cfg,
 		privateKey: privateKey,
 		publicKey:  publicKey,
-	}, nil
+		keys:       map[string]*SigningKey{key.ID: key},
+		currentKID: key.ID,
+	}
+	a.SkipPaths(cfg.SkipPaths...)
+	a.SkipGRPCMethods(DefaultSkipGRPCMethods...)
+	a.SkipGRPCMethods(cfg.SkipGRPCMethods...)
+	return a, nil
 }
 
 func (a *AuthService) ValidateToken(tokenString string) (*UserClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(a.cfg.PublicKey))
+		kid, _ := token.Header["kid"].(string)
+		keys, err := a.verificationKeys(kid, token.Method.Alg())
 		if err != nil {
 			return nil, err
 		}
-		return publicKey, nil
-	})
+		verificationKeys := jwt.VerificationKeySet{}
+		for _, key := range keys {
+			verificationKeys.Keys = append(verificationKeys.Keys, key)
+		}
+		return verificationKeys, nil
+	}, jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA}))
 
 	if err != nil {
 		return nil, err
@@ -112,33 +108,75 @@ func (a *AuthService) ValidateToken(tokenString string) (*UserClaims, error) {
 type UserClaims struct {
 	UserID         uuid.UUID `json:"user_id"`
 	Email          string    `json:"email"`
+	Name           string    `json:"name,omitempty"`
 	ClientDeviceID string    `json:"client_device_id"`
+	Roles          []string  `json:"roles,omitempty"`
+	Permissions    []string  `json:"permissions,omitempty"`
+	TokenType      string    `json:"token_type,omitempty"`
+	ClientID       string    `json:"client_id,omitempty"`
+	Scopes         []string  `json:"scopes,omitempty"`
+	TenantID       string    `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// HTTPMiddleware authenticates every request except those matching SkipPaths or SkipRoute
 func (a *AuthService) HTTPMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("Authorization")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+		if a.ShouldSkipHTTP(c) {
+			c.Next()
 			return
 		}
-
-		if len(token) > 7 && token[:7] == "Bearer " {
-			token = token[7:]
+		if !a.Authenticate(c) {
+			return
 		}
+		c.Next()
+	}
+}
 
-		claims, err := a.ValidateToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
+// Authenticate validates the request's token and stores its claims under UserClaimsKey and in the request context.
+// It ignores the skip lists and aborts with 401 or 403, returning false, when the request is not authenticated.
+func (a *AuthService) Authenticate(c *gin.Context) bool {
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		cookieToken, ok := a.tokenFromCookie(c)
+		if !ok {
+			abortWithError(c, app_error.Unauthenticated("Authorization header required"))
+			return false
 		}
+		// Browsers attach cookies to cross-site requests, so unsafe methods must prove same-origin
+		if !a.validCSRF(c) {
+			a.auditRequest(c, &AuditEvent{Type: AuditTokenRejected, Reason: "invalid csrf token"})
+			abortWithError(c, app_error.PermissionDenied("Invalid CSRF token"))
+			return false
+		}
+		token = cookieToken
+	}
 
-		c.Set("user_claims", claims)
-		c.Next()
+	if len(token) > 7 && token[:7] == "Bearer " {
+		token = token[7:]
 	}
+
+	claims, err := a.ValidateTokenContext(c.Request.Context(), token)
+	if err != nil {
+		a.auditRequest(c, &AuditEvent{Type: AuditTokenRejected, Reason: err.Error()})
+		abortWithError(c, app_error.Unauthenticated("Invalid token"))
+		return false
+	}
+
+	c.Set(UserClaimsKey, claims)
+	c.Request = c.Request.WithContext(ContextWithClaims(c.Request.Context(), claims))
+	return true
+}
+
+// abortWithError aborts the request with the standard error envelope, tagged with the correlation IDs if set
+func abortWithError(c *gin.Context, err *app_error.AppError) {
+	var xid uuid.UUID
+	var traceID string
+	if corr, ok := c.Request.Context().Value(correlationKey{}).(correlation); ok {
+		xid, traceID = corr.xid, corr.traceID
+	}
+	status, body := app_error.Response(err, xid, traceID)
+	c.AbortWithStatusJSON(status, body)
 }
 
 func (a *AuthService) GRPCInterceptor() grpc.UnaryServerInterceptor {
@@ -148,11 +186,7 @@ func (a *AuthService) GRPCInterceptor() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		// Skip auth for reflection and health check
-		if info.FullMethod == "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo" ||
-			info.FullMethod == "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo" ||
-			info.FullMethod == "/grpc.health.v1.Health/Check" ||
-			info.FullMethod == "/grpc.health.v1.Health/Watch" {
+		if a.ShouldSkipGRPC(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
@@ -171,13 +205,13 @@ func (a *AuthService) GRPCInterceptor() grpc.UnaryServerInterceptor {
 			token = token[7:]
 		}
 
-		claims, err := a.ValidateToken(token)
+		claims, err := a.ValidateTokenContext(ctx, token)
 		if err != nil {
+			a.auditGRPC(ctx, info.FullMethod, err)
 			return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 		}
 
-		ctx = context.WithValue(ctx, "user_claims", claims)
-		return handler(ctx, req)
+		return handler(ContextWithClaims(ctx, claims), req)
 	}
 }
 
@@ -188,11 +222,7 @@ func (a *AuthService) GRPCStreamInterceptor() grpc.StreamServerInterceptor {
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		// Skip auth for reflection and health check
-		if info.FullMethod == "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo" ||
-			info.FullMethod == "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo" ||
-			info.FullMethod == "/grpc.health.v1.Health/Check" ||
-			info.FullMethod == "/grpc.health.v1.Health/Watch" {
+		if a.ShouldSkipGRPC(info.FullMethod) {
 			return handler(srv, ss)
 		}
 
@@ -212,13 +242,14 @@ func (a *AuthService) GRPCStreamInterceptor() grpc.StreamServerInterceptor {
 			token = token[7:]
 		}
 
-		claims, err := a.ValidateToken(token)
+		claims, err := a.ValidateTokenContext(ctx, token)
 		if err != nil {
+			a.auditGRPC(ctx, info.FullMethod, err)
 			return status.Errorf(codes.Unauthenticated, "invalid token")
 		}
 
 		// Create a wrapped stream with auth request
-		ctx = context.WithValue(ctx, "user_claims", claims)
+		ctx = ContextWithClaims(ctx, claims)
 		wrappedStream := &wrappedServerStream{
 			ServerStream: ss,
 			ctx:          ctx,
@@ -228,8 +259,32 @@ func (a *AuthService) GRPCStreamInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
+// TokenOption adds optional claims to a generated access token
+type TokenOption func(claims jwt.MapClaims)
+
+// WithRoles adds roles, resolved to permissions through the PermissionRegistry on each request
+func WithRoles(roles ...string) TokenOption {
+	return func(claims jwt.MapClaims) {
+		claims["roles"] = roles
+	}
+}
+
+// WithPermissions grants permissions directly, in addition to those from roles
+func WithPermissions(permissions ...string) TokenOption {
+	return func(claims jwt.MapClaims) {
+		claims["permissions"] = permissions
+	}
+}
+
+// WithTenant binds the token to a tenant; tenant-scoped repositories only see that tenant's rows
+func WithTenant(tenantID string) TokenOption {
+	return func(claims jwt.MapClaims) {
+		claims["tenant_id"] = tenantID
+	}
+}
+
 // GenerateAccessToken generates a short-lived JWT access token
-func (a *AuthService) GenerateAccessToken(userID uuid.UUID, email string, clientDeviceID string) (string, error) {
+func (a *AuthService) GenerateAccessToken(userID uuid.UUID, email string, clientDeviceID string, opts ...TokenOption) (string, error) {
 	jti := uuid.New().String() // JWT ID for potential blacklisting
 
 	claims := jwt.MapClaims{
@@ -241,9 +296,22 @@ func (a *AuthService) GenerateAccessToken(userID uuid.UUID, email string, client
 		"exp":              time.Now().Add(time.Duration(a.cfg.AccessTokenDuration) * time.Second).Unix(),
 		"iat":              time.Now().Unix(),
 	}
+	for _, opt := range opts {
+		opt(claims)
+	}
+	return a.signClaims(claims)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(a.privateKey)
+// signClaims signs claims with the current signing key
+func (a *AuthService) signClaims(claims jwt.MapClaims) (string, error) {
+	key := a.signingKey()
+	method, err := signingMethod(key.Algorithm)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
 }
 
 // GenerateRefreshToken generates a secure random refresh token
@@ -260,9 +328,9 @@ func (a *AuthService) GenerateRefreshToken() (string, error) {
 }
 
 // GenerateTokenPair generates both access and refresh tokens
-func (a *AuthService) GenerateTokenPair(userID uuid.UUID, email string, clientDeviceID string) (accessToken string, refreshToken string, err error) {
+func (a *AuthService) GenerateTokenPair(userID uuid.UUID, email string, clientDeviceID string, opts ...TokenOption) (accessToken string, refreshToken string, err error) {
 	// Generate access token
-	accessToken, err = a.GenerateAccessToken(userID, email, clientDeviceID)
+	accessToken, err = a.GenerateAccessToken(userID, email, clientDeviceID, opts...)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -341,6 +409,13 @@ func (a *AuthService) StoreRefreshToken(db *sql.DB, userID uuid.UUID, refreshTok
 		return fmt.Errorf("failed to store refresh token: %w", err)
 	}
 
+	a.Audit(context.Background(), &AuditEvent{
+		Type:           AuditSessionCreated,
+		ActorID:        userID.String(),
+		ClientDeviceID: clientDeviceID,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+	})
 	return nil
 }
 
@@ -365,6 +440,7 @@ func (a *AuthService) ValidateRefreshToken(db *sql.DB, refreshToken string) (*Re
 		&storedToken.UseCount,
 	)
 	if err == sql.ErrNoRows {
+		a.Audit(context.Background(), &AuditEvent{Type: AuditRefreshFailed, Reason: "refresh token not found"})
 		return nil, fmt.Errorf("refresh token not found")
 	}
 	if err != nil {
@@ -373,10 +449,18 @@ func (a *AuthService) ValidateRefreshToken(db *sql.DB, refreshToken string) (*Re
 
 	// Check if token is valid
 	if !storedToken.IsValid() {
+		err := fmt.Errorf("refresh token has expired")
 		if storedToken.RevokedAt != nil {
-			return nil, fmt.Errorf("refresh token has been revoked")
+			// Reuse of a revoked token may mean it was stolen
+			err = fmt.Errorf("refresh token has been revoked")
 		}
-		return nil, fmt.Errorf("refresh token has expired")
+		a.Audit(context.Background(), &AuditEvent{
+			Type:           AuditRefreshFailed,
+			ActorID:        storedToken.UserID.String(),
+			ClientDeviceID: storedToken.ClientDeviceID,
+			Reason:         err.Error(),
+		})
+		return nil, err
 	}
 
 	// Update last used time and use count
@@ -392,6 +476,11 @@ func (a *AuthService) ValidateRefreshToken(db *sql.DB, refreshToken string) (*Re
 		fmt.Printf("Failed to update refresh token usage: %v\n", err)
 	}
 
+	a.Audit(context.Background(), &AuditEvent{
+		Type:           AuditTokenRefresh,
+		ActorID:        storedToken.UserID.String(),
+		ClientDeviceID: storedToken.ClientDeviceID,
+	})
 	return &storedToken, nil
 }
 
@@ -404,6 +493,9 @@ func (a *AuthService) RevokeRefreshToken(db *sql.DB, tokenID uuid.UUID, reason s
 	`
 	_, err := db.ExecContext(context.Background(), query,
 		time.Now(), reason, revokedBy, tokenID)
+	if err == nil {
+		a.auditRevocation(revokedBy, "refresh token "+tokenID.String()+": "+reason)
+	}
 	return err
 }
 
@@ -416,6 +508,9 @@ func (a *AuthService) RevokeAllUserTokens(db *sql.DB, userID uuid.UUID, reason s
 	`
 	_, err := db.ExecContext(context.Background(), query,
 		time.Now(), reason, userID)
+	if err == nil {
+		a.auditRevocation(&userID, "all refresh tokens: "+reason)
+	}
 	return err
 }
 
@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenTypeService marks access tokens issued to services with client credentials
+const TokenTypeService = "service"
+
+var (
+	ErrClientNotFound     = errors.New("client not found")
+	ErrDuplicateClient    = errors.New("client already registered")
+	ErrInvalidClient      = errors.New("invalid client credentials")
+	ErrInvalidScope       = errors.New("scope not allowed for client")
+	ErrClientStoreMissing = errors.New("client store not configured")
+)
+
+// serviceNamespace derives stable principal IDs for service clients
+var serviceNamespace = uuid.MustParse("6f1c2a52-52f4-4c1e-9b55-3c0f4d6e8a17")
+
+// ServiceClient is a registered machine client allowed to obtain service tokens
+type ServiceClient struct {
+	ID         string    `json:"client_id"`
+	Name       string    `json:"name"`
+	SecretHash string    `json:"-"`
+	Scopes     []string  `json:"scopes"`
+	Disabled   bool      `json:"disabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	// TenantID binds the client's tokens to one tenant; empty issues tokens without a tenant
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// ClientStore loads and saves service clients; implement it over the application's database
+type ClientStore interface {
+	// GetClient returns the client with clientID, or ErrClientNotFound
+	GetClient(ctx context.Context, clientID string) (*ServiceClient, error)
+
+	// SaveClient creates or replaces a client
+	SaveClient(ctx context.Context, client *ServiceClient) error
+}
+
+// MemoryClientStore keeps clients in memory, for tests and single instance deployments
+type MemoryClientStore struct {
+	mu      sync.RWMutex
+	clients map[string]*ServiceClient
+}
+
+func NewMemoryClientStore() *MemoryClientStore {
+	return &MemoryClientStore{clients: make(map[string]*ServiceClient)}
+}
+
+func (s *MemoryClientStore) GetClient(ctx context.Context, clientID string) (*ServiceClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	client, ok := s.clients[clientID]
+	if !ok {
+		return nil, ErrClientNotFound
+	}
+	copied := *client
+	return &copied, nil
+}
+
+func (s *MemoryClientStore) SaveClient(ctx context.Context, client *ServiceClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *client
+	s.clients[client.ID] = &copied
+	return nil
+}
+
+var _ ClientStore = (*MemoryClientStore)(nil)
+
+// ServicePrincipalID returns the stable UserID used in service tokens for clientID
+func ServicePrincipalID(clientID string) uuid.UUID {
+	return uuid.NewSHA1(serviceNamespace, []byte(clientID))
+}
+
+// IsService reports whether the claims belong to a service token rather than a user
+func (c *UserClaims) IsService() bool {
+	return c.TokenType == TokenTypeService && c.ClientID != ""
+}
+
+// HasScope reports whether a service token was granted scope
+func (c *UserClaims) HasScope(scope string) bool {
+	return containsScope(c.Scopes, scope)
+}
+
+// SetClientStore enables client credentials using store
+func (a *AuthService) SetClientStore(store ClientStore) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clients = store
+}
+
+func (a *AuthService) clientStore() ClientStore {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.clients
+}
+
+// RegisterClient creates a service client allowed the given scopes and returns its secret.
+// Only a hash of the secret is stored, so it cannot be recovered later.
+func (a *AuthService) RegisterClient(ctx context.Context, clientID, name string, scopes ...string) (string, error) {
+	store := a.clientStore()
+	if store == nil {
+		return "", ErrClientStoreMissing
+	}
+	if _, err := store.GetClient(ctx, clientID); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrDuplicateClient, clientID)
+	} else if !errors.Is(err, ErrClientNotFound) {
+		return "", err
+	}
+
+	secret, err := generateClientSecret()
+	if err != nil {
+		return "", err
+	}
+	client := &ServiceClient{
+		ID:         clientID,
+		Name:       name,
+		SecretHash: hashClientSecret(secret),
+		Scopes:     scopes,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.SaveClient(ctx, client); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// IssueServiceToken exchanges client credentials for a short-lived service token.
+// scopes narrows the token to a subset of the client's scopes; none requests them all.
+func (a *AuthService) IssueServiceToken(ctx context.Context, clientID, secret string, scopes ...string) (string, error) {
+	store := a.clientStore()
+	if store == nil {
+		return "", ErrClientStoreMissing
+	}
+
+	client, err := store.GetClient(ctx, clientID)
+	if errors.Is(err, ErrClientNotFound) {
+		a.Audit(ctx, &AuditEvent{Type: AuditServiceTokenFailed, Identifier: clientID, Reason: "unknown client"})
+		return "", ErrInvalidClient
+	}
+	if err != nil {
+		return "", err
+	}
+	if client.Disabled || subtle.ConstantTimeCompare([]byte(hashClientSecret(secret)), []byte(client.SecretHash)) != 1 {
+		a.Audit(ctx, &AuditEvent{Type: AuditServiceTokenFailed, Identifier: clientID, Reason: "invalid secret or disabled client"})
+		return "", ErrInvalidClient
+	}
+
+	granted := client.Scopes
+	if len(scopes) > 0 {
+		for _, scope := range scopes {
+			if !containsScope(client.Scopes, scope) {
+				a.Audit(ctx, &AuditEvent{Type: AuditServiceTokenFailed, Identifier: clientID, Reason: "scope " + scope + " not allowed"})
+				return "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+			}
+		}
+		granted = scopes
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id":    ServicePrincipalID(client.ID).String(),
+		"sub":        client.ID,
+		"client_id":  client.ID,
+		"name":       client.Name,
+		"scopes":     granted,
+		"token_type": TokenTypeService,
+		"jti":        uuid.New().String(),
+		"exp":        now.Add(a.serviceTokenDuration()).Unix(),
+		"iat":        now.Unix(),
+	}
+	if client.TenantID != "" {
+		claims["tenant_id"] = client.TenantID
+	}
+	token, err := a.signClaims(claims)
+	if err != nil {
+		return "", err
+	}
+
+	a.Audit(ctx, &AuditEvent{Type: AuditServiceToken, ActorID: ServicePrincipalID(client.ID).String(), Identifier: client.ID})
+	return token, nil
+}
+
+func (a *AuthService) serviceTokenDuration() time.Duration {
+	if a.cfg.ServiceTokenDuration <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(a.cfg.ServiceTokenDuration) * time.Second
+}
+
+// ClientCredentialsHandler serves an OAuth 2.0 client credentials token endpoint (RFC 6749 section 4.4).
+// Clients authenticate with HTTP Basic auth or client_id and client_secret form fields.
+func (a *AuthService) ClientCredentialsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+
+		if c.PostForm("grant_type") != "client_credentials" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+			return
+		}
+
+		clientID, secret, ok := c.Request.BasicAuth()
+		if !ok {
+			clientID, secret = c.PostForm("client_id"), c.PostForm("client_secret")
+		}
+		scopes := strings.Fields(c.PostForm("scope"))
+
+		token, err := a.IssueServiceToken(c.Request.Context(), clientID, secret, scopes...)
+		switch {
+		case errors.Is(err, ErrInvalidClient):
+			c.Header("WWW-Authenticate", `Basic realm="token"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+			return
+		case errors.Is(err, ErrInvalidScope):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope"})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+			return
+		}
+
+		response := gin.H{
+			"access_token": token,
+			"token_type":   "Bearer",
+			"expires_in":   int(a.serviceTokenDuration().Seconds()),
+		}
+		if len(scopes) > 0 {
+			response["scope"] = strings.Join(scopes, " ")
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func containsScope(allowed []string, scope string) bool {
+	for _, granted := range allowed {
+		if matchPermission(granted, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func generateClientSecret() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate client secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// hashClientSecret uses SHA-256 rather than a password hash since secrets are long and random
+func hashClientSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClientCredentials(t *testing.T) (*AuthService, string) {
+	authService := newTestAuthService(t)
+	authService.SetClientStore(NewMemoryClientStore())
+	secret, err := authService.RegisterClient(context.Background(), "billing", "Billing service", "invoices:read", "ledger:*")
+	require.NoError(t, err)
+	return authService, secret
+}
+
+func TestIssueServiceToken(t *testing.T) {
+	authService, secret := newTestClientCredentials(t)
+	ctx := context.Background()
+
+	token, err := authService.IssueServiceToken(ctx, "billing", secret, "ledger:write")
+	require.NoError(t, err)
+
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+	assert.True(t, claims.IsService())
+	assert.Equal(t, "billing", claims.ClientID)
+	assert.Equal(t, ServicePrincipalID("billing"), claims.UserID)
+	assert.Equal(t, []string{"ledger:write"}, claims.Scopes)
+	assert.True(t, claims.HasScope("ledger:write"))
+	assert.False(t, claims.HasScope("invoices:read"))
+}
+
+func TestIssueServiceToken_Rejects(t *testing.T) {
+	authService, secret := newTestClientCredentials(t)
+	ctx := context.Background()
+
+	_, err := authService.IssueServiceToken(ctx, "billing", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidClient)
+
+	_, err = authService.IssueServiceToken(ctx, "unknown", secret)
+	assert.ErrorIs(t, err, ErrInvalidClient)
+
+	_, err = authService.IssueServiceToken(ctx, "billing", secret, "users:delete")
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	_, err = authService.RegisterClient(ctx, "billing", "Duplicate")
+	assert.ErrorIs(t, err, ErrDuplicateClient)
+}
+
+func TestUserTokenIsNotService(t *testing.T) {
+	authService := newTestAuthService(t)
+	token, err := authService.GenerateAccessToken(ServicePrincipalID("user"), "user@example.com", "device-1")
+	require.NoError(t, err)
+
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+	assert.False(t, claims.IsService())
+}
+
+func TestClientCredentialsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService, secret := newTestClientCredentials(t)
+
+	engine := gin.New()
+	engine.POST("/oauth/token", authService.ClientCredentialsHandler())
+
+	serve := func(form url.Values, basicSecret string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if basicSecret != "" {
+			req.SetBasicAuth("billing", basicSecret)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(url.Values{"grant_type": {"client_credentials"}, "scope": {"invoices:read"}}, secret)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+		Scope       string `json:"scope"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Bearer", response.TokenType)
+	assert.Equal(t, 300, response.ExpiresIn)
+	assert.Equal(t, "invoices:read", response.Scope)
+	assert.NotEmpty(t, response.AccessToken)
+
+	w = serve(url.Values{"grant_type": {"client_credentials"}, "client_id": {"billing"}, "client_secret": {secret}}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(url.Values{"grant_type": {"client_credentials"}}, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = serve(url.Values{"grant_type": {"password"}}, secret)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
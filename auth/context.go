@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserClaimsKey is the gin.Context key HTTPMiddleware stores claims under
+const UserClaimsKey = "user_claims"
+
+// ClaimsContextKey is used to store UserClaims in a context.Context
+type ClaimsContextKey struct{}
+
+// ContextWithClaims returns a copy of ctx carrying claims
+func ContextWithClaims(ctx context.Context, claims *UserClaims) context.Context {
+	return context.WithValue(ctx, ClaimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by the auth middleware or interceptors.
+// It accepts a *gin.Context directly as well as a request or gRPC context.
+func ClaimsFromContext(ctx context.Context) (*UserClaims, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	if c, ok := ctx.(*gin.Context); ok {
+		if raw, exists := c.Get(UserClaimsKey); exists {
+			claims, ok := raw.(*UserClaims)
+			return claims, ok && claims != nil
+		}
+		if c.Request == nil {
+			return nil, false
+		}
+		ctx = c.Request.Context()
+	}
+	claims, ok := ctx.Value(ClaimsContextKey{}).(*UserClaims)
+	return claims, ok && claims != nil
+}
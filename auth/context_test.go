@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimsFromContext(t *testing.T) {
+	claims := &UserClaims{UserID: uuid.New()}
+
+	_, ok := ClaimsFromContext(context.Background())
+	assert.False(t, ok)
+
+	// A plain string key must not be mistaken for the typed key
+	_, ok = ClaimsFromContext(context.WithValue(context.Background(), UserClaimsKey, claims))
+	assert.False(t, ok)
+
+	got, ok := ClaimsFromContext(ContextWithClaims(context.Background(), claims))
+	assert.True(t, ok)
+	assert.Same(t, claims, got)
+}
+
+func TestHTTPMiddleware_StoresClaims(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+
+	userID := uuid.New()
+	token, err := authService.GenerateAccessToken(userID, "user@example.com", "device-1")
+	require.NoError(t, err)
+
+	var fromGin, fromRequest *UserClaims
+	engine := gin.New()
+	engine.Use(authService.HTTPMiddleware())
+	engine.GET("/me", func(c *gin.Context) {
+		fromGin, _ = ClaimsFromContext(c)
+		fromRequest, _ = ClaimsFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, fromGin)
+	require.NotNil(t, fromRequest)
+	assert.Equal(t, userID, fromGin.UserID)
+	assert.Equal(t, userID, fromRequest.UserID)
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/config"
+)
+
+// CookieConfig controls delivery of tokens as cookies for browser clients
+type CookieConfig struct {
+	AccessTokenName  string
+	RefreshTokenName string
+	CSRFCookieName   string
+	CSRFHeaderName   string
+	Domain           string
+	Path             string
+	// RefreshPath limits the refresh cookie to the refresh endpoint so it is not sent on every request
+	RefreshPath string
+	Secure      bool
+	SameSite    http.SameSite
+}
+
+// DefaultCookieConfig returns Secure, SameSite=Lax cookies scoped to the whole site
+func DefaultCookieConfig() *CookieConfig {
+	return &CookieConfig{
+		AccessTokenName:  "access_token",
+		RefreshTokenName: "refresh_token",
+		CSRFCookieName:   "csrf_token",
+		CSRFHeaderName:   "X-CSRF-Token",
+		Path:             "/",
+		RefreshPath:      "/",
+		Secure:           true,
+		SameSite:         http.SameSiteLaxMode,
+	}
+}
+
+// GetCookieConfig resolves cookie settings from auth.cookie.* config keys and AUTH_COOKIE_* environment variables
+func GetCookieConfig(resolver *config.ConfigResolver) *CookieConfig {
+	defaults := DefaultCookieConfig()
+	return &CookieConfig{
+		AccessTokenName:  resolver.GetString("auth.cookie.access_token_name", "AUTH_COOKIE_ACCESS_TOKEN_NAME", defaults.AccessTokenName),
+		RefreshTokenName: resolver.GetString("auth.cookie.refresh_token_name", "AUTH_COOKIE_REFRESH_TOKEN_NAME", defaults.RefreshTokenName),
+		CSRFCookieName:   resolver.GetString("auth.cookie.csrf_cookie_name", "AUTH_COOKIE_CSRF_COOKIE_NAME", defaults.CSRFCookieName),
+		CSRFHeaderName:   resolver.GetString("auth.cookie.csrf_header_name", "AUTH_COOKIE_CSRF_HEADER_NAME", defaults.CSRFHeaderName),
+		Domain:           resolver.GetString("auth.cookie.domain", "AUTH_COOKIE_DOMAIN", ""),
+		Path:             resolver.GetString("auth.cookie.path", "AUTH_COOKIE_PATH", defaults.Path),
+		RefreshPath:      resolver.GetString("auth.cookie.refresh_path", "AUTH_COOKIE_REFRESH_PATH", defaults.RefreshPath),
+		Secure:           resolver.GetBool("auth.cookie.secure", "AUTH_COOKIE_SECURE", defaults.Secure),
+		SameSite:         parseSameSite(resolver.GetString("auth.cookie.same_site", "AUTH_COOKIE_SAME_SITE", "lax")),
+	}
+}
+
+func parseSameSite(mode string) http.SameSite {
+	switch strings.ToLower(mode) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// EnableCookieAuth makes HTTPMiddleware accept the access token cookie when no Authorization header is sent.
+// Cookie authenticated requests with unsafe methods must echo the CSRF cookie in the CSRF header.
+func (a *AuthService) EnableCookieAuth(cfg *CookieConfig) {
+	if cfg == nil {
+		cfg = DefaultCookieConfig()
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cookies = cfg
+}
+
+func (a *AuthService) cookieConfig() *CookieConfig {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cookies
+}
+
+// SetTokenCookies delivers tokens as HttpOnly cookies and issues a fresh CSRF token readable by scripts
+func (a *AuthService) SetTokenCookies(c *gin.Context, accessToken, refreshToken string) error {
+	cfg := a.cookieConfig()
+	if cfg == nil {
+		return fmt.Errorf("cookie auth is not enabled")
+	}
+
+	csrfToken, err := generateCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(c.Writer, cfg.cookie(cfg.AccessTokenName, accessToken, cfg.Path, a.cfg.AccessTokenDuration, true))
+	if refreshToken != "" {
+		http.SetCookie(c.Writer, cfg.cookie(cfg.RefreshTokenName, refreshToken, cfg.RefreshPath, a.cfg.RefreshTokenDuration, true))
+	}
+	// Outlives the access token so a refresh can still be CSRF protected
+	http.SetCookie(c.Writer, cfg.cookie(cfg.CSRFCookieName, csrfToken, cfg.Path, a.cfg.RefreshTokenDuration, false))
+	return nil
+}
+
+// ClearTokenCookies expires all auth cookies, e.g. on logout
+func (a *AuthService) ClearTokenCookies(c *gin.Context) {
+	cfg := a.cookieConfig()
+	if cfg == nil {
+		return
+	}
+	http.SetCookie(c.Writer, cfg.cookie(cfg.AccessTokenName, "", cfg.Path, -1, true))
+	http.SetCookie(c.Writer, cfg.cookie(cfg.RefreshTokenName, "", cfg.RefreshPath, -1, true))
+	http.SetCookie(c.Writer, cfg.cookie(cfg.CSRFCookieName, "", cfg.Path, -1, false))
+}
+
+// RefreshTokenFromCookie returns the refresh token cookie for the refresh endpoint
+func (a *AuthService) RefreshTokenFromCookie(c *gin.Context) (string, bool) {
+	cfg := a.cookieConfig()
+	if cfg == nil {
+		return "", false
+	}
+	token, err := c.Cookie(cfg.RefreshTokenName)
+	return token, err == nil && token != ""
+}
+
+func (cfg *CookieConfig) cookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: cfg.SameSite,
+	}
+}
+
+// CSRFMiddleware enforces the double-submit check for routes not behind HTTPMiddleware.
+// Requests carrying an Authorization header are exempt since browsers never attach it automatically.
+func (a *AuthService) CSRFMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" && !a.validCSRF(c) {
+			abortWithError(c, app_error.PermissionDenied("Invalid CSRF token"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// validCSRF reports whether a request is safe or its CSRF header matches the CSRF cookie
+func (a *AuthService) validCSRF(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	cfg := a.cookieConfig()
+	if cfg == nil {
+		return true
+	}
+
+	cookie, err := c.Cookie(cfg.CSRFCookieName)
+	if err != nil || cookie == "" {
+		return false
+	}
+	header := c.GetHeader(cfg.CSRFHeaderName)
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+// tokenFromCookie returns the access token cookie when cookie auth is enabled
+func (a *AuthService) tokenFromCookie(c *gin.Context) (string, bool) {
+	cfg := a.cookieConfig()
+	if cfg == nil {
+		return "", false
+	}
+	token, err := c.Cookie(cfg.AccessTokenName)
+	return token, err == nil && token != ""
+}
+
+func generateCSRFToken() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTokenCookies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	authService.EnableCookieAuth(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	require.NoError(t, authService.SetTokenCookies(c, "access", "refresh"))
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	require.Len(t, cookies, 3)
+
+	access := cookies["access_token"]
+	assert.Equal(t, "access", access.Value)
+	assert.True(t, access.HttpOnly)
+	assert.True(t, access.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, access.SameSite)
+	assert.Equal(t, 1800, access.MaxAge)
+
+	assert.True(t, cookies["refresh_token"].HttpOnly)
+	assert.Equal(t, 7776000, cookies["refresh_token"].MaxAge)
+
+	csrf := cookies["csrf_token"]
+	assert.False(t, csrf.HttpOnly, "csrf cookie must be readable by scripts")
+	assert.NotEmpty(t, csrf.Value)
+}
+
+func TestSetTokenCookies_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Error(t, authService.SetTokenCookies(c, "access", "refresh"))
+}
+
+func TestHTTPMiddleware_CookieAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	authService.EnableCookieAuth(nil)
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "user@example.com", "device-1")
+	require.NoError(t, err)
+
+	engine := gin.New()
+	engine.Use(authService.HTTPMiddleware())
+	engine.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.POST("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(method, csrfCookie, csrfHeader string) int {
+		req := httptest.NewRequest(method, "/me", nil)
+		req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+		if csrfCookie != "" {
+			req.AddCookie(&http.Cookie{Name: "csrf_token", Value: csrfCookie})
+		}
+		if csrfHeader != "" {
+			req.Header.Set("X-CSRF-Token", csrfHeader)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "", ""))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "", ""))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "csrf", ""))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "csrf", "other"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "csrf", "csrf"))
+}
+
+func TestHTTPMiddleware_CookieIgnoredWhenDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "user@example.com", "device-1")
+	require.NoError(t, err)
+
+	engine := gin.New()
+	engine.Use(authService.HTTPMiddleware())
+	engine.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestCSRFMiddleware_BearerExempt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	authService.EnableCookieAuth(nil)
+
+	engine := gin.New()
+	engine.Use(authService.CSRFMiddleware())
+	engine.POST("/refresh", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodPost, "/refresh", nil)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/refresh", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,385 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWKSPath is the conventional path the JWKS handler is served on
+const JWKSPath = "/.well-known/jwks.json"
+
+// Signing algorithms supported for locally issued tokens
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnknownKey           = errors.New("unknown signing key")
+	ErrKeyInUse             = errors.New("signing key is in use")
+	ErrDuplicateKey         = errors.New("signing key already exists")
+	ErrInvalidKeyPEM        = errors.New("invalid PEM block")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnsupportedKey       = errors.New("unsupported key type")
+	ErrAlgorithmMismatch    = errors.New("token algorithm does not match key")
+)
+
+// SigningKey is a key pair identified by the kid header of the tokens it signs.
+// Keys without a private half are only used to verify tokens, e.g. keys published by another instance.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+	CreatedAt  time.Time
+}
+
+// JSONWebKey is the RFC 7517 representation of a public key
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the document served by the JWKS handler
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// ParsePrivateKey parses a PEM encoded RSA, ECDSA or Ed25519 private key in PKCS8, PKCS1 or SEC1 form
+func ParsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", ErrInvalidKeyPEM)
+	}
+
+	// Try PKCS8 first
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("failed to parse private key: %w: %T", ErrUnsupportedKey, key)
+		}
+		return signer, nil
+	}
+
+	// Try PKCS1 format (for RSA)
+	if rsaKey, pkcs1Err := x509.ParsePKCS1PrivateKey(block.Bytes); pkcs1Err == nil {
+		return rsaKey, nil
+	}
+
+	// Try SEC1 format (for ECDSA)
+	if ecKey, sec1Err := x509.ParseECPrivateKey(block.Bytes); sec1Err == nil {
+		return ecKey, nil
+	}
+
+	return nil, fmt.Errorf("failed to parse private key: not PKCS8, PKCS1 or SEC1: %v", err)
+}
+
+// ParsePublicKey parses a PKIX PEM encoded RSA, ECDSA or Ed25519 public key
+func ParsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", ErrInvalidKeyPEM)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+// ParseRSAPrivateKey parses a PKCS8 or PKCS1 PEM encoded RSA private key
+func ParseRSAPrivateKey(data string) (*rsa.PrivateKey, error) {
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse private key: key is not RSA type")
+	}
+	return rsaKey, nil
+}
+
+// ParseRSAPublicKey parses a PKIX PEM encoded RSA public key
+func ParseRSAPublicKey(data string) (*rsa.PublicKey, error) {
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse public key: key is not RSA type")
+	}
+	return rsaKey, nil
+}
+
+// KeyAlgorithm returns the signing algorithm used with key: RS256, ES256 or EdDSA
+func KeyAlgorithm(key crypto.PublicKey) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRS256, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return "", fmt.Errorf("%w: ECDSA curve %s, only P-256 is supported", ErrUnsupportedKey, k.Curve.Params().Name)
+		}
+		return AlgorithmES256, nil
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+}
+
+// signingMethod returns the jwt signing method for a supported algorithm
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmES256:
+		return jwt.SigningMethodES256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// newSigningKey validates that the key matches alg; an empty alg is inferred from the key
+func newSigningKey(kid, alg string, privateKey crypto.Signer, publicKey crypto.PublicKey) (*SigningKey, error) {
+	keyAlg, err := KeyAlgorithm(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if alg == "" {
+		alg = keyAlg
+	}
+	if _, err := signingMethod(alg); err != nil {
+		return nil, err
+	}
+	if alg != keyAlg {
+		return nil, fmt.Errorf("%w: %s key cannot sign %s", ErrAlgorithmMismatch, keyAlg, alg)
+	}
+	if kid == "" {
+		kid = KeyThumbprint(publicKey)
+	}
+	return &SigningKey{
+		ID:         kid,
+		Algorithm:  alg,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// KeyThumbprint returns the RFC 7638 thumbprint of key, used as the kid when none is configured
+func KeyThumbprint(key crypto.PublicKey) string {
+	// Members in lexicographic order with no whitespace, as the RFC requires
+	var canonical string
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, encodeExponent(k.E), encodeBigInt(k.N))
+	case *ecdsa.PublicKey:
+		x, y := encodeCoordinates(k)
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, k.Curve.Params().Name, x, y)
+	case ed25519.PublicKey:
+		canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, base64.RawURLEncoding.EncodeToString(k))
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func encodeExponent(e int) string {
+	return encodeBigInt(big.NewInt(int64(e)))
+}
+
+// encodeCoordinates encodes EC coordinates padded to the curve size, as RFC 7518 requires
+func encodeCoordinates(key *ecdsa.PublicKey) (string, string) {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	x := base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+	y := base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	return x, y
+}
+
+// CurrentKeyID returns the kid of the key new tokens are signed with
+func (a *AuthService) CurrentKeyID() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.currentKID
+}
+
+// RotateKey makes privateKey the signing key for new tokens under kid.
+// The algorithm is inferred from the key, so rotation can also migrate between algorithms.
+// Previous keys stay valid for verification until retired with RetireKey,
+// so retire them only once tokens they signed have expired.
+func (a *AuthService) RotateKey(kid string, privateKey crypto.Signer) error {
+	if privateKey == nil {
+		return fmt.Errorf("private key is required")
+	}
+	key, err := newSigningKey(kid, "", privateKey, privateKey.Public())
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.keys[key.ID]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateKey, key.ID)
+	}
+	a.keys[key.ID] = key
+	a.currentKID = key.ID
+	a.privateKey = key.PrivateKey
+	a.publicKey = key.PublicKey
+	return nil
+}
+
+// AddVerificationKey accepts tokens signed by another issuer's key without signing with it
+func (a *AuthService) AddVerificationKey(kid string, publicKey crypto.PublicKey) error {
+	if publicKey == nil {
+		return fmt.Errorf("public key is required")
+	}
+	key, err := newSigningKey(kid, "", nil, publicKey)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.keys[key.ID]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateKey, key.ID)
+	}
+	a.keys[key.ID] = key
+	return nil
+}
+
+// RetireKey stops accepting tokens signed with kid. The current signing key cannot be retired.
+func (a *AuthService) RetireKey(kid string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.keys[kid]; !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+	if kid == a.currentKID {
+		return fmt.Errorf("%w: %s", ErrKeyInUse, kid)
+	}
+	delete(a.keys, kid)
+	return nil
+}
+
+// Keys returns the active keys, oldest first
+func (a *AuthService) Keys() []SigningKey {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	keys := make([]SigningKey, 0, len(a.keys))
+	for _, key := range a.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys
+}
+
+// signingKey returns the current key used to sign new tokens
+func (a *AuthService) signingKey() *SigningKey {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.keys[a.currentKID]
+}
+
+// verificationKeys returns the key for kid, or every active key for tokens issued without one.
+// Only keys registered for alg are returned, so a token cannot pick the algorithm its key is checked with.
+func (a *AuthService) verificationKeys(kid, alg string) ([]crypto.PublicKey, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if kid != "" {
+		key, exists := a.keys[kid]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+		}
+		if key.Algorithm != alg {
+			return nil, fmt.Errorf("%w: %s key %s used with %s", ErrAlgorithmMismatch, key.Algorithm, kid, alg)
+		}
+		return []crypto.PublicKey{key.PublicKey}, nil
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(a.keys))
+	for _, key := range a.keys {
+		if key.Algorithm == alg {
+			keys = append(keys, key.PublicKey)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no %s keys", ErrAlgorithmMismatch, alg)
+	}
+	return keys, nil
+}
+
+// JWKS returns the public halves of all active keys
+func (a *AuthService) JWKS() JSONWebKeySet {
+	keys := a.Keys()
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(keys))}
+	for _, key := range keys {
+		jwk := JSONWebKey{Use: "sig", Alg: key.Algorithm, Kid: key.ID}
+		switch k := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encodeBigInt(k.N)
+			jwk.E = encodeExponent(k.E)
+		case *ecdsa.PublicKey:
+			jwk.Kty = "EC"
+			jwk.Crv = k.Curve.Params().Name
+			jwk.X, jwk.Y = encodeCoordinates(k)
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(k)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// JWKSHandler serves JWKS so other services can verify tokens; mount it on JWKSPath without auth
+func (a *AuthService) JWKSHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, a.JWKS())
+	}
+}
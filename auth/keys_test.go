@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/config"
+)
+
+func generateTestKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestGenerateAccessToken_KeyIDHeader(t *testing.T) {
+	authService := newTestAuthService(t)
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &UserClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, KeyThumbprint(authService.publicKey), parsed.Header["kid"])
+	assert.Equal(t, authService.CurrentKeyID(), parsed.Header["kid"])
+}
+
+func TestRotateKey(t *testing.T) {
+	authService := newTestAuthService(t)
+	originalKID := authService.CurrentKeyID()
+
+	oldToken, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+
+	require.NoError(t, authService.RotateKey("2024-06", generateTestKey(t)))
+	assert.Equal(t, "2024-06", authService.CurrentKeyID())
+
+	newToken, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+
+	// Both keys verify until the old one is retired
+	_, err = authService.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	_, err = authService.ValidateToken(newToken)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, authService.RetireKey("2024-06"), ErrKeyInUse)
+	require.NoError(t, authService.RetireKey(originalKID))
+
+	_, err = authService.ValidateToken(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = authService.ValidateToken(newToken)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, authService.RotateKey("2024-06", generateTestKey(t)), ErrDuplicateKey)
+}
+
+func TestValidateToken_WithoutKeyID(t *testing.T) {
+	authService := newTestAuthService(t)
+	require.NoError(t, authService.RotateKey("next", generateTestKey(t)))
+
+	// Tokens issued before kid headers were introduced are checked against every key
+	legacy := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"user_id": uuid.New().String(),
+		"email":   "test@example.com",
+	})
+	originalKey, err := ParseRSAPrivateKey(testPrivateKey)
+	require.NoError(t, err)
+	token, err := legacy.SignedString(originalKey)
+	require.NoError(t, err)
+
+	_, err = authService.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	require.NoError(t, authService.RotateKey("next", generateTestKey(t)))
+
+	engine := gin.New()
+	engine.GET(JWKSPath, authService.JWKSHandler())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", JWKSPath, nil)
+	engine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var set JSONWebKeySet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "next", set.Keys[1].Kid)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "RS256", set.Keys[0].Alg)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+}
+
+func newTestAuthServiceWithKey(t *testing.T, key crypto.Signer, algorithm string) (*AuthService, error) {
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	return NewAuthService(&config.AuthConfig{
+		PrivateKey:          string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey})),
+		PublicKey:           string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})),
+		Algorithm:           algorithm,
+		AccessTokenDuration: 1800,
+	})
+}
+
+func TestAuthService_Algorithms(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		key       crypto.Signer
+		algorithm string
+		kty       string
+	}{
+		{"ES256", ecKey, AlgorithmES256, "EC"},
+		{"EdDSA", edKey, AlgorithmEdDSA, "OKP"},
+		{"inferred", ecKey, "", "EC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService, err := newTestAuthServiceWithKey(t, tt.key, tt.algorithm)
+			require.NoError(t, err)
+
+			userID := uuid.New()
+			token, err := authService.GenerateAccessToken(userID, "test@example.com", "test-device-123")
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &UserClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, authService.Keys()[0].Algorithm, parsed.Method.Alg())
+
+			claims, err := authService.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, userID, claims.UserID)
+
+			jwks := authService.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tt.kty, jwks.Keys[0].Kty)
+		})
+	}
+}
+
+func TestNewAuthService_AlgorithmMismatch(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = newTestAuthServiceWithKey(t, ecKey, AlgorithmRS256)
+	assert.ErrorIs(t, err, ErrAlgorithmMismatch)
+
+	_, err = newTestAuthServiceWithKey(t, ecKey, "HS256")
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestValidateToken_RejectsAlgorithmSwitch(t *testing.T) {
+	authService := newTestAuthService(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// An ES256 token naming the RSA key must not be checked against it
+	forged := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"user_id": uuid.New().String()})
+	forged.Header["kid"] = authService.CurrentKeyID()
+	token, err := forged.SignedString(ecKey)
+	require.NoError(t, err)
+
+	_, err = authService.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrAlgorithmMismatch)
+
+	// Symmetric algorithms are never accepted
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": uuid.New().String()})
+	hmac.Header["kid"] = authService.CurrentKeyID()
+	token, err = hmac.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = authService.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestRotateKey_ToEdDSA(t *testing.T) {
+	authService := newTestAuthService(t)
+	oldToken, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, authService.RotateKey("ed-1", edKey))
+
+	newToken, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+
+	_, err = authService.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	_, err = authService.ValidateToken(newToken)
+	assert.NoError(t, err)
+}
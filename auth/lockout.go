@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// loginLimiterPrefix namespaces login attempt counters and lockouts in the cache
+const loginLimiterPrefix = "auth:login:"
+
+// Login events reported through LoginLimiterConfig.OnEvent
+const (
+	LoginEventFailed    = "login_failed"
+	LoginEventSucceeded = "login_succeeded"
+	LoginEventLocked    = "login_locked"
+	LoginEventBlocked   = "login_blocked"
+)
+
+// ErrLoginLocked is returned while an identifier or IP is locked out
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// LoginEvent describes a login attempt outcome, for audit logging
+type LoginEvent struct {
+	Type       string
+	Identifier string
+	IP         string
+	Attempts   int
+	Time       time.Time
+}
+
+// LoginLimiterConfig configures brute-force protection
+type LoginLimiterConfig struct {
+	// MaxAttemptsPerUser locks an identifier after this many failures within Window (default 5)
+	MaxAttemptsPerUser int
+
+	// MaxAttemptsPerIP locks a client IP after this many failures within Window (default 20)
+	MaxAttemptsPerIP int
+
+	// Window is the sliding window failures are counted over (default 15m)
+	Window time.Duration
+
+	// LockoutDuration is how long a lockout lasts (default 15m)
+	LockoutDuration time.Duration
+
+	// OnEvent receives every login event (default logs lockouts)
+	OnEvent func(LoginEvent)
+}
+
+// LoginLimiter counts failed logins per identifier and per IP and locks either out once a limit is reached.
+// Counts use a sliding window approximated from the current and previous fixed windows,
+// which works with any CacheService using only Incr and Expire.
+type LoginLimiter struct {
+	cache  cache.CacheService
+	config LoginLimiterConfig
+	now    func() time.Time
+}
+
+// NewLoginLimiter creates a limiter storing its state in c; share the cache across instances
+func NewLoginLimiter(c cache.CacheService, cfg LoginLimiterConfig) *LoginLimiter {
+	if cfg.MaxAttemptsPerUser <= 0 {
+		cfg.MaxAttemptsPerUser = 5
+	}
+	if cfg.MaxAttemptsPerIP <= 0 {
+		cfg.MaxAttemptsPerIP = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 15 * time.Minute
+	}
+	if cfg.LockoutDuration <= 0 {
+		cfg.LockoutDuration = 15 * time.Minute
+	}
+	if cfg.OnEvent == nil {
+		cfg.OnEvent = func(event LoginEvent) {
+			if event.Type == LoginEventLocked {
+				log.Printf("[Auth] login locked for identifier=%q ip=%s after %d attempts", event.Identifier, event.IP, event.Attempts)
+			}
+		}
+	}
+	return &LoginLimiter{cache: c, config: cfg, now: time.Now}
+}
+
+// Check returns ErrLoginLocked with the remaining lockout time if identifier or ip is locked.
+// Call it before verifying credentials so locked accounts never reach the password check.
+func (l *LoginLimiter) Check(ctx context.Context, identifier, ip string) (time.Duration, error) {
+	for _, subject := range l.subjects(identifier, ip) {
+		ttl, err := l.cache.TTL(ctx, l.lockKey(subject))
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if ttl > 0 {
+			l.emit(LoginEventBlocked, identifier, ip, 0)
+			return ttl, fmt.Errorf("%w: retry after %s", ErrLoginLocked, ttl.Round(time.Second))
+		}
+	}
+	return 0, nil
+}
+
+// RecordFailure counts a failed attempt, locking the identifier or IP once its limit is reached
+func (l *LoginLimiter) RecordFailure(ctx context.Context, identifier, ip string) error {
+	var attempts int
+	for _, subject := range l.subjects(identifier, ip) {
+		count, err := l.increment(ctx, subject)
+		if err != nil {
+			return err
+		}
+		if subject.user {
+			attempts = count
+		}
+		if count >= l.limit(subject) {
+			if err := l.cache.Set(ctx, l.lockKey(subject), count, l.config.LockoutDuration); err != nil {
+				return err
+			}
+			l.emit(LoginEventLocked, identifier, ip, count)
+		}
+	}
+	l.emit(LoginEventFailed, identifier, ip, attempts)
+	return nil
+}
+
+// RecordSuccess clears the identifier's failure count; IP counts are kept so one valid login
+// does not reset a credential stuffing run from the same address
+func (l *LoginLimiter) RecordSuccess(ctx context.Context, identifier, ip string) error {
+	subject := limiterSubject{key: "user:" + normalizeIdentifier(identifier), user: true}
+	window := l.windowIndex(l.now())
+	if err := l.cache.Delete(ctx, l.counterKey(subject, window), l.counterKey(subject, window-1)); err != nil {
+		return err
+	}
+	l.emit(LoginEventSucceeded, identifier, ip, 0)
+	return nil
+}
+
+// Unlock lifts a lockout early, e.g. after an administrator verifies the account owner
+func (l *LoginLimiter) Unlock(ctx context.Context, identifier string) error {
+	subject := limiterSubject{key: "user:" + normalizeIdentifier(identifier), user: true}
+	window := l.windowIndex(l.now())
+	return l.cache.Delete(ctx, l.lockKey(subject), l.counterKey(subject, window), l.counterKey(subject, window-1))
+}
+
+// Middleware rejects requests with 429 and Retry-After while the identifier or client IP is locked.
+// identifier extracts the login identifier, e.g. from a form field; it may return "" to check the IP only.
+func (l *LoginLimiter) Middleware(identifier func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := ""
+		if identifier != nil {
+			id = identifier(c)
+		}
+		retryAfter, err := l.Check(c.Request.Context(), id, c.ClientIP())
+		if errors.Is(err, ErrLoginLocked) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			abortWithError(c, app_error.RateLimited("Too many login attempts"))
+			return
+		}
+		if err != nil {
+			abortWithError(c, app_error.Unavailable(""))
+			return
+		}
+		c.Next()
+	}
+}
+
+type limiterSubject struct {
+	key  string
+	user bool
+}
+
+func (l *LoginLimiter) subjects(identifier, ip string) []limiterSubject {
+	var subjects []limiterSubject
+	if id := normalizeIdentifier(identifier); id != "" {
+		subjects = append(subjects, limiterSubject{key: "user:" + id, user: true})
+	}
+	if ip != "" {
+		subjects = append(subjects, limiterSubject{key: "ip:" + ip})
+	}
+	return subjects
+}
+
+func (l *LoginLimiter) limit(subject limiterSubject) int {
+	if subject.user {
+		return l.config.MaxAttemptsPerUser
+	}
+	return l.config.MaxAttemptsPerIP
+}
+
+// increment adds a failure and returns the sliding window count
+func (l *LoginLimiter) increment(ctx context.Context, subject limiterSubject) (int, error) {
+	now := l.now()
+	window := l.windowIndex(now)
+
+	key := l.counterKey(subject, window)
+	current, err := l.cache.Incr(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if current == 1 {
+		// Kept for two windows so it can serve as the previous window next time
+		if err := l.cache.Expire(ctx, key, 2*l.config.Window); err != nil {
+			return 0, err
+		}
+	}
+
+	previous, err := l.counter(ctx, l.counterKey(subject, window-1))
+	if err != nil {
+		return 0, err
+	}
+
+	// Weight the previous window by how much of it still overlaps the sliding window
+	elapsed := float64(now.UnixNano()%int64(l.config.Window)) / float64(l.config.Window)
+	return int(current) + int(float64(previous)*(1-elapsed)), nil
+}
+
+func (l *LoginLimiter) counter(ctx context.Context, key string) (int64, error) {
+	value, err := l.cache.Get(ctx, key)
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, nil
+	}
+}
+
+func (l *LoginLimiter) windowIndex(t time.Time) int64 {
+	return t.UnixNano() / int64(l.config.Window)
+}
+
+func (l *LoginLimiter) counterKey(subject limiterSubject, window int64) string {
+	return fmt.Sprintf("%sfail:%s:%d", loginLimiterPrefix, subject.key, window)
+}
+
+func (l *LoginLimiter) lockKey(subject limiterSubject) string {
+	return loginLimiterPrefix + "lock:" + subject.key
+}
+
+func (l *LoginLimiter) emit(eventType, identifier, ip string, attempts int) {
+	l.config.OnEvent(LoginEvent{
+		Type:       eventType,
+		Identifier: identifier,
+		IP:         ip,
+		Attempts:   attempts,
+		Time:       l.now(),
+	})
+}
+
+func normalizeIdentifier(identifier string) string {
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
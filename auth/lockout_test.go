@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func TestLoginLimiter_LocksUser(t *testing.T) {
+	ctx := context.Background()
+	var events []LoginEvent
+	limiter := NewLoginLimiter(local.NewLocalCache(nil), LoginLimiterConfig{
+		MaxAttemptsPerUser: 3,
+		OnEvent:            func(event LoginEvent) { events = append(events, event) },
+	})
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, limiter.RecordFailure(ctx, "Jane@Example.com", "10.0.0.1"))
+	}
+	_, err := limiter.Check(ctx, "jane@example.com", "10.0.0.2")
+	assert.NoError(t, err)
+
+	require.NoError(t, limiter.RecordFailure(ctx, "jane@example.com", "10.0.0.1"))
+	retryAfter, err := limiter.Check(ctx, "jane@example.com", "10.0.0.2")
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	assert.InDelta(t, (15 * time.Minute).Seconds(), retryAfter.Seconds(), 2)
+
+	// Other users are unaffected
+	_, err = limiter.Check(ctx, "john@example.com", "10.0.0.2")
+	assert.NoError(t, err)
+
+	require.NoError(t, limiter.Unlock(ctx, "jane@example.com"))
+	_, err = limiter.Check(ctx, "jane@example.com", "10.0.0.2")
+	assert.NoError(t, err)
+
+	var locked int
+	for _, event := range events {
+		if event.Type == LoginEventLocked {
+			locked++
+			assert.Equal(t, 3, event.Attempts)
+		}
+	}
+	assert.Equal(t, 1, locked)
+}
+
+func TestLoginLimiter_LocksIP(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLoginLimiter(local.NewLocalCache(nil), LoginLimiterConfig{MaxAttemptsPerIP: 2})
+
+	require.NoError(t, limiter.RecordFailure(ctx, "a@example.com", "10.0.0.1"))
+	require.NoError(t, limiter.RecordFailure(ctx, "b@example.com", "10.0.0.1"))
+
+	_, err := limiter.Check(ctx, "c@example.com", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	_, err = limiter.Check(ctx, "c@example.com", "10.0.0.2")
+	assert.NoError(t, err)
+}
+
+func TestLoginLimiter_SuccessResetsUser(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLoginLimiter(local.NewLocalCache(nil), LoginLimiterConfig{MaxAttemptsPerUser: 2})
+
+	require.NoError(t, limiter.RecordFailure(ctx, "jane@example.com", "10.0.0.1"))
+	require.NoError(t, limiter.RecordSuccess(ctx, "jane@example.com", "10.0.0.1"))
+	require.NoError(t, limiter.RecordFailure(ctx, "jane@example.com", "10.0.0.1"))
+
+	_, err := limiter.Check(ctx, "jane@example.com", "10.0.0.1")
+	assert.NoError(t, err)
+}
+
+func TestLoginLimiter_SlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLoginLimiter(local.NewLocalCache(nil), LoginLimiterConfig{
+		MaxAttemptsPerUser: 100,
+		Window:             time.Minute,
+	})
+	start := time.Unix(0, 0).Add(1000 * time.Minute)
+	limiter.now = func() time.Time { return start }
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.RecordFailure(ctx, "jane@example.com", ""))
+	}
+
+	// Three quarters into the next window a quarter of the previous count still applies
+	limiter.now = func() time.Time { return start.Add(time.Minute + 45*time.Second) }
+	count, err := limiter.increment(ctx, limiterSubject{key: "user:jane@example.com", user: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestLoginLimiter_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewLoginLimiter(local.NewLocalCache(nil), LoginLimiterConfig{MaxAttemptsPerUser: 1})
+	require.NoError(t, limiter.RecordFailure(context.Background(), "jane@example.com", "10.0.0.1"))
+
+	engine := gin.New()
+	engine.POST("/login", limiter.Middleware(func(c *gin.Context) string { return c.Query("email") }), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(email string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/login?email="+email, nil)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("jane@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, serve("john@example.com").Code)
+}
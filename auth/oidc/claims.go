@@ -0,0 +1,118 @@
+package oidc
+
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/auth"
+)
+
+// ClaimMapping names the provider claims copied into UserClaims.
+// Names are looked up literally first, so namespaced Auth0 claims such as
+// "https://example.com/roles" work, then as dotted paths such as Keycloak's "realm_access.roles".
+type ClaimMapping struct {
+	Email string
+	Name  string
+	Roles string
+}
+
+func (m ClaimMapping) withDefaults() ClaimMapping {
+	if m.Email == "" {
+		m.Email = "email"
+	}
+	if m.Name == "" {
+		m.Name = "name"
+	}
+	if m.Roles == "" {
+		m.Roles = "roles"
+	}
+	return m
+}
+
+// UserClaims maps verified provider claims onto auth.UserClaims; the request Principal is built from these
+func (m ClaimMapping) UserClaims(issuer string, claims jwt.MapClaims) (*auth.UserClaims, error) {
+	m = m.withDefaults()
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, ErrMissingSubject
+	}
+
+	userClaims := &auth.UserClaims{
+		UserID: SubjectID(issuer, subject),
+		Email:  stringClaim(claims, m.Email),
+		Name:   stringClaim(claims, m.Name),
+		Roles:  stringsClaim(claims, m.Roles),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:  issuer,
+			Subject: subject,
+		},
+	}
+	userClaims.Audience, _ = claims.GetAudience()
+	userClaims.ExpiresAt, _ = claims.GetExpirationTime()
+	userClaims.IssuedAt, _ = claims.GetIssuedAt()
+	userClaims.NotBefore, _ = claims.GetNotBefore()
+	if jti, ok := claims["jti"].(string); ok {
+		userClaims.ID = jti
+	}
+	return userClaims, nil
+}
+
+// SubjectID maps a provider subject onto a stable user ID by hashing it with the issuer, so the same
+// subject from different providers never collides. UUID subjects (e.g. Keycloak) are hashed too, so a
+// provider can't pick a subject equal to a local user's ID. Issuers carry no fragment, so "#" can't be
+// part of one and keeps issuer and subject apart.
+func SubjectID(issuer, subject string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(issuer+"#"+subject))
+}
+
+// lookup finds name literally or as a dotted path into nested objects
+func lookup(claims map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := claims[name]; ok {
+		return value, true
+	}
+
+	parts := strings.Split(name, ".")
+	var current interface{} = claims
+	for _, part := range parts {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := lookup(claims, name)
+	s, _ := value.(string)
+	return s
+}
+
+// stringsClaim accepts either a list of strings or a single space or comma separated string
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	value, ok := lookup(claims, name)
+	if !ok {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+// Package oidc validates tokens issued by external OpenID Connect providers such as
+// Google, Auth0 or Keycloak, using discovery and the provider's JWKS.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/config"
+)
+
+// discoveryPath is appended to the issuer to locate provider metadata
+const discoveryPath = "/.well-known/openid-configuration"
+
+var (
+	ErrIssuerMismatch = errors.New("discovered issuer does not match configured issuer")
+	ErrUnknownKey     = errors.New("unknown signing key")
+	ErrMissingSubject = errors.New("token has no subject")
+)
+
+// DefaultAlgorithms are the signing algorithms accepted when Config.Algorithms is empty
+var DefaultAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Config describes an external identity provider
+type Config struct {
+	// Issuer is the provider's issuer URL, e.g. https://accounts.google.com
+	Issuer string
+
+	// Audiences are the accepted client IDs; a token must name at least one
+	Audiences []string
+
+	// Algorithms restricts accepted signing algorithms (default DefaultAlgorithms)
+	Algorithms []string
+
+	// Claims maps provider claims onto UserClaims
+	Claims ClaimMapping
+
+	// ClockSkew tolerated when checking exp, nbf and iat (default 1m)
+	ClockSkew time.Duration
+
+	// KeyCacheTTL is how long fetched keys are trusted before refetching (default 1h)
+	KeyCacheTTL time.Duration
+
+	// KeyRefreshInterval limits refetches triggered by unknown key IDs (default 1m)
+	KeyRefreshInterval time.Duration
+
+	// HTTPClient fetches discovery and key documents (default 10s timeout)
+	HTTPClient *http.Client
+}
+
+// GetConfig resolves a provider configuration from oidc.* config keys and OIDC_* environment variables
+func GetConfig(resolver *config.ConfigResolver) *Config {
+	return &Config{
+		Issuer:    resolver.GetString("oidc.issuer", "OIDC_ISSUER", ""),
+		Audiences: resolver.GetStringSlice("oidc.audiences", "OIDC_AUDIENCES", nil),
+		Claims: ClaimMapping{
+			Email: resolver.GetString("oidc.claims.email", "OIDC_EMAIL_CLAIM", ""),
+			Name:  resolver.GetString("oidc.claims.name", "OIDC_NAME_CLAIM", ""),
+			Roles: resolver.GetString("oidc.claims.roles", "OIDC_ROLES_CLAIM", ""),
+		},
+	}
+}
+
+// Provider verifies tokens from a single issuer
+type Provider struct {
+	issuer     string
+	config     Config
+	keys       *keySet
+	algorithms []string
+}
+
+var _ auth.TokenVerifier = (*Provider)(nil)
+
+// NewProvider discovers the provider's metadata and prepares its key set.
+// Keys are fetched lazily on first verification.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("oidc issuer is required")
+	}
+	if len(cfg.Audiences) == 0 {
+		return nil, fmt.Errorf("at least one oidc audience is required")
+	}
+	if len(cfg.Algorithms) == 0 {
+		cfg.Algorithms = DefaultAlgorithms
+	}
+	if cfg.ClockSkew <= 0 {
+		cfg.ClockSkew = time.Minute
+	}
+	if cfg.KeyCacheTTL <= 0 {
+		cfg.KeyCacheTTL = time.Hour
+	}
+	if cfg.KeyRefreshInterval <= 0 {
+		cfg.KeyRefreshInterval = time.Minute
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.Claims = cfg.Claims.withDefaults()
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	url := strings.TrimSuffix(cfg.Issuer, "/") + discoveryPath
+	if err := getJSON(ctx, cfg.HTTPClient, url, &discovery); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if discovery.Issuer != cfg.Issuer {
+		return nil, fmt.Errorf("%w: %s != %s", ErrIssuerMismatch, discovery.Issuer, cfg.Issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery for %s has no jwks_uri", cfg.Issuer)
+	}
+
+	return &Provider{
+		issuer:     cfg.Issuer,
+		config:     cfg,
+		keys:       newKeySet(discovery.JWKSURI, cfg.HTTPClient, cfg.KeyCacheTTL, cfg.KeyRefreshInterval),
+		algorithms: cfg.Algorithms,
+	}, nil
+}
+
+// Issuer returns the issuer tokens are checked against
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+// Verify validates token and returns its raw claims
+func (p *Provider) Verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods(p.algorithms),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.config.Audiences...),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(p.config.ClockSkew),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// VerifyToken implements auth.TokenVerifier, so the provider can be added with AuthService.AddTokenVerifier.
+// Tokens from other issuers return auth.ErrTokenNotHandled without a signature check.
+func (p *Provider) VerifyToken(ctx context.Context, token string) (*auth.UserClaims, error) {
+	if !p.issued(token) {
+		return nil, auth.ErrTokenNotHandled
+	}
+
+	claims, err := p.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return p.config.Claims.UserClaims(p.issuer, claims)
+}
+
+// issued reports whether the unverified iss claim names this provider
+func (p *Provider) issued(token string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return false
+	}
+	issuer, err := claims.GetIssuer()
+	return err == nil && issuer == p.issuer
+}
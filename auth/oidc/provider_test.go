@@ -0,0 +1,186 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/config"
+)
+
+type testIdP struct {
+	server    *httptest.Server
+	key       *ecdsa.PrivateKey
+	kid       string
+	jwksCalls int
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	idp := &testIdP{key: key, kid: "idp-key-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   idp.server.URL,
+			"jwks_uri": idp.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		idp.jwksCalls++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "EC",
+				"crv": "P-256",
+				"use": "sig",
+				"kid": idp.kid,
+				"x":   base64.RawURLEncoding.EncodeToString(idp.key.PublicKey.X.FillBytes(make([]byte, 32))),
+				"y":   base64.RawURLEncoding.EncodeToString(idp.key.PublicKey.Y.FillBytes(make([]byte, 32))),
+			}},
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *testIdP) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = idp.kid
+	signed, err := token.SignedString(idp.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (idp *testIdP) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   idp.server.URL,
+		"aud":   "my-client",
+		"sub":   "google-oauth2|1234",
+		"email": "jane@example.com",
+		"name":  "Jane Doe",
+		"realm_access": map[string]interface{}{
+			"roles": []string{"operator"},
+		},
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}
+}
+
+func newTestProvider(t *testing.T, idp *testIdP) *Provider {
+	provider, err := NewProvider(context.Background(), Config{
+		Issuer:    idp.server.URL,
+		Audiences: []string{"my-client"},
+		Claims:    ClaimMapping{Roles: "realm_access.roles"},
+	})
+	require.NoError(t, err)
+	return provider
+}
+
+func TestProvider_VerifyToken(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := newTestProvider(t, idp)
+
+	claims, err := provider.VerifyToken(context.Background(), idp.sign(t, idp.claims()))
+	require.NoError(t, err)
+
+	assert.Equal(t, SubjectID(idp.server.URL, "google-oauth2|1234"), claims.UserID)
+	assert.Equal(t, "jane@example.com", claims.Email)
+	assert.Equal(t, "Jane Doe", claims.Name)
+	assert.Equal(t, []string{"operator"}, claims.Roles)
+	assert.Equal(t, "google-oauth2|1234", claims.Subject)
+
+	// Keys are cached between verifications
+	_, err = provider.VerifyToken(context.Background(), idp.sign(t, idp.claims()))
+	require.NoError(t, err)
+	assert.Equal(t, 1, idp.jwksCalls)
+}
+
+func TestProvider_VerifyToken_Rejects(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := newTestProvider(t, idp)
+
+	wrongAudience := idp.claims()
+	wrongAudience["aud"] = "other-client"
+	_, err := provider.VerifyToken(context.Background(), idp.sign(t, wrongAudience))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	expired := idp.claims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = provider.VerifyToken(context.Background(), idp.sign(t, expired))
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+	otherIssuer := idp.claims()
+	otherIssuer["iss"] = "https://issuer.example.com"
+	_, err = provider.VerifyToken(context.Background(), idp.sign(t, otherIssuer))
+	assert.ErrorIs(t, err, auth.ErrTokenNotHandled)
+}
+
+func TestNewProvider_IssuerMismatch(t *testing.T) {
+	idp := newTestIdP(t)
+
+	_, err := NewProvider(context.Background(), Config{
+		Issuer:    idp.server.URL + "/",
+		Audiences: []string{"my-client"},
+	})
+	assert.ErrorIs(t, err, ErrIssuerMismatch)
+}
+
+func TestSubjectID(t *testing.T) {
+	id := uuid.New()
+	assert.NotEqual(t, id, SubjectID("https://keycloak.example.com", id.String()))
+	assert.Equal(t, SubjectID("https://keycloak.example.com", id.String()), SubjectID("https://keycloak.example.com", id.String()))
+	assert.Equal(t, SubjectID("a", "user"), SubjectID("a", "user"))
+	assert.NotEqual(t, SubjectID("a", "user"), SubjectID("b", "user"))
+}
+
+func newTestAuthService(t *testing.T) *auth.AuthService {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	service, err := auth.NewAuthService(&config.AuthConfig{
+		PrivateKey:          string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		PublicKey:           string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})),
+		AccessTokenDuration: 1800,
+	})
+	require.NoError(t, err)
+	return service
+}
+
+func TestAuthService_AcceptsProviderTokens(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := newTestProvider(t, idp)
+
+	service := newTestAuthService(t)
+	service.AddTokenVerifier(provider)
+
+	claims, err := service.ValidateTokenContext(context.Background(), idp.sign(t, idp.claims()))
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", claims.Email)
+
+	// Locally issued tokens keep working alongside provider tokens
+	local, err := service.GenerateAccessToken(uuid.New(), "local@example.com", "device")
+	require.NoError(t, err)
+	claims, err = service.ValidateTokenContext(context.Background(), local)
+	require.NoError(t, err)
+	assert.Equal(t, "local@example.com", claims.Email)
+}
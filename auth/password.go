@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidHash        = errors.New("invalid password hash")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrCredentialNotFound = errors.New("credential not found")
+	ErrUnsupportedHash    = errors.New("unsupported password hash algorithm")
+)
+
+// Argon2Params tunes argon2id hashing; hashes record their parameters so they can be raised over time
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follow the OWASP recommendation of 64 MiB, 3 iterations
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// HashPassword hashes password with argon2id and DefaultArgon2Params
+func HashPassword(password string) (string, error) {
+	return HashPasswordArgon2(password, DefaultArgon2Params)
+}
+
+// HashPasswordArgon2 returns a PHC formatted argon2id hash: $argon2id$v=19$m=...,t=...,p=...$salt$key
+func HashPasswordArgon2(password string, params Argon2Params) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// HashPasswordBcrypt hashes password with bcrypt, for stores that must stay compatible with bcrypt
+func HashPasswordBcrypt(password string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword checks password against an argon2id or bcrypt hash in constant time.
+// needsRehash reports that the hash should be replaced with HashPassword once the password is known to match,
+// either because it uses bcrypt or weaker argon2id parameters than DefaultArgon2Params.
+func VerifyPassword(password, encoded string) (match bool, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2(encoded)
+		if err != nil {
+			return false, false, err
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		match = subtle.ConstantTimeCompare(candidate, key) == 1
+		return match, match && weakerThan(params, DefaultArgon2Params), nil
+
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+		}
+		return true, true, nil
+
+	default:
+		return false, false, ErrUnsupportedHash
+	}
+}
+
+func decodeArgon2(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("%w: unsupported argon2 version", ErrInvalidHash)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("%w: invalid key", ErrInvalidHash)
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
+func weakerThan(params, target Argon2Params) bool {
+	return params.Memory < target.Memory ||
+		params.Iterations < target.Iterations ||
+		params.KeyLength < target.KeyLength
+}
+
+// Credential is a stored password for a user
+type Credential struct {
+	UserID       uuid.UUID
+	Identifier   string
+	PasswordHash string
+}
+
+// CredentialStore loads and updates password hashes; implement it over the application's user table
+type CredentialStore interface {
+	// GetCredential returns the credential for a login identifier such as an email, or ErrCredentialNotFound
+	GetCredential(ctx context.Context, identifier string) (*Credential, error)
+
+	// UpdatePasswordHash replaces the stored hash, used to upgrade hashes on login
+	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, hash string) error
+}
+
+// PasswordAuthenticator verifies logins against a CredentialStore, upgrading outdated hashes as users sign in
+type PasswordAuthenticator struct {
+	store     CredentialStore
+	dummyHash string
+}
+
+// NewPasswordAuthenticator creates an authenticator backed by store
+func NewPasswordAuthenticator(store CredentialStore) (*PasswordAuthenticator, error) {
+	// Verified against for unknown identifiers so they take as long as wrong passwords
+	dummyHash, err := HashPassword(uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	return &PasswordAuthenticator{store: store, dummyHash: dummyHash}, nil
+}
+
+// Authenticate returns the credential when password matches, or ErrInvalidCredentials for
+// an unknown identifier or wrong password alike so callers cannot leak which accounts exist
+func (p *PasswordAuthenticator) Authenticate(ctx context.Context, identifier, password string) (*Credential, error) {
+	credential, err := p.store.GetCredential(ctx, identifier)
+	if errors.Is(err, ErrCredentialNotFound) {
+		_, _, _ = VerifyPassword(password, p.dummyHash)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match, needsRehash, err := VerifyPassword(password, credential.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		if hash, err := HashPassword(password); err == nil {
+			if err := p.store.UpdatePasswordHash(ctx, credential.UserID, hash); err != nil {
+				// Login still succeeds; the upgrade is retried next time
+				log.Printf("[Auth] failed to upgrade password hash for %s: %v", credential.UserID, err)
+			} else {
+				credential.PasswordHash = hash
+			}
+		}
+	}
+	return credential, nil
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2Params keeps tests quick; never use in production
+var fastArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$"))
+
+	match, needsRehash, err := VerifyPassword("correct horse battery staple", hash)
+	require.NoError(t, err)
+	assert.True(t, match)
+	assert.False(t, needsRehash)
+
+	match, _, err = VerifyPassword("wrong", hash)
+	require.NoError(t, err)
+	assert.False(t, match)
+
+	// Salts differ between hashes of the same password
+	other, err := HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
+
+func TestVerifyPassword_NeedsRehash(t *testing.T) {
+	weak, err := HashPasswordArgon2("secret", fastArgon2Params)
+	require.NoError(t, err)
+	match, needsRehash, err := VerifyPassword("secret", weak)
+	require.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, needsRehash)
+
+	legacy, err := HashPasswordBcrypt("secret", bcrypt.MinCost)
+	require.NoError(t, err)
+	match, needsRehash, err = VerifyPassword("secret", legacy)
+	require.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, needsRehash)
+
+	match, needsRehash, err = VerifyPassword("wrong", legacy)
+	require.NoError(t, err)
+	assert.False(t, match)
+	assert.False(t, needsRehash)
+}
+
+func TestVerifyPassword_InvalidHash(t *testing.T) {
+	_, _, err := VerifyPassword("secret", "plaintext")
+	assert.ErrorIs(t, err, ErrUnsupportedHash)
+
+	_, _, err = VerifyPassword("secret", "$argon2id$v=19$m=1024$abc")
+	assert.ErrorIs(t, err, ErrInvalidHash)
+}
+
+type memoryCredentialStore struct {
+	credentials map[string]*Credential
+	updates     int
+}
+
+func (s *memoryCredentialStore) GetCredential(ctx context.Context, identifier string) (*Credential, error) {
+	credential, ok := s.credentials[identifier]
+	if !ok {
+		return nil, ErrCredentialNotFound
+	}
+	copied := *credential
+	return &copied, nil
+}
+
+func (s *memoryCredentialStore) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, hash string) error {
+	for _, credential := range s.credentials {
+		if credential.UserID == userID {
+			credential.PasswordHash = hash
+			s.updates++
+		}
+	}
+	return nil
+}
+
+func TestPasswordAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	legacy, err := HashPasswordBcrypt("secret", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	store := &memoryCredentialStore{credentials: map[string]*Credential{
+		"jane@example.com": {UserID: uuid.New(), Identifier: "jane@example.com", PasswordHash: legacy},
+	}}
+	authenticator, err := NewPasswordAuthenticator(store)
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(ctx, "jane@example.com", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = authenticator.Authenticate(ctx, "nobody@example.com", "secret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, 0, store.updates)
+
+	credential, err := authenticator.Authenticate(ctx, "jane@example.com", "secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(credential.PasswordHash, "$argon2id$"))
+	assert.Equal(t, 1, store.updates)
+
+	// The upgraded hash keeps working and is not upgraded again
+	_, err = authenticator.Authenticate(ctx, "jane@example.com", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.updates)
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WildcardPermission grants every permission
+const WildcardPermission = "*"
+
+var (
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrPermissionDenied  = errors.New("permission denied")
+)
+
+// PermissionRegistry holds the known permissions and the permissions granted by each role.
+// Permissions are "<resource>:<action>" strings such as "nodes:write"; "nodes:*" grants every action on nodes.
+type PermissionRegistry struct {
+	mu          sync.RWMutex
+	permissions map[string]string
+	roles       map[string][]string
+}
+
+var (
+	globalPermissions     = NewPermissionRegistry()
+	globalPermissionsLock sync.RWMutex
+)
+
+// NewPermissionRegistry creates an empty registry
+func NewPermissionRegistry() *PermissionRegistry {
+	return &PermissionRegistry{
+		permissions: make(map[string]string),
+		roles:       make(map[string][]string),
+	}
+}
+
+// GetPermissionRegistry returns the global registry used by UserClaims.HasPermission
+func GetPermissionRegistry() *PermissionRegistry {
+	globalPermissionsLock.RLock()
+	defer globalPermissionsLock.RUnlock()
+	return globalPermissions
+}
+
+// SetPermissionRegistry replaces the global registry
+func SetPermissionRegistry(registry *PermissionRegistry) {
+	globalPermissionsLock.Lock()
+	defer globalPermissionsLock.Unlock()
+	globalPermissions = registry
+}
+
+// RegisterPermission declares a permission with a human readable description
+func (r *PermissionRegistry) RegisterPermission(permission, description string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.permissions[permission] = description
+}
+
+// Permissions returns the registered permissions in sorted order
+func (r *PermissionRegistry) Permissions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	permissions := make([]string, 0, len(r.permissions))
+	for permission := range r.permissions {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+// Description returns the description of a registered permission
+func (r *PermissionRegistry) Description(permission string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	description, ok := r.permissions[permission]
+	return description, ok
+}
+
+// DefineRole sets the permissions granted by role, replacing any earlier definition.
+// Every permission must be registered or be a wildcard.
+func (r *PermissionRegistry) DefineRole(role string, permissions ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, permission := range permissions {
+		if !r.knownLocked(permission) {
+			return fmt.Errorf("%w: %s for role %s", ErrUnknownPermission, permission, role)
+		}
+	}
+	r.roles[role] = append([]string(nil), permissions...)
+	return nil
+}
+
+func (r *PermissionRegistry) knownLocked(permission string) bool {
+	if permission == WildcardPermission || strings.HasSuffix(permission, ":*") {
+		return true
+	}
+	_, ok := r.permissions[permission]
+	return ok
+}
+
+// RolePermissions returns the permissions granted by roles, without duplicates
+func (r *PermissionRegistry) RolePermissions(roles ...string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var permissions []string
+	for _, role := range roles {
+		for _, permission := range r.roles[role] {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+// Allows reports whether claims grant permission, either directly, through a scope or through a role
+func (r *PermissionRegistry) Allows(claims *UserClaims, permission string) bool {
+	if claims == nil {
+		return false
+	}
+	for _, granted := range claims.Permissions {
+		if matchPermission(granted, permission) {
+			return true
+		}
+	}
+	// Service tokens are granted their scopes directly
+	for _, granted := range claims.Scopes {
+		if matchPermission(granted, permission) {
+			return true
+		}
+	}
+	for _, granted := range r.RolePermissions(claims.Roles...) {
+		if matchPermission(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns ErrPermissionDenied unless claims grant every permission
+func (r *PermissionRegistry) Authorize(claims *UserClaims, permissions ...string) error {
+	for _, permission := range permissions {
+		if !r.Allows(claims, permission) {
+			return fmt.Errorf("%w: %s", ErrPermissionDenied, permission)
+		}
+	}
+	return nil
+}
+
+// matchPermission reports whether granted covers required, honouring "*" and "<resource>:*"
+func matchPermission(granted, required string) bool {
+	if granted == WildcardPermission || granted == required {
+		return true
+	}
+	if resource, ok := strings.CutSuffix(granted, ":*"); ok {
+		return strings.HasPrefix(required, resource+":")
+	}
+	return false
+}
+
+// HasRole reports whether the claims include role
+func (c *UserClaims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the claims grant permission using the global registry
+func (c *UserClaims) HasPermission(permission string) bool {
+	return GetPermissionRegistry().Allows(c, permission)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPermissionRegistry(t *testing.T) *PermissionRegistry {
+	registry := NewPermissionRegistry()
+	registry.RegisterPermission("nodes:read", "View nodes")
+	registry.RegisterPermission("nodes:write", "Create and modify nodes")
+	registry.RegisterPermission("users:read", "View users")
+
+	require.NoError(t, registry.DefineRole("viewer", "nodes:read", "users:read"))
+	require.NoError(t, registry.DefineRole("operator", "nodes:*"))
+	require.NoError(t, registry.DefineRole("admin", WildcardPermission))
+	return registry
+}
+
+func TestPermissionRegistry_DefineRoleUnknownPermission(t *testing.T) {
+	registry := NewPermissionRegistry()
+	err := registry.DefineRole("viewer", "nodes:read")
+	assert.ErrorIs(t, err, ErrUnknownPermission)
+}
+
+func TestPermissionRegistry_Allows(t *testing.T) {
+	registry := newTestPermissionRegistry(t)
+
+	tests := []struct {
+		name       string
+		claims     *UserClaims
+		permission string
+		expected   bool
+	}{
+		{"nil claims", nil, "nodes:read", false},
+		{"role grants permission", &UserClaims{Roles: []string{"viewer"}}, "nodes:read", true},
+		{"role lacks permission", &UserClaims{Roles: []string{"viewer"}}, "nodes:write", false},
+		{"resource wildcard", &UserClaims{Roles: []string{"operator"}}, "nodes:write", true},
+		{"resource wildcard other resource", &UserClaims{Roles: []string{"operator"}}, "users:read", false},
+		{"global wildcard", &UserClaims{Roles: []string{"admin"}}, "users:read", true},
+		{"direct permission", &UserClaims{Permissions: []string{"nodes:write"}}, "nodes:write", true},
+		{"unknown role", &UserClaims{Roles: []string{"ghost"}}, "nodes:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, registry.Allows(tt.claims, tt.permission))
+		})
+	}
+}
+
+func TestPermissionRegistry_Authorize(t *testing.T) {
+	registry := newTestPermissionRegistry(t)
+	claims := &UserClaims{Roles: []string{"viewer"}}
+
+	assert.NoError(t, registry.Authorize(claims, "nodes:read", "users:read"))
+	assert.ErrorIs(t, registry.Authorize(claims, "nodes:read", "nodes:write"), ErrPermissionDenied)
+}
+
+func TestGenerateAccessToken_WithRoles(t *testing.T) {
+	authService := newTestAuthService(t)
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123",
+		WithRoles("viewer"), WithPermissions("nodes:write"))
+	require.NoError(t, err)
+
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viewer"}, claims.Roles)
+	assert.Equal(t, []string{"nodes:write"}, claims.Permissions)
+	assert.True(t, claims.HasRole("viewer"))
+	assert.True(t, claims.HasPermission("nodes:write"))
+}
+
+func TestGenerateAccessToken_WithTenant(t *testing.T) {
+	authService := newTestAuthService(t)
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123", WithTenant("acme"))
+	require.NoError(t, err)
+
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", claims.TenantID)
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// revokedTokenPrefix namespaces revoked jti entries in the cache
+const revokedTokenPrefix = "auth:revoked:"
+
+var (
+	ErrTokenRevoked          = errors.New("token has been revoked")
+	ErrRevocationDisabled    = errors.New("no revocation store configured")
+	ErrMissingTokenID        = errors.New("token has no jti")
+	ErrRevocationCheckFailed = errors.New("failed to check token revocation")
+)
+
+// RevocationStore records revoked access tokens by jti. Entries expire with the token,
+// so the store only ever holds tokens that would otherwise still be accepted.
+type RevocationStore struct {
+	cache cache.CacheService
+}
+
+// NewRevocationStore creates a store backed by cache; use a shared cache such as Redis when running several instances
+func NewRevocationStore(c cache.CacheService) *RevocationStore {
+	return &RevocationStore{cache: c}
+}
+
+// Revoke marks jti revoked until expiresAt; tokens that already expired are ignored
+func (s *RevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return ErrMissingTokenID
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.cache.Set(ctx, revokedTokenPrefix+jti, expiresAt.Unix(), ttl)
+}
+
+// IsRevoked reports whether jti has been revoked
+func (s *RevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	return s.cache.Exists(ctx, revokedTokenPrefix+jti)
+}
+
+// SetRevocationStore enables revocation checks in ValidateTokenContext and the middleware
+func (a *AuthService) SetRevocationStore(store *RevocationStore) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.revocations = store
+}
+
+func (a *AuthService) revocationStore() *RevocationStore {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.revocations
+}
+
+// RevokeAccessToken revokes the token with jti. The remaining lifetime is unknown,
+// so the entry is kept for the full access token duration.
+func (a *AuthService) RevokeAccessToken(ctx context.Context, jti string) error {
+	store := a.revocationStore()
+	if store == nil {
+		return ErrRevocationDisabled
+	}
+	if err := store.Revoke(ctx, jti, time.Now().Add(time.Duration(a.cfg.AccessTokenDuration)*time.Second)); err != nil {
+		return err
+	}
+	a.Audit(ctx, &AuditEvent{Type: AuditRevocation, Reason: "access token " + jti})
+	return nil
+}
+
+// RevokeClaims revokes the token the claims were parsed from, e.g. on logout
+func (a *AuthService) RevokeClaims(ctx context.Context, claims *UserClaims) error {
+	store := a.revocationStore()
+	if store == nil {
+		return ErrRevocationDisabled
+	}
+	expiresAt := time.Now().Add(time.Duration(a.cfg.AccessTokenDuration) * time.Second)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if err := store.Revoke(ctx, claims.ID, expiresAt); err != nil {
+		return err
+	}
+	a.Audit(ctx, &AuditEvent{
+		Type:           AuditRevocation,
+		ActorID:        claims.UserID.String(),
+		ClientDeviceID: claims.ClientDeviceID,
+		Reason:         "access token " + claims.ID,
+	})
+	return nil
+}
+
+// ValidateTokenContext validates the token, locally or with a registered TokenVerifier, and rejects it if revoked.
+// Revocation lookups fail closed: a cache error rejects the token.
+func (a *AuthService) ValidateTokenContext(ctx context.Context, tokenString string) (*UserClaims, error) {
+	claims, err := a.verifyToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	store := a.revocationStore()
+	if store == nil {
+		return claims, nil
+	}
+
+	revoked, err := store.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRevocationCheckFailed, err)
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
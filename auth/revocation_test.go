@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func TestRevokeAccessToken(t *testing.T) {
+	ctx := context.Background()
+	authService := newTestAuthService(t)
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+	claims, err := authService.ValidateTokenContext(ctx, token)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, authService.RevokeAccessToken(ctx, claims.ID), ErrRevocationDisabled)
+
+	authService.SetRevocationStore(NewRevocationStore(local.NewLocalCache(nil)))
+	require.NoError(t, authService.RevokeAccessToken(ctx, claims.ID))
+
+	_, err = authService.ValidateTokenContext(ctx, token)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	// Revocation does not affect signature-only validation
+	_, err = authService.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestRevokeClaims(t *testing.T) {
+	ctx := context.Background()
+	authService := newTestAuthService(t)
+	cache := local.NewLocalCache(nil)
+	authService.SetRevocationStore(NewRevocationStore(cache))
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+
+	require.NoError(t, authService.RevokeClaims(ctx, claims))
+
+	ttl, err := cache.TTL(ctx, revokedTokenPrefix+claims.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Until(claims.ExpiresAt.Time).Seconds(), ttl.Seconds(), 2)
+}
+
+func TestRevocationStore_ExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	store := NewRevocationStore(local.NewLocalCache(nil))
+
+	require.NoError(t, store.Revoke(ctx, "expired", time.Now().Add(-time.Minute)))
+	revoked, err := store.IsRevoked(ctx, "expired")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	assert.ErrorIs(t, store.Revoke(ctx, "", time.Now().Add(time.Minute)), ErrMissingTokenID)
+}
+
+func TestHTTPMiddleware_RevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	authService.SetRevocationStore(NewRevocationStore(local.NewLocalCache(nil)))
+
+	engine := gin.New()
+	engine.GET("/me", authService.HTTPMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token, err := authService.GenerateAccessToken(uuid.New(), "test@example.com", "test-device-123")
+	require.NoError(t, err)
+
+	serve := func() int {
+		req, _ := http.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	claims, err := authService.ValidateToken(token)
+	require.NoError(t, err)
+	require.NoError(t, authService.RevokeClaims(context.Background(), claims))
+
+	assert.Equal(t, http.StatusUnauthorized, serve())
+}
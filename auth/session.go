@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSessionNotFound is returned when a session does not exist, is already revoked or belongs to another user
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session is an active refresh token as shown on a "manage my devices" screen
+type Session struct {
+	ID             uuid.UUID `json:"id"`
+	ClientDeviceID string    `json:"client_device_id"`
+	IPAddress      *string   `json:"ip_address,omitempty"`
+	UserAgent      *string   `json:"user_agent,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastUsedAt     time.Time `json:"last_used_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	Current        bool      `json:"current"`
+}
+
+// ListSessions returns the user's unrevoked, unexpired sessions, most recently used first.
+// The session for currentDeviceID, if any, is flagged as Current.
+func (a *AuthService) ListSessions(db *sql.DB, userID uuid.UUID, currentDeviceID string) ([]*Session, error) {
+	query := `
+		SELECT id, client_device_id, ip_address, user_agent, created_at, last_used_at, expires_at
+		FROM refresh_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC
+	`
+	rows, err := db.QueryContext(context.Background(), query, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]*Session, 0)
+	for rows.Next() {
+		var session Session
+		if err := rows.Scan(&session.ID, &session.ClientDeviceID, &session.IPAddress, &session.UserAgent,
+			&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		session.Current = currentDeviceID != "" && session.ClientDeviceID == currentDeviceID
+		sessions = append(sessions, &session)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeSession revokes one of the user's sessions. Scoping by userID stops users revoking each other's sessions.
+func (a *AuthService) RevokeSession(db *sql.DB, userID, sessionID uuid.UUID, revokedBy *uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1, revoked_reason = $2, revoked_by = $3
+		WHERE id = $4 AND user_id = $5 AND revoked_at IS NULL
+	`
+	result, err := db.ExecContext(context.Background(), query,
+		time.Now(), RevocationReasonLogout, revokedBy, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if affected == 0 {
+		return ErrSessionNotFound
+	}
+	a.auditRevocation(&userID, "session "+sessionID.String()+": "+RevocationReasonLogout)
+	return nil
+}
+
+// RevokeOtherSessions revokes every session of the user except the one for currentDeviceID,
+// returning the number revoked
+func (a *AuthService) RevokeOtherSessions(db *sql.DB, userID uuid.UUID, currentDeviceID string, reason string) (int64, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE user_id = $3 AND client_device_id <> $4 AND revoked_at IS NULL
+	`
+	result, err := db.ExecContext(context.Background(), query,
+		time.Now(), reason, userID, currentDeviceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	a.Audit(context.Background(), &AuditEvent{
+		Type:           AuditRevocation,
+		ActorID:        userID.String(),
+		ClientDeviceID: currentDeviceID,
+		Reason:         "other sessions: " + reason,
+	})
+	return result.RowsAffected()
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultSkipGRPCMethods are always exempt from gRPC auth so reflection and health probes work unauthenticated
+var DefaultSkipGRPCMethods = []string{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// SkipPaths exempts HTTP requests under the given path prefixes from HTTPMiddleware.
+// Prefixes match whole segments, so "/health" covers "/health/live" but not "/healthz".
+func (a *AuthService) SkipPaths(prefixes ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, prefix := range prefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			a.skipPaths = append(a.skipPaths, prefix)
+		}
+	}
+}
+
+// SkipRoute exempts one Gin route pattern, e.g. ("GET", "/api/v1/users/:id"), from HTTPMiddleware
+func (a *AuthService) SkipRoute(method, pattern string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.skipRoutes == nil {
+		a.skipRoutes = make(map[string]bool)
+	}
+	a.skipRoutes[routeKey(method, pattern)] = true
+}
+
+// SkipGRPCMethods exempts gRPC full methods such as "/pkg.Service/Method" from the interceptors.
+// An entry ending in "/" exempts every method of that service.
+func (a *AuthService) SkipGRPCMethods(methods ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.skipGRPCMethods == nil {
+		a.skipGRPCMethods = make(map[string]bool)
+	}
+	for _, method := range methods {
+		if method = strings.TrimSpace(method); method != "" {
+			a.skipGRPCMethods[method] = true
+		}
+	}
+}
+
+// ShouldSkipHTTP reports whether the request matches a skipped path prefix or route
+func (a *AuthService) ShouldSkipHTTP(c *gin.Context) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if pattern := c.FullPath(); pattern != "" && a.skipRoutes[routeKey(c.Request.Method, pattern)] {
+		return true
+	}
+
+	path := c.Request.URL.Path
+	for _, prefix := range a.skipPaths {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldSkipGRPC reports whether fullMethod, or its whole service, is exempt from auth
+func (a *AuthService) ShouldSkipGRPC(fullMethod string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.skipGRPCMethods[fullMethod] {
+		return true
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+		return a.skipGRPCMethods[fullMethod[:i+1]]
+	}
+	return false
+}
+
+func routeKey(method, pattern string) string {
+	return strings.ToUpper(method) + " " + pattern
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldSkipHTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := newTestAuthService(t)
+	authService.SkipPaths("/health", "/public/")
+	authService.SkipRoute("GET", "/api/items/:id")
+
+	engine := gin.New()
+	engine.Use(authService.HTTPMiddleware())
+	for _, path := range []string{"/health", "/health/live", "/healthz", "/public/docs", "/api/items/:id"} {
+		engine.Any(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	testCases := []struct {
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{"GET", "/health", http.StatusOK},
+		{"GET", "/health/live", http.StatusOK},
+		{"GET", "/healthz", http.StatusUnauthorized},
+		{"GET", "/public/docs", http.StatusOK},
+		{"GET", "/api/items/42", http.StatusOK},
+		{"DELETE", "/api/items/42", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestShouldSkipGRPC(t *testing.T) {
+	authService := newTestAuthService(t)
+	authService.SkipGRPCMethods("/billing.v1.Plans/", "/users.v1.Users/SignUp")
+
+	assert.True(t, authService.ShouldSkipGRPC("/grpc.health.v1.Health/Check"))
+	assert.True(t, authService.ShouldSkipGRPC("/billing.v1.Plans/List"))
+	assert.True(t, authService.ShouldSkipGRPC("/users.v1.Users/SignUp"))
+	assert.False(t, authService.ShouldSkipGRPC("/users.v1.Users/Delete"))
+}
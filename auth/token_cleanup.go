@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yadunandan004/scaffold/metrics"
+)
+
+// TokenCleanupConfig configures purging of stale refresh tokens
+type TokenCleanupConfig struct {
+	// Interval between purges (default 1h)
+	Interval time.Duration
+
+	// BatchSize caps rows deleted per statement to keep locks short (default 1000)
+	BatchSize int
+
+	// Retention keeps expired and revoked rows this long, e.g. for session history (default 7 days, negative purges immediately)
+	Retention time.Duration
+}
+
+func (cfg TokenCleanupConfig) withDefaults() TokenCleanupConfig {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.Retention < 0 {
+		cfg.Retention = 0
+	} else if cfg.Retention == 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	return cfg
+}
+
+// PurgeRefreshTokens deletes refresh tokens that expired or were revoked before the retention period,
+// in batches of cfg.BatchSize, and returns the number of rows deleted
+func (a *AuthService) PurgeRefreshTokens(ctx context.Context, db *sql.DB, cfg TokenCleanupConfig) (int64, error) {
+	cfg = cfg.withDefaults()
+	cutoff := time.Now().Add(-cfg.Retention)
+
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE id IN (
+			SELECT id FROM refresh_tokens
+			WHERE expires_at < $1 OR revoked_at < $1
+			LIMIT $2
+		)
+	`
+
+	start := time.Now()
+	var total int64
+	for {
+		result, err := db.ExecContext(ctx, query, cutoff, cfg.BatchSize)
+		if err != nil {
+			metrics.RecordPurge(ctx, "refresh_tokens", total, time.Since(start), false)
+			return total, fmt.Errorf("failed to purge refresh tokens: %w", err)
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			metrics.RecordPurge(ctx, "refresh_tokens", total, time.Since(start), false)
+			return total, fmt.Errorf("failed to purge refresh tokens: %w", err)
+		}
+		total += deleted
+		if deleted < int64(cfg.BatchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	metrics.RecordPurge(ctx, "refresh_tokens", total, time.Since(start), true)
+	return total, nil
+}
+
+// StartRefreshTokenCleanup purges stale refresh tokens every cfg.Interval until ctx is cancelled
+func (a *AuthService) StartRefreshTokenCleanup(ctx context.Context, db *sql.DB, cfg TokenCleanupConfig) {
+	cfg = cfg.withDefaults()
+
+	metrics.TraceGoroutine(ctx, "auth", "refresh_token_cleanup", func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := a.PurgeRefreshTokens(ctx, db, cfg)
+				if err != nil {
+					log.Printf("[Auth] refresh token cleanup failed after %d rows: %v", deleted, err)
+					continue
+				}
+				if deleted > 0 {
+					log.Printf("[Auth] purged %d stale refresh tokens", deleted)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCleanupConfigDefaults(t *testing.T) {
+	cfg := TokenCleanupConfig{}.withDefaults()
+	assert.Equal(t, time.Hour, cfg.Interval)
+	assert.Equal(t, 1000, cfg.BatchSize)
+	assert.Equal(t, 7*24*time.Hour, cfg.Retention)
+
+	cfg = TokenCleanupConfig{Retention: -1}.withDefaults()
+	assert.Equal(t, time.Duration(0), cfg.Retention)
+}
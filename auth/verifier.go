@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTokenNotHandled is returned by a TokenVerifier for tokens it does not issue, e.g. another issuer's
+var ErrTokenNotHandled = errors.New("token not handled by verifier")
+
+// TokenVerifier validates tokens issued outside this service, such as by an OIDC provider,
+// mapping them into UserClaims so they pass through the same middleware as local tokens.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*UserClaims, error)
+}
+
+// AddTokenVerifier accepts tokens validated by v in addition to locally issued tokens.
+// Verifiers are tried in the order added when a token fails local validation.
+func (a *AuthService) AddTokenVerifier(v TokenVerifier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.verifiers = append(a.verifiers, v)
+}
+
+func (a *AuthService) tokenVerifiers() []TokenVerifier {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.verifiers
+}
+
+// verifyToken validates a local token, falling back to external verifiers
+func (a *AuthService) verifyToken(ctx context.Context, token string) (*UserClaims, error) {
+	claims, localErr := a.ValidateToken(token)
+	if localErr == nil {
+		return claims, nil
+	}
+
+	for _, verifier := range a.tokenVerifiers() {
+		claims, err := verifier.VerifyToken(ctx, token)
+		if err == nil {
+			return claims, nil
+		}
+		if !errors.Is(err, ErrTokenNotHandled) {
+			return nil, err
+		}
+	}
+	return nil, localErr
+}
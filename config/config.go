@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,9 +18,11 @@ const (
 var hostingEnv string
 
 type ServerConfig struct {
-	HTTP HTTPConfig `yaml:"http"`
-	GRPC GRPCConfig `yaml:"grpc"`
-	TLS  TLSConfig  `yaml:"tls"`
+	HTTP            HTTPConfig            `yaml:"http"`
+	GRPC            GRPCConfig            `yaml:"grpc"`
+	TLS             TLSConfig             `yaml:"tls"`
+	CORS            CORSConfig            `yaml:"cors"`
+	SecurityHeaders SecurityHeadersConfig `yaml:"security_headers"`
 }
 
 type HTTPConfig struct {
@@ -40,31 +43,89 @@ type TLSConfig struct {
 	KeyFile  string `yaml:"key_file"`
 }
 
+type CORSConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// AllowedOrigins are exact origins, "*", or subdomain wildcards such as "https://*.example.com"
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	// MaxAge is how long browsers may cache a preflight response, in seconds
+	MaxAge int `yaml:"max_age"`
+}
+
+type SecurityHeadersConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// HSTSMaxAge is the Strict-Transport-Security max-age in seconds; 0 omits the header
+	HSTSMaxAge            int    `yaml:"hsts_max_age"`
+	HSTSIncludeSubdomains bool   `yaml:"hsts_include_subdomains"`
+	FrameOptions          string `yaml:"frame_options"`
+	ContentSecurityPolicy string `yaml:"content_security_policy"`
+	ReferrerPolicy        string `yaml:"referrer_policy"`
+}
+
 type AuthConfig struct {
 	JWTSecret            string `yaml:"jwt_secret"`
 	PublicKey            string `yaml:"public_key"`
 	PrivateKey           string `yaml:"private_key"`
+	KeyID                string `yaml:"key_id"`
+	Algorithm            string `yaml:"algorithm"`
 	AccessTokenDuration  int    `yaml:"access_token_duration"`
 	RefreshTokenDuration int    `yaml:"refresh_token_duration"`
+	ServiceTokenDuration int    `yaml:"service_token_duration"`
+	// SkipPaths are HTTP path prefixes served without authentication
+	SkipPaths []string `yaml:"skip_paths"`
+	// SkipGRPCMethods are gRPC full methods, or "/pkg.Service/" prefixes, served without authentication
+	SkipGRPCMethods []string `yaml:"skip_grpc_methods"`
 }
 
 type LoggerConfig struct {
-	Level      string `yaml:"level"`
-	Format     string `yaml:"format"`
-	OutputPath string `yaml:"output_path"`
+	Level      string            `yaml:"level"`
+	Format     string            `yaml:"format"`
+	OutputPath string            `yaml:"output_path"`
+	Sampling   LogSamplingConfig `yaml:"sampling"`
+}
+
+// LogSamplingConfig holds the sampling rule for each level; a zero rule logs every entry
+type LogSamplingConfig struct {
+	Debug LogSamplingRule `yaml:"debug"`
+	Info  LogSamplingRule `yaml:"info"`
+	Warn  LogSamplingRule `yaml:"warn"`
+	Error LogSamplingRule `yaml:"error"`
+}
+
+// LogSamplingRule limits entries sharing a message and call site. The first PerSecond entries in
+// each second are kept, then every OneIn-th; with OneIn unset the rest of the second is dropped.
+type LogSamplingRule struct {
+	PerSecond int `yaml:"per_second"`
+	OneIn     int `yaml:"one_in"`
+}
+
+type SearchConfig struct {
+	// DefaultTake is the page size used when a search sets none; 0 leaves it unlimited
+	DefaultTake int `yaml:"default_take"`
+	// MaxTake caps the page size a search may ask for; 0 disables the cap
+	MaxTake int `yaml:"max_take"`
+	// MaxFilters caps the number of filters in one search; 0 disables the cap
+	MaxFilters int `yaml:"max_filters"`
 }
 
 type RedisConfig struct {
-	Enabled      bool   `yaml:"enabled"`
-	Host         string `yaml:"host"`
-	Port         int    `yaml:"port"`
-	Password     string `yaml:"password"`
-	DB           int    `yaml:"db"`
-	MaxRetries   int    `yaml:"max_retries"`
-	DialTimeout  int    `yaml:"dial_timeout"`
-	ReadTimeout  int    `yaml:"read_timeout"`
-	WriteTimeout int    `yaml:"write_timeout"`
-	PoolSize     int    `yaml:"pool_size"`
+	Enabled          bool     `yaml:"enabled"`
+	Mode             string   `yaml:"mode"`
+	Host             string   `yaml:"host"`
+	Port             int      `yaml:"port"`
+	Addrs            []string `yaml:"addrs"`
+	MasterName       string   `yaml:"master_name"`
+	Password         string   `yaml:"password"`
+	SentinelPassword string   `yaml:"sentinel_password"`
+	DB               int      `yaml:"db"`
+	MaxRetries       int      `yaml:"max_retries"`
+	DialTimeout      int      `yaml:"dial_timeout"`
+	ReadTimeout      int      `yaml:"read_timeout"`
+	WriteTimeout     int      `yaml:"write_timeout"`
+	PoolSize         int      `yaml:"pool_size"`
 }
 
 func GetServerConfig(resolver *ConfigResolver) *ServerConfig {
@@ -84,6 +145,23 @@ func GetServerConfig(resolver *ConfigResolver) *ServerConfig {
 			CertFile: resolver.GetString("server.tls.cert_file", "TLS_CERT_FILE", "/app/certs/server.crt"),
 			KeyFile:  resolver.GetString("server.tls.key_file", "TLS_KEY_FILE", "/app/certs/server.key"),
 		},
+		CORS: CORSConfig{
+			Enabled:          resolver.GetBool("server.cors.enabled", "CORS_ENABLED", false),
+			AllowedOrigins:   resolver.GetStringSlice("server.cors.allowed_origins", "CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods:   resolver.GetStringSlice("server.cors.allowed_methods", "CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders:   resolver.GetStringSlice("server.cors.allowed_headers", "CORS_ALLOWED_HEADERS", []string{"Authorization", "Content-Type", "Idempotency-Key", "traceparent", "tracestate"}),
+			ExposedHeaders:   resolver.GetStringSlice("server.cors.exposed_headers", "CORS_EXPOSED_HEADERS", []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Idempotent-Replayed"}),
+			AllowCredentials: resolver.GetBool("server.cors.allow_credentials", "CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           resolver.GetInt("server.cors.max_age", "CORS_MAX_AGE", 600),
+		},
+		SecurityHeaders: SecurityHeadersConfig{
+			Enabled:               resolver.GetBool("server.security_headers.enabled", "SECURITY_HEADERS_ENABLED", true),
+			HSTSMaxAge:            resolver.GetInt("server.security_headers.hsts_max_age", "HSTS_MAX_AGE", 31536000),
+			HSTSIncludeSubdomains: resolver.GetBool("server.security_headers.hsts_include_subdomains", "HSTS_INCLUDE_SUBDOMAINS", true),
+			FrameOptions:          resolver.GetString("server.security_headers.frame_options", "FRAME_OPTIONS", "DENY"),
+			ContentSecurityPolicy: resolver.GetString("server.security_headers.content_security_policy", "CONTENT_SECURITY_POLICY", ""),
+			ReferrerPolicy:        resolver.GetString("server.security_headers.referrer_policy", "REFERRER_POLICY", "strict-origin-when-cross-origin"),
+		},
 	}
 }
 
@@ -92,8 +170,13 @@ func GetAuthConfig(resolver *ConfigResolver) *AuthConfig {
 		JWTSecret:            resolver.GetString("auth.jwt_secret", "JWT_SECRET", ""),
 		PublicKey:            resolver.GetString("auth.public_key", "JWT_PUBLIC_KEY", ""),
 		PrivateKey:           resolver.GetString("auth.private_key", "JWT_PRIVATE_KEY", ""),
+		KeyID:                resolver.GetString("auth.key_id", "JWT_KEY_ID", ""),
+		Algorithm:            resolver.GetString("auth.algorithm", "JWT_ALGORITHM", ""),
 		AccessTokenDuration:  resolver.GetInt("auth.access_token_duration", "ACCESS_TOKEN_DURATION", 3600),
 		RefreshTokenDuration: resolver.GetInt("auth.refresh_token_duration", "REFRESH_TOKEN_DURATION", 7776000),
+		ServiceTokenDuration: resolver.GetInt("auth.service_token_duration", "SERVICE_TOKEN_DURATION", 300),
+		SkipPaths:            resolver.GetStringSlice("auth.skip_paths", "AUTH_SKIP_PATHS", nil),
+		SkipGRPCMethods:      resolver.GetStringSlice("auth.skip_grpc_methods", "AUTH_SKIP_GRPC_METHODS", nil),
 	}
 }
 
@@ -102,21 +185,47 @@ func GetLoggerConfig(resolver *ConfigResolver) *LoggerConfig {
 		Level:      resolver.GetString("logger.level", "LOG_LEVEL", "info"),
 		Format:     resolver.GetString("logger.format", "LOG_FORMAT", "console"),
 		OutputPath: resolver.GetString("logger.output_path", "LOG_OUTPUT_PATH", ""),
+		Sampling: LogSamplingConfig{
+			Debug: getLogSamplingRule(resolver, "debug"),
+			Info:  getLogSamplingRule(resolver, "info"),
+			Warn:  getLogSamplingRule(resolver, "warn"),
+			Error: getLogSamplingRule(resolver, "error"),
+		},
+	}
+}
+
+func getLogSamplingRule(resolver *ConfigResolver, level string) LogSamplingRule {
+	envPrefix := "LOG_SAMPLING_" + strings.ToUpper(level)
+	return LogSamplingRule{
+		PerSecond: resolver.GetInt("logger.sampling."+level+".per_second", envPrefix+"_PER_SECOND", 0),
+		OneIn:     resolver.GetInt("logger.sampling."+level+".one_in", envPrefix+"_ONE_IN", 0),
+	}
+}
+
+func GetSearchConfig(resolver *ConfigResolver) *SearchConfig {
+	return &SearchConfig{
+		DefaultTake: resolver.GetInt("search.default_take", "SEARCH_DEFAULT_TAKE", 50),
+		MaxTake:     resolver.GetInt("search.max_take", "SEARCH_MAX_TAKE", 1000),
+		MaxFilters:  resolver.GetInt("search.max_filters", "SEARCH_MAX_FILTERS", 20),
 	}
 }
 
 func GetRedisConfig(resolver *ConfigResolver) *RedisConfig {
 	return &RedisConfig{
-		Enabled:      resolver.GetBool("redis.enabled", "REDIS_ENABLED", false),
-		Host:         resolver.GetString("redis.host", "REDIS_HOST", "localhost"),
-		Port:         resolver.GetInt("redis.port", "REDIS_PORT", 6379),
-		Password:     resolver.GetString("redis.password", "REDIS_PASSWORD", ""),
-		DB:           resolver.GetInt("redis.db", "REDIS_DB", 0),
-		MaxRetries:   resolver.GetInt("redis.max_retries", "REDIS_MAX_RETRIES", 3),
-		DialTimeout:  resolver.GetInt("redis.dial_timeout", "REDIS_DIAL_TIMEOUT", 5),
-		ReadTimeout:  resolver.GetInt("redis.read_timeout", "REDIS_READ_TIMEOUT", 3),
-		WriteTimeout: resolver.GetInt("redis.write_timeout", "REDIS_WRITE_TIMEOUT", 3),
-		PoolSize:     resolver.GetInt("redis.pool_size", "REDIS_POOL_SIZE", 10),
+		Enabled:          resolver.GetBool("redis.enabled", "REDIS_ENABLED", false),
+		Mode:             resolver.GetString("redis.mode", "REDIS_MODE", "single"),
+		Host:             resolver.GetString("redis.host", "REDIS_HOST", "localhost"),
+		Port:             resolver.GetInt("redis.port", "REDIS_PORT", 6379),
+		Addrs:            resolver.GetStringSlice("redis.addrs", "REDIS_ADDRS", nil),
+		MasterName:       resolver.GetString("redis.master_name", "REDIS_MASTER_NAME", ""),
+		Password:         resolver.GetString("redis.password", "REDIS_PASSWORD", ""),
+		SentinelPassword: resolver.GetString("redis.sentinel_password", "REDIS_SENTINEL_PASSWORD", ""),
+		DB:               resolver.GetInt("redis.db", "REDIS_DB", 0),
+		MaxRetries:       resolver.GetInt("redis.max_retries", "REDIS_MAX_RETRIES", 3),
+		DialTimeout:      resolver.GetInt("redis.dial_timeout", "REDIS_DIAL_TIMEOUT", 5),
+		ReadTimeout:      resolver.GetInt("redis.read_timeout", "REDIS_READ_TIMEOUT", 3),
+		WriteTimeout:     resolver.GetInt("redis.write_timeout", "REDIS_WRITE_TIMEOUT", 3),
+		PoolSize:         resolver.GetInt("redis.pool_size", "REDIS_POOL_SIZE", 10),
 	}
 }
 
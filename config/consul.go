@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConsulSource reads the keys under a prefix of Consul's KV store, with "/" separating sections:
+// with prefix "scaffold/", the key "scaffold/features/checkout" is read as features.checkout.
+// Wait uses Consul's blocking queries, so changes arrive as soon as they are written.
+type ConsulSource struct {
+	Address string
+	Prefix  string
+	Token   string
+	Client  *http.Client
+	// WaitTime bounds each blocking query; Consul caps it at ten minutes
+	WaitTime time.Duration
+
+	mu    sync.Mutex
+	index uint64
+}
+
+// NewConsulSource creates a source reading the keys under prefix from the Consul agent at address
+func NewConsulSource(address, prefix string) *ConsulSource {
+	return &ConsulSource{
+		Address:  strings.TrimRight(address, "/"),
+		Prefix:   prefix,
+		Client:   &http.Client{},
+		WaitTime: 5 * time.Minute,
+	}
+}
+
+type consulPair struct {
+	Key   string
+	Value []byte // base64 in the response, decoded by encoding/json
+}
+
+// Load reads every key under the prefix
+func (c *ConsulSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	pairs, index, err := c.list(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.index = index
+	c.mu.Unlock()
+
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key := strings.TrimPrefix(pair.Key, c.Prefix)
+		if key == "" || strings.HasSuffix(key, "/") {
+			// The prefix itself or a folder marker
+			continue
+		}
+		values[key] = string(pair.Value)
+	}
+	return remoteTree(values, "/")
+}
+
+// Wait blocks until Consul reports a change under the prefix since the last Load
+func (c *ConsulSource) Wait(ctx context.Context) error {
+	c.mu.Lock()
+	last := c.index
+	c.mu.Unlock()
+	if last == 0 {
+		// Without an index to block on, fall back to polling
+		sleepContext(ctx, remoteRetryInterval)
+		return ctx.Err()
+	}
+
+	for {
+		_, index, err := c.list(ctx, last)
+		if err != nil {
+			return err
+		}
+		// A lower index means Consul's state was reset, so the values must be read again
+		if index != last {
+			return nil
+		}
+	}
+}
+
+// list reads the keys under the prefix; a non-zero index makes it a blocking query that returns
+// once the index moves past it or WaitTime passes
+func (c *ConsulSource) list(ctx context.Context, index uint64) ([]consulPair, uint64, error) {
+	query := url.Values{"recurse": {""}}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", fmt.Sprintf("%ds", int(c.WaitTime.Seconds())))
+	}
+	endpoint := fmt.Sprintf("%s/v1/kv/%s?%s", c.Address, c.Prefix, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("consul request: %w", err)
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("consul request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	newIndex, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// No keys under the prefix yet
+		return nil, newIndex, nil
+	default:
+		return nil, 0, fmt.Errorf("consul returned %s for %s", resp.Status, c.Prefix)
+	}
+
+	var pairs []consulPair
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, 0, fmt.Errorf("consul response: %w", err)
+	}
+	return pairs, newIndex, nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadDotEnv reads KEY=VALUE lines from a .env file into the resolver's environment stage. A
+// variable set in the real environment still wins, so the file suits local development while
+// deployments keep setting real variables. The process environment is left unchanged, and
+// ${VAR} references in the config files are expanded again with the new variables.
+//
+// Blank lines, "#" comments and an "export " prefix are allowed; values may be single- or
+// double-quoted, and double-quoted values understand escapes such as \n.
+func (cr *ConfigResolver) LoadDotEnv(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	values, err := parseDotEnv(bufio.NewScanner(file))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	cr.loadMu.Lock()
+	defer cr.loadMu.Unlock()
+
+	cr.mu.Lock()
+	if cr.dotEnv == nil {
+		cr.dotEnv = make(map[string]string, len(values))
+	}
+	for key, value := range values {
+		cr.dotEnv[key] = value
+	}
+	cr.mu.Unlock()
+
+	cr.rebuild()
+	return nil
+}
+
+func parseDotEnv(scanner *bufio.Scanner) (map[string]string, error) {
+	values := make(map[string]string)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			// Unquoted values end at an inline comment
+			if index := strings.Index(value, " #"); index >= 0 {
+				value = strings.TrimSpace(value[:index])
+			}
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// getenv returns an environment variable, falling back to the loaded .env file
+func (cr *ConfigResolver) getenv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.dotEnv[name]
+}
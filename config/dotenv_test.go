@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(`
+# local development
+TEST_DOTENV_HOST=db.local
+export TEST_DOTENV_PORT=6543 # inline comment
+TEST_DOTENV_GREETING="hello\nworld"
+TEST_DOTENV_RAW='a # b'
+TEST_DOTENV_SHADOWED=from-file
+`), 0o600))
+	t.Setenv("TEST_DOTENV_SHADOWED", "from-env")
+
+	resolver := NewConfigResolver(writeConfig(t, "database:\n  host: from-yaml\n"))
+	require.NoError(t, resolver.LoadDotEnv(path))
+
+	assert.Equal(t, "from-yaml", resolver.GetString("database.host", "TEST_DOTENV_HOST", ""))
+	assert.Equal(t, "db.local", resolver.GetString("missing", "TEST_DOTENV_HOST", ""))
+	assert.Equal(t, 6543, resolver.GetInt("missing", "TEST_DOTENV_PORT", 0))
+	assert.Equal(t, "hello\nworld", resolver.GetString("missing", "TEST_DOTENV_GREETING", ""))
+	assert.Equal(t, "a # b", resolver.GetString("missing", "TEST_DOTENV_RAW", ""))
+	assert.Equal(t, "from-env", resolver.GetString("missing", "TEST_DOTENV_SHADOWED", ""))
+
+	_, set := os.LookupEnv("TEST_DOTENV_HOST")
+	assert.False(t, set, "the process environment is left unchanged")
+}
+
+func TestLoadDotEnv_Errors(t *testing.T) {
+	resolver := NewConfigResolver("")
+	assert.Error(t, resolver.LoadDotEnv(filepath.Join(t.TempDir(), "missing.env")))
+
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("VALID=1\nnot a pair\n"), 0o600))
+	assert.ErrorContains(t, resolver.LoadDotEnv(path), "line 2")
+}
@@ -0,0 +1,73 @@
+package config
+
+import "strings"
+
+// interpolate returns a copy of data with ${VAR} and ${VAR:default} references in string values
+// expanded from the environment, so one file can carry per-environment values:
+//
+//	database:
+//	  host: ${DB_HOST:localhost}
+//	  url: postgres://${DB_USER}@${DB_HOST:localhost}/app
+//
+// A variable that is unset or empty takes the default, or expands to "" without one. Variables are
+// looked up by their exact name, without the resolver's prefix, and "$${" writes a literal "${".
+func (cr *ConfigResolver) interpolate(data map[string]interface{}) map[string]interface{} {
+	expanded := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		expanded[key] = cr.interpolateValue(value)
+	}
+	return expanded
+}
+
+func (cr *ConfigResolver) interpolateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return cr.expandString(v)
+	case map[string]interface{}:
+		return cr.interpolate(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = cr.interpolateValue(item)
+		}
+		return items
+	}
+	return value
+}
+
+func (cr *ConfigResolver) expandString(value string) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+
+	var result strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			result.WriteString(value)
+			return result.String()
+		}
+		if start > 0 && value[start-1] == '$' {
+			// "$${" is an escaped "${"
+			result.WriteString(value[:start-1])
+			result.WriteString("${")
+			value = value[start+2:]
+			continue
+		}
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			// Unterminated reference, left as written
+			result.WriteString(value)
+			return result.String()
+		}
+
+		result.WriteString(value[:start])
+		name, defaultValue, hasDefault := strings.Cut(value[start+2:start+end], ":")
+		resolved := cr.getenv(name)
+		if resolved == "" && hasDefault {
+			resolved = defaultValue
+		}
+		result.WriteString(resolved)
+		value = value[start+end+1:]
+	}
+}
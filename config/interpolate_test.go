@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("TEST_INTERP_HOST", "db.internal")
+	t.Setenv("TEST_INTERP_USER", "app")
+	path := writeConfig(t, `
+database:
+  host: ${TEST_INTERP_HOST:localhost}
+  port: ${TEST_INTERP_PORT:5432}
+  url: postgres://${TEST_INTERP_USER}@${TEST_INTERP_HOST}/app
+  missing: "[${TEST_INTERP_UNSET}]"
+  literal: "$${NOT_EXPANDED}"
+  broken: "${TEST_INTERP_HOST"
+  hosts: ["${TEST_INTERP_HOST}", other]
+`)
+	resolver := NewConfigResolver(path)
+
+	assert.Equal(t, "db.internal", resolver.GetString("database.host", "", ""))
+	assert.Equal(t, 5432, resolver.GetInt("database.port", "", 0))
+	assert.Equal(t, "postgres://app@db.internal/app", resolver.GetString("database.url", "", ""))
+	assert.Equal(t, "[]", resolver.GetString("database.missing", "", ""))
+	assert.Equal(t, "${NOT_EXPANDED}", resolver.GetString("database.literal", "", ""))
+	assert.Equal(t, "${TEST_INTERP_HOST", resolver.GetString("database.broken", "", ""))
+	assert.Equal(t, []string{"db.internal", "other"}, resolver.GetStringSlice("database.hosts", "", nil))
+}
+
+func TestInterpolate_UsesDotEnv(t *testing.T) {
+	resolver := NewConfigResolver(writeConfig(t, "cache:\n  host: ${TEST_INTERP_CACHE_HOST:localhost}\n"))
+	assert.Equal(t, "localhost", resolver.GetString("cache.host", "", ""))
+
+	envPath := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envPath, []byte("TEST_INTERP_CACHE_HOST=redis.local\n"), 0o600))
+	require.NoError(t, resolver.LoadDotEnv(envPath))
+	assert.Equal(t, "redis.local", resolver.GetString("cache.host", "", ""))
+}
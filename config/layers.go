@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewLayeredConfigResolver creates a resolver from several config files merged in order, so a base
+// file can be followed by overlays that change only what differs:
+//
+//	resolver := config.NewLayeredConfigResolver("config/base.yaml", config.OverlayPath("config/base.yaml", config.GetHostingEnv()))
+//
+// Sections are merged key by key; any other value in a later file replaces the earlier one, lists
+// included. Files that don't exist are skipped.
+func NewLayeredConfigResolver(configPaths ...string) *ConfigResolver {
+	resolver := &ConfigResolver{
+		configPaths: configPaths,
+		envPrefix:   "", // No prefix by default, can be added later if needed
+	}
+
+	if len(configPaths) > 0 {
+		resolver.loaded = statFiles(configPaths)
+		if data, err := loadConfigFiles(configPaths); err == nil {
+			resolver.rawData = data
+			resolver.configData = resolver.interpolate(data)
+		}
+		// Silently continue if config files don't exist - env vars and defaults will be used
+	}
+
+	return resolver
+}
+
+// OverlayPath returns the per-environment overlay for a base file: "config/config.yaml" with env
+// "production" gives "config/config.production.yaml"
+func OverlayPath(basePath, env string) string {
+	ext := filepath.Ext(basePath)
+	return strings.TrimSuffix(basePath, ext) + "." + env + ext
+}
+
+// loadConfigFiles merges the files that exist, failing on any that can't be read or parsed
+func loadConfigFiles(configPaths []string) (map[string]interface{}, error) {
+	var merged map[string]interface{}
+	found := false
+	for _, path := range configPaths {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		data, err := loadConfigFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		found = true
+		merged = mergeConfig(merged, data)
+	}
+	if !found {
+		return nil, fmt.Errorf("none of the config files exist: %s", strings.Join(configPaths, ", "))
+	}
+	return merged, nil
+}
+
+// mergeConfig returns base with overlay applied, without modifying either
+func mergeConfig(base, overlay map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		return overlay
+	}
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseSection, baseIsMap := merged[key].(map[string]interface{})
+		overlaySection, overlayIsMap := value.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			merged[key] = mergeConfig(baseSection, overlaySection)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
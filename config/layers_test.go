@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayeredConfigResolver_OverlayWins(t *testing.T) {
+	base := writeConfig(t, `
+server:
+  port: 8080
+  hosts: [a, b]
+database:
+  host: localhost
+  pool:
+    max_open: 25
+`)
+	overlay := OverlayPath(base, "production")
+	require.NoError(t, os.WriteFile(overlay, []byte(`
+server:
+  hosts: [c]
+database:
+  pool:
+    max_open: 100
+`), 0o600))
+
+	resolver := NewLayeredConfigResolver(base, overlay, OverlayPath(base, "missing"))
+	assert.Equal(t, 8080, resolver.GetInt("server.port", "", 0))
+	assert.Equal(t, []string{"c"}, resolver.GetStringSlice("server.hosts", "", nil))
+	assert.Equal(t, "localhost", resolver.GetString("database.host", "", ""))
+	assert.Equal(t, 100, resolver.GetInt("database.pool.max_open", "", 0))
+
+	// Reload picks up a change to any layer
+	require.NoError(t, os.WriteFile(overlay, []byte("database:\n  host: db.internal\n"), 0o600))
+	require.NoError(t, resolver.Reload())
+	assert.Equal(t, "db.internal", resolver.GetString("database.host", "", ""))
+	assert.Equal(t, 25, resolver.GetInt("database.pool.max_open", "", 0))
+}
+
+func TestLayeredConfigResolver_NoFiles(t *testing.T) {
+	dir := t.TempDir()
+	resolver := NewLayeredConfigResolver(filepath.Join(dir, "base.yaml"))
+	assert.False(t, resolver.HasConfigFile())
+	assert.Error(t, resolver.Reload())
+}
+
+func TestOverlayPath(t *testing.T) {
+	assert.Equal(t, "config/config.stage.yaml", OverlayPath("config/config.yaml", "stage"))
+	assert.Equal(t, "app.local", OverlayPath("app", "local"))
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// remoteRetryInterval is how long WatchRemote waits after a failed call to the remote source
+const remoteRetryInterval = 5 * time.Second
+
+// RemoteSource is a key/value store such as Consul or etcd holding settings shared by every node.
+// Its values sit above the config files in the resolver's precedence, so a change made there
+// reaches the whole cluster.
+type RemoteSource interface {
+	// Load returns the current values as a config tree, keyed like a config file
+	Load(ctx context.Context) (map[string]interface{}, error)
+	// Wait blocks until the values may have changed since the last Load, or ctx is done
+	Wait(ctx context.Context) error
+}
+
+// SetRemoteSource loads the values of source into the resolver. Call WatchRemote to keep them
+// current. When the first load fails the error is returned and the source isn't used.
+func (cr *ConfigResolver) SetRemoteSource(ctx context.Context, source RemoteSource) error {
+	data, err := source.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("config: load remote values: %w", err)
+	}
+
+	cr.loadMu.Lock()
+	defer cr.loadMu.Unlock()
+	cr.mu.Lock()
+	cr.remote = source
+	cr.remoteData = data
+	cr.mu.Unlock()
+
+	cr.rebuild()
+	return nil
+}
+
+// WatchRemote applies changes from the remote source until ctx is done. OnChange subscribers run
+// for the keys that changed. While the source can't be reached the last values are kept and the
+// call is retried.
+func (cr *ConfigResolver) WatchRemote(ctx context.Context) {
+	cr.mu.RLock()
+	source := cr.remote
+	cr.mu.RUnlock()
+	if source == nil {
+		return
+	}
+
+	for ctx.Err() == nil {
+		if err := source.Wait(ctx); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[Config] remote watch failed, retrying: %v", err)
+				sleepContext(ctx, remoteRetryInterval)
+			}
+			continue
+		}
+		data, err := source.Load(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[Config] remote load failed, keeping previous values: %v", err)
+				sleepContext(ctx, remoteRetryInterval)
+			}
+			continue
+		}
+
+		cr.loadMu.Lock()
+		cr.mu.Lock()
+		cr.remoteData = data
+		cr.mu.Unlock()
+		cr.rebuild()
+		cr.loadMu.Unlock()
+	}
+}
+
+func sleepContext(ctx context.Context, duration time.Duration) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// remoteTree builds a config tree from flat keys such as "features/checkout/enabled", splitting on
+// separator. Each value is decoded as a YAML scalar or list, so "true", "25" and "[a, b]" read the
+// same as in a config file. A key that is both a value and a parent of other keys is an error.
+func remoteTree(values map[string]string, separator string) (map[string]interface{}, error) {
+	tree := make(map[string]interface{})
+	for key, raw := range values {
+		parts := strings.Split(strings.Trim(key, separator), separator)
+		current := tree
+		for _, part := range parts[:len(parts)-1] {
+			next, exists := current[part]
+			if !exists {
+				section := make(map[string]interface{})
+				current[part] = section
+				current = section
+				continue
+			}
+			section, ok := next.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("remote key %s is below a key that holds a value", key)
+			}
+			current = section
+		}
+
+		leaf := parts[len(parts)-1]
+		if _, isSection := current[leaf].(map[string]interface{}); isSection {
+			return nil, fmt.Errorf("remote key %s holds a value and has keys below it", key)
+		}
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+			value = raw
+		}
+		if _, isMap := value.(map[string]interface{}); isMap {
+			value = raw
+		}
+		current[leaf] = value
+	}
+	return tree, nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySource is a RemoteSource whose values change when set is called
+type memorySource struct {
+	mu      sync.Mutex
+	values  map[string]string
+	changed chan struct{}
+}
+
+func newMemorySource(values map[string]string) *memorySource {
+	return &memorySource{values: values, changed: make(chan struct{}, 1)}
+}
+
+func (m *memorySource) set(key, value string) {
+	m.mu.Lock()
+	m.values[key] = value
+	m.mu.Unlock()
+	m.changed <- struct{}{}
+}
+
+func (m *memorySource) Load(context.Context) (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return remoteTree(m.values, "/")
+}
+
+func (m *memorySource) Wait(ctx context.Context) error {
+	select {
+	case <-m.changed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestRemoteSource_OverridesFilesAndWatches(t *testing.T) {
+	path := writeConfig(t, `
+features:
+  checkout: false
+  search: true
+pool:
+  size: 10
+`)
+	resolver := NewConfigResolver(path)
+	source := newMemorySource(map[string]string{"features/checkout": "true"})
+	require.NoError(t, resolver.SetRemoteSource(context.Background(), source))
+
+	assert.True(t, resolver.GetBool("features.checkout", "", false))
+	assert.True(t, resolver.GetBool("features.search", "", false))
+
+	sizes := make(chan int, 1)
+	resolver.OnChange("pool.size", func() { sizes <- resolver.GetInt("pool.size", "", 0) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go resolver.WatchRemote(ctx)
+
+	source.set("pool/size", "40")
+	select {
+	case size := <-sizes:
+		assert.Equal(t, 40, size)
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote change was not applied")
+	}
+
+	// A file reload keeps the remote layer on top
+	require.NoError(t, resolver.Reload())
+	assert.Equal(t, 40, resolver.GetInt("pool.size", "", 0))
+}
+
+func TestRemoteTree(t *testing.T) {
+	tree, err := remoteTree(map[string]string{
+		"features/checkout": "true",
+		"pool/size":         "25",
+		"hosts":             "[a, b]",
+		"note":              "key: value",
+		"empty":             "",
+	}, "/")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"features": map[string]interface{}{"checkout": true},
+		"pool":     map[string]interface{}{"size": 25},
+		"hosts":    []interface{}{"a", "b"},
+		"note":     "key: value",
+		"empty":    "",
+	}, tree)
+
+	_, err = remoteTree(map[string]string{"pool": "1", "pool/size": "2"}, "/")
+	assert.Error(t, err)
+}
+
+func TestConsulSource(t *testing.T) {
+	var mu sync.Mutex
+	index := 7
+	pairs := []map[string]interface{}{
+		{"Key": "app/", "Value": nil},
+		{"Key": "app/rate/limit", "Value": []byte("100")},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/kv/app/", r.URL.Path)
+		assert.Equal(t, "secret-token", r.Header.Get("X-Consul-Token"))
+		if waitIndex := r.URL.Query().Get("index"); waitIndex != "" {
+			assert.Equal(t, "7", waitIndex)
+			mu.Lock()
+			index = 8
+			pairs[1]["Value"] = []byte("250")
+			mu.Unlock()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("X-Consul-Index", strconv.Itoa(index))
+		_ = json.NewEncoder(w).Encode(pairs)
+	}))
+	defer server.Close()
+
+	source := NewConsulSource(server.URL, "app/")
+	source.Token = "secret-token"
+	data, err := source.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"rate": map[string]interface{}{"limit": 100}}, data)
+
+	require.NoError(t, source.Wait(context.Background()))
+	data, err = source.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"rate": map[string]interface{}{"limit": 250}}, data)
+}
+
+func TestConsulSource_MissingPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Consul-Index", "3")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	data, err := NewConsulSource(server.URL, "app/").Load(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
@@ -5,34 +5,40 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // ConfigResolver provides unified configuration resolution with precedence:
-// 1. Config file values
-// 2. Environment variables
-// 3. Default values
+// 1. Remote values from a RemoteSource
+// 2. Config file values, later files in a layered resolver overriding earlier ones
+// 3. Environment variables
+// 4. Variables from a .env file loaded with LoadDotEnv
+// 5. Default values
 type ConfigResolver struct {
-	configData map[string]interface{} // Loaded config file data
-	envPrefix  string                 // Optional prefix for environment variables
+	mu          sync.RWMutex
+	configData  map[string]interface{} // Remote values over interpolated file data, swapped whole on rebuild
+	rawData     map[string]interface{} // Loaded config file data as written
+	remoteData  map[string]interface{} // Values from the remote source
+	remote      RemoteSource
+	loadMu      sync.Mutex // Serializes rebuilding configData
+	configPaths []string
+	loaded      []fileStamp       // the config files' state when configData was read
+	dotEnv      map[string]string // Variables from a .env file, used when the environment lacks them
+	envPrefix   string            // Optional prefix for environment variables
+
+	subscribers []*subscription
+	secrets     secretStore
 }
 
 // NewConfigResolver creates a new configuration resolver
 func NewConfigResolver(configPath string) *ConfigResolver {
-	resolver := &ConfigResolver{
-		envPrefix: "", // No prefix by default, can be added later if needed
+	if configPath == "" {
+		return NewLayeredConfigResolver()
 	}
-
-	// Try to load config file first (optional)
-	if configPath != "" {
-		if data, err := loadConfigFile(configPath); err == nil {
-			resolver.configData = data
-		}
-		// Silently continue if config file doesn't exist - env vars and defaults will be used
-	}
-
-	return resolver
+	return NewLayeredConfigResolver(configPath)
 }
 
 // NewConfigResolverWithPrefix creates a resolver with an environment variable prefix
@@ -45,7 +51,7 @@ func NewConfigResolverWithPrefix(configPath, envPrefix string) *ConfigResolver {
 // GetString resolves a string configuration value with precedence: file → env → default
 func (cr *ConfigResolver) GetString(configKey, envKey, defaultValue string) string {
 	// 1st Priority: Config file
-	if cr.configData != nil {
+	if cr.HasConfigFile() {
 		if value, exists := cr.getNestedValue(configKey); exists {
 			if str, ok := cr.toString(value); ok {
 				return str
@@ -55,7 +61,7 @@ func (cr *ConfigResolver) GetString(configKey, envKey, defaultValue string) stri
 
 	// 2nd Priority: Environment variable (with optional prefix)
 	envVarName := cr.buildEnvVarName(envKey)
-	if envValue := os.Getenv(envVarName); envValue != "" {
+	if envValue := cr.getenv(envVarName); envValue != "" {
 		return envValue
 	}
 
@@ -66,7 +72,7 @@ func (cr *ConfigResolver) GetString(configKey, envKey, defaultValue string) stri
 // GetInt resolves an integer configuration value with precedence: file → env → default
 func (cr *ConfigResolver) GetInt(configKey, envKey string, defaultValue int) int {
 	// 1st Priority: Config file
-	if cr.configData != nil {
+	if cr.HasConfigFile() {
 		if value, exists := cr.getNestedValue(configKey); exists {
 			if intVal, ok := cr.toInt(value); ok {
 				return intVal
@@ -76,7 +82,7 @@ func (cr *ConfigResolver) GetInt(configKey, envKey string, defaultValue int) int
 
 	// 2nd Priority: Environment variable
 	envVarName := cr.buildEnvVarName(envKey)
-	if envValue := os.Getenv(envVarName); envValue != "" {
+	if envValue := cr.getenv(envVarName); envValue != "" {
 		if parsed, err := strconv.Atoi(envValue); err == nil {
 			return parsed
 		}
@@ -89,7 +95,7 @@ func (cr *ConfigResolver) GetInt(configKey, envKey string, defaultValue int) int
 // GetBool resolves a boolean configuration value with precedence: file → env → default
 func (cr *ConfigResolver) GetBool(configKey, envKey string, defaultValue bool) bool {
 	// 1st Priority: Config file
-	if cr.configData != nil {
+	if cr.HasConfigFile() {
 		if value, exists := cr.getNestedValue(configKey); exists {
 			if boolVal, ok := cr.toBool(value); ok {
 				return boolVal
@@ -99,7 +105,7 @@ func (cr *ConfigResolver) GetBool(configKey, envKey string, defaultValue bool) b
 
 	// 2nd Priority: Environment variable
 	envVarName := cr.buildEnvVarName(envKey)
-	if envValue := os.Getenv(envVarName); envValue != "" {
+	if envValue := cr.getenv(envVarName); envValue != "" {
 		// Parse common boolean representations
 		switch strings.ToLower(envValue) {
 		case "true", "1", "yes", "on":
@@ -116,7 +122,7 @@ func (cr *ConfigResolver) GetBool(configKey, envKey string, defaultValue bool) b
 // GetStringSlice resolves a string slice configuration value
 func (cr *ConfigResolver) GetStringSlice(configKey, envKey string, defaultValue []string) []string {
 	// 1st Priority: Config file
-	if cr.configData != nil {
+	if cr.HasConfigFile() {
 		if value, exists := cr.getNestedValue(configKey); exists {
 			if slice, ok := cr.toStringSlice(value); ok {
 				return slice
@@ -126,7 +132,7 @@ func (cr *ConfigResolver) GetStringSlice(configKey, envKey string, defaultValue
 
 	// 2nd Priority: Environment variable (comma-separated)
 	envVarName := cr.buildEnvVarName(envKey)
-	if envValue := os.Getenv(envVarName); envValue != "" {
+	if envValue := cr.getenv(envVarName); envValue != "" {
 		return strings.Split(envValue, ",")
 	}
 
@@ -134,21 +140,99 @@ func (cr *ConfigResolver) GetStringSlice(configKey, envKey string, defaultValue
 	return defaultValue
 }
 
-// HasConfigFile returns true if a config file was successfully loaded
+// GetFloat resolves a float configuration value with precedence: file → env → default
+func (cr *ConfigResolver) GetFloat(configKey, envKey string, defaultValue float64) float64 {
+	// 1st Priority: Config file
+	if cr.HasConfigFile() {
+		if value, exists := cr.getNestedValue(configKey); exists {
+			if floatVal, ok := cr.toFloat(value); ok {
+				return floatVal
+			}
+		}
+	}
+
+	// 2nd Priority: Environment variable
+	envVarName := cr.buildEnvVarName(envKey)
+	if envValue := cr.getenv(envVarName); envValue != "" {
+		if parsed, err := strconv.ParseFloat(envValue, 64); err == nil {
+			return parsed
+		}
+	}
+
+	// 3rd Priority: Default value
+	return defaultValue
+}
+
+// GetDuration resolves a duration written as a Go duration string ("30s", "5m", "1h30m") with
+// precedence: file → env → default. Bare numbers are rejected since their unit would be a guess.
+func (cr *ConfigResolver) GetDuration(configKey, envKey string, defaultValue time.Duration) time.Duration {
+	// 1st Priority: Config file
+	if cr.HasConfigFile() {
+		if value, exists := cr.getNestedValue(configKey); exists {
+			if duration, ok := cr.toDuration(value); ok {
+				return duration
+			}
+		}
+	}
+
+	// 2nd Priority: Environment variable
+	envVarName := cr.buildEnvVarName(envKey)
+	if envValue := cr.getenv(envVarName); envValue != "" {
+		if parsed, err := time.ParseDuration(envValue); err == nil {
+			return parsed
+		}
+	}
+
+	// 3rd Priority: Default value
+	return defaultValue
+}
+
+// GetTime resolves an RFC 3339 timestamp ("2024-06-01T09:00:00Z") with precedence: file → env → default
+func (cr *ConfigResolver) GetTime(configKey, envKey string, defaultValue time.Time) time.Time {
+	// 1st Priority: Config file
+	if cr.HasConfigFile() {
+		if value, exists := cr.getNestedValue(configKey); exists {
+			if parsed, ok := cr.toTime(value); ok {
+				return parsed
+			}
+		}
+	}
+
+	// 2nd Priority: Environment variable
+	envVarName := cr.buildEnvVarName(envKey)
+	if envValue := cr.getenv(envVarName); envValue != "" {
+		if parsed, err := time.Parse(time.RFC3339, envValue); err == nil {
+			return parsed
+		}
+	}
+
+	// 3rd Priority: Default value
+	return defaultValue
+}
+
+// HasConfigFile returns true if a config file or remote values were successfully loaded
 func (cr *ConfigResolver) HasConfigFile() bool {
-	return cr.configData != nil
+	return cr.data() != nil
 }
 
 // GetLoadedConfigKeys returns all keys present in the loaded config file (for debugging)
 func (cr *ConfigResolver) GetLoadedConfigKeys() []string {
-	if cr.configData == nil {
+	data := cr.data()
+	if data == nil {
 		return nil
 	}
-	return cr.extractKeys(cr.configData, "")
+	return cr.extractKeys(data, "")
 }
 
 // Private helper methods
 
+// data returns the current config map; a reload replaces it rather than modifying it
+func (cr *ConfigResolver) data() map[string]interface{} {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.configData
+}
+
 func (cr *ConfigResolver) buildEnvVarName(envKey string) string {
 	if cr.envPrefix == "" {
 		return envKey
@@ -157,18 +241,19 @@ func (cr *ConfigResolver) buildEnvVarName(envKey string) string {
 }
 
 func (cr *ConfigResolver) getNestedValue(key string) (interface{}, bool) {
-	if cr.configData == nil {
+	data := cr.data()
+	if data == nil {
 		return nil, false
 	}
 
 	parts := strings.Split(key, ".")
-	current := cr.configData
+	current := data
 
 	for i, part := range parts {
 		if i == len(parts)-1 {
 			// Last part - get the value
 			if value, exists := current[part]; exists {
-				return value, true
+				return cr.resolveSecret(value)
 			}
 			return nil, false
 		}
@@ -263,6 +348,50 @@ func (cr *ConfigResolver) toStringSlice(value interface{}) ([]string, bool) {
 	return nil, false
 }
 
+func (cr *ConfigResolver) toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			return parsed, true
+		}
+	}
+	return 0, false
+}
+
+func (cr *ConfigResolver) toDuration(value interface{}) (time.Duration, bool) {
+	switch v := value.(type) {
+	case string:
+		if parsed, err := time.ParseDuration(v); err == nil {
+			return parsed, true
+		}
+	case int:
+		// Zero needs no unit
+		if v == 0 {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func (cr *ConfigResolver) toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		// YAML decodes unquoted timestamps itself
+		return v, true
+	case string:
+		if parsed, err := time.Parse(time.RFC3339, v); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
 func (cr *ConfigResolver) extractKeys(data map[string]interface{}, prefix string) []string {
 	var keys []string
 	for key, value := range data {
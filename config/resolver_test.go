@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDuration(t *testing.T) {
+	path := writeConfig(t, `
+cache:
+  ttl: 90s
+  bare: 30
+  off: 0
+`)
+	resolver := NewConfigResolver(path)
+	assert.Equal(t, 90*time.Second, resolver.GetDuration("cache.ttl", "", time.Minute))
+	assert.Equal(t, time.Duration(0), resolver.GetDuration("cache.off", "", time.Minute))
+
+	// A bare number has no unit, so it's skipped for the environment and then the default
+	assert.Equal(t, time.Minute, resolver.GetDuration("cache.bare", "", time.Minute))
+	t.Setenv("TEST_CACHE_BARE", "2m")
+	assert.Equal(t, 2*time.Minute, resolver.GetDuration("cache.bare", "TEST_CACHE_BARE", time.Minute))
+
+	t.Setenv("TEST_CACHE_BAD", "soon")
+	assert.Equal(t, time.Minute, resolver.GetDuration("cache.missing", "TEST_CACHE_BAD", time.Minute))
+}
+
+func TestGetFloat(t *testing.T) {
+	resolver := NewConfigResolver(writeConfig(t, "sampling:\n  rate: 0.25\n  whole: 2\n"))
+	assert.Equal(t, 0.25, resolver.GetFloat("sampling.rate", "", 1))
+	assert.Equal(t, 2.0, resolver.GetFloat("sampling.whole", "", 1))
+
+	t.Setenv("TEST_SAMPLING_RATE", "0.5")
+	assert.Equal(t, 0.5, resolver.GetFloat("sampling.missing", "TEST_SAMPLING_RATE", 1))
+}
+
+func TestGetTime(t *testing.T) {
+	resolver := NewConfigResolver(writeConfig(t, `
+launch:
+  unquoted: 2024-06-01T09:00:00Z
+  quoted: "2024-06-01T09:00:00+02:00"
+  invalid: "next tuesday"
+`))
+	expected := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	assert.True(t, expected.Equal(resolver.GetTime("launch.unquoted", "", time.Time{})))
+	assert.True(t, expected.Add(-2*time.Hour).Equal(resolver.GetTime("launch.quoted", "", time.Time{})))
+
+	t.Setenv("TEST_LAUNCH_AT", "2024-07-01T00:00:00Z")
+	assert.True(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC).Equal(resolver.GetTime("launch.invalid", "TEST_LAUNCH_AT", time.Time{})))
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSecretTTL is how long a resolved secret is reused when its provider gives no lease
+const DefaultSecretTTL = 5 * time.Minute
+
+// secretFetchTimeout bounds a provider call made from a getter
+const secretFetchTimeout = 10 * time.Second
+
+// Secret is a value read from a SecretProvider. TTL is how long it may be cached before it is read
+// again to pick up a rotation; zero uses the resolver's secret TTL.
+type Secret struct {
+	Value string
+	TTL   time.Duration
+}
+
+// SecretProvider reads secrets from a store such as Vault or AWS Secrets Manager. ref is the part of
+// a config value after the provider's scheme: "secret/db#password" for "vault:secret/db#password".
+type SecretProvider interface {
+	GetSecret(ctx context.Context, ref string) (Secret, error)
+}
+
+// SecretProviderFunc adapts a function, such as a wrapper around a cloud SDK client, to SecretProvider
+type SecretProviderFunc func(ctx context.Context, ref string) (Secret, error)
+
+func (f SecretProviderFunc) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	return f(ctx, ref)
+}
+
+type secretStore struct {
+	mu        sync.Mutex
+	providers map[string]SecretProvider
+	cache     map[string]*cachedSecret
+	ttl       time.Duration
+}
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// RegisterSecretProvider makes config values of the form "<scheme>:<ref>" resolve through provider,
+// so the file holds a reference instead of the credential:
+//
+//	database:
+//	  password: vault:secret/db#password
+//
+// Values are cached until their TTL passes and then read again, so a rotated secret is picked up.
+func (cr *ConfigResolver) RegisterSecretProvider(scheme string, provider SecretProvider) {
+	cr.secrets.mu.Lock()
+	defer cr.secrets.mu.Unlock()
+	if cr.secrets.providers == nil {
+		cr.secrets.providers = make(map[string]SecretProvider)
+	}
+	cr.secrets.providers[scheme] = provider
+}
+
+// SetSecretTTL sets how long secrets without a provider lease are cached
+func (cr *ConfigResolver) SetSecretTTL(ttl time.Duration) {
+	cr.secrets.mu.Lock()
+	defer cr.secrets.mu.Unlock()
+	cr.secrets.ttl = ttl
+}
+
+// RefreshSecrets reads every cached secret again, ignoring TTLs, for example after a rotation is
+// announced. OnChange subscribers run for the config keys whose secret changed. A failed read keeps
+// the cached value; the errors are joined.
+func (cr *ConfigResolver) RefreshSecrets(ctx context.Context) error {
+	cr.secrets.mu.Lock()
+	refs := make([]string, 0, len(cr.secrets.cache))
+	for ref := range cr.secrets.cache {
+		refs = append(refs, ref)
+	}
+	cr.secrets.mu.Unlock()
+
+	var errs []error
+	for _, ref := range refs {
+		if _, err := cr.fetchSecret(ctx, ref); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveSecret returns the secret a config value refers to. Values that aren't references to a
+// registered scheme are returned as they are; ok is false when a reference can't be resolved.
+func (cr *ConfigResolver) resolveSecret(value interface{}) (interface{}, bool) {
+	str, isString := value.(string)
+	if !isString {
+		return value, true
+	}
+	scheme, _, found := strings.Cut(str, ":")
+	if !found {
+		return value, true
+	}
+	cr.secrets.mu.Lock()
+	_, registered := cr.secrets.providers[scheme]
+	cached, isCached := cr.secrets.cache[str]
+	cr.secrets.mu.Unlock()
+	if !registered {
+		return value, true
+	}
+	if isCached && time.Now().Before(cached.expiresAt) {
+		return cached.value, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretFetchTimeout)
+	defer cancel()
+	secret, err := cr.fetchSecret(ctx, str)
+	if err == nil {
+		return secret, true
+	}
+	if isCached {
+		// Better a stale credential than none while the store is unreachable
+		log.Printf("[Config] %v, using cached value", err)
+		return cached.value, true
+	}
+	log.Printf("[Config] %v", err)
+	return nil, false
+}
+
+// fetchSecret reads a reference from its provider, caches it, and notifies the keys using it when
+// the value has rotated
+func (cr *ConfigResolver) fetchSecret(ctx context.Context, reference string) (string, error) {
+	scheme, ref, _ := strings.Cut(reference, ":")
+	cr.secrets.mu.Lock()
+	provider := cr.secrets.providers[scheme]
+	ttl := cr.secrets.ttl
+	cr.secrets.mu.Unlock()
+	if provider == nil {
+		return "", fmt.Errorf("secret %s: no provider registered for %q", reference, scheme)
+	}
+
+	secret, err := provider.GetSecret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", reference, err)
+	}
+	if secret.TTL > 0 {
+		ttl = secret.TTL
+	} else if ttl <= 0 {
+		ttl = DefaultSecretTTL
+	}
+
+	cr.secrets.mu.Lock()
+	if cr.secrets.cache == nil {
+		cr.secrets.cache = make(map[string]*cachedSecret)
+	}
+	previous, existed := cr.secrets.cache[reference]
+	cr.secrets.cache[reference] = &cachedSecret{value: secret.Value, expiresAt: time.Now().Add(ttl)}
+	cr.secrets.mu.Unlock()
+
+	if existed && previous.value != secret.Value {
+		cr.notify(cr.keysWithValue(reference))
+	}
+	return secret.Value, nil
+}
+
+// keysWithValue returns the config keys whose raw file value is value
+func (cr *ConfigResolver) keysWithValue(value string) []string {
+	var keys []string
+	for key, raw := range flatten(cr.data(), "", map[string]interface{}{}) {
+		if raw == value {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
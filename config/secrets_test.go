@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotatingProvider serves the current value of each ref and counts reads
+type rotatingProvider struct {
+	values map[string]string
+	reads  int
+	err    error
+	ttl    time.Duration
+}
+
+func (p *rotatingProvider) GetSecret(_ context.Context, ref string) (Secret, error) {
+	p.reads++
+	if p.err != nil {
+		return Secret{}, p.err
+	}
+	return Secret{Value: p.values[ref], TTL: p.ttl}, nil
+}
+
+func TestSecrets_ResolvedAndCached(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  password: vault:secret/db#password
+  host: "db:5432"
+`)
+	resolver := NewConfigResolver(path)
+	provider := &rotatingProvider{values: map[string]string{"secret/db#password": "s3cret"}}
+	resolver.RegisterSecretProvider("vault", provider)
+
+	assert.Equal(t, "s3cret", resolver.GetString("database.password", "DB_PASSWORD", ""))
+	assert.Equal(t, "s3cret", resolver.GetString("database.password", "DB_PASSWORD", ""))
+	assert.Equal(t, 1, provider.reads)
+
+	// Values whose prefix isn't a registered scheme are left alone
+	assert.Equal(t, "db:5432", resolver.GetString("database.host", "", ""))
+}
+
+func TestSecrets_RefreshedAfterTTL(t *testing.T) {
+	path := writeConfig(t, "database:\n  password: vault:secret/db#password\n")
+	resolver := NewConfigResolver(path)
+	provider := &rotatingProvider{values: map[string]string{"secret/db#password": "old"}, ttl: time.Millisecond}
+	resolver.RegisterSecretProvider("vault", provider)
+
+	rotated := 0
+	resolver.OnChange("database", func() { rotated++ })
+
+	assert.Equal(t, "old", resolver.GetString("database.password", "", ""))
+	provider.values["secret/db#password"] = "new"
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "new", resolver.GetString("database.password", "", ""))
+	assert.Equal(t, 1, rotated)
+
+	// An unreachable store keeps serving the cached value
+	provider.err = errors.New("connection refused")
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "new", resolver.GetString("database.password", "", ""))
+}
+
+func TestSecrets_UnresolvedFallsBackToEnv(t *testing.T) {
+	path := writeConfig(t, "database:\n  password: vault:secret/db#password\n")
+	resolver := NewConfigResolver(path)
+	resolver.RegisterSecretProvider("vault", &rotatingProvider{err: errors.New("permission denied")})
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+
+	assert.Equal(t, "from-env", resolver.GetString("database.password", "TEST_DB_PASSWORD", ""))
+}
+
+func TestRefreshSecrets(t *testing.T) {
+	path := writeConfig(t, "database:\n  password: vault:secret/db#password\n")
+	resolver := NewConfigResolver(path)
+	provider := &rotatingProvider{values: map[string]string{"secret/db#password": "old"}, ttl: time.Hour}
+	resolver.RegisterSecretProvider("vault", provider)
+
+	var changed string
+	resolver.OnChange("database.password", func() {
+		changed = resolver.GetString("database.password", "", "")
+	})
+	resolver.GetString("database.password", "", "")
+
+	provider.values["secret/db#password"] = "rotated"
+	require.NoError(t, resolver.RefreshSecrets(context.Background()))
+	assert.Equal(t, "rotated", changed)
+}
+
+func TestVaultProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "/v1/secret/data/app/db", r.URL.Path)
+		_, _ = w.Write([]byte(`{"lease_duration": 60, "data": {"data": {"password": "s3cret", "port": 5432}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewVaultProvider(server.URL+"/", "token")
+	secret, err := provider.GetSecret(context.Background(), "secret/app/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "s3cret", TTL: time.Minute}, secret)
+
+	_, err = provider.GetSecret(context.Background(), "secret/app/db#missing")
+	assert.ErrorContains(t, err, `no field "missing"`)
+	_, err = provider.GetSecret(context.Background(), "secret/app/db#port")
+	assert.ErrorContains(t, err, "not a string")
+	_, err = provider.GetSecret(context.Background(), "secret/app/db")
+	assert.ErrorContains(t, err, "<mount>/<path>#<field>")
+
+	provider.Token = "wrong"
+	_, err = provider.GetSecret(context.Background(), "secret/app/db#password")
+	assert.ErrorContains(t, err, "403")
+}
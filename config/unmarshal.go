@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unmarshal fills the struct out points to from the config section at key ("" for the whole file).
+// Each field resolves on its own with the getters' precedence: the file value named by its yaml tag,
+// then the environment variable named by its env tag, then its default tag. Fields with none of the
+// three keep their current value, and nested structs are read from the sub-section of their yaml key.
+//
+//	type DatabaseConfig struct {
+//	    Host string `yaml:"host" env:"DB_HOST" default:"localhost"`
+//	    Port int    `yaml:"port" env:"DB_PORT" default:"5432"`
+//	}
+//	err := resolver.Unmarshal("database", &cfg)
+//
+// Values that can't be converted to the field's type are reported, joined, in the returned error and
+// skipped in favour of the next source, so every valid field is still filled.
+func (cr *ConfigResolver) Unmarshal(key string, out interface{}) error {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Unmarshal needs a non-nil pointer to a struct, got %T", out)
+	}
+	var errs []error
+	cr.unmarshalStruct(key, value.Elem(), &errs)
+	return errors.Join(errs...)
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+func (cr *ConfigResolver) unmarshalStruct(prefix string, value reflect.Value, errs *[]error) {
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := yamlName(field)
+		if name == "-" {
+			continue
+		}
+		configKey := name
+		if prefix != "" {
+			configKey = prefix + "." + name
+		}
+
+		fieldValue := value.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			cr.unmarshalStruct(configKey, fieldValue, errs)
+			continue
+		}
+
+		if fileValue, exists := cr.getNestedValue(configKey); exists {
+			err := cr.setFromFile(fieldValue, fileValue)
+			if err == nil {
+				continue
+			}
+			*errs = append(*errs, fmt.Errorf("config %s: %w", configKey, err))
+		}
+		if envKey := field.Tag.Get("env"); envKey != "" {
+			envVarName := cr.buildEnvVarName(envKey)
+			if envValue := cr.getenv(envVarName); envValue != "" {
+				err := setFromString(fieldValue, envValue)
+				if err == nil {
+					continue
+				}
+				*errs = append(*errs, fmt.Errorf("config env %s: %w", envVarName, err))
+			}
+		}
+		if defaultValue, ok := field.Tag.Lookup("default"); ok {
+			if err := setFromString(fieldValue, defaultValue); err != nil {
+				*errs = append(*errs, fmt.Errorf("config %s default: %w", configKey, err))
+			}
+		}
+	}
+}
+
+// yamlName returns the key a field is read from, following yaml.v3's lower-cased field name default
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// setFromFile converts a value decoded from YAML with the getters' conversions
+func (cr *ConfigResolver) setFromFile(field reflect.Value, value interface{}) error {
+	switch field.Type() {
+	case durationType:
+		if duration, ok := cr.toDuration(value); ok {
+			field.SetInt(int64(duration))
+			return nil
+		}
+		return fmt.Errorf("can't use %v as a duration", value)
+	case timeType:
+		if parsed, ok := cr.toTime(value); ok {
+			field.Set(reflect.ValueOf(parsed))
+			return nil
+		}
+		return fmt.Errorf("can't use %v as an RFC 3339 time", value)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		if str, ok := cr.toString(value); ok {
+			field.SetString(str)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if intVal, ok := cr.toInt(value); ok {
+			field.SetInt(int64(intVal))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if intVal, ok := cr.toInt(value); ok && intVal >= 0 {
+			field.SetUint(uint64(intVal))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if floatVal, ok := cr.toFloat(value); ok {
+			field.SetFloat(floatVal)
+			return nil
+		}
+	case reflect.Bool:
+		if boolVal, ok := cr.toBool(value); ok {
+			field.SetBool(boolVal)
+			return nil
+		}
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.String {
+			if slice, ok := cr.toStringSlice(value); ok {
+				field.Set(reflect.ValueOf(slice).Convert(field.Type()))
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return fmt.Errorf("can't use %v as %s", value, field.Type())
+}
+
+// setFromString parses an environment or default value into field
+func setFromString(field reflect.Value, value string) error {
+	switch field.Type() {
+	case durationType:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("can't use %q as a duration", value)
+		}
+		field.SetInt(int64(parsed))
+		return nil
+	case timeType:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("can't use %q as an RFC 3339 time", value)
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("can't use %q as %s", value, field.Type())
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("can't use %q as %s", value, field.Type())
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("can't use %q as %s", value, field.Type())
+		}
+		field.SetFloat(parsed)
+	case reflect.Bool:
+		switch strings.ToLower(value) {
+		case "true", "1", "yes", "on":
+			field.SetBool(true)
+		case "false", "0", "no", "off":
+			field.SetBool(false)
+		default:
+			return fmt.Errorf("can't use %q as %s", value, field.Type())
+		}
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		var items []string
+		if value != "" {
+			items = strings.Split(value, ",")
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
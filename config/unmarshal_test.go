@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+type poolConfig struct {
+	MaxOpen int `yaml:"max_open" env:"TEST_POOL_MAX_OPEN" default:"25"`
+}
+
+type databaseConfig struct {
+	Host    string   `yaml:"host" env:"TEST_DB_HOST" default:"localhost"`
+	Port    int      `yaml:"port" env:"TEST_DB_PORT" default:"5432"`
+	TLS     bool     `yaml:"tls" env:"TEST_DB_TLS"`
+	Ratio   float64  `yaml:"ratio" default:"0.5"`
+	Schemas []string `yaml:"schemas" env:"TEST_DB_SCHEMAS"`
+	Pool    poolConfig
+	Ignored string `yaml:"-" default:"never"`
+	Preset  string `yaml:"preset"`
+}
+
+func TestUnmarshal_PrecedencePerField(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  host: db.internal
+  schemas: [app, audit]
+  pool:
+    max_open: 50
+`)
+	t.Setenv("TEST_DB_HOST", "ignored.because.file.wins")
+	t.Setenv("TEST_DB_PORT", "6432")
+	t.Setenv("TEST_DB_TLS", "yes")
+
+	cfg := databaseConfig{Preset: "kept"}
+	require.NoError(t, NewConfigResolver(path).Unmarshal("database", &cfg))
+
+	assert.Equal(t, databaseConfig{
+		Host:    "db.internal",
+		Port:    6432,
+		TLS:     true,
+		Ratio:   0.5,
+		Schemas: []string{"app", "audit"},
+		Pool:    poolConfig{MaxOpen: 50},
+		Preset:  "kept",
+	}, cfg)
+}
+
+func TestUnmarshal_ReportsInvalidValues(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  port: not-a-number
+`)
+	t.Setenv("TEST_POOL_MAX_OPEN", "lots")
+
+	var cfg databaseConfig
+	err := NewConfigResolver(path).Unmarshal("database", &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.port")
+	assert.Contains(t, err.Error(), "TEST_POOL_MAX_OPEN")
+
+	// Invalid sources fall through to the next one
+	assert.Equal(t, 5432, cfg.Port)
+	assert.Equal(t, 25, cfg.Pool.MaxOpen)
+	assert.Equal(t, "localhost", cfg.Host)
+}
+
+func TestUnmarshal_RequiresStructPointer(t *testing.T) {
+	var cfg databaseConfig
+	assert.Error(t, NewConfigResolver("").Unmarshal("database", cfg))
+	assert.Error(t, NewConfigResolver("").Unmarshal("database", (*databaseConfig)(nil)))
+}
+
+type scheduleConfig struct {
+	Interval time.Duration `yaml:"interval" default:"1m"`
+	Timeout  time.Duration `yaml:"timeout" env:"TEST_SCHEDULE_TIMEOUT" default:"5s"`
+	StartAt  time.Time     `yaml:"start_at"`
+}
+
+func TestUnmarshal_DurationsAndTimes(t *testing.T) {
+	path := writeConfig(t, `
+schedule:
+  interval: 15m
+  start_at: 2024-06-01T09:00:00Z
+`)
+	t.Setenv("TEST_SCHEDULE_TIMEOUT", "250ms")
+
+	var cfg scheduleConfig
+	require.NoError(t, NewConfigResolver(path).Unmarshal("schedule", &cfg))
+	assert.Equal(t, 15*time.Minute, cfg.Interval)
+	assert.Equal(t, 250*time.Millisecond, cfg.Timeout)
+	assert.True(t, time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC).Equal(cfg.StartAt))
+
+	err := NewConfigResolver(writeConfig(t, "schedule:\n  interval: 15\n")).Unmarshal("schedule", &cfg)
+	assert.ErrorContains(t, err, "can't use 15 as a duration")
+	assert.Equal(t, time.Minute, cfg.Interval)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault KV version 2 engine over its HTTP API.
+// A ref is "<mount>/<path>#<field>", so "secret/db#password" reads the password field of the
+// secret at path db in the engine mounted at secret.
+type VaultProvider struct {
+	Address string
+	Token   string
+	Client  *http.Client
+}
+
+// NewVaultProvider creates a provider for the Vault server at address
+func NewVaultProvider(address, token string) *VaultProvider {
+	return &VaultProvider{
+		Address: strings.TrimRight(address, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: secretFetchTimeout},
+	}
+}
+
+// NewVaultProviderFromEnv creates a provider from VAULT_ADDR and VAULT_TOKEN, the variables the Vault
+// CLI uses
+func NewVaultProviderFromEnv() *VaultProvider {
+	return NewVaultProvider(os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN"))
+}
+
+type vaultKVResponse struct {
+	LeaseDuration int `json:"lease_duration"`
+	Data          struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// GetSecret reads one field of a KV secret. The lease duration Vault returns, if any, becomes the TTL.
+func (p *VaultProvider) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	path, field, found := strings.Cut(ref, "#")
+	mount, secretPath, hasPath := strings.Cut(path, "/")
+	if !found || field == "" || !hasPath || secretPath == "" {
+		return Secret{}, fmt.Errorf("vault ref %q must look like <mount>/<path>#<field>", ref)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", p.Address, url.PathEscape(mount), secretPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Secret{}, fmt.Errorf("vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Secret{}, fmt.Errorf("vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("vault returned %s for %s", resp.Status, path)
+	}
+
+	var body vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Secret{}, fmt.Errorf("vault response: %w", err)
+	}
+	value, exists := body.Data.Data[field]
+	if !exists {
+		return Secret{}, fmt.Errorf("vault secret %s has no field %q", path, field)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return Secret{}, fmt.Errorf("vault secret %s field %q is not a string", path, field)
+	}
+	return Secret{Value: str, TTL: time.Duration(body.LeaseDuration) * time.Second}, nil
+}
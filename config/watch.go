@@ -0,0 +1,271 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+type subscription struct {
+	key string
+	fn  func()
+}
+
+// OnChange calls fn after a reload changes key or any key below it ("log" covers "log.level"); an
+// empty key is notified of every change. fn runs on the reloading goroutine and should read the new
+// values with the getters. The returned func removes the subscription.
+func (cr *ConfigResolver) OnChange(key string, fn func()) (unsubscribe func()) {
+	sub := &subscription{key: key, fn: fn}
+	cr.mu.Lock()
+	cr.subscribers = append(cr.subscribers, sub)
+	cr.mu.Unlock()
+
+	return func() {
+		cr.mu.Lock()
+		defer cr.mu.Unlock()
+		for i, existing := range cr.subscribers {
+			if existing == sub {
+				cr.subscribers = append(cr.subscribers[:i:i], cr.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Reload reads the config files again and swaps the new values in at once, so a getter sees either
+// the old file or the new one, never a mix. Subscribers of the keys that changed are then notified.
+// When the file can't be read or parsed the current values are kept and the error returned.
+func (cr *ConfigResolver) Reload() error {
+	if len(cr.configPaths) == 0 {
+		return errors.New("config: resolver has no config file to reload")
+	}
+	cr.loadMu.Lock()
+	defer cr.loadMu.Unlock()
+
+	// Stat before reading, so a write racing the read leaves a newer stamp for Watch to catch
+	stamps := statFiles(cr.configPaths)
+	data, err := loadConfigFiles(cr.configPaths)
+	if err != nil {
+		return err
+	}
+
+	cr.mu.Lock()
+	cr.rawData = data
+	cr.loaded = stamps
+	cr.mu.Unlock()
+
+	cr.rebuild()
+	return nil
+}
+
+// rebuild swaps in configData built from the current file and remote layers and notifies the
+// subscribers of keys that changed. Callers hold loadMu.
+func (cr *ConfigResolver) rebuild() {
+	cr.mu.RLock()
+	raw, remote := cr.rawData, cr.remoteData
+	cr.mu.RUnlock()
+
+	var data map[string]interface{}
+	if raw != nil {
+		data = cr.interpolate(raw)
+	}
+	data = mergeConfig(data, remote)
+
+	cr.mu.Lock()
+	previous := cr.configData
+	cr.configData = data
+	cr.mu.Unlock()
+
+	cr.notify(changedKeys(previous, data))
+}
+
+// notify runs the subscribers covering any of the changed keys
+func (cr *ConfigResolver) notify(changed []string) {
+	if len(changed) == 0 {
+		return
+	}
+	cr.mu.RLock()
+	subscribers := append([]*subscription(nil), cr.subscribers...)
+	cr.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		if subscribedTo(sub.key, changed) {
+			sub.fn()
+		}
+	}
+}
+
+// Watch reloads the config files when one changes until ctx is done. It watches the files' directories
+// with fsnotify, so a file replaced by renaming over it, as editors and Kubernetes ConfigMaps do, is
+// caught too. A change is only applied once no event has arrived for debounce, so a file written in
+// several steps causes a single reload. Reload errors are logged. When the directories can't be
+// watched, the files are polled every debounce instead.
+func (cr *ConfigResolver) Watch(ctx context.Context, debounce time.Duration) {
+	if len(cr.configPaths) == 0 {
+		return
+	}
+	watcher, err := watchDirs(cr.configPaths)
+	if err != nil {
+		log.Printf("[Config] can't watch config files, polling instead: %v", err)
+		cr.poll(ctx, debounce)
+		return
+	}
+	defer watcher.Close()
+
+	// Armed at start to catch a change made before the watch began
+	settled := time.NewTimer(debounce)
+	defer settled.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			settled.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("[Config] file watch error: %v", err)
+		case <-settled.C:
+			// Events cover the whole directory, so only reload when a config file itself changed
+			cr.mu.RLock()
+			loaded := cr.loaded
+			cr.mu.RUnlock()
+			if slices.Equal(statFiles(cr.configPaths), loaded) {
+				continue
+			}
+			if err := cr.Reload(); err != nil {
+				log.Printf("[Config] reload failed, keeping previous values: %v", err)
+			}
+		}
+	}
+}
+
+// watchDirs watches the directories holding paths rather than the files, as a watch on a file is
+// lost when it is replaced
+func watchDirs(paths []string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	return watcher, nil
+}
+
+// poll checks the files every interval and reloads once they have stayed the same for a whole interval
+func (cr *ConfigResolver) poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	cr.mu.RLock()
+	last := cr.loaded
+	cr.mu.RUnlock()
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := statFiles(cr.configPaths)
+		if !slices.Equal(current, last) {
+			last = current
+			pending = true
+			continue
+		}
+		if !pending {
+			continue
+		}
+		pending = false
+		if err := cr.Reload(); err != nil {
+			log.Printf("[Config] reload failed, keeping previous values: %v", err)
+		}
+	}
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFiles(paths []string) []fileStamp {
+	stamps := make([]fileStamp, len(paths))
+	for i, path := range paths {
+		stamps[i] = statFile(path)
+	}
+	return stamps
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// changedKeys returns the leaf keys whose value differs between two config maps, including keys
+// only present in one of them
+func changedKeys(previous, current map[string]interface{}) []string {
+	before := flatten(previous, "", map[string]interface{}{})
+	after := flatten(current, "", map[string]interface{}{})
+
+	var changed []string
+	for key, value := range after {
+		if old, exists := before[key]; !exists || !reflect.DeepEqual(old, value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, exists := after[key]; !exists {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+func flatten(data map[string]interface{}, prefix string, into map[string]interface{}) map[string]interface{} {
+	for key, value := range data {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+		if subMap, ok := value.(map[string]interface{}); ok {
+			flatten(subMap, fullKey, into)
+		} else {
+			into[fullKey] = value
+		}
+	}
+	return into
+}
+
+// subscribedTo reports whether a subscription to key covers one of the changed keys: the key itself,
+// a key below it, or a section above it that was replaced by a single value
+func subscribedTo(key string, changed []string) bool {
+	if key == "" {
+		return true
+	}
+	for _, changedKey := range changed {
+		if changedKey == key || strings.HasPrefix(changedKey, key+".") || strings.HasPrefix(key, changedKey+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload_SwapsValuesAndNotifiesChangedKeys(t *testing.T) {
+	path := writeConfig(t, `
+log:
+  level: info
+server:
+  port: 8080
+`)
+	resolver := NewConfigResolver(path)
+
+	var notified []string
+	resolver.OnChange("log.level", func() {
+		notified = append(notified, "log.level="+resolver.GetString("log.level", "", ""))
+	})
+	resolver.OnChange("log", func() { notified = append(notified, "log") })
+	resolver.OnChange("server.port", func() { notified = append(notified, "server.port") })
+	unsubscribe := resolver.OnChange("", func() { notified = append(notified, "all") })
+	unsubscribe()
+
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: debug\nserver:\n  port: 8080\n"), 0o600))
+	require.NoError(t, resolver.Reload())
+
+	assert.Equal(t, []string{"log.level=debug", "log"}, notified)
+	assert.Equal(t, 8080, resolver.GetInt("server.port", "", 0))
+}
+
+func TestReload_KeepsValuesOnParseError(t *testing.T) {
+	path := writeConfig(t, "log:\n  level: info\n")
+	resolver := NewConfigResolver(path)
+	called := false
+	resolver.OnChange("", func() { called = true })
+
+	require.NoError(t, os.WriteFile(path, []byte("log: [unclosed\n"), 0o600))
+	assert.Error(t, resolver.Reload())
+	assert.False(t, called)
+	assert.Equal(t, "info", resolver.GetString("log.level", "", ""))
+}
+
+func TestReload_WithoutConfigFile(t *testing.T) {
+	assert.Error(t, NewConfigResolver("").Reload())
+}
+
+func TestChangedKeys(t *testing.T) {
+	previous := map[string]interface{}{
+		"log":  map[string]interface{}{"level": "info", "format": "json"},
+		"tags": []interface{}{"a"},
+	}
+	current := map[string]interface{}{
+		"log":  "off",
+		"tags": []interface{}{"a"},
+		"new":  1,
+	}
+	assert.ElementsMatch(t, []string{"log", "log.level", "log.format", "new"}, changedKeys(previous, current))
+	assert.True(t, subscribedTo("log.level", []string{"log"}))
+	assert.False(t, subscribedTo("logger", []string{"log"}))
+}
+
+func TestWatch_ReloadsOnceFileSettles(t *testing.T) {
+	path := writeConfig(t, "rate:\n  limit: 10\n")
+	resolver := NewConfigResolver(path)
+	changes := make(chan int, 4)
+	resolver.OnChange("rate.limit", func() { changes <- resolver.GetInt("rate.limit", "", 0) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go resolver.Watch(ctx, 20*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("rate:\n  limit: 20\n"), 0o600))
+	// Move the modification time so the change is seen even on coarse-grained filesystems
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	select {
+	case limit := <-changes:
+		assert.Equal(t, 20, limit)
+	case <-time.After(2 * time.Second):
+		t.Fatal("watcher did not reload the changed file")
+	}
+	assert.Empty(t, changes)
+}
+
+func TestWatch_ReloadsFileReplacedByRename(t *testing.T) {
+	path := writeConfig(t, "rate:\n  limit: 10\n")
+	resolver := NewConfigResolver(path)
+	changes := make(chan int, 4)
+	resolver.OnChange("rate.limit", func() { changes <- resolver.GetInt("rate.limit", "", 0) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go resolver.Watch(ctx, 20*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	replacement := filepath.Join(filepath.Dir(path), "config.yaml.tmp")
+	require.NoError(t, os.WriteFile(replacement, []byte("rate:\n  limit: 30\n"), 0o600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(replacement, later, later))
+	require.NoError(t, os.Rename(replacement, path))
+
+	select {
+	case limit := <-changes:
+		assert.Equal(t, 30, limit)
+	case <-time.After(2 * time.Second):
+		t.Fatal("watcher did not reload the replaced file")
+	}
+}
@@ -0,0 +1,109 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yadunandan004/scaffold/logger"
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// AccessLog is the access-log entry of one request
+type AccessLog struct {
+	Method string
+	Path   string
+	// Route is the route template, such as /api/users/:id
+	Route    string
+	Status   int
+	Duration time.Duration
+	// Bytes is the response body size
+	Bytes    int
+	ClientIP string
+	// Slow is set when Duration reached AccessLogOptions.SlowThreshold
+	Slow bool
+}
+
+// AccessLogOptions configures UseAccessLog
+type AccessLogOptions struct {
+	// SkipPaths are request paths that aren't logged; defaults to the health probe paths
+	SkipPaths []string
+	// SlowThreshold marks requests taking at least this long as slow; defaults to 1s, negative disables it
+	SlowThreshold time.Duration
+	// Sink receives each entry; defaults to the logger package, at error level for 5xx and warn level
+	// for 4xx and slow requests
+	Sink func(ctx request.Context, entry AccessLog)
+}
+
+type accessLogger struct {
+	skip          map[string]bool
+	slowThreshold time.Duration
+	sink          func(ctx request.Context, entry AccessLog)
+}
+
+// UseAccessLog logs one entry for every request served by a registry route, tagged with the request's
+// XID, trace and user. The entry covers the whole request, so throttled, unauthenticated and
+// forbidden requests are logged too. It applies to routes added before and after the call.
+func (r *Registry) UseAccessLog(opts AccessLogOptions) {
+	if opts.SkipPaths == nil {
+		opts.SkipPaths = []string{LivenessPath, ReadinessPath}
+	}
+	if opts.SlowThreshold == 0 {
+		opts.SlowThreshold = time.Second
+	}
+	if opts.Sink == nil {
+		opts.Sink = logAccess
+	}
+	skip := make(map[string]bool, len(opts.SkipPaths))
+	for _, path := range opts.SkipPaths {
+		skip[path] = true
+	}
+	r.accessLog = &accessLogger{skip: skip, slowThreshold: opts.SlowThreshold, sink: opts.Sink}
+}
+
+// record sends the entry for a request to route that began at start
+func (l *accessLogger) record(ctx request.Context, ginCtx *gin.Context, route string, start time.Time) {
+	if l.skip[ginCtx.Request.URL.Path] {
+		return
+	}
+	entry := AccessLog{
+		Method:   ginCtx.Request.Method,
+		Path:     ginCtx.Request.URL.Path,
+		Route:    route,
+		Status:   ginCtx.Writer.Status(),
+		Duration: time.Since(start),
+		Bytes:    max(ginCtx.Writer.Size(), 0),
+		ClientIP: ginCtx.ClientIP(),
+	}
+	entry.Slow = l.slowThreshold > 0 && entry.Duration >= l.slowThreshold
+	l.sink(ctx, entry)
+}
+
+func logAccess(ctx request.Context, entry AccessLog) {
+	level := logwriter.InfoLevel
+	switch {
+	case entry.Status >= http.StatusInternalServerError:
+		level = logwriter.ErrorLevel
+	case entry.Status >= http.StatusBadRequest || entry.Slow:
+		level = logwriter.WarnLevel
+	}
+	fields := map[string]interface{}{
+		"method":      entry.Method,
+		"path":        entry.Path,
+		"route":       entry.Route,
+		"status":      entry.Status,
+		"duration_ms": entry.Duration.Milliseconds(),
+		"bytes":       entry.Bytes,
+		"client_ip":   entry.ClientIP,
+	}
+	// The message names the route rather than the path, so entries for one route sample together
+	msg := fmt.Sprintf("%s %s %d", entry.Method, entry.Route, entry.Status)
+	if entry.Slow {
+		fields["slow"] = true
+		msg += " SLOW"
+	}
+	logger.LogWithFields(ctx, level, msg, fields)
+}
@@ -0,0 +1,91 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/health"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestRegistry_UseAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+
+	type logged struct {
+		xid   uuid.UUID
+		entry AccessLog
+	}
+	var entries []logged
+	registry := NewRegistry(engine, nil)
+	registry.UseAccessLog(AccessLogOptions{
+		SlowThreshold: 20 * time.Millisecond,
+		Sink: func(ctx request.Context, entry AccessLog) {
+			entries = append(entries, logged{xid: ctx.XID(), entry: entry})
+		},
+	})
+	registry.AddHealthRoutes(health.NewChecker(health.CheckerConfig{}))
+	registry.AddGroup(RouteGroup{
+		Name:     "users",
+		BasePath: "/api/users",
+		RouteList: []Route{
+			{
+				Method: "GET",
+				Path:   "/:id",
+				Handler: func(ctx request.Context) {
+					ctx.JSON(http.StatusOK, gin.H{"id": ctx.GetGinContext().Param("id")})
+				},
+				ShouldSkipAuth: true,
+				ShouldSkipTxn:  true,
+			},
+			{
+				Method: "POST",
+				Path:   "/import",
+				Handler: func(ctx request.Context) {
+					time.Sleep(30 * time.Millisecond)
+					ctx.JSON(http.StatusAccepted, gin.H{})
+				},
+				ShouldSkipAuth: true,
+				ShouldSkipTxn:  true,
+			},
+			{
+				Method:        "DELETE",
+				Path:          "/:id",
+				Handler:       func(ctx request.Context) { ctx.JSON(http.StatusNoContent, nil) },
+				ShouldSkipTxn: true,
+			},
+		},
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/api/users/42", nil),
+		httptest.NewRequest("POST", "/api/users/import", nil),
+		httptest.NewRequest("DELETE", "/api/users/42", nil),
+		httptest.NewRequest("GET", ReadinessPath, nil),
+	} {
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Len(t, entries, 3, "health probes are skipped")
+
+	get := entries[0].entry
+	assert.NotEqual(t, uuid.Nil, entries[0].xid)
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "/api/users/42", get.Path)
+	assert.Equal(t, "/api/users/:id", get.Route)
+	assert.Equal(t, http.StatusOK, get.Status)
+	assert.Equal(t, len(`{"id":"42"}`), get.Bytes)
+	assert.False(t, get.Slow)
+
+	assert.True(t, entries[1].entry.Slow)
+	assert.GreaterOrEqual(t, entries[1].entry.Duration, 30*time.Millisecond)
+
+	assert.Equal(t, http.StatusUnauthorized, entries[2].entry.Status, "rejected requests are logged too")
+}
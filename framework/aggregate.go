@@ -0,0 +1,134 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// AggregateExpr returns a select expression applying function to column, e.g. AggregateExpr("SUM", "amount", "total")
+func AggregateExpr(function, column, alias string) string {
+	return fmt.Sprintf("%s(%s) AS %s", function, column, alias)
+}
+
+// CountExpr counts the rows of each group
+func CountExpr(alias string) string {
+	return AggregateExpr("COUNT", "*", alias)
+}
+
+func SumExpr(column, alias string) string {
+	return AggregateExpr("SUM", column, alias)
+}
+
+func AvgExpr(column, alias string) string {
+	return AggregateExpr("AVG", column, alias)
+}
+
+func MinExpr(column, alias string) string {
+	return AggregateExpr("MIN", column, alias)
+}
+
+func MaxExpr(column, alias string) string {
+	return AggregateExpr("MAX", column, alias)
+}
+
+// WindowExpr builds a window function select expression, e.g.
+// Over("RANK()").PartitionBy("region").OrderBy("SUM(amount) DESC").As("region_rank")
+type WindowExpr struct {
+	function    string
+	partitionBy []string
+	orderBy     []string
+}
+
+// Over starts a window expression for function, such as "ROW_NUMBER()" or "SUM(amount)"
+func Over(function string) *WindowExpr {
+	return &WindowExpr{function: function}
+}
+
+func (w *WindowExpr) PartitionBy(columns ...string) *WindowExpr {
+	w.partitionBy = append(w.partitionBy, columns...)
+	return w
+}
+
+// OrderBy orders rows within the window; columns may carry ASC or DESC
+func (w *WindowExpr) OrderBy(columns ...string) *WindowExpr {
+	w.orderBy = append(w.orderBy, columns...)
+	return w
+}
+
+func (w *WindowExpr) String() string {
+	var window []string
+	if len(w.partitionBy) > 0 {
+		window = append(window, "PARTITION BY "+strings.Join(w.partitionBy, ", "))
+	}
+	if len(w.orderBy) > 0 {
+		window = append(window, "ORDER BY "+strings.Join(w.orderBy, ", "))
+	}
+	return fmt.Sprintf("%s OVER (%s)", w.function, strings.Join(window, " "))
+}
+
+// As returns the expression named alias, for SearchRequest.AddColumns
+func (w *WindowExpr) As(alias string) string {
+	return w.String() + " AS " + alias
+}
+
+// Aggregate runs req's Columns against T's table, with its filters, GroupBy, Having, Sort and paging, and
+// scans each row into an R by column name as orm.RawScanner does. T's tenant scope applies. It runs in
+// the request transaction, or on the primary without one.
+func Aggregate[T tableModel, R any](ctx Context, req *SearchRequest) ([]R, error) {
+	if !req.HasColumns() {
+		return nil, fmt.Errorf("aggregate needs select columns")
+	}
+	offset, err := req.Offset()
+	if err != nil {
+		return nil, err
+	}
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	table := qualifiedTable[T](ctx)
+	query, args := aggregateSQL(table, req, scope, offset)
+
+	results := []R{}
+	if q := ctx.GetPgTxn(); q != nil {
+		err = q.QueryRows(query, &results, args...)
+	} else {
+		db := postgres.GetDB()
+		if db == nil || db.DB == nil {
+			return nil, fmt.Errorf("no database connection available")
+		}
+		rows, queryErr := db.QueryContext(ctx.GetCtx(), query, args...)
+		if queryErr != nil {
+			return nil, fmt.Errorf("aggregate %s: %w", table, queryErr)
+		}
+		defer rows.Close()
+		err = (&orm.RawScanner{}).ScanRaw(rows, &results)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aggregate %s: %w", table, err)
+	}
+	return results, nil
+}
+
+// aggregateSQL builds the statement Aggregate runs, with req's filters restricted to scope's tenant
+func aggregateSQL(table string, req *SearchRequest, scope *tenantScope, offset int) (string, []interface{}) {
+	argCount := 1
+	whereClause, args := scope.whereClause(req.Filters, &argCount)
+	if whereClause != "" {
+		whereClause = " " + whereClause
+	}
+	havingClause, havingArgs := BuildHavingClause(req.Having, &argCount)
+	args = append(args, havingArgs...)
+
+	paginationClause := ""
+	if req.Take > 0 {
+		paginationClause = fmt.Sprintf(" LIMIT %d OFFSET %d", req.Take, offset)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s%s%s%s%s%s", strings.Join(req.GetColumns(), ", "), table, whereClause,
+		BuildGroupByClause(req.GroupBy), havingClause, BuildOrderByClause(req.Sort), paginationClause)
+	return query, args
+}
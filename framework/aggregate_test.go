@@ -0,0 +1,59 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+type regionStats struct {
+	Region string  `json:"region"`
+	Orders int64   `json:"orders"`
+	Total  float64 `json:"total"`
+}
+
+func TestAggregateSQL(t *testing.T) {
+	req := NewSearchRequest().
+		AddColumns("region", CountExpr("orders"), SumExpr("amount", "total")).
+		AddEqual("status", "paid").
+		AddGroupBy("region").
+		AddHaving(*GreaterThanFilter("SUM(amount)", 100)).
+		SortDesc("total").
+		WithTake(10).WithPage(2)
+	offset, err := req.Offset()
+	require.NoError(t, err)
+
+	query, args := aggregateSQL("orders", req, nil, offset)
+	assert.Equal(t, "SELECT region, COUNT(*) AS orders, SUM(amount) AS total FROM orders WHERE status = $1"+
+		" GROUP BY region HAVING SUM(amount) > $2 ORDER BY total DESC LIMIT 10 OFFSET 10", query)
+	assert.Equal(t, []interface{}{"paid", 100}, args)
+}
+
+func TestWindowExpr(t *testing.T) {
+	assert.Equal(t, "RANK() OVER (PARTITION BY region ORDER BY SUM(amount) DESC) AS region_rank",
+		Over("RANK()").PartitionBy("region").OrderBy("SUM(amount) DESC").As("region_rank"))
+	assert.Equal(t, "ROW_NUMBER() OVER ()", Over("ROW_NUMBER()").String())
+	assert.Equal(t, "AVG(amount) AS average", AvgExpr("amount", "average"))
+}
+
+func TestSearchRequest_ToQueryGroupsRows(t *testing.T) {
+	req := NewSearchRequest().AddEqual("status", "paid").AddGroupBy("region").AddHaving(*GreaterThanFilter("COUNT(*)", 5))
+	query, args := req.ToQuery("SELECT region, COUNT(*) FROM orders")
+	assert.Equal(t, "SELECT region, COUNT(*) FROM orders WHERE status = $1 GROUP BY region HAVING COUNT(*) > $2", query)
+	assert.Equal(t, []interface{}{"paid", 5}, args)
+}
+
+func TestAggregate_RequiresColumns(t *testing.T) {
+	_, err := Aggregate[TestSample, regionStats](request.NewTestContext(), NewSearchRequest().AddGroupBy("status"))
+	assert.ErrorContains(t, err, "needs select columns")
+}
+
+func TestSearch_RejectsGroupedRequests(t *testing.T) {
+	repo := NewPostgresReadOnlyRepository[TestSample, uuid.UUID]()
+	_, err := repo.Search(request.NewTestContext(), NewSearchRequest().AddGroupBy("status"))
+	assert.ErrorContains(t, err, "use Aggregate")
+}
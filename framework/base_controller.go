@@ -1,6 +1,7 @@
 package framework
 
 import (
+	"github.com/yadunandan004/scaffold/app_error"
 	"github.com/yadunandan004/scaffold/request"
 	"net/http"
 
@@ -14,6 +15,9 @@ type IDParser[ID IDType] interface {
 type BaseReadController[T BaseReadModel[ID], ID IDType] struct {
 	Service  ReadOnlyService[T, ID]
 	IDParser func(string) (ID, error)
+	// SearchFields are the columns HandleList and HandleSearch accept as filters, sort keys and
+	// HandleSearch columns, defaulting to the model's SearchableColumns
+	SearchFields []string
 }
 
 func NewBaseReadController[T BaseReadModel[ID], ID IDType](service ReadOnlyService[T, ID], idParser func(string) (ID, error)) *BaseReadController[T, ID] {
@@ -24,12 +28,12 @@ func (ctrl *BaseReadController[T, ID]) HandleGetByID(ctx request.Context, paramN
 	idStr := ctx.GetRequestContext().Param(paramName)
 	id, err := ctrl.IDParser(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		RespondError(ctx, app_error.InvalidArgument("Invalid ID"))
 		return
 	}
 	entity, err := ctrl.Service.GetByID(ctx, id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, entity)
@@ -38,17 +42,49 @@ func (ctrl *BaseReadController[T, ID]) HandleGetByID(ctx request.Context, paramN
 func (ctrl *BaseReadController[T, ID]) HandleSearch(ctx request.Context) {
 	var searchReq SearchRequest
 	if err := ctx.GetRequestContext().ShouldBindJSON(&searchReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondError(ctx, app_error.InvalidArgument(err.Error()))
+		return
+	}
+	if err := ValidateSearchRequest(&searchReq, ctrl.searchFields()); err != nil {
+		RespondError(ctx, err)
 		return
 	}
 	results, err := ctrl.Service.Search(ctx, &searchReq)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
+// HandleList searches with filters, sort and paging taken from the query string; see ParseSearchQuery
+func (ctrl *BaseReadController[T, ID]) HandleList(ctx request.Context) {
+	ginCtx := ctx.GetGinContext()
+	if ginCtx == nil {
+		RespondError(ctx, app_error.InvalidArgument("Query parameters are only available over HTTP"))
+		return
+	}
+	searchReq, err := ParseSearchQuery(ginCtx.Request.URL.Query(), ctrl.searchFields())
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+	results, err := ctrl.Service.Search(ctx, searchReq)
+	if err != nil {
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, results)
 }
 
+// searchFields returns the columns HandleList and HandleSearch accept
+func (ctrl *BaseReadController[T, ID]) searchFields() []string {
+	if len(ctrl.SearchFields) == 0 {
+		return SearchColumns[T]()
+	}
+	return ctrl.SearchFields
+}
+
 type BaseInsertController[T BaseInsertModel[ID], ID IDType] struct {
 	BaseReadController[T, ID]
 	Service InsertService[T, ID]
@@ -62,30 +98,30 @@ func NewBaseInsertController[T BaseInsertModel[ID], ID IDType](service InsertSer
 }
 
 func (ctrl *BaseInsertController[T, ID]) HandleCreate(ctx request.Context) {
-	var entity T
-	if err := ctx.GetRequestContext().ShouldBindJSON(&entity); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	entity, err := BindAndValidate[T](ctx)
+	if err != nil {
+		RespondError(ctx, err)
 		return
 	}
 
-	createdEntity, err := ctrl.Service.Create(ctx, &entity)
+	createdEntity, err := ctrl.Service.Create(ctx, entity)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, createdEntity)
 }
 
 func (ctrl *BaseInsertController[T, ID]) HandleCreateMultiple(ctx request.Context) {
-	var entities []*T
-	if err := ctx.GetRequestContext().ShouldBindJSON(&entities); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	entities, err := BindAndValidate[[]*T](ctx)
+	if err != nil {
+		RespondError(ctx, err)
 		return
 	}
 
-	createdEntities, err := ctrl.Service.CreateMultiple(ctx, entities)
+	createdEntities, err := ctrl.Service.CreateMultiple(ctx, *entities)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, createdEntities)
@@ -110,19 +146,19 @@ func (ctrl *BaseController[T, ID]) HandleUpdate(ctx request.Context) {
 	idStr := ctx.GetRequestContext().Param("id")
 	id, err := ctrl.IDParser(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		RespondError(ctx, app_error.InvalidArgument("Invalid ID"))
 		return
 	}
 
-	var entity T
-	if err := ctx.GetRequestContext().ShouldBindJSON(&entity); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	entity, err := BindAndValidate[T](ctx)
+	if err != nil {
+		RespondError(ctx, err)
 		return
 	}
 
-	updatedEntity, err := ctrl.Service.Update(ctx, id, &entity)
+	updatedEntity, err := ctrl.Service.Update(ctx, id, entity)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, updatedEntity)
@@ -132,28 +168,28 @@ func (ctrl *BaseController[T, ID]) HandleDelete(ctx request.Context, paramName s
 	idStr := ctx.GetRequestContext().Param(paramName)
 	id, err := ctrl.IDParser(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		RespondError(ctx, app_error.InvalidArgument("Invalid ID"))
 		return
 	}
 
 	err = ctrl.Service.Delete(ctx, id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
 }
 
 func (ctrl *BaseController[T, ID]) HandleUpdateMultiple(ctx request.Context) {
-	var entities []*T
-	if err := ctx.GetRequestContext().ShouldBindJSON(&entities); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	entities, err := BindAndValidate[[]*T](ctx)
+	if err != nil {
+		RespondError(ctx, err)
 		return
 	}
 
-	updatedEntities, err := ctrl.Service.UpdateMultiple(ctx, entities)
+	updatedEntities, err := ctrl.Service.UpdateMultiple(ctx, *entities)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, updatedEntities)
@@ -166,7 +202,7 @@ type DeleteMultipleRequest struct {
 func (ctrl *BaseController[T, ID]) HandleDeleteMultiple(ctx request.Context) {
 	var req DeleteMultipleRequest
 	if err := ctx.GetRequestContext().ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		RespondError(ctx, app_error.InvalidArgument(err.Error()))
 		return
 	}
 
@@ -174,7 +210,7 @@ func (ctrl *BaseController[T, ID]) HandleDeleteMultiple(ctx request.Context) {
 	for i, idStr := range req.IDs {
 		id, err := ctrl.IDParser(idStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID: " + idStr})
+			RespondError(ctx, app_error.InvalidArgument("Invalid ID: "+idStr))
 			return
 		}
 		ids[i] = id
@@ -182,7 +218,7 @@ func (ctrl *BaseController[T, ID]) HandleDeleteMultiple(ctx request.Context) {
 
 	err := ctrl.Service.DeleteMultiple(ctx, ids)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Deleted successfully", "count": len(ids)})
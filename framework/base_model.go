@@ -11,6 +11,11 @@ type IDType interface {
 	~int | ~int64 | ~string | uuid.UUID
 }
 
+// tableModel is any model the ORM maps to a table or view
+type tableModel interface {
+	TableName() string
+}
+
 type BaseReadModel[ID IDType] interface {
 	TableName() string
 	SaveInCache() bool
@@ -13,7 +13,7 @@ type Context = injContext.Context
 
 type ReadOnlyRepository[T BaseReadModel[ID], ID IDType] interface {
 	GetByID(ctx Context, id ID) (*T, error)
-	Search(ctx Context, req *SearchRequest) ([]*T, error)
+	Search(ctx Context, req *SearchRequest) (*PagedResult[T], error)
 }
 
 type InsertRepository[T BaseInsertModel[ID], ID IDType] interface {
@@ -26,83 +26,198 @@ type UpdateRepository[T BaseUpdateModel[ID], ID IDType] interface {
 	InsertRepository[T, ID]
 	Update(ctx Context, entity *T) error
 	UpdateMultiple(ctx Context, entities []*T) error
+	UpdateWhere(ctx Context, req *SearchRequest, changes map[string]any) ([]*T, error)
 }
 
 type DeleteRepository[T BaseDeleteModel[ID], ID IDType] interface {
 	UpdateRepository[T, ID]
 	Delete(ctx Context, entity *T) error
 	DeleteMultiple(ctx Context, entities []*T) error
+	DeleteWhere(ctx Context, req *SearchRequest) ([]*T, error)
 }
 
 type BaseRepository[T BaseCompleteModel[ID], ID IDType] interface {
 	DeleteRepository[T, ID]
 	Upsert(ctx Context, entity *T) error
+	UpsertMultiple(ctx Context, entities []*T) error
 }
 
-type PostgresReadOnlyRepository[T BaseReadModel[ID], ID IDType] struct{}
+type PostgresReadOnlyRepository[T BaseReadModel[ID], ID IDType] struct {
+	config readOnlyRepositoryConfig
+}
 
-func NewPostgresReadOnlyRepository[T BaseReadModel[ID], ID IDType]() *PostgresReadOnlyRepository[T, ID] {
-	return &PostgresReadOnlyRepository[T, ID]{}
+func NewPostgresReadOnlyRepository[T BaseReadModel[ID], ID IDType](opts ...ReadOnlyRepositoryOption) *PostgresReadOnlyRepository[T, ID] {
+	r := &PostgresReadOnlyRepository[T, ID]{}
+	for _, opt := range opts {
+		opt(&r.config)
+	}
+	return r
 }
 
 func getExecutor[T any](ctx Context) interface{} {
 	tx := injContext.GetTransaction[T](ctx)
 	if tx != nil {
-		return tx
+		return tx.WithSchema(requestSchema[T](ctx))
 	}
 
 	db := postgres.GetDB()
 	if db == nil || db.DB == nil {
 		return nil
 	}
-	return orm.NewDB[T](db.DB)
+	return orm.NewDB[T](db.DB).WithSchema(requestSchema[T](ctx))
+}
+
+// readExecutor returns the executor reads use: the replica with WithReadReplica, otherwise getExecutor
+func (r *PostgresReadOnlyRepository[T, ID]) readExecutor(ctx Context) (interface{}, error) {
+	if !r.config.replica {
+		if executor := getExecutor[T](ctx); executor != nil {
+			return executor, nil
+		}
+		return nil, fmt.Errorf("no database connection available")
+	}
+	db := postgres.GetReadOnlyDB()
+	if db == nil || db.DB == nil {
+		return nil, fmt.Errorf("no read replica connection available")
+	}
+	return orm.NewDB[T](db.DB).WithSchema(requestSchema[T](ctx)), nil
+}
+
+// readScope resolves the tenant scope for a read. The schema strategy sets search_path on the request
+// transaction, which a replica read doesn't use, so those models can't be read from a replica.
+func (r *PostgresReadOnlyRepository[T, ID]) readScope(ctx Context) (*tenantScope, error) {
+	var model T
+	if scoped, ok := any(model).(TenantScoped); ok && r.config.replica && scoped.TenantStrategy() == TenantSchemaStrategy {
+		return nil, fmt.Errorf("%T uses the tenant schema strategy and can't be read from a replica", model)
+	}
+	return scopeTenant[T](ctx)
 }
 
 func (r *PostgresReadOnlyRepository[T, ID]) GetByID(ctx Context, id ID) (*T, error) {
 	var entity T
-	executor := getExecutor[T](ctx)
-	if executor == nil {
-		return nil, fmt.Errorf("no database connection available")
+	scope, err := r.readScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	executor, err := r.readExecutor(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	if tx, ok := executor.(*orm.Transaction[T]); ok {
 		query := ctx.GetPgTxn()
-		err := tx.FindByPK(query, &entity, id)
-		return &entity, err
+		err = tx.FindByPK(query, &entity, id)
+	} else {
+		db, ok := executor.(*orm.DB[T])
+		if !ok || db == nil {
+			return nil, fmt.Errorf("invalid database executor")
+		}
+		err = db.FindByPK(ctx.GetCtx(), &entity, id)
 	}
-
-	db, ok := executor.(*orm.DB[T])
-	if !ok || db == nil {
-		return nil, fmt.Errorf("invalid database executor")
+	if err != nil {
+		return nil, err
+	}
+	if !scope.owns(entity) {
+		// Another tenant's row is reported as missing rather than revealing it exists
+		return nil, orm.NewNotFoundError(entity.TableName(), id)
 	}
-	err := db.FindByPK(ctx.GetCtx(), &entity, id)
-	return &entity, err
+	return &entity, nil
 }
 
-func (r *PostgresReadOnlyRepository[T, ID]) Search(ctx Context, req *SearchRequest) ([]*T, error) {
-	var entity T
-	tableName := entity.TableName()
+// Search returns one page of matches. It reads one row past Take to know whether NextCursor is needed,
+// and runs a COUNT(*) with the same filters when req.IncludeTotal is set.
+func (r *PostgresReadOnlyRepository[T, ID]) Search(ctx Context, req *SearchRequest) (*PagedResult[T], error) {
+	tableName := qualifiedTable[T](ctx)
+	if req.IsAggregate() {
+		return nil, fmt.Errorf("search on %s groups rows; use Aggregate", tableName)
+	}
 
 	selectClause := "*"
 	if req.HasColumns() {
 		selectClause = strings.Join(req.GetColumns(), ", ")
 	}
 
-	whereClause, args := BuildWhereClause(req.Filters)
+	offset, err := req.Offset()
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := r.readScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	argCount := 1
+	whereClause, args := scope.whereClause(req.Filters, &argCount)
 	orderByClause := BuildOrderByClause(req.Sort)
-	paginationClause := BuildPaginationClause(req.Page, req.Take)
+	paginationClause := ""
+	if req.Take > 0 {
+		paginationClause = fmt.Sprintf(" LIMIT %d OFFSET %d", req.Take+1, offset)
+	}
 	query := fmt.Sprintf("SELECT %s FROM %s %s%s%s", selectClause, tableName, whereClause, orderByClause, paginationClause)
+	if r.config.replica && !IsReadOnlySQL(query) {
+		return nil, fmt.Errorf("search on %s is not a read-only statement", tableName)
+	}
 
-	executor := getExecutor[T](ctx)
-	if executor == nil {
-		return nil, fmt.Errorf("no database connection available")
+	executor, err := r.readExecutor(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	var items []*T
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", tableName, whereClause)
 	if tx, ok := executor.(*orm.Transaction[T]); ok {
 		q := ctx.GetPgTxn()
-		return tx.FindByQuery(q, query, args...)
+		if items, err = tx.FindByQuery(q, query, args...); err != nil {
+			return nil, err
+		}
+		if req.IncludeTotal {
+			if total, err = q.Count(countQuery, args...); err != nil {
+				return nil, fmt.Errorf("count %s: %w", tableName, err)
+			}
+		}
+	} else {
+		db, ok := executor.(*orm.DB[T])
+		if !ok || db == nil {
+			return nil, fmt.Errorf("invalid database executor")
+		}
+		if items, err = db.FindByQuery(ctx.GetCtx(), query, args...); err != nil {
+			return nil, err
+		}
+		if req.IncludeTotal {
+			if total, err = db.Count(ctx.GetCtx(), countQuery, args...); err != nil {
+				return nil, fmt.Errorf("count %s: %w", tableName, err)
+			}
+		}
+	}
+
+	result := NewPagedResult(items, req)
+	if req.Take > 0 {
+		result.Page = offset/req.Take + 1
+		if len(items) > req.Take {
+			result.Items = items[:req.Take]
+			result.NextCursor = EncodeCursor(offset + req.Take)
+		}
+	}
+	if req.IncludeTotal {
+		result.WithTotal(int64(total))
 	}
+	return result, nil
+}
 
+// FindByQuery runs a custom read for repositories embedding this one. It rejects statements that could
+// write and, like GetByID and Search, reads from the replica with WithReadReplica.
+func (r *PostgresReadOnlyRepository[T, ID]) FindByQuery(ctx Context, query string, args ...interface{}) ([]*T, error) {
+	if !IsReadOnlySQL(query) {
+		return nil, fmt.Errorf("FindByQuery only runs read-only statements")
+	}
+	executor, err := r.readExecutor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tx, ok := executor.(*orm.Transaction[T]); ok {
+		return tx.FindByQuery(ctx.GetPgTxn(), query, args...)
+	}
 	db, ok := executor.(*orm.DB[T])
 	if !ok || db == nil {
 		return nil, fmt.Errorf("invalid database executor")
@@ -119,6 +234,13 @@ func NewPostgresInsertRepository[T BaseInsertModel[ID], ID IDType]() *PostgresIn
 }
 
 func (r *PostgresInsertRepository[T, ID]) Create(ctx Context, entity *T) error {
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return err
+	}
+	if err := stampTenant(scope, entity); err != nil {
+		return err
+	}
 	if err := (*entity).PreInsert(ctx); err != nil {
 		return err
 	}
@@ -128,7 +250,6 @@ func (r *PostgresInsertRepository[T, ID]) Create(ctx Context, entity *T) error {
 		return fmt.Errorf("no database connection available")
 	}
 
-	var err error
 	if tx, ok := executor.(*orm.Transaction[T]); ok {
 		query := ctx.GetPgTxn()
 		err = tx.Create(query, entity)
@@ -152,6 +273,13 @@ func (r *PostgresInsertRepository[T, ID]) CreateMultiple(ctx Context, entities [
 		return nil
 	}
 
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return err
+	}
+	if err := stampTenant(scope, entities...); err != nil {
+		return err
+	}
 	for _, entity := range entities {
 		if err := (*entity).PreInsert(ctx); err != nil {
 			return fmt.Errorf("pre-insert failed: %w", err)
@@ -163,7 +291,6 @@ func (r *PostgresInsertRepository[T, ID]) CreateMultiple(ctx Context, entities [
 		return fmt.Errorf("no database connection available")
 	}
 
-	var err error
 	if tx, ok := executor.(*orm.Transaction[T]); ok {
 		query := ctx.GetPgTxn()
 		err = tx.CreateMultiple(query, entities)
@@ -192,11 +319,27 @@ type PostgresUpdateRepository[T BaseUpdateModel[ID], ID IDType] struct {
 	PostgresInsertRepository[T, ID]
 }
 
+// checkTenant stamps tenant-scoped entities with the request tenant and refuses writes
+// addressed to rows owned by another tenant, returning the scope the write runs under
+func (r *PostgresInsertRepository[T, ID]) checkTenant(ctx Context, entities ...*T) (*tenantScope, error) {
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stampTenant(scope, entities...); err != nil {
+		return nil, err
+	}
+	return scope, ensureTenantOwns[T, ID](ctx, scope, entities...)
+}
+
 func NewPostgresUpdateRepository[T BaseUpdateModel[ID], ID IDType]() *PostgresUpdateRepository[T, ID] {
 	return &PostgresUpdateRepository[T, ID]{}
 }
 
 func (r *PostgresUpdateRepository[T, ID]) Update(ctx Context, entity *T) error {
+	if _, err := r.checkTenant(ctx, entity); err != nil {
+		return err
+	}
 	if err := (*entity).PreUpdate(ctx); err != nil {
 		return err
 	}
@@ -230,6 +373,9 @@ func (r *PostgresUpdateRepository[T, ID]) UpdateMultiple(ctx Context, entities [
 		return nil
 	}
 
+	if _, err := r.checkTenant(ctx, entities...); err != nil {
+		return err
+	}
 	for _, entity := range entities {
 		if err := (*entity).PreUpdate(ctx); err != nil {
 			return err
@@ -266,6 +412,24 @@ func (r *PostgresUpdateRepository[T, ID]) UpdateMultiple(ctx Context, entities [
 	return nil
 }
 
+// UpdateWhere sets changes, keyed by column, on every row matching req's filters in one UPDATE and returns
+// the updated rows. Sort and paging are ignored, at least one filter is required, and model hooks don't run.
+func (r *PostgresUpdateRepository[T, ID]) UpdateWhere(ctx Context, req *SearchRequest, changes map[string]any) ([]*T, error) {
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, whereArgs, err := whereClauseFor(scope, req)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := buildUpdateWhereSQL[T](qualifiedTable[T](ctx), whereClause, whereArgs, changes)
+	if err != nil {
+		return nil, err
+	}
+	return queryWhere[T](ctx, query, args...)
+}
+
 type PostgresDeleteRepository[T BaseDeleteModel[ID], ID IDType] struct {
 	PostgresUpdateRepository[T, ID]
 }
@@ -275,6 +439,9 @@ func NewPostgresDeleteRepository[T BaseDeleteModel[ID], ID IDType]() *PostgresDe
 }
 
 func (r *PostgresDeleteRepository[T, ID]) Delete(ctx Context, entity *T) error {
+	if _, err := r.checkTenant(ctx, entity); err != nil {
+		return err
+	}
 	if err := (*entity).PreDelete(ctx); err != nil {
 		return err
 	}
@@ -308,6 +475,9 @@ func (r *PostgresDeleteRepository[T, ID]) DeleteMultiple(ctx Context, entities [
 		return nil
 	}
 
+	if _, err := r.checkTenant(ctx, entities...); err != nil {
+		return err
+	}
 	for _, entity := range entities {
 		if err := (*entity).PreDelete(ctx); err != nil {
 			return err
@@ -344,6 +514,20 @@ func (r *PostgresDeleteRepository[T, ID]) DeleteMultiple(ctx Context, entities [
 	return nil
 }
 
+// DeleteWhere deletes every row matching req's filters in one DELETE and returns the deleted rows.
+// Sort and paging are ignored, at least one filter is required, and model hooks don't run.
+func (r *PostgresDeleteRepository[T, ID]) DeleteWhere(ctx Context, req *SearchRequest) ([]*T, error) {
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, args, err := whereClauseFor(scope, req)
+	if err != nil {
+		return nil, err
+	}
+	return queryWhere[T](ctx, buildDeleteWhereSQL(qualifiedTable[T](ctx), whereClause), args...)
+}
+
 type PostgresRepository[T BaseCompleteModel[ID], ID IDType] struct {
 	PostgresDeleteRepository[T, ID]
 }
@@ -353,24 +537,123 @@ func NewPostgresRepository[T BaseCompleteModel[ID], ID IDType]() *PostgresReposi
 }
 
 func (r *PostgresRepository[T, ID]) Upsert(ctx Context, entity *T) error {
+	scope, err := r.checkTenant(ctx, entity)
+	if err != nil {
+		return err
+	}
 	conflictColumns := (*entity).OnConflict()
+	opts := upsertOptions(scope, *entity)
+	original := *entity
 
 	executor := getExecutor[T](ctx)
 	if executor == nil {
 		return fmt.Errorf("no database connection available")
 	}
 
-	var err error
 	if tx, ok := executor.(*orm.Transaction[T]); ok {
 		query := ctx.GetPgTxn()
-		err = tx.Upsert(query, entity, conflictColumns)
+		err = tx.Upsert(query, entity, conflictColumns, opts...)
 	} else {
 		db, ok := executor.(*orm.DB[T])
 		if !ok || db == nil {
 			return fmt.Errorf("invalid database executor")
 		}
-		err = db.Upsert(ctx.GetCtx(), entity, conflictColumns)
+		err = db.Upsert(ctx.GetCtx(), entity, conflictColumns, opts...)
+	}
+	if err != nil {
+		return err
 	}
 
-	return err
+	// A conflict with another tenant's row leaves it unchanged and reads it back; don't hand it out
+	if !scope.owns(*entity) {
+		*entity = original
+		return orm.NewNotFoundError((*entity).TableName(), nil)
+	}
+	return nil
+}
+
+// UpsertMultiple writes entities with one INSERT ... ON CONFLICT per distinct OnConflict() column set, so
+// each entity resolves conflicts on its own columns; entities without conflict columns are created.
+// PreInsert runs before the write, then PostInsert or PostUpdate depending on whether each row was inserted.
+func (r *PostgresRepository[T, ID]) UpsertMultiple(ctx Context, entities []*T) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	scope, err := r.checkTenant(ctx, entities...)
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		if err := (*entity).PreInsert(ctx); err != nil {
+			return fmt.Errorf("pre-insert failed: %w", err)
+		}
+	}
+
+	executor := getExecutor[T](ctx)
+	if executor == nil {
+		return fmt.Errorf("no database connection available")
+	}
+	tx, isTx := executor.(*orm.Transaction[T])
+	db, isDB := executor.(*orm.DB[T])
+	if !isTx && (!isDB || db == nil) {
+		return fmt.Errorf("invalid database executor")
+	}
+
+	var order []string
+	groups := make(map[string][]*T)
+	for _, entity := range entities {
+		key := strings.Join((*entity).OnConflict(), ",")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], entity)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		conflictColumns := (*group[0]).OnConflict()
+		opts := upsertOptions(scope, *group[0])
+		originals := make([]T, len(group))
+		for i, entity := range group {
+			originals[i] = *entity
+		}
+
+		var inserted []bool
+		switch {
+		case len(conflictColumns) == 0 && isTx:
+			err = tx.CreateMultiple(ctx.GetPgTxn(), group)
+		case len(conflictColumns) == 0:
+			err = db.CreateMultiple(ctx.GetCtx(), group)
+		case isTx:
+			inserted, err = tx.UpsertMultiple(ctx.GetPgTxn(), group, conflictColumns, opts...)
+		default:
+			inserted, err = db.UpsertMultiple(ctx.GetCtx(), group, conflictColumns, opts...)
+		}
+		if err != nil {
+			return err
+		}
+		// Rows that conflicted with another tenant's are left unchanged and returned; don't hand them out
+		foreign := false
+		for i, entity := range group {
+			if !scope.owns(*entity) {
+				*entity = originals[i]
+				foreign = true
+			}
+		}
+		if foreign {
+			return orm.NewNotFoundError((*group[0]).TableName(), nil)
+		}
+
+		for i, entity := range group {
+			if inserted == nil || inserted[i] {
+				err = (*entity).PostInsert(ctx)
+			} else {
+				err = (*entity).PostUpdate(ctx)
+			}
+			if err != nil {
+				return fmt.Errorf("post-upsert failed: %w", err)
+			}
+		}
+	}
+	return nil
 }
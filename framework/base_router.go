@@ -2,6 +2,7 @@ package framework
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/yadunandan004/scaffold/request"
 )
@@ -16,12 +17,35 @@ type Route struct {
 	ShouldSkipTxn  bool
 	RateLimitRPS   int
 	RateLimitBurst int
+	// Permissions must all be granted to the caller; see RequirePermission
+	Permissions []string
+	// Middleware runs in order around the handler, inside the group's middleware; see Use
+	Middleware []Middleware
+	// Timeout bounds the middleware, transaction and handler; 0 uses the group's, negative disables it
+	Timeout time.Duration
+}
+
+// RequirePermission returns a copy of the route that only callers granted every permission may reach.
+// The registry enforces it after authentication, responding 403 when a permission is missing.
+func (r Route) RequirePermission(permissions ...string) Route {
+	r.Permissions = append(append([]string(nil), r.Permissions...), permissions...)
+	return r
+}
+
+// Use returns a copy of the route with middleware appended to its chain
+func (r Route) Use(middleware ...Middleware) Route {
+	r.Middleware = append(append([]Middleware(nil), r.Middleware...), middleware...)
+	return r
 }
 
 type RouteGroup struct {
 	Name      string
 	BasePath  string
 	RouteList []Route
+	// Middleware wraps every route in the group, outside each route's own middleware
+	Middleware []Middleware
+	// Timeout is the handler timeout of routes that don't set their own
+	Timeout time.Duration
 }
 
 type BaseReadRouter[T BaseReadModel[ID], ID IDType] struct {
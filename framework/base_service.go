@@ -12,7 +12,7 @@ import (
 
 type ReadOnlyService[T BaseReadModel[ID], ID IDType] interface {
 	GetByID(ctx request.Context, id ID) (*T, error)
-	Search(ctx request.Context, req *SearchRequest) ([]*T, error)
+	Search(ctx request.Context, req *SearchRequest) (*PagedResult[T], error)
 }
 
 type InsertService[T BaseInsertModel[ID], ID IDType] interface {
@@ -27,7 +27,10 @@ type BaseService[T BaseModel[ID], ID IDType] interface {
 	UpdateMultiple(ctx request.Context, entities []*T) ([]*T, error)
 	Delete(ctx request.Context, id ID) error
 	DeleteMultiple(ctx request.Context, ids []ID) error
+	UpdateWhere(ctx request.Context, req *SearchRequest, changes map[string]any) ([]*T, error)
+	DeleteWhere(ctx request.Context, req *SearchRequest) ([]*T, error)
 	Upsert(ctx request.Context, entity *T) (*T, error)
+	UpsertMultiple(ctx request.Context, entities []*T) ([]*T, error)
 }
 
 type ReadOnlyServiceImpl[T BaseReadModel[ID], ID IDType] struct {
@@ -131,13 +134,17 @@ func (s *ReadOnlyServiceImpl[T, ID]) GetByID(ctx request.Context, id ID) (*T, er
 	return result, nil
 }
 
-func (s *ReadOnlyServiceImpl[T, ID]) Search(ctx request.Context, req *SearchRequest) ([]*T, error) {
+func (s *ReadOnlyServiceImpl[T, ID]) Search(ctx request.Context, req *SearchRequest) (*PagedResult[T], error) {
 	startTime := time.Now()
 	logger.LogInfo(ctx, "→ ENTER: Search(filters: %d)", len(req.Filters))
 	defer func() {
 		logger.LogInfo(ctx, "← EXIT: Search (duration: %v)", time.Since(startTime))
 	}()
 
+	req, err := GetSearchLimits().Apply(req)
+	if err != nil {
+		return nil, err
+	}
 	return s.repository.Search(ctx, req)
 }
 
@@ -221,6 +228,37 @@ func (s *BaseServiceImpl[T, ID]) DeleteMultiple(ctx request.Context, ids []ID) e
 	return s.repository.DeleteMultiple(ctx, entities)
 }
 
+// UpdateWhere applies changes to every entity matching req's filters in a single statement
+func (s *BaseServiceImpl[T, ID]) UpdateWhere(ctx request.Context, req *SearchRequest, changes map[string]any) ([]*T, error) {
+	updated, err := s.repository.UpdateWhere(ctx, req, changes)
+	if err != nil {
+		return nil, err
+	}
+	s.evictCached(ctx, updated)
+	return updated, nil
+}
+
+// DeleteWhere deletes every entity matching req's filters in a single statement, without loading them first
+func (s *BaseServiceImpl[T, ID]) DeleteWhere(ctx request.Context, req *SearchRequest) ([]*T, error) {
+	deleted, err := s.repository.DeleteWhere(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.evictCached(ctx, deleted)
+	return deleted, nil
+}
+
+func (s *BaseServiceImpl[T, ID]) evictCached(ctx request.Context, entities []*T) {
+	if s.cacheService == nil || len(entities) == 0 || !(*entities[0]).SaveInCache() {
+		return
+	}
+	keys := make([]string, len(entities))
+	for i, entity := range entities {
+		keys[i] = s.getCacheKey(entity, (*entity).GetID())
+	}
+	_ = s.cacheService.Delete(ctx.GetRequestContext().GetCtx(), keys...)
+}
+
 func (s *BaseServiceImpl[T, ID]) Upsert(ctx request.Context, entity *T) (*T, error) {
 	startTime := time.Now()
 	logger.LogInfo(ctx, "→ ENTER: Upsert")
@@ -242,3 +280,10 @@ func (s *BaseServiceImpl[T, ID]) Upsert(ctx request.Context, entity *T) (*T, err
 
 	return entity, nil
 }
+
+func (s *BaseServiceImpl[T, ID]) UpsertMultiple(ctx request.Context, entities []*T) ([]*T, error) {
+	if err := s.repository.UpsertMultiple(ctx, entities); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
@@ -358,3 +358,46 @@ func TestBaseService_WithCache(t *testing.T) {
 	assert.Equal(t, retrieved1.ID, retrieved2.ID)
 	assert.Equal(t, retrieved1.Name, retrieved2.Name)
 }
+
+func TestBaseService_SearchPaged(t *testing.T) {
+	// Setup
+	repo := NewTestSampleRepository()
+	service := NewBaseService[TestSample](repo)
+	ctx := request.NewTestContext()
+
+	// Start transaction for test
+	_, err := request.BeginTransactionForModel[TestSample](ctx)
+	require.NoError(t, err)
+	defer ctx.CloseTxn(err)
+
+	// A status unique to this test keeps other rows out of the results
+	status := "paged-" + uuid.NewString()[:8]
+	for i := 0; i < 5; i++ {
+		_, err = service.Create(ctx, &TestSample{Name: "Paged Sample", Status: status, Count: i, Metadata: JSONB{}})
+		require.NoError(t, err)
+	}
+
+	req := NewSearchRequest().AddEqual("status", status).SortAsc("count").WithTake(2).WithTotal()
+
+	// First page
+	page, err := service.Search(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.NotNil(t, page.Total)
+	assert.Equal(t, int64(5), *page.Total)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 0, page.Items[0].Count)
+	require.NotEmpty(t, page.NextCursor)
+
+	// Follow cursors to the last page
+	page, err = service.Search(ctx, req.WithCursor(page.NextCursor))
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, 2, page.Items[0].Count)
+
+	page, err = service.Search(ctx, req.WithCursor(page.NextCursor))
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Page)
+	require.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+}
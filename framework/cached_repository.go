@@ -0,0 +1,269 @@
+package framework
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+const defaultRepositoryCacheTTL = 5 * time.Minute
+
+type cachedRepositoryConfig struct {
+	ttl             time.Duration
+	searchTTL       time.Duration
+	searchCacheable func(req *SearchRequest) bool
+}
+
+// CachedRepositoryOption configures a CachedRepository
+type CachedRepositoryOption func(*cachedRepositoryConfig)
+
+// WithCacheTTL sets the expiration for cached GetByID results
+func WithCacheTTL(ttl time.Duration) CachedRepositoryOption {
+	return func(c *cachedRepositoryConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithSearchCaching caches Search results for requests accepted by cacheable
+func WithSearchCaching(ttl time.Duration, cacheable func(req *SearchRequest) bool) CachedRepositoryOption {
+	return func(c *cachedRepositoryConfig) {
+		c.searchTTL = ttl
+		c.searchCacheable = cacheable
+	}
+}
+
+// CachedRepository wraps a BaseRepository with read-through caching of GetByID and selected Search calls.
+// Writes invalidate affected keys; inside a transaction, cache population and invalidation are deferred until commit.
+type CachedRepository[T BaseCompleteModel[ID], ID IDType] struct {
+	inner  BaseRepository[T, ID]
+	cache  cache.CacheService
+	config cachedRepositoryConfig
+}
+
+// NewCachedRepository wraps inner with caching backed by cacheService
+func NewCachedRepository[T BaseCompleteModel[ID], ID IDType](inner BaseRepository[T, ID], cacheService cache.CacheService, opts ...CachedRepositoryOption) *CachedRepository[T, ID] {
+	config := cachedRepositoryConfig{ttl: defaultRepositoryCacheTTL}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &CachedRepository[T, ID]{
+		inner:  inner,
+		cache:  cacheService,
+		config: config,
+	}
+}
+
+func (r *CachedRepository[T, ID]) enabled() bool {
+	var entity T
+	return r.cache != nil && entity.SaveInCache()
+}
+
+// namespace prefixes cache keys with the table, and with the request tenant for tenant-scoped models
+// so one tenant's cached rows and searches are never served to another
+func (r *CachedRepository[T, ID]) namespace(ctx Context) string {
+	var entity T
+	if _, ok := any(entity).(TenantScoped); ok {
+		return entity.TableName() + ":tenant:" + TenantID(ctx)
+	}
+	return entity.TableName()
+}
+
+func (r *CachedRepository[T, ID]) entityKey(ctx Context, id ID) string {
+	return fmt.Sprintf("%s:%v", r.namespace(ctx), id)
+}
+
+func (r *CachedRepository[T, ID]) searchTag(ctx Context) string {
+	return r.namespace(ctx) + ":search"
+}
+
+func (r *CachedRepository[T, ID]) searchKey(ctx Context, req *SearchRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s:search:%s", r.namespace(ctx), hex.EncodeToString(sum[:])), nil
+}
+
+func (r *CachedRepository[T, ID]) readCached(ctx Context, key string, dest interface{}) bool {
+	cached, err := r.cache.Get(ctx.GetCtx(), key)
+	if err != nil || cached == nil {
+		return false
+	}
+	data, ok := cached.(string)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal([]byte(data), dest) == nil
+}
+
+func (r *CachedRepository[T, ID]) GetByID(ctx Context, id ID) (*T, error) {
+	if !r.enabled() {
+		return r.inner.GetByID(ctx, id)
+	}
+
+	key := r.entityKey(ctx, id)
+	var entity T
+	if r.readCached(ctx, key, &entity) {
+		return &entity, nil
+	}
+
+	result, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		// Populating after commit keeps uncommitted rows out of the shared cache
+		ctx.AfterCommit(func(c context.Context) {
+			_ = r.cache.Set(c, key, string(data), r.config.ttl)
+		})
+	}
+
+	return result, nil
+}
+
+func (r *CachedRepository[T, ID]) Search(ctx Context, req *SearchRequest) (*PagedResult[T], error) {
+	if !r.enabled() || r.config.searchCacheable == nil || !r.config.searchCacheable(req) {
+		return r.inner.Search(ctx, req)
+	}
+
+	key, err := r.searchKey(ctx, req)
+	if err != nil {
+		return r.inner.Search(ctx, req)
+	}
+
+	var page PagedResult[T]
+	if r.readCached(ctx, key, &page) {
+		return &page, nil
+	}
+
+	results, err := r.inner.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(results); err == nil {
+		ctx.AfterCommit(func(c context.Context) {
+			_ = r.cache.SetWithTags(c, key, string(data), r.config.searchTTL, r.searchTag(ctx))
+		})
+	}
+
+	return results, nil
+}
+
+// invalidate drops cached entities and all cached searches.
+// Keys are removed immediately so the writing transaction never reads its own stale entries,
+// and again on commit so concurrent readers cannot leave pre-commit values behind.
+func (r *CachedRepository[T, ID]) invalidate(ctx Context, entities ...*T) {
+	if !r.enabled() {
+		return
+	}
+
+	keys := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		keys = append(keys, r.entityKey(ctx, (*entity).GetID()))
+	}
+
+	tag := r.searchTag(ctx)
+	drop := func(c context.Context) {
+		if len(keys) > 0 {
+			_ = r.cache.Delete(c, keys...)
+		}
+		_ = r.cache.InvalidateTag(c, tag)
+	}
+
+	if request.GetQuery(ctx) != nil {
+		drop(ctx.GetCtx())
+	}
+	ctx.AfterCommit(drop)
+}
+
+func (r *CachedRepository[T, ID]) Create(ctx Context, entity *T) error {
+	if err := r.inner.Create(ctx, entity); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entity)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) CreateMultiple(ctx Context, entities []*T) error {
+	if err := r.inner.CreateMultiple(ctx, entities); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entities...)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) Update(ctx Context, entity *T) error {
+	if err := r.inner.Update(ctx, entity); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entity)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) UpdateMultiple(ctx Context, entities []*T) error {
+	if err := r.inner.UpdateMultiple(ctx, entities); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entities...)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) Delete(ctx Context, entity *T) error {
+	if err := r.inner.Delete(ctx, entity); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entity)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) DeleteMultiple(ctx Context, entities []*T) error {
+	if err := r.inner.DeleteMultiple(ctx, entities); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entities...)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) UpdateWhere(ctx Context, req *SearchRequest, changes map[string]any) ([]*T, error) {
+	updated, err := r.inner.UpdateWhere(ctx, req, changes)
+	if err != nil {
+		return nil, err
+	}
+	r.invalidate(ctx, updated...)
+	return updated, nil
+}
+
+func (r *CachedRepository[T, ID]) DeleteWhere(ctx Context, req *SearchRequest) ([]*T, error) {
+	deleted, err := r.inner.DeleteWhere(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.invalidate(ctx, deleted...)
+	return deleted, nil
+}
+
+func (r *CachedRepository[T, ID]) Upsert(ctx Context, entity *T) error {
+	if err := r.inner.Upsert(ctx, entity); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entity)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) UpsertMultiple(ctx Context, entities []*T) error {
+	if err := r.inner.UpsertMultiple(ctx, entities); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entities...)
+	return nil
+}
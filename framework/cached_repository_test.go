@@ -0,0 +1,91 @@
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func newTestCachedRepository(t *testing.T) (*CachedRepository[TestSample, uuid.UUID], *local.LocalCache) {
+	localCache := local.NewLocalCache(cache.DefaultCacheOptions())
+	t.Cleanup(func() { _ = localCache.Close() })
+
+	repo := NewCachedRepository[TestSample, uuid.UUID](
+		NewPostgresRepository[TestSample, uuid.UUID](),
+		localCache,
+		WithSearchCaching(0, func(req *SearchRequest) bool { return true }),
+	)
+	return repo, localCache
+}
+
+func TestCachedRepository_PopulatesOnCommit(t *testing.T) {
+	repo, localCache := newTestCachedRepository(t)
+	ctx := request.NewTestContext()
+
+	_, err := request.BeginTransactionForModel[TestSample](ctx)
+	require.NoError(t, err)
+
+	sample, err := CreateSampleTable(ctx)
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, sample.ID)
+	require.NoError(t, err)
+
+	// Nothing is cached until the transaction commits
+	exists, err := localCache.Exists(context.Background(), repo.entityKey(ctx, sample.ID))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, ctx.CloseTxn(nil))
+
+	exists, err = localCache.Exists(context.Background(), repo.entityKey(ctx, sample.ID))
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestCachedRepository_InvalidatesOnUpdate(t *testing.T) {
+	repo, localCache := newTestCachedRepository(t)
+
+	seedCtx := request.NewTestContext()
+	_, err := request.BeginTransactionForModel[TestSample](seedCtx)
+	require.NoError(t, err)
+	sample, err := CreateSampleTable(seedCtx)
+	require.NoError(t, err)
+	require.NoError(t, seedCtx.CloseTxn(nil))
+
+	// Warm the entity and search caches outside a transaction
+	readCtx := request.NewTestContext()
+	_, err = repo.GetByID(readCtx, sample.ID)
+	require.NoError(t, err)
+	_, err = repo.Search(readCtx, &SearchRequest{})
+	require.NoError(t, err)
+
+	ctx := request.NewTestContext()
+	_, err = request.BeginTransactionForModel[TestSample](ctx)
+	require.NoError(t, err)
+
+	sample.Name = "Updated Cached Sample"
+	require.NoError(t, repo.Update(ctx, sample))
+
+	// The writing transaction reads through to the database
+	retrieved, err := repo.GetByID(ctx, sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated Cached Sample", retrieved.Name)
+
+	require.NoError(t, ctx.CloseTxn(nil))
+
+	keys, err := localCache.Keys(context.Background(), sample.TableName()+":search:*")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	retrieved, err = repo.GetByID(request.NewTestContext(), sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated Cached Sample", retrieved.Name)
+}
@@ -0,0 +1,149 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yadunandan004/scaffold/config"
+)
+
+// CORS answers preflight requests and adds Access-Control-* headers for allowed origins.
+// It is engine middleware rather than route Middleware because preflight requests must be answered
+// for paths that have no OPTIONS route; install it with Registry.UseServerMiddleware or engine.Use,
+// before any auth middleware. "*" can't be combined with AllowCredentials, as any site could then
+// read credentialed responses; list the trusted origins instead.
+func CORS(cfg config.CORSConfig) (gin.HandlerFunc, error) {
+	allowMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := ""
+	if cfg.MaxAge > 0 {
+		maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+	anyOrigin := false
+	for _, origin := range cfg.AllowedOrigins {
+		anyOrigin = anyOrigin || origin == "*"
+	}
+	if anyOrigin && cfg.AllowCredentials {
+		return nil, fmt.Errorf("cors: allowed origin \"*\" can't be used with allow_credentials; list the origins explicitly")
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if anyOrigin {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+		if allowHeaders != "" {
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+		}
+		if maxAge != "" {
+			header.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}, nil
+}
+
+// originAllowed matches exact origins, "*", and wildcard subdomains such as "https://*.example.com"
+func originAllowed(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		a = strings.TrimSuffix(a, "/")
+		if a == "*" || strings.EqualFold(a, origin) {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(a, "://*."); ok {
+			prefix := scheme + "://"
+			if len(origin) > len(prefix) && strings.EqualFold(origin[:len(prefix)], prefix) &&
+				strings.HasSuffix(strings.ToLower(origin[len(prefix):]), "."+strings.ToLower(host)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SecurityHeaders sets standard response hardening headers on every response
+func SecurityHeaders(cfg config.SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.FrameOptions != "" {
+			header.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ContentSecurityPolicy != "" {
+			header.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		c.Next()
+	}
+}
+
+// UseServerMiddleware installs the CORS and security header middleware enabled in cfg on the engine.
+// Call it before registering auth middleware so preflight requests are answered without credentials.
+// It returns an error, installing nothing, when the CORS settings are unsafe.
+func (r *Registry) UseServerMiddleware(cfg *config.ServerConfig) error {
+	var cors gin.HandlerFunc
+	if cfg.CORS.Enabled {
+		var err error
+		if cors, err = CORS(cfg.CORS); err != nil {
+			return err
+		}
+	}
+	if cfg.SecurityHeaders.Enabled {
+		r.engine.Use(SecurityHeaders(cfg.SecurityHeaders))
+	}
+	if cors != nil {
+		r.engine.Use(cors)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/config"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func newCORSEngine(t *testing.T, cfg *config.ServerConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	registry := NewRegistry(engine, nil)
+	require.NoError(t, registry.UseServerMiddleware(cfg))
+	registry.AddGroup(RouteGroup{
+		Name:     "test",
+		BasePath: "/api",
+		RouteList: []Route{{
+			Method:         "GET",
+			Path:           "/items",
+			Handler:        func(ctx request.Context) { ctx.JSON(200, gin.H{}) },
+			ShouldSkipAuth: true,
+			ShouldSkipTxn:  true,
+		}},
+	})
+	return engine
+}
+
+func TestCORS(t *testing.T) {
+	engine := newCORSEngine(t, &config.ServerConfig{CORS: config.CORSConfig{
+		Enabled:          true,
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.preview.example.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"RateLimit-Remaining"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}})
+
+	tests := []struct {
+		name      string
+		method    string
+		origin    string
+		preflight bool
+		code      int
+		allowed   string
+	}{
+		{"no origin", "GET", "", false, http.StatusOK, ""},
+		{"allowed origin", "GET", "https://app.example.com", false, http.StatusOK, "https://app.example.com"},
+		{"wildcard subdomain", "GET", "https://pr-12.preview.example.com", false, http.StatusOK, "https://pr-12.preview.example.com"},
+		{"wildcard needs a subdomain", "GET", "https://preview.example.com", false, http.StatusOK, ""},
+		{"other origin", "GET", "https://evil.com", false, http.StatusOK, ""},
+		{"preflight", "OPTIONS", "https://app.example.com", true, http.StatusNoContent, "https://app.example.com"},
+		{"preflight from other origin", "OPTIONS", "https://evil.com", true, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/items", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.allowed, w.Header().Get("Access-Control-Allow-Origin"))
+			if tt.allowed == "" {
+				return
+			}
+			assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+			if tt.preflight {
+				assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+			} else {
+				assert.Equal(t, "RateLimit-Remaining", w.Header().Get("Access-Control-Expose-Headers"))
+			}
+		})
+	}
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	engine := newCORSEngine(t, &config.ServerConfig{CORS: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}}})
+
+	req := httptest.NewRequest("GET", "/api/items", nil)
+	req.Header.Set("Origin", "https://anywhere.dev")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_AnyOriginRejectsCredentials(t *testing.T) {
+	_, err := CORS(config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	assert.Error(t, err)
+
+	err = NewRegistry(gin.New(), nil).UseServerMiddleware(&config.ServerConfig{CORS: config.CORSConfig{
+		Enabled:          true,
+		AllowedOrigins:   []string{"https://app.example.com", "*"},
+		AllowCredentials: true,
+	}})
+	assert.Error(t, err)
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	engine := newCORSEngine(t, &config.ServerConfig{SecurityHeaders: config.SecurityHeadersConfig{
+		Enabled:               true,
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+	}})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/items", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", w.Header().Get("Referrer-Policy"))
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+}
@@ -0,0 +1,120 @@
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// CRUDOptions configures the routes MountCRUD registers
+type CRUDOptions[ID IDType] struct {
+	// Name of the route group; defaults to the base path
+	Name string
+	// IDParser turns the :id path parameter into an ID; defaults to ParseID
+	IDParser func(string) (ID, error)
+	// SearchFields are the columns GET basePath and POST basePath/search may filter and sort on;
+	// defaults to T's SearchableColumns
+	SearchFields []string
+	// SkipAuth exposes every route without authentication
+	SkipAuth bool
+	// ReadPermissions and WritePermissions are required on read and write routes respectively
+	ReadPermissions  []string
+	WritePermissions []string
+	RateLimitRPS     int
+	RateLimitBurst   int
+	// DisableBulk leaves out the /bulk create, update and delete routes
+	DisableBulk bool
+}
+
+// MountCRUD registers the standard create, read, update, delete, list and search routes for T under basePath.
+// Reads skip the transaction and writes run in one; auth, permissions and rate limits come from opts.
+//
+//	GET    basePath          list, filtered by query parameters (see ParseSearchQuery)
+//	GET    basePath/:id      get by ID
+//	POST   basePath/search   search with a SearchRequest body (see ValidateSearchRequest)
+//	POST   basePath          create
+//	PUT    basePath/:id      update
+//	DELETE basePath/:id      delete
+//	POST|PUT|DELETE basePath/bulk  bulk create, update and delete
+func MountCRUD[T BaseModel[ID], ID IDType](registry *Registry, basePath string, service BaseService[T, ID], opts CRUDOptions[ID]) *BaseController[T, ID] {
+	idParser := opts.IDParser
+	if idParser == nil {
+		idParser = ParseID[ID]
+	}
+	ctrl := NewBaseController[T, ID](service, idParser)
+	ctrl.SearchFields = opts.SearchFields
+
+	read := func(method, path string, handler HandlerFunc) Route {
+		return opts.route(method, path, handler, true, opts.ReadPermissions)
+	}
+	write := func(method, path string, handler HandlerFunc) Route {
+		return opts.route(method, path, handler, false, opts.WritePermissions)
+	}
+
+	routes := []Route{
+		read(request.HTTPMethod.Get(), "", ctrl.HandleList),
+		read(request.HTTPMethod.Get(), "/:id", func(ctx request.Context) { ctrl.HandleGetByID(ctx, "id") }),
+		read(request.HTTPMethod.Post(), "/search", ctrl.HandleSearch),
+		write(request.HTTPMethod.Post(), "", ctrl.HandleCreate),
+		write(request.HTTPMethod.Put(), "/:id", ctrl.HandleUpdate),
+		write(request.HTTPMethod.Delete(), "/:id", func(ctx request.Context) { ctrl.HandleDelete(ctx, "id") }),
+	}
+	if !opts.DisableBulk {
+		routes = append(routes,
+			write(request.HTTPMethod.Post(), "/bulk", ctrl.HandleCreateMultiple),
+			write(request.HTTPMethod.Put(), "/bulk", ctrl.HandleUpdateMultiple),
+			write(request.HTTPMethod.Delete(), "/bulk", ctrl.HandleDeleteMultiple),
+		)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = basePath
+	}
+	registry.AddGroup(RouteGroup{Name: name, BasePath: basePath, RouteList: routes})
+	return ctrl
+}
+
+func (opts CRUDOptions[ID]) route(method, path string, handler HandlerFunc, skipTxn bool, permissions []string) Route {
+	return Route{
+		Method:         method,
+		Path:           path,
+		Handler:        handler,
+		ShouldSkipAuth: opts.SkipAuth,
+		ShouldSkipTxn:  skipTxn,
+		RateLimitRPS:   opts.RateLimitRPS,
+		RateLimitBurst: opts.RateLimitBurst,
+		Permissions:    append([]string(nil), permissions...),
+	}
+}
+
+// ParseID parses a path parameter into any IDType: UUIDs, integers and strings
+func ParseID[ID IDType](s string) (ID, error) {
+	var id ID
+	value := reflect.ValueOf(&id).Elem()
+	switch value.Kind() {
+	case reflect.String:
+		if strings.TrimSpace(s) == "" {
+			return id, fmt.Errorf("empty id")
+		}
+		value.SetString(s)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return id, err
+		}
+		value.SetInt(n)
+	default:
+		parsed, err := uuid.Parse(s)
+		if err != nil {
+			return id, err
+		}
+		value.Set(reflect.ValueOf(parsed))
+	}
+	return id, nil
+}
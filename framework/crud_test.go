@@ -0,0 +1,173 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// stubSampleService serves reads from memory and records the last search
+type stubSampleService struct {
+	BaseService[TestSample, uuid.UUID]
+	samples    map[uuid.UUID]*TestSample
+	lastSearch *SearchRequest
+}
+
+func (s *stubSampleService) GetByID(ctx request.Context, id uuid.UUID) (*TestSample, error) {
+	if sample, ok := s.samples[id]; ok {
+		return sample, nil
+	}
+	return nil, app_error.NotFound("Sample not found")
+}
+
+func (s *stubSampleService) Search(ctx request.Context, req *SearchRequest) (*PagedResult[TestSample], error) {
+	s.lastSearch = req
+	results := make([]*TestSample, 0, len(s.samples))
+	for _, sample := range s.samples {
+		results = append(results, sample)
+	}
+	return NewPagedResult(results, req).WithTotal(int64(len(results))), nil
+}
+
+func mountSamples(t *testing.T, opts CRUDOptions[uuid.UUID]) (*gin.Engine, *stubSampleService, uuid.UUID) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	id := uuid.New()
+	sample := &TestSample{Name: "first", Status: "active"}
+	sample.ID = id
+	service := &stubSampleService{samples: map[uuid.UUID]*TestSample{id: sample}}
+
+	engine := gin.New()
+	MountCRUD[TestSample, uuid.UUID](NewRegistry(engine, nil), "/api/v1/samples", service, opts)
+	return engine, service, id
+}
+
+func TestMountCRUD_RegistersRoutes(t *testing.T) {
+	engine, _, _ := mountSamples(t, CRUDOptions[uuid.UUID]{SkipAuth: true})
+
+	var routes []string
+	for _, route := range engine.Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"GET /api/v1/samples",
+		"GET /api/v1/samples/:id",
+		"POST /api/v1/samples/search",
+		"POST /api/v1/samples",
+		"PUT /api/v1/samples/:id",
+		"DELETE /api/v1/samples/:id",
+		"POST /api/v1/samples/bulk",
+		"PUT /api/v1/samples/bulk",
+		"DELETE /api/v1/samples/bulk",
+	}, routes)
+
+	engine, _, _ = mountSamples(t, CRUDOptions[uuid.UUID]{SkipAuth: true, DisableBulk: true})
+	assert.Len(t, engine.Routes(), 6)
+}
+
+func TestMountCRUD_GetByID(t *testing.T) {
+	engine, _, id := mountSamples(t, CRUDOptions[uuid.UUID]{SkipAuth: true})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/samples/"+id.String(), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var sample TestSample
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sample))
+	assert.Equal(t, id, sample.ID)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/samples/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMountCRUD_ListParsesQuery(t *testing.T) {
+	engine, service, _ := mountSamples(t, CRUDOptions[uuid.UUID]{
+		SkipAuth:     true,
+		SearchFields: []string{"status", "created_at"},
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/samples?status=active&sort=-created_at&page=2&take=10", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.NotNil(t, service.lastSearch)
+	assert.Equal(t, []FilterPayload{*EqualFilter("status", "active")}, service.lastSearch.Filters)
+	assert.Equal(t, &SortPayload{Fields: []string{"created_at"}, Direction: "DESC"}, service.lastSearch.Sort)
+	assert.Equal(t, 2, service.lastSearch.Page)
+	assert.Equal(t, 10, service.lastSearch.Take)
+
+	var page PagedResult[TestSample]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Items, 1)
+	require.NotNil(t, page.Total)
+	assert.Equal(t, int64(1), *page.Total)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/samples?name=first", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMountCRUD_SearchChecksBody(t *testing.T) {
+	engine, service, _ := mountSamples(t, CRUDOptions[uuid.UUID]{
+		SkipAuth:     true,
+		SearchFields: []string{"status", "created_at"},
+	})
+
+	body := `{"Filters":[{"Field":"status","Operator":"eq","Values":["active"]}],"Sort":{"Fields":["created_at"],"Direction":"DESC"}}`
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/samples/search", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, service.lastSearch)
+	assert.Equal(t, []FilterPayload{*EqualFilter("status", "active")}, service.lastSearch.Filters)
+
+	service.lastSearch = nil
+	for _, body := range []string{
+		`{"Filters":[{"Field":"name","Operator":"eq","Values":["first"]}]}`,
+		`{"Filters":[{"Field":"status = 'x' OR 1=1 OR status","Operator":"eq","Values":["x"]}]}`,
+		`{"Sort":{"Fields":["created_at; DROP TABLE samples"]}}`,
+		`{"Columns":["password"]}`,
+	} {
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/samples/search", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Nil(t, service.lastSearch, "rejected bodies never reach the service")
+}
+
+func TestMountCRUD_RequiresAuthByDefault(t *testing.T) {
+	engine, _, id := mountSamples(t, CRUDOptions[uuid.UUID]{})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/samples/"+id.String(), nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestParseID(t *testing.T) {
+	type orderID int64
+
+	id := uuid.New()
+	parsed, err := ParseID[uuid.UUID](id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	n, err := ParseID[orderID]("42")
+	require.NoError(t, err)
+	assert.Equal(t, orderID(42), n)
+
+	_, err = ParseID[int]("abc")
+	assert.Error(t, err)
+	_, err = ParseID[string]("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package framework
+
+import (
+	"log"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// ErrorHandlerFunc is a route handler that returns its error instead of writing it
+type ErrorHandlerFunc func(ctx request.Context) error
+
+// HandleError adapts fn to a HandlerFunc, writing a returned error with RespondError
+func HandleError(fn ErrorHandlerFunc) HandlerFunc {
+	return func(ctx request.Context) {
+		if err := fn(ctx); err != nil {
+			RespondError(ctx, err)
+		}
+	}
+}
+
+// RespondError writes err as the standard error envelope with its status code.
+// Errors that are not AppErrors are reported as internal errors and logged with the request XID;
+// their text is never sent to the client.
+func RespondError(ctx request.Context, err error) {
+	status, body := app_error.Response(err, ctx.XID(), ctx.TraceID())
+	if status >= 500 {
+		log.Printf("[Registry] xid=%s: %v", ctx.XID(), err)
+	}
+	ctx.JSON(status, body)
+}
+
+// runHandler calls handler, converting a panic into an internal error response
+func runHandler(ctx request.Context, handler HandlerFunc) {
+	defer func() {
+		if r := recover(); r != nil {
+			route := ""
+			if ginCtx := ctx.GetGinContext(); ginCtx != nil {
+				route = ginCtx.Request.Method + " " + ginCtx.FullPath()
+			}
+			RespondError(ctx, reportPanic(ctx, transportHTTP, route, r))
+		}
+	}()
+	handler(ctx)
+}
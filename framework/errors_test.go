@@ -0,0 +1,112 @@
+package framework
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func serveErrorRoute(t *testing.T, authService *auth.AuthService, route Route) (int, app_error.ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	registry := NewRegistry(engine, authService)
+	registry.AddGroup(RouteGroup{Name: "errors", BasePath: "/api", RouteList: []Route{route}})
+
+	req, _ := http.NewRequest(route.Method, "/api/test", nil)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	var body app_error.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return w.Code, body
+}
+
+func TestHandleError_WritesEnvelope(t *testing.T) {
+	code, body := serveErrorRoute(t, nil, Route{
+		Method: "GET",
+		Path:   "/test",
+		Handler: HandleError(func(ctx request.Context) error {
+			return app_error.NotFound("Order not found").WithDetail("order_id", "42")
+		}),
+		ShouldSkipAuth: true,
+		ShouldSkipTxn:  true,
+	})
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, app_error.CodeNotFound, body.Error.Code)
+	assert.Equal(t, "Order not found", body.Error.Message)
+	assert.Equal(t, "42", body.Error.Details["order_id"])
+	assert.NotEmpty(t, body.Error.XID)
+}
+
+func TestHandleError_MapsNotFound(t *testing.T) {
+	code, body := serveErrorRoute(t, nil, Route{
+		Method: "GET",
+		Path:   "/test",
+		Handler: HandleError(func(ctx request.Context) error {
+			return fmt.Errorf("load order: %w", orm.NewNotFoundError("orders", 42))
+		}),
+		ShouldSkipAuth: true,
+		ShouldSkipTxn:  true,
+	})
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, app_error.CodeNotFound, body.Error.Code)
+	assert.NotContains(t, body.Error.Message, "orders", "the table isn't revealed")
+}
+
+func TestHandleError_HidesInternalErrors(t *testing.T) {
+	code, body := serveErrorRoute(t, nil, Route{
+		Method: "GET",
+		Path:   "/test",
+		Handler: HandleError(func(ctx request.Context) error {
+			return errors.New("pq: password authentication failed for user admin")
+		}),
+		ShouldSkipAuth: true,
+		ShouldSkipTxn:  true,
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, app_error.CodeInternal, body.Error.Code)
+	assert.NotContains(t, body.Error.Message, "password")
+}
+
+func TestRegistry_RecoversPanics(t *testing.T) {
+	code, body := serveErrorRoute(t, nil, Route{
+		Method:         "GET",
+		Path:           "/test",
+		Handler:        func(ctx request.Context) { panic("nil map") },
+		ShouldSkipAuth: true,
+		ShouldSkipTxn:  true,
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, app_error.CodeInternal, body.Error.Code)
+}
+
+func TestRegistry_AuthErrorsUseEnvelope(t *testing.T) {
+	code, body := serveErrorRoute(t, &auth.AuthService{}, Route{
+		Method:        "GET",
+		Path:          "/test",
+		Handler:       func(ctx request.Context) { ctx.JSON(200, gin.H{"message": "ok"}) },
+		ShouldSkipTxn: true,
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, app_error.CodeUnauthenticated, body.Error.Code)
+	assert.Equal(t, "Authorization header required", body.Error.Message)
+}
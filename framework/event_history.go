@@ -0,0 +1,193 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// HistoryOptions filters the changes GetHistory returns
+type HistoryOptions struct {
+	// Since and Until bound OccurredAt; zero values are unbounded
+	Since time.Time
+	Until time.Time
+	// Actions keeps only these actions (EventCreate, EventUpdate, EventDelete); empty keeps all
+	Actions []string
+	// Limit keeps only the most recent changes; 0 keeps all
+	Limit int
+}
+
+// FieldChange is the old and new value of one field in a change
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Change is one recorded write of an entity
+type Change[T any] struct {
+	EventID    uuid.UUID
+	Action     string
+	Actor      string
+	XID        uuid.UUID
+	TraceID    string
+	OccurredAt time.Time
+	Fields     map[string]FieldChange
+	// Entity is the version written by this change, rebuilt by replaying the history; nil after a delete
+	Entity *T
+}
+
+// GetHistory returns the recorded changes of one entity, oldest first, read from the domain_events
+// table written by PostgresEventRecorder. Tenant-scoped entities of another tenant are reported as not found.
+func GetHistory[T BaseReadModel[ID], ID IDType](ctx Context, entityID ID, opts HistoryOptions) ([]*Change[T], error) {
+	var entity T
+	scope, err := scopeTenant[T](ctx)
+	if err != nil {
+		return nil, err
+	}
+	if orm.GetMetadata[DomainEvent]() == nil {
+		orm.RegisterModel[DomainEvent]()
+	}
+
+	// The full history is always read so every version can be rebuilt; options are applied afterwards
+	query := "SELECT * FROM domain_events WHERE table_name = $1 AND entity_id = $2 ORDER BY occurred_at, id"
+	args := []interface{}{entity.TableName(), fmt.Sprintf("%v", entityID)}
+
+	var events []*DomainEvent
+	if q := ctx.GetPgTxn(); q != nil {
+		events, err = orm.NewTransaction[DomainEvent]().FindByQuery(q, query, args...)
+	} else {
+		db := postgres.GetDB()
+		if db == nil || db.DB == nil {
+			return nil, fmt.Errorf("no database connection available")
+		}
+		events, err = orm.NewDB[DomainEvent](db.DB).FindByQuery(ctx.GetCtx(), query, args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history of %s %v: %w", entity.TableName(), entityID, err)
+	}
+
+	changes, err := buildHistory[T](events)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 && !scope.owns(firstVersion(changes)) {
+		return nil, orm.NewNotFoundError(entity.TableName(), entityID)
+	}
+	return filterHistory(changes, opts), nil
+}
+
+// DiffVersions returns the fields that differ between two versions of an entity, such as two Change.Entity
+// values; a nil version diffs as an entity with no fields
+func DiffVersions[T any](from, to *T) (map[string]FieldChange, error) {
+	diff, err := buildDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return parseFieldChanges(diff)
+}
+
+// buildHistory replays events in order, applying each diff to the previous version
+func buildHistory[T any](events []*DomainEvent) ([]*Change[T], error) {
+	changes := make([]*Change[T], 0, len(events))
+	state := make(map[string]interface{})
+	for _, event := range events {
+		fields, err := parseFieldChanges(event.Diff)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+
+		change := &Change[T]{
+			EventID:    event.ID,
+			Action:     event.Action,
+			Actor:      event.Actor,
+			XID:        event.XID,
+			TraceID:    event.TraceID,
+			OccurredAt: event.OccurredAt,
+			Fields:     fields,
+		}
+
+		if event.Action == EventDelete {
+			state = make(map[string]interface{})
+		} else {
+			if event.Action == EventCreate {
+				state = make(map[string]interface{})
+			}
+			for field, fieldChange := range fields {
+				state[field] = fieldChange.New
+			}
+			if change.Entity, err = versionFromFields[T](state); err != nil {
+				return nil, fmt.Errorf("event %s: %w", event.ID, err)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func parseFieldChanges(diff string) (map[string]FieldChange, error) {
+	fields := make(map[string]FieldChange)
+	if diff == "" {
+		return fields, nil
+	}
+	if err := json.Unmarshal([]byte(diff), &fields); err != nil {
+		return nil, fmt.Errorf("parse diff: %w", err)
+	}
+	return fields, nil
+}
+
+func versionFromFields[T any](state map[string]interface{}) (*T, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var version T
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// firstVersion returns the earliest rebuilt version, used to decide which tenant owns the entity
+func firstVersion[T any](changes []*Change[T]) interface{} {
+	for _, change := range changes {
+		if change.Entity != nil {
+			return *change.Entity
+		}
+	}
+	var zero T
+	return zero
+}
+
+func filterHistory[T any](changes []*Change[T], opts HistoryOptions) []*Change[T] {
+	filtered := make([]*Change[T], 0, len(changes))
+	for _, change := range changes {
+		if !opts.Since.IsZero() && change.OccurredAt.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && change.OccurredAt.After(opts.Until) {
+			continue
+		}
+		if len(opts.Actions) > 0 && !containsString(opts.Actions, change.Action) {
+			continue
+		}
+		filtered = append(filtered, change)
+	}
+	if opts.Limit > 0 && len(filtered) > opts.Limit {
+		filtered = filtered[len(filtered)-opts.Limit:]
+	}
+	return filtered
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type historySample struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func historyEvent(t *testing.T, action string, at time.Time, before, after *historySample) *DomainEvent {
+	diff, err := buildDiff(before, after)
+	require.NoError(t, err)
+	return &DomainEvent{ID: uuid.New(), Action: action, Actor: "user-1", Diff: diff, OccurredAt: at}
+}
+
+func TestBuildHistory_ReplaysVersions(t *testing.T) {
+	start := time.Now()
+	v1 := &historySample{Name: "a", Count: 1}
+	v2 := &historySample{Name: "a", Count: 2}
+	events := []*DomainEvent{
+		historyEvent(t, EventCreate, start, nil, v1),
+		historyEvent(t, EventUpdate, start.Add(time.Second), v1, v2),
+		historyEvent(t, EventDelete, start.Add(2*time.Second), v2, nil),
+	}
+
+	changes, err := buildHistory[historySample](events)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, v1, changes[0].Entity)
+	assert.Equal(t, v2, changes[1].Entity)
+	assert.Nil(t, changes[2].Entity)
+	assert.Equal(t, "user-1", changes[1].Actor)
+	assert.Equal(t, map[string]FieldChange{"count": {Old: float64(1), New: float64(2)}}, changes[1].Fields)
+}
+
+func TestFilterHistory(t *testing.T) {
+	start := time.Now()
+	changes := []*Change[historySample]{
+		{Action: EventCreate, OccurredAt: start},
+		{Action: EventUpdate, OccurredAt: start.Add(time.Minute)},
+		{Action: EventUpdate, OccurredAt: start.Add(2 * time.Minute)},
+		{Action: EventDelete, OccurredAt: start.Add(3 * time.Minute)},
+	}
+
+	assert.Len(t, filterHistory(changes, HistoryOptions{Actions: []string{EventUpdate}}), 2)
+	assert.Len(t, filterHistory(changes, HistoryOptions{Since: start.Add(time.Minute), Until: start.Add(2 * time.Minute)}), 2)
+
+	latest := filterHistory(changes, HistoryOptions{Limit: 1})
+	require.Len(t, latest, 1)
+	assert.Equal(t, EventDelete, latest[0].Action)
+}
+
+func TestDiffVersions(t *testing.T) {
+	diff, err := DiffVersions(&historySample{Name: "a", Count: 1}, &historySample{Name: "b", Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]FieldChange{"name": {Old: "a", New: "b"}}, diff)
+}
@@ -0,0 +1,363 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/store/analytics/clickhouse"
+)
+
+// Event actions recorded for CRUD operations
+const (
+	EventCreate = "create"
+	EventUpdate = "update"
+	EventDelete = "delete"
+)
+
+// DomainEventsDDL creates the ClickHouse table DomainEvent is written to, for use with clickhouse.Migrator
+const DomainEventsDDL = `CREATE TABLE IF NOT EXISTS domain_events {on_cluster} (
+	id UUID,
+	table_name LowCardinality(String),
+	entity_id String,
+	action LowCardinality(String),
+	actor String,
+	xid UUID,
+	trace_id String,
+	diff String,
+	occurred_at DateTime64(3)
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (table_name, occurred_at)`
+
+// EventRecordable is implemented by models that opt into domain event recording
+type EventRecordable interface {
+	RecordEvents() bool
+}
+
+// DomainEvent describes a single create, update or delete of an entity
+type DomainEvent struct {
+	ID         uuid.UUID `json:"id" orm:"column:id;pk"`
+	Table      string    `json:"table_name" orm:"column:table_name"`
+	EntityID   string    `json:"entity_id" orm:"column:entity_id"`
+	Action     string    `json:"action" orm:"column:action"`
+	Actor      string    `json:"actor" orm:"column:actor"`
+	XID        uuid.UUID `json:"xid" orm:"column:xid"`
+	TraceID    string    `json:"trace_id" orm:"column:trace_id"`
+	Diff       string    `json:"diff" orm:"column:diff"`
+	OccurredAt time.Time `json:"occurred_at" orm:"column:occurred_at"`
+}
+
+func (DomainEvent) TableName() string {
+	return "domain_events"
+}
+
+// EventRecorder persists domain events asynchronously
+type EventRecorder interface {
+	Record(ctx context.Context, events ...*DomainEvent) error
+}
+
+// ClickHouseEventRecorder buffers events and writes them to ClickHouse in batches
+type ClickHouseEventRecorder struct {
+	writer *clickhouse.BatchWriter[DomainEvent]
+}
+
+// NewClickHouseEventRecorder creates a recorder backed by a clickhouse.BatchWriter
+func NewClickHouseEventRecorder(client *clickhouse.Client, cfg clickhouse.BatchWriterConfig) (*ClickHouseEventRecorder, error) {
+	writer, err := clickhouse.NewBatchWriter[DomainEvent](client, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ClickHouseEventRecorder{writer: writer}, nil
+}
+
+// Record buffers events; they are flushed by size, interval or clickhouse.Shutdown
+func (r *ClickHouseEventRecorder) Record(ctx context.Context, events ...*DomainEvent) error {
+	return r.writer.Write(ctx, events...)
+}
+
+// EventRecordingRepository wraps a BaseRepository and records an event for every write of a model that opts in.
+// Events are recorded once the request transaction commits, so rolled back writes are never reported.
+type EventRecordingRepository[T BaseCompleteModel[ID], ID IDType] struct {
+	inner    BaseRepository[T, ID]
+	recorder EventRecorder
+}
+
+// NewEventRecordingRepository wraps inner, sending events to recorder
+func NewEventRecordingRepository[T BaseCompleteModel[ID], ID IDType](inner BaseRepository[T, ID], recorder EventRecorder) *EventRecordingRepository[T, ID] {
+	return &EventRecordingRepository[T, ID]{
+		inner:    inner,
+		recorder: recorder,
+	}
+}
+
+func (r *EventRecordingRepository[T, ID]) enabled() bool {
+	var entity T
+	recordable, ok := any(entity).(EventRecordable)
+	if !ok {
+		recordable, ok = any(&entity).(EventRecordable)
+	}
+	return r.recorder != nil && ok && recordable.RecordEvents()
+}
+
+// newEvent builds an event for entity, with diff holding the changed fields between before and after
+func (r *EventRecordingRepository[T, ID]) newEvent(ctx Context, action string, entity, before, after *T) *DomainEvent {
+	event := &DomainEvent{
+		ID:         uuid.New(),
+		Table:      (*entity).TableName(),
+		EntityID:   fmt.Sprintf("%v", (*entity).GetID()),
+		Action:     action,
+		XID:        ctx.XID(),
+		TraceID:    ctx.TraceID(),
+		OccurredAt: time.Now().UTC(),
+	}
+	if user := ctx.GetUserInfo(); user != nil {
+		event.Actor = user.GetID().String()
+	}
+	if diff, err := buildDiff(before, after); err == nil {
+		event.Diff = diff
+	}
+	return event
+}
+
+// record sends events after commit; failures are dropped so analytics never fails a write
+func (r *EventRecordingRepository[T, ID]) record(ctx Context, events ...*DomainEvent) {
+	ctx.AfterCommit(func(c context.Context) {
+		_ = r.recorder.Record(c, events...)
+	})
+}
+
+func (r *EventRecordingRepository[T, ID]) GetByID(ctx Context, id ID) (*T, error) {
+	return r.inner.GetByID(ctx, id)
+}
+
+func (r *EventRecordingRepository[T, ID]) Search(ctx Context, req *SearchRequest) (*PagedResult[T], error) {
+	return r.inner.Search(ctx, req)
+}
+
+func (r *EventRecordingRepository[T, ID]) Create(ctx Context, entity *T) error {
+	if err := r.inner.Create(ctx, entity); err != nil {
+		return err
+	}
+	if r.enabled() {
+		r.record(ctx, r.newEvent(ctx, EventCreate, entity, nil, entity))
+	}
+	return nil
+}
+
+func (r *EventRecordingRepository[T, ID]) CreateMultiple(ctx Context, entities []*T) error {
+	if err := r.inner.CreateMultiple(ctx, entities); err != nil {
+		return err
+	}
+	if r.enabled() {
+		events := make([]*DomainEvent, 0, len(entities))
+		for _, entity := range entities {
+			events = append(events, r.newEvent(ctx, EventCreate, entity, nil, entity))
+		}
+		r.record(ctx, events...)
+	}
+	return nil
+}
+
+// previous loads the stored version of entity so updates can be diffed
+func (r *EventRecordingRepository[T, ID]) previous(ctx Context, entity *T) *T {
+	before, err := r.inner.GetByID(ctx, (*entity).GetID())
+	if err != nil {
+		return nil
+	}
+	return before
+}
+
+func (r *EventRecordingRepository[T, ID]) Update(ctx Context, entity *T) error {
+	if !r.enabled() {
+		return r.inner.Update(ctx, entity)
+	}
+
+	before := r.previous(ctx, entity)
+	if err := r.inner.Update(ctx, entity); err != nil {
+		return err
+	}
+	r.record(ctx, r.newEvent(ctx, EventUpdate, entity, before, entity))
+	return nil
+}
+
+func (r *EventRecordingRepository[T, ID]) UpdateMultiple(ctx Context, entities []*T) error {
+	if !r.enabled() {
+		return r.inner.UpdateMultiple(ctx, entities)
+	}
+
+	befores := make([]*T, len(entities))
+	for i, entity := range entities {
+		befores[i] = r.previous(ctx, entity)
+	}
+	if err := r.inner.UpdateMultiple(ctx, entities); err != nil {
+		return err
+	}
+
+	events := make([]*DomainEvent, 0, len(entities))
+	for i, entity := range entities {
+		events = append(events, r.newEvent(ctx, EventUpdate, entity, befores[i], entity))
+	}
+	r.record(ctx, events...)
+	return nil
+}
+
+func (r *EventRecordingRepository[T, ID]) Delete(ctx Context, entity *T) error {
+	if err := r.inner.Delete(ctx, entity); err != nil {
+		return err
+	}
+	if r.enabled() {
+		r.record(ctx, r.newEvent(ctx, EventDelete, entity, entity, nil))
+	}
+	return nil
+}
+
+func (r *EventRecordingRepository[T, ID]) DeleteMultiple(ctx Context, entities []*T) error {
+	if err := r.inner.DeleteMultiple(ctx, entities); err != nil {
+		return err
+	}
+	if r.enabled() {
+		events := make([]*DomainEvent, 0, len(entities))
+		for _, entity := range entities {
+			events = append(events, r.newEvent(ctx, EventDelete, entity, entity, nil))
+		}
+		r.record(ctx, events...)
+	}
+	return nil
+}
+
+// UpdateWhere reads the matching rows first, in one query, so each update event carries its diff
+func (r *EventRecordingRepository[T, ID]) UpdateWhere(ctx Context, req *SearchRequest, changes map[string]any) ([]*T, error) {
+	if !r.enabled() || req == nil {
+		return r.inner.UpdateWhere(ctx, req, changes)
+	}
+
+	matches, err := r.inner.Search(ctx, &SearchRequest{Filters: req.Filters})
+	if err != nil {
+		return nil, err
+	}
+	befores := make(map[ID]*T, len(matches.Items))
+	for _, before := range matches.Items {
+		befores[(*before).GetID()] = before
+	}
+
+	updated, err := r.inner.UpdateWhere(ctx, req, changes)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*DomainEvent, 0, len(updated))
+	for _, entity := range updated {
+		events = append(events, r.newEvent(ctx, EventUpdate, entity, befores[(*entity).GetID()], entity))
+	}
+	r.record(ctx, events...)
+	return updated, nil
+}
+
+func (r *EventRecordingRepository[T, ID]) DeleteWhere(ctx Context, req *SearchRequest) ([]*T, error) {
+	deleted, err := r.inner.DeleteWhere(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if r.enabled() {
+		events := make([]*DomainEvent, 0, len(deleted))
+		for _, entity := range deleted {
+			events = append(events, r.newEvent(ctx, EventDelete, entity, entity, nil))
+		}
+		r.record(ctx, events...)
+	}
+	return deleted, nil
+}
+
+func (r *EventRecordingRepository[T, ID]) Upsert(ctx Context, entity *T) error {
+	if !r.enabled() {
+		return r.inner.Upsert(ctx, entity)
+	}
+
+	before := r.previous(ctx, entity)
+	if err := r.inner.Upsert(ctx, entity); err != nil {
+		return err
+	}
+
+	action := EventUpdate
+	if before == nil {
+		action = EventCreate
+	}
+	r.record(ctx, r.newEvent(ctx, action, entity, before, entity))
+	return nil
+}
+
+func (r *EventRecordingRepository[T, ID]) UpsertMultiple(ctx Context, entities []*T) error {
+	if !r.enabled() {
+		return r.inner.UpsertMultiple(ctx, entities)
+	}
+
+	befores := make([]*T, len(entities))
+	for i, entity := range entities {
+		befores[i] = r.previous(ctx, entity)
+	}
+	if err := r.inner.UpsertMultiple(ctx, entities); err != nil {
+		return err
+	}
+
+	events := make([]*DomainEvent, 0, len(entities))
+	for i, entity := range entities {
+		action := EventUpdate
+		if befores[i] == nil {
+			action = EventCreate
+		}
+		events = append(events, r.newEvent(ctx, action, entity, befores[i], entity))
+	}
+	r.record(ctx, events...)
+	return nil
+}
+
+// buildDiff returns a JSON object mapping each changed field to its old and new value.
+// A nil before or after records the full entity as new or old values respectively.
+func buildDiff(before, after interface{}) (string, error) {
+	old, err := toFieldMap(before)
+	if err != nil {
+		return "", err
+	}
+	updated, err := toFieldMap(after)
+	if err != nil {
+		return "", err
+	}
+
+	diff := make(map[string]map[string]interface{})
+	for field, value := range updated {
+		if previous, ok := old[field]; !ok || !reflect.DeepEqual(previous, value) {
+			diff[field] = map[string]interface{}{"old": old[field], "new": value}
+		}
+	}
+	for field, value := range old {
+		if _, ok := updated[field]; !ok {
+			diff[field] = map[string]interface{}{"old": value, "new": nil}
+		}
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func toFieldMap(entity interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if entity == nil || reflect.ValueOf(entity).IsNil() {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
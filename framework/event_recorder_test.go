@@ -0,0 +1,31 @@
+package framework
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDiff(t *testing.T) {
+	before := &TestSample{Name: "before", Status: "active", Count: 1}
+	after := &TestSample{Name: "after", Status: "active", Count: 2}
+
+	data, err := buildDiff(before, after)
+	require.NoError(t, err)
+
+	var diff map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(data), &diff))
+	assert.Equal(t, "before", diff["name"]["old"])
+	assert.Equal(t, "after", diff["name"]["new"])
+	assert.Contains(t, diff, "count")
+	assert.NotContains(t, diff, "status")
+
+	var missing *TestSample
+	data, err = buildDiff(missing, after)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(data), &diff))
+	assert.Equal(t, "active", diff["status"]["new"])
+	assert.Nil(t, diff["status"]["old"])
+}
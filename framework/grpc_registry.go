@@ -0,0 +1,311 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/config"
+	"github.com/yadunandan004/scaffold/rate_limiter"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/singleton"
+)
+
+// GRPCService is a service implementation plus the auth and transaction rules for its methods,
+// the gRPC counterpart of a RouteGroup
+type GRPCService struct {
+	Desc           *grpc.ServiceDesc
+	Impl           interface{}
+	ShouldSkipAuth bool
+	ShouldSkipTxn  bool
+	// SkipAuthMethods and SkipTxnMethods name single methods, e.g. "GetOrder"
+	SkipAuthMethods []string
+	SkipTxnMethods  []string
+}
+
+// GRPCRegistryConfig configures the server built by NewGRPCRegistry
+type GRPCRegistryConfig struct {
+	// Server supplies keepalive limits; zero values leave gRPC's defaults
+	Server config.GRPCConfig
+	// EnableReflection registers the server reflection service for tools like grpcurl
+	EnableReflection bool
+	// RateLimitRPS and RateLimitBurst throttle every call when both are set
+	RateLimitRPS   int
+	RateLimitBurst int
+	// UnaryInterceptors and StreamInterceptors run after the built-in chain, closest to the handler
+	UnaryInterceptors  []grpc.UnaryServerInterceptor
+	StreamInterceptors []grpc.StreamServerInterceptor
+	ServerOptions      []grpc.ServerOption
+}
+
+// GRPCRegistry builds a gRPC server with the same request handling as Registry: panic recovery,
+// error mapping, logging, rate limiting, authentication, a request.Context and a transaction per unary call.
+// Handlers get their request.Context with request.GetGRPCCtx.
+type GRPCRegistry struct {
+	auth   *auth.AuthService
+	server *grpc.Server
+	health *health.Server
+
+	mu       sync.RWMutex
+	skipAuth map[string]bool
+	skipTxn  map[string]bool
+}
+
+// NewGRPCRegistry creates the server and registers the health service, plus reflection when enabled
+func NewGRPCRegistry(authService *auth.AuthService, cfg GRPCRegistryConfig) *GRPCRegistry {
+	r := &GRPCRegistry{
+		auth:     authService,
+		health:   health.NewServer(),
+		skipAuth: make(map[string]bool),
+		skipTxn:  make(map[string]bool),
+	}
+	for _, method := range auth.DefaultSkipGRPCMethods {
+		r.skipAuth[method] = true
+		r.skipTxn[method] = true
+	}
+
+	unary := []grpc.UnaryServerInterceptor{
+		GRPCRecoveryUnary,
+		grpcLoggingUnary,
+		app_error.UnaryServerInterceptor(),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		GRPCRecoveryStream,
+		grpcLoggingStream,
+		app_error.StreamServerInterceptor(),
+	}
+	if cfg.RateLimitRPS > 0 && cfg.RateLimitBurst > 0 {
+		unary = append(unary, rate_limiter.NewUnaryRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst).UnaryInterceptor())
+		stream = append(stream, rate_limiter.NewStreamRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst).StreamInterceptor())
+	}
+	unary = append(unary, r.authUnary, r.contextUnary, r.txnUnary)
+	stream = append(stream, r.authStream, r.contextStream)
+	unary = append(unary, cfg.UnaryInterceptors...)
+	stream = append(stream, cfg.StreamInterceptors...)
+	// Recover again next to the handler, where the request.Context is available
+	unary = append(unary, GRPCRecoveryUnary)
+	stream = append(stream, GRPCRecoveryStream)
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if cfg.Server.MaxConnectionIdle > 0 || cfg.Server.MaxConnectionAge > 0 {
+		opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: time.Duration(cfg.Server.MaxConnectionIdle) * time.Second,
+			MaxConnectionAge:  time.Duration(cfg.Server.MaxConnectionAge) * time.Second,
+		}))
+	}
+	opts = append(opts, cfg.ServerOptions...)
+
+	r.server = grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(r.server, r.health)
+	if cfg.EnableReflection {
+		reflection.Register(r.server)
+	}
+	return r
+}
+
+// AddService registers a service and marks it SERVING in the health service.
+// Like grpc.Server.RegisterService it must be called before Serve.
+func (r *GRPCRegistry) AddService(svc GRPCService) {
+	skipAuth := toSet(svc.SkipAuthMethods)
+	skipTxn := toSet(svc.SkipTxnMethods)
+
+	var skippedAuth []string
+	r.mu.Lock()
+	for _, name := range grpcMethodNames(svc.Desc) {
+		fullMethod := fmt.Sprintf("/%s/%s", svc.Desc.ServiceName, name)
+		if svc.ShouldSkipAuth || skipAuth[name] {
+			r.skipAuth[fullMethod] = true
+			skippedAuth = append(skippedAuth, fullMethod)
+		}
+		if svc.ShouldSkipTxn || skipTxn[name] {
+			r.skipTxn[fullMethod] = true
+		}
+	}
+	r.mu.Unlock()
+
+	// Let a separately installed auth interceptor honor the same exemptions
+	if r.auth != nil && len(skippedAuth) > 0 {
+		r.auth.SkipGRPCMethods(skippedAuth...)
+	}
+
+	r.server.RegisterService(svc.Desc, svc.Impl)
+	r.health.SetServingStatus(svc.Desc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
+
+// Server returns the underlying gRPC server
+func (r *GRPCRegistry) Server() *grpc.Server {
+	return r.server
+}
+
+// Health returns the health service, for reporting per-service status changes
+func (r *GRPCRegistry) Health() *health.Server {
+	return r.health
+}
+
+// Serve accepts connections on lis until Shutdown
+func (r *GRPCRegistry) Serve(lis net.Listener) error {
+	r.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	return r.server.Serve(lis)
+}
+
+// ListenAndServe listens on the TCP address, e.g. ":9090", and serves
+func (r *GRPCRegistry) ListenAndServe(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("grpc listen on %s: %w", addr, err)
+	}
+	return r.Serve(lis)
+}
+
+// Shutdown marks every service NOT_SERVING and waits for in-flight calls to finish.
+// When ctx ends first, remaining calls are cancelled and ctx's error is returned.
+func (r *GRPCRegistry) Shutdown(ctx context.Context) error {
+	r.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		r.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		r.server.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (r *GRPCRegistry) shouldSkipAuth(fullMethod string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.skipAuth[fullMethod]
+}
+
+func (r *GRPCRegistry) shouldSkipTxn(fullMethod string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.skipTxn[fullMethod]
+}
+
+func (r *GRPCRegistry) authUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if r.shouldSkipAuth(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	if r.auth == nil {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return r.auth.GRPCInterceptor()(ctx, req, info, handler)
+}
+
+func (r *GRPCRegistry) authStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if r.shouldSkipAuth(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	if r.auth == nil {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return r.auth.GRPCStreamInterceptor()(srv, ss, info, handler)
+}
+
+// contextUnary attaches the request.Context, with a request scope closed once the call returns
+func (r *GRPCRegistry) contextUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	reqCtx := request.NewApiContextForGRPC(ctx)
+	setXIDHeader(reqCtx)
+	scope := singleton.BeginScope(reqCtx)
+	defer closeScope(reqCtx, scope)
+	return handler(context.WithValue(reqCtx.GetCtx(), request.GRPCCtxKey, reqCtx), req)
+}
+
+func (r *GRPCRegistry) contextStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	reqCtx := request.NewApiContextForGRPC(ss.Context())
+	setXIDHeader(reqCtx)
+	scope := singleton.BeginScope(reqCtx)
+	defer closeScope(reqCtx, scope)
+	ctx := context.WithValue(reqCtx.GetCtx(), request.GRPCCtxKey, reqCtx)
+	return handler(srv, &grpcServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// setXIDHeader returns the XID to the caller; a failure is logged rather than failing the call
+func setXIDHeader(reqCtx request.Context) {
+	if grpcCtx, ok := reqCtx.(*request.GRPCCtx); ok {
+		if err := grpcCtx.SetXIDHeader(); err != nil {
+			log.Printf("[GRPCRegistry] xid=%s: setting the xid header failed: %v", reqCtx.XID(), err)
+		}
+	}
+}
+
+// txnUnary runs the call in a transaction, committed when the handler returns no error.
+// Streams never get one, matching SSE and WebSocket routes.
+func (r *GRPCRegistry) txnUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if r.shouldSkipTxn(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	var resp interface{}
+	err := runInGRPCTxn(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func grpcLoggingUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	log.Printf("[GRPC] %s %s %v", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+func grpcLoggingStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	log.Printf("[GRPC] %s %s %v", info.FullMethod, status.Code(err), time.Since(start))
+	return err
+}
+
+// grpcServerStream swaps in a context carrying the request.Context
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func grpcMethodNames(desc *grpc.ServiceDesc) []string {
+	names := make([]string, 0, len(desc.Methods)+len(desc.Streams))
+	for _, method := range desc.Methods {
+		names = append(names, method.MethodName)
+	}
+	for _, stream := range desc.Streams {
+		names = append(names, stream.StreamName)
+	}
+	return names
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,176 @@
+package framework
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+type echoServer interface {
+	Echo(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+}
+
+type echoImpl struct{}
+
+func (echoImpl) Echo(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	switch in.Value {
+	case "panic":
+		panic("boom")
+	case "missing":
+		return nil, app_error.NotFound("Echo not found")
+	}
+	reqCtx, ok := request.GetGRPCCtx(ctx)
+	if !ok || reqCtx.XID().String() == "" {
+		return nil, status.Error(codes.FailedPrecondition, "no request context")
+	}
+	if in.Value == "details" {
+		// Report the deadline and peer in trailers, and echo the traceparent a downstream call would get
+		grpcCtx := reqCtx.(*request.GRPCCtx)
+		_, hasDeadline := grpcCtx.Deadline()
+		if err := grpcCtx.SetTrailer(metadata.Pairs("has-deadline", strconv.FormatBool(hasDeadline), "peer", grpcCtx.Peer().Addr.String())); err != nil {
+			return nil, err
+		}
+		outgoing, _ := metadata.FromOutgoingContext(request.OutgoingGRPCContext(reqCtx))
+		return wrapperspb.String(outgoing.Get("traceparent")[0]), nil
+	}
+	return wrapperspb.String(in.Value), nil
+}
+
+func echoDesc(serviceName string) *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*echoServer)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Echo",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(wrapperspb.StringValue)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Echo"}
+				return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(echoServer).Echo(ctx, req.(*wrapperspb.StringValue))
+				})
+			},
+		}},
+	}
+}
+
+func startGRPCRegistry(t *testing.T, registry *GRPCRegistry) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = registry.Serve(lis) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = registry.Shutdown(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCRegistry_ServesWithInterceptors(t *testing.T) {
+	registry := NewGRPCRegistry(nil, GRPCRegistryConfig{})
+	registry.AddService(GRPCService{Desc: echoDesc("test.Public"), Impl: echoImpl{}, ShouldSkipAuth: true, ShouldSkipTxn: true})
+	registry.AddService(GRPCService{Desc: echoDesc("test.Private"), Impl: echoImpl{}})
+	conn := startGRPCRegistry(t, registry)
+	ctx := context.Background()
+
+	out := new(wrapperspb.StringValue)
+	require.NoError(t, conn.Invoke(ctx, "/test.Public/Echo", wrapperspb.String("hi"), out))
+	assert.Equal(t, "hi", out.Value)
+
+	tests := []struct {
+		name   string
+		method string
+		value  string
+		code   codes.Code
+	}{
+		{"app error", "/test.Public/Echo", "missing", codes.NotFound},
+		{"panic", "/test.Public/Echo", "panic", codes.Internal},
+		{"requires auth", "/test.Private/Echo", "hi", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := conn.Invoke(ctx, tt.method, wrapperspb.String(tt.value), new(wrapperspb.StringValue))
+			assert.Equal(t, tt.code, status.Code(err), err)
+		})
+	}
+}
+
+func TestGRPCRegistry_RequestMetadata(t *testing.T) {
+	registry := NewGRPCRegistry(nil, GRPCRegistryConfig{})
+	registry.AddService(GRPCService{Desc: echoDesc("test.Public"), Impl: echoImpl{}, ShouldSkipAuth: true, ShouldSkipTxn: true})
+	conn := startGRPCRegistry(t, registry)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	var header, trailer metadata.MD
+	out := new(wrapperspb.StringValue)
+	require.NoError(t, conn.Invoke(ctx, "/test.Public/Echo", wrapperspb.String("details"), out, grpc.Header(&header), grpc.Trailer(&trailer)))
+
+	assert.Contains(t, out.Value, "4bf92f3577b34da6a3ce929d0e0e4736", "downstream calls join the caller's trace")
+	assert.Len(t, header.Get(request.XIDMetadataKey), 1)
+	assert.Equal(t, []string{"true"}, trailer.Get("has-deadline"))
+	assert.NotEmpty(t, trailer.Get("peer"))
+}
+
+func TestGRPCRegistry_Health(t *testing.T) {
+	registry := NewGRPCRegistry(nil, GRPCRegistryConfig{})
+	registry.AddService(GRPCService{Desc: echoDesc("test.Public"), Impl: echoImpl{}})
+	conn := startGRPCRegistry(t, registry)
+	client := healthpb.NewHealthClient(conn)
+
+	// Health checks are exempt from auth by default
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "test.Public"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "test.Unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	registry.Health().Shutdown()
+	resp, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "test.Public"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+func TestGRPCRegistry_ShutdownStopsServing(t *testing.T) {
+	registry := NewGRPCRegistry(nil, GRPCRegistryConfig{})
+	lis := bufconn.Listen(1 << 20)
+	served := make(chan error, 1)
+	go func() { served <- registry.Serve(lis) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, registry.Shutdown(ctx))
+
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("Serve did not return after Shutdown")
+	}
+}
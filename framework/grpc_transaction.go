@@ -0,0 +1,130 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// GRPCTxnMode is the transaction a gRPC method runs in
+type GRPCTxnMode int
+
+const (
+	// GRPCTxnAuto gives mutating methods a read-write transaction and read methods none; a method is
+	// a read when its name starts with one of GRPCTxnPolicy.ReadPrefixes
+	GRPCTxnAuto GRPCTxnMode = iota
+	GRPCTxnNone
+	GRPCTxnReadWrite
+	GRPCTxnReadOnly
+)
+
+// DefaultReadMethodPrefixes mark methods that don't mutate, e.g. GetOrder and ListOrders
+var DefaultReadMethodPrefixes = []string{"Get", "List", "Search", "Find", "Count", "Watch", "Check", "Describe"}
+
+// GRPCTxnPolicy decides the transaction of each method for GRPCTxnUnary and GRPCTxnStream
+type GRPCTxnPolicy struct {
+	// Methods sets the mode of full methods, "/pkg.Service/Method", or of whole services, "/pkg.Service/"
+	Methods map[string]GRPCTxnMode
+	// Default is the mode of methods not in Methods
+	Default GRPCTxnMode
+	// ReadPrefixes mark read methods for GRPCTxnAuto; defaults to DefaultReadMethodPrefixes
+	ReadPrefixes []string
+}
+
+// Mode returns the transaction mode of fullMethod, resolving GRPCTxnAuto
+func (p GRPCTxnPolicy) Mode(fullMethod string) GRPCTxnMode {
+	mode, ok := p.Methods[fullMethod]
+	if !ok {
+		service, _, _ := cutMethod(fullMethod)
+		if mode, ok = p.Methods[service]; !ok {
+			mode = p.Default
+		}
+	}
+	if mode != GRPCTxnAuto {
+		return mode
+	}
+
+	prefixes := p.ReadPrefixes
+	if prefixes == nil {
+		prefixes = DefaultReadMethodPrefixes
+	}
+	_, method, _ := cutMethod(fullMethod)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return GRPCTxnNone
+		}
+	}
+	return GRPCTxnReadWrite
+}
+
+// cutMethod splits "/pkg.Service/Method" into "/pkg.Service/" and "Method"
+func cutMethod(fullMethod string) (service, method string, ok bool) {
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 0 {
+		return "", fullMethod, false
+	}
+	return fullMethod[:i+1], fullMethod[i+1:], true
+}
+
+// GRPCTxnUnary runs each call in the transaction policy gives its method, stored under request.QueryKey
+// so request.GetQuery and the repositories use it. The transaction commits when the handler returns
+// no error and rolls back otherwise. Install it after the interceptor attaching the request.Context;
+// without one, a request.Context is created for the call.
+func GRPCTxnUnary(policy GRPCTxnPolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		mode := policy.Mode(info.FullMethod)
+		if mode == GRPCTxnNone {
+			return handler(ctx, req)
+		}
+		var resp interface{}
+		err := runInGRPCTxn(ctx, mode == GRPCTxnReadOnly, func(ctx context.Context) (err error) {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// GRPCTxnStream is the stream counterpart of GRPCTxnUnary; the transaction spans the whole stream
+func GRPCTxnStream(policy GRPCTxnPolicy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		mode := policy.Mode(info.FullMethod)
+		if mode == GRPCTxnNone {
+			return handler(srv, ss)
+		}
+		return runInGRPCTxn(ss.Context(), mode == GRPCTxnReadOnly, func(ctx context.Context) error {
+			return handler(srv, &grpcServerStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+}
+
+// runInGRPCTxn calls call with ctx carrying a new transaction, closed by call's error. A panic rolls
+// the transaction back and is rethrown for the recovery interceptor.
+func runInGRPCTxn(ctx context.Context, readOnly bool, call func(ctx context.Context) error) error {
+	reqCtx, ok := request.GetGRPCCtx(ctx)
+	if !ok {
+		reqCtx = request.NewApiContextForGRPC(ctx)
+	}
+	if _, err := request.BeginTransaction(reqCtx, request.TxOptions{ReadOnly: readOnly}); err != nil {
+		return app_error.Internal(fmt.Errorf("failed to start transaction: %w", err))
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			_ = reqCtx.CloseTxn(fmt.Errorf("panic: %v", rec))
+			panic(rec)
+		}
+	}()
+
+	// Hand the handler the transaction-carrying context so both lookups see it
+	err := call(context.WithValue(reqCtx.GetCtx(), request.GRPCCtxKey, reqCtx))
+	if closeErr := reqCtx.CloseTxn(err); closeErr != nil && err == nil {
+		return app_error.Internal(fmt.Errorf("failed to commit transaction: %w", closeErr))
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestGRPCTxnPolicy_Mode(t *testing.T) {
+	policy := GRPCTxnPolicy{
+		Methods: map[string]GRPCTxnMode{
+			"/orders.OrderService/GetOrderForUpdate": GRPCTxnReadWrite,
+			"/reports.ReportService/":                GRPCTxnReadOnly,
+			"/orders.OrderService/Ping":              GRPCTxnNone,
+		},
+	}
+
+	tests := []struct {
+		method string
+		want   GRPCTxnMode
+	}{
+		{"/orders.OrderService/CreateOrder", GRPCTxnReadWrite},
+		{"/orders.OrderService/GetOrder", GRPCTxnNone},
+		{"/orders.OrderService/ListOrders", GRPCTxnNone},
+		{"/orders.OrderService/GetOrderForUpdate", GRPCTxnReadWrite},
+		{"/orders.OrderService/Ping", GRPCTxnNone},
+		{"/reports.ReportService/BuildReport", GRPCTxnReadOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Mode(tt.method))
+		})
+	}
+
+	custom := GRPCTxnPolicy{Default: GRPCTxnAuto, ReadPrefixes: []string{"Fetch"}}
+	assert.Equal(t, GRPCTxnNone, custom.Mode("/orders.OrderService/FetchOrder"))
+	assert.Equal(t, GRPCTxnReadWrite, custom.Mode("/orders.OrderService/GetOrder"))
+	assert.Equal(t, GRPCTxnNone, GRPCTxnPolicy{Default: GRPCTxnNone}.Mode("/orders.OrderService/CreateOrder"))
+}
+
+func TestGRPCTxnUnary_ReadMethodRunsWithoutTransaction(t *testing.T) {
+	interceptor := GRPCTxnUnary(GRPCTxnPolicy{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.OrderService/GetOrder"}
+
+	resp, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Nil(t, ctx.Value(request.QueryKey{}))
+		return "resp", nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+}
@@ -0,0 +1,51 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yadunandan004/scaffold/health"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// Health probe paths served by AddHealthRoutes
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// AddHealthRoutes serves checker's liveness report at /healthz and readiness report at /readyz.
+// Both skip auth and transactions, and answer 503 when a critical check is down.
+func (r *Registry) AddHealthRoutes(checker *health.Checker) {
+	r.AddGroup(RouteGroup{
+		Name:     "health",
+		BasePath: "/",
+		RouteList: []Route{
+			{
+				Method:         "GET",
+				Path:           LivenessPath,
+				Handler:        healthHandler(checker.Liveness),
+				ShouldSkipAuth: true,
+				ShouldSkipTxn:  true,
+			},
+			{
+				Method:         "GET",
+				Path:           ReadinessPath,
+				Handler:        healthHandler(checker.Readiness),
+				ShouldSkipAuth: true,
+				ShouldSkipTxn:  true,
+			},
+		},
+	})
+}
+
+func healthHandler(probe func(ctx context.Context) health.Report) HandlerFunc {
+	return func(ctx request.Context) {
+		report := probe(ctx.GetCtx())
+		code := http.StatusOK
+		if report.Status == health.StatusDown {
+			code = http.StatusServiceUnavailable
+		}
+		ctx.JSON(code, report)
+	}
+}
@@ -0,0 +1,41 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/health"
+)
+
+func TestRegistry_AddHealthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+
+	checker := health.NewChecker(health.CheckerConfig{})
+	require.NoError(t, checker.Register(
+		health.Check{Name: "process", Check: func(context.Context) error { return nil }, Liveness: true},
+		health.Check{Name: "postgres", Check: func(context.Context) error { return errors.New("refused") }},
+	))
+	NewRegistry(engine, nil).AddHealthRoutes(checker)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", LivenessPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", ReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, "refused", report.Checks["postgres"].Error)
+}
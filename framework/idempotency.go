@@ -0,0 +1,362 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// IdempotencyKeyHeader is the request header clients use to make a POST or PUT safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyReplayedHeader is set on responses served from a stored record
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds client keys; UUIDs and ULIDs fit comfortably
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyKeysDDL creates the Postgres table PostgresIdempotencyStore writes to
+const IdempotencyKeysDDL = `CREATE TABLE IF NOT EXISTS idempotency_keys (
+	key VARCHAR(64) PRIMARY KEY,
+	fingerprint VARCHAR(64) NOT NULL,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	content_type VARCHAR(255) NOT NULL DEFAULT '',
+	body BYTEA,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys (expires_at)`
+
+// IdempotencyRecord is a completed response stored against an idempotency key
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request the key was first used with
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyStore keeps idempotency records and the claims held while a request is in flight
+type IdempotencyStore interface {
+	// Get returns the completed record for key, or nil when there is none
+	Get(ctx context.Context, key string) (*IdempotencyRecord, error)
+	// Lock claims key for a request with the given fingerprint until ttl elapses or unlock is called.
+	// Returns cache.ErrLockNotAcquired while another request holds the key.
+	Lock(ctx context.Context, key, fingerprint string, ttl time.Duration) (unlock func(context.Context) error, err error)
+	// Save stores the completed record for key until ttl elapses
+	Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+}
+
+// CacheIdempotencyStore keeps records in a CacheService, so every instance sharing the cache sees them
+type CacheIdempotencyStore struct {
+	cache cache.CacheService
+}
+
+func NewCacheIdempotencyStore(c cache.CacheService) *CacheIdempotencyStore {
+	return &CacheIdempotencyStore{cache: c}
+}
+
+func (s *CacheIdempotencyStore) Get(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	cached, err := s.cache.Get(ctx, "idempotency:"+key)
+	if errors.Is(err, cache.ErrKeyNotFound) || cached == nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cached.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected idempotency record type %T", cached)
+	}
+	var record IdempotencyRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Lock claims key with a single cache lock that expires after ttl, so keys from abandoned requests
+// leave nothing behind. A ttl of zero or less is rejected, as the claim would never expire.
+func (s *CacheIdempotencyStore) Lock(ctx context.Context, key, _ string, ttl time.Duration) (func(context.Context) error, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("idempotency lock needs a positive ttl, got %s", ttl)
+	}
+	unlocker, err := s.cache.Lock(ctx, "idempotency:"+key+":lock", ttl)
+	if err != nil {
+		return nil, err
+	}
+	return unlocker.Unlock, nil
+}
+
+func (s *CacheIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, "idempotency:"+key, string(data), ttl)
+}
+
+// PostgresIdempotencyStore keeps records in idempotency_keys; create it with IdempotencyKeysDDL.
+// Expired rows are reclaimed on reuse; call DeleteExpired periodically to remove the rest.
+type PostgresIdempotencyStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+func NewPostgresIdempotencyStore(db *sql.DB) *PostgresIdempotencyStore {
+	return &PostgresIdempotencyStore{db: db, now: time.Now}
+}
+
+func (s *PostgresIdempotencyStore) Get(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	var record IdempotencyRecord
+	err := s.db.QueryRowContext(ctx, `
+		SELECT fingerprint, status_code, content_type, body FROM idempotency_keys
+		WHERE key = $1 AND status_code <> 0 AND expires_at > $2
+	`, key, s.now()).Scan(&record.Fingerprint, &record.StatusCode, &record.ContentType, &record.Body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+	return &record, nil
+}
+
+func (s *PostgresIdempotencyStore) Lock(ctx context.Context, key, fingerprint string, ttl time.Duration) (func(context.Context) error, error) {
+	now := s.now()
+	// A pending row is the claim; it can be taken over once it or a completed record has expired
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, fingerprint, status_code, content_type, body, expires_at)
+		VALUES ($1, $2, 0, '', NULL, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status_code = 0, content_type = '', body = NULL, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= $4
+	`, key, fingerprint, now.Add(ttl), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if affected == 0 {
+		return nil, cache.ErrLockNotAcquired
+	}
+	// Drop the pending row unless the response was saved
+	return func(ctx context.Context) error {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND status_code = 0`, key)
+		return err
+	}, nil
+}
+
+func (s *PostgresIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET fingerprint = $2, status_code = $3, content_type = $4, body = $5, expires_at = $6
+		WHERE key = $1
+	`, key, record.Fingerprint, record.StatusCode, record.ContentType, record.Body, s.now().Add(ttl))
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes expired records and abandoned claims
+func (s *PostgresIdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, s.now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+var (
+	_ IdempotencyStore = (*CacheIdempotencyStore)(nil)
+	_ IdempotencyStore = (*PostgresIdempotencyStore)(nil)
+)
+
+// IdempotencyOptions configures the Idempotency middleware
+type IdempotencyOptions struct {
+	// Header carrying the client key; defaults to IdempotencyKeyHeader
+	Header string
+	// TTL is how long responses are replayed; defaults to 24 hours
+	TTL time.Duration
+	// LockTTL bounds how long an in-flight request holds its key; defaults to one minute
+	LockTTL time.Duration
+	// Required rejects POST and PUT requests without a key
+	Required bool
+}
+
+// Idempotency records the response of POST and PUT requests carrying an Idempotency-Key header and replays
+// it when the request is retried with the same key, so a client re-sending after a timeout creates nothing twice.
+// Keys are scoped to the caller. A retry that arrives while the first request is still running gets 409,
+// and reusing a key with a different request gets 422. Server errors and 429s are not recorded, so those
+// requests can be retried.
+func Idempotency(store IdempotencyStore, opts IdempotencyOptions) Middleware {
+	if opts.Header == "" {
+		opts.Header = IdempotencyKeyHeader
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = time.Minute
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx request.Context) {
+			ginCtx := ctx.GetGinContext()
+			if ginCtx == nil || (ginCtx.Request.Method != http.MethodPost && ginCtx.Request.Method != http.MethodPut) {
+				next(ctx)
+				return
+			}
+			clientKey := ginCtx.GetHeader(opts.Header)
+			if clientKey == "" {
+				if opts.Required {
+					RespondError(ctx, app_error.InvalidArgument(opts.Header+" header is required"))
+					return
+				}
+				next(ctx)
+				return
+			}
+			if len(clientKey) > maxIdempotencyKeyLength {
+				RespondError(ctx, app_error.InvalidArgument(fmt.Sprintf("%s must be at most %d characters", opts.Header, maxIdempotencyKeyLength)))
+				return
+			}
+
+			fingerprint, err := requestFingerprint(ginCtx)
+			if err != nil {
+				RespondError(ctx, err)
+				return
+			}
+			key := idempotencyKey(ctx, clientKey)
+
+			record, err := store.Get(ctx.GetCtx(), key)
+			if err != nil {
+				log.Printf("[Idempotency] xid=%s: %v", ctx.XID(), err)
+				RespondError(ctx, app_error.Unavailable(""))
+				return
+			}
+			if record != nil {
+				replayIdempotent(ctx, ginCtx, record, fingerprint)
+				return
+			}
+
+			unlock, err := store.Lock(ctx.GetCtx(), key, fingerprint, opts.LockTTL)
+			if errors.Is(err, cache.ErrLockNotAcquired) {
+				// The holder may have just completed
+				if record, _ := store.Get(ctx.GetCtx(), key); record != nil {
+					replayIdempotent(ctx, ginCtx, record, fingerprint)
+					return
+				}
+				RespondError(ctx, app_error.Conflict("A request with this "+opts.Header+" is already in progress"))
+				return
+			}
+			if err != nil {
+				log.Printf("[Idempotency] xid=%s: %v", ctx.XID(), err)
+				RespondError(ctx, app_error.Unavailable(""))
+				return
+			}
+			defer func() {
+				if err := unlock(context.WithoutCancel(ctx.GetCtx())); err != nil {
+					log.Printf("[Idempotency] xid=%s: failed to release key: %v", ctx.XID(), err)
+				}
+			}()
+
+			// The previous holder may have completed between Get and Lock
+			if record, err = store.Get(ctx.GetCtx(), key); err == nil && record != nil {
+				replayIdempotent(ctx, ginCtx, record, fingerprint)
+				return
+			}
+
+			writer := &capturingResponseWriter{ResponseWriter: ginCtx.Writer}
+			ginCtx.Writer = writer
+			defer func() { ginCtx.Writer = writer.ResponseWriter }()
+
+			next(ctx)
+
+			status := writer.Status()
+			if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+				return
+			}
+			record = &IdempotencyRecord{
+				Fingerprint: fingerprint,
+				StatusCode:  status,
+				ContentType: writer.Header().Get("Content-Type"),
+				Body:        writer.body.Bytes(),
+			}
+			if err := store.Save(context.WithoutCancel(ctx.GetCtx()), key, record, opts.TTL); err != nil {
+				log.Printf("[Idempotency] xid=%s: failed to save response: %v", ctx.XID(), err)
+			}
+		}
+	}
+}
+
+func replayIdempotent(ctx request.Context, ginCtx *gin.Context, record *IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		RespondError(ctx, app_error.InvalidArgument("Idempotency key was already used with a different request").
+			WithStatus(http.StatusUnprocessableEntity))
+		return
+	}
+	ginCtx.Header(IdempotencyReplayedHeader, "true")
+	ginCtx.Data(record.StatusCode, record.ContentType, record.Body)
+}
+
+// idempotencyKey scopes the client's key to the caller and hashes it to a fixed length
+func idempotencyKey(ctx request.Context, clientKey string) string {
+	subject := principalKey(ctx)
+	if subject == "" {
+		subject = "anonymous"
+	}
+	sum := sha256.Sum256([]byte(subject + "|" + clientKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestFingerprint hashes the method, path and body, restoring the body for the handler
+func requestFingerprint(ginCtx *gin.Context) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(ginCtx.Request.Method + " " + ginCtx.Request.URL.RequestURI() + "\n"))
+	if ginCtx.Request.Body != nil {
+		body, err := io.ReadAll(ginCtx.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return "", bodyTooLarge(maxBytesErr.Limit)
+			}
+			return "", app_error.InvalidArgument("Failed to read request body")
+		}
+		ginCtx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// capturingResponseWriter keeps a copy of the response body as it is written
+type capturingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
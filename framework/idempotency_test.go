@@ -0,0 +1,140 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func newIdempotencyEngine(t *testing.T, store IdempotencyStore, handler HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewRegistry(engine, nil).AddGroup(RouteGroup{
+		Name:       "test",
+		BasePath:   "/api",
+		Middleware: []Middleware{Idempotency(store, IdempotencyOptions{})},
+		RouteList: []Route{
+			{Method: "POST", Path: "/orders", Handler: handler, ShouldSkipAuth: true, ShouldSkipTxn: true},
+		},
+	})
+	return engine
+}
+
+func postOrder(engine *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	calls := 0
+	engine := newIdempotencyEngine(t, NewCacheIdempotencyStore(local.NewLocalCache(nil)), func(ctx request.Context) {
+		calls++
+		ctx.JSON(http.StatusCreated, gin.H{"order": calls})
+	})
+
+	first := postOrder(engine, "key-1", `{"item":"book"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	retry := postOrder(engine, "key-1", `{"item":"book"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(IdempotencyReplayedHeader))
+	assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	assert.Equal(t, 1, calls)
+
+	// A new key or no key runs the handler again
+	assert.Equal(t, `{"order":2}`, postOrder(engine, "key-2", `{"item":"book"}`).Body.String())
+	assert.Equal(t, `{"order":3}`, postOrder(engine, "", `{"item":"book"}`).Body.String())
+}
+
+func TestIdempotency_RejectsReuseWithDifferentRequest(t *testing.T) {
+	engine := newIdempotencyEngine(t, NewCacheIdempotencyStore(local.NewLocalCache(nil)), func(ctx request.Context) {
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	require.Equal(t, http.StatusCreated, postOrder(engine, "key-1", `{"item":"book"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, postOrder(engine, "key-1", `{"item":"pen"}`).Code)
+}
+
+func TestIdempotency_ConflictWhileInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	engine := newIdempotencyEngine(t, NewCacheIdempotencyStore(local.NewLocalCache(nil)), func(ctx request.Context) {
+		if ctx.GetGinContext().Request.Header.Get("X-Block") != "" {
+			close(started)
+			<-release
+		}
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		req := httptest.NewRequest("POST", "/api/orders", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req.Header.Set("X-Block", "1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		done <- w
+	}()
+	<-started
+
+	w := postOrder(engine, "key-1", `{}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), string(app_error.CodeConflict))
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+}
+
+func TestIdempotency_DoesNotRecordServerErrors(t *testing.T) {
+	calls := 0
+	engine := newIdempotencyEngine(t, NewCacheIdempotencyStore(local.NewLocalCache(nil)), HandleError(func(ctx request.Context) error {
+		calls++
+		if calls == 1 {
+			return app_error.Unavailable("")
+		}
+		ctx.JSON(http.StatusCreated, gin.H{})
+		return nil
+	}))
+
+	assert.Equal(t, http.StatusServiceUnavailable, postOrder(engine, "key-1", `{}`).Code)
+	assert.Equal(t, http.StatusCreated, postOrder(engine, "key-1", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCacheIdempotencyStore_LockExpires(t *testing.T) {
+	store := NewCacheIdempotencyStore(local.NewLocalCache(nil))
+	ctx := context.Background()
+
+	_, err := store.Lock(ctx, "key-1", "fp", 0)
+	assert.Error(t, err)
+
+	_, err = store.Lock(ctx, "key-1", "fp", 20*time.Millisecond)
+	require.NoError(t, err)
+	_, err = store.Lock(ctx, "key-1", "fp", time.Minute)
+	assert.ErrorIs(t, err, cache.ErrLockNotAcquired)
+
+	time.Sleep(30 * time.Millisecond)
+	unlock, err := store.Lock(ctx, "key-1", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.NoError(t, unlock(ctx))
+}
@@ -0,0 +1,55 @@
+package framework
+
+import (
+	"net/http"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/logger"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// LogLevelPath is where AddLogLevelRoutes serves the runtime log levels
+const LogLevelPath = "/admin/log-level"
+
+// AddLogLevelRoutes serves the current log levels on GET /admin/log-level and changes them on PUT
+// with a logger.LevelConfig body. Both routes require permissions and skip transactions.
+func (r *Registry) AddLogLevelRoutes(permissions ...string) {
+	r.AddGroup(RouteGroup{
+		Name:     "log-level",
+		BasePath: "/",
+		RouteList: []Route{
+			{
+				Method:        "GET",
+				Path:          LogLevelPath,
+				Handler:       getLogLevels,
+				ShouldSkipTxn: true,
+				Permissions:   permissions,
+			},
+			{
+				Method:        "PUT",
+				Path:          LogLevelPath,
+				Handler:       putLogLevels,
+				ShouldSkipTxn: true,
+				Permissions:   permissions,
+			},
+		},
+	})
+}
+
+func getLogLevels(ctx request.Context) {
+	ctx.JSON(http.StatusOK, logger.Levels())
+}
+
+func putLogLevels(ctx request.Context) {
+	var cfg logger.LevelConfig
+	if err := ctx.GetRequestContext().ShouldBindJSON(&cfg); err != nil {
+		RespondError(ctx, app_error.InvalidArgument(err.Error()))
+		return
+	}
+	if err := logger.ApplyLevels(cfg); err != nil {
+		RespondError(ctx, app_error.InvalidArgument(err.Error()))
+		return
+	}
+	logger.LogInfo(ctx, "Log levels changed to %+v", logger.Levels())
+	ctx.JSON(http.StatusOK, logger.Levels())
+}
@@ -0,0 +1,56 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/logger"
+)
+
+func TestRegistry_AddLogLevelRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	permissions := auth.NewPermissionRegistry()
+	permissions.RegisterPermission("logs:admin", "Change log levels")
+	require.NoError(t, permissions.DefineRole("operator", "logs:admin"))
+	previousPermissions := auth.GetPermissionRegistry()
+	auth.SetPermissionRegistry(permissions)
+	defer auth.SetPermissionRegistry(previousPermissions)
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set(auth.UserClaimsKey, &auth.UserClaims{UserID: uuid.New(), Roles: []string{"operator"}})
+	})
+	NewRegistry(engine, &auth.AuthService{}).AddLogLevelRoutes("logs:admin")
+
+	previous := logger.Levels()
+	t.Cleanup(func() {
+		_ = logger.SetModuleLevel("orm", "")
+		_ = logger.ApplyLevels(previous)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("PUT", LogLevelPath, strings.NewReader(`{"level":"warn","modules":{"orm":"debug"}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", LogLevelPath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var levels logger.LevelConfig
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &levels))
+	assert.Equal(t, logger.LevelConfig{Level: "warn", Modules: map[string]string{"orm": "debug"}}, levels)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("PUT", LogLevelPath, strings.NewReader(`{"level":"loud"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "warn", logger.Levels().Level)
+}
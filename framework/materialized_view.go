@@ -0,0 +1,82 @@
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// materializedViews holds the refresh of every model registered with RegisterMaterializedView, keyed by view name
+var materializedViews sync.Map
+
+// RegisterMaterializedView registers T, a read model backed by a materialized view, with the ORM and
+// records it for RefreshMaterializedViews. Serve it with PostgresReadOnlyRepository; Postgres rejects writes.
+func RegisterMaterializedView[T tableModel]() {
+	orm.RegisterModel[T]()
+	var model T
+	materializedViews.Store(model.TableName(), RefreshMaterializedView[T])
+}
+
+// RefreshMaterializedView refreshes T's view in the request transaction, or on the primary without one.
+// A concurrent refresh keeps the view readable meanwhile but needs a unique index on it.
+func RefreshMaterializedView[T tableModel](ctx Context, concurrently bool) error {
+	if orm.GetMetadata[T]() == nil {
+		var model T
+		return fmt.Errorf("no orm metadata registered for %T", model)
+	}
+	table := qualifiedTable[T](ctx)
+
+	var err error
+	if query := ctx.GetPgTxn(); query != nil {
+		_, err = query.Exec(refreshSQL(table, concurrently))
+	} else {
+		db := postgres.GetDB()
+		if db == nil || db.DB == nil {
+			return fmt.Errorf("no database connection available")
+		}
+		_, err = db.ExecContext(ctx.GetCtx(), refreshSQL(table, concurrently))
+	}
+	if err != nil {
+		return fmt.Errorf("refresh materialized view %s: %w", table, err)
+	}
+	return nil
+}
+
+// MaterializedViewRefreshTask returns a task refreshing T's view, for registering with a jobs.Scheduler
+func MaterializedViewRefreshTask[T tableModel](concurrently bool) func(ctx Context) error {
+	return func(ctx Context) error {
+		return RefreshMaterializedView[T](ctx, concurrently)
+	}
+}
+
+// RefreshMaterializedViews refreshes every registered view in name order, stopping at the first failure
+func RefreshMaterializedViews(ctx Context, concurrently bool) error {
+	for _, name := range MaterializedViews() {
+		refresh, _ := materializedViews.Load(name)
+		if err := refresh.(func(Context, bool) error)(ctx, concurrently); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaterializedViews returns the names of the registered views, sorted
+func MaterializedViews() []string {
+	var names []string
+	materializedViews.Range(func(key, _ any) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+func refreshSQL(table string, concurrently bool) string {
+	if concurrently {
+		return "REFRESH MATERIALIZED VIEW CONCURRENTLY " + table
+	}
+	return "REFRESH MATERIALIZED VIEW " + table
+}
@@ -0,0 +1,39 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+type salesSummary struct {
+	Region string `orm:"column:region;pk"`
+	Total  int64  `orm:"column:total"`
+}
+
+func (salesSummary) TableName() string {
+	return "sales_summaries"
+}
+
+type unregisteredView struct{}
+
+func (unregisteredView) TableName() string {
+	return "unregistered_views"
+}
+
+func TestRegisterMaterializedView(t *testing.T) {
+	RegisterMaterializedView[salesSummary]()
+	assert.Contains(t, MaterializedViews(), "sales_summaries")
+}
+
+func TestRefreshSQL(t *testing.T) {
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW sales_summaries", refreshSQL("sales_summaries", false))
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW CONCURRENTLY reporting.sales_summaries", refreshSQL("reporting.sales_summaries", true))
+}
+
+func TestRefreshMaterializedView_RequiresRegistration(t *testing.T) {
+	err := MaterializedViewRefreshTask[unregisteredView](false)(request.NewTestContext())
+	assert.ErrorContains(t, err, "no orm metadata registered")
+}
@@ -0,0 +1,46 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// Middleware wraps a route handler. The registry runs route middleware after authentication and
+// permission checks and before the route's transaction starts, so a middleware that responds without
+// calling next never opens a transaction.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Chain wraps handler in middleware, the first one outermost
+func Chain(handler HandlerFunc, middleware ...Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// MaxBodySize rejects request bodies larger than limit bytes with 413.
+// Bodies without a Content-Length are cut off at the limit, and binding them fails the same way.
+func MaxBodySize(limit int64) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx request.Context) {
+			ginCtx := ctx.GetGinContext()
+			if ginCtx == nil || ginCtx.Request.Body == nil {
+				next(ctx)
+				return
+			}
+			if ginCtx.Request.ContentLength > limit {
+				RespondError(ctx, bodyTooLarge(limit))
+				return
+			}
+			ginCtx.Request.Body = http.MaxBytesReader(ginCtx.Writer, ginCtx.Request.Body, limit)
+			next(ctx)
+		}
+	}
+}
+
+func bodyTooLarge(limit int64) *app_error.AppError {
+	return app_error.InvalidArgument(fmt.Sprintf("Request body exceeds %d bytes", limit)).WithStatus(http.StatusRequestEntityTooLarge)
+}
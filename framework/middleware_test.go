@@ -0,0 +1,138 @@
+package framework
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx request.Context) {
+			*calls = append(*calls, name+":before")
+			next(ctx)
+			*calls = append(*calls, name+":after")
+		}
+	}
+}
+
+func TestRegistry_RunsMiddlewareInOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	var calls []string
+
+	route := Route{
+		Method:         "GET",
+		Path:           "/test",
+		Handler:        func(ctx request.Context) { calls = append(calls, "handler"); ctx.JSON(200, gin.H{}) },
+		ShouldSkipAuth: true,
+		ShouldSkipTxn:  true,
+	}.Use(recordingMiddleware("route", &calls))
+
+	NewRegistry(engine, nil).AddGroup(RouteGroup{
+		Name:       "test",
+		BasePath:   "/api",
+		RouteList:  []Route{route},
+		Middleware: []Middleware{recordingMiddleware("group1", &calls), recordingMiddleware("group2", &calls)},
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/test", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, []string{
+		"group1:before", "group2:before", "route:before", "handler", "route:after", "group2:after", "group1:after",
+	}, calls)
+}
+
+func TestRegistry_MiddlewareCanStopRequest(t *testing.T) {
+	handlerCalled := false
+	deny := func(next HandlerFunc) HandlerFunc {
+		return func(ctx request.Context) {
+			RespondError(ctx, app_error.PermissionDenied("Maintenance window"))
+		}
+	}
+
+	code, body := serveErrorRoute(t, nil, Route{
+		Method:         "GET",
+		Path:           "/test",
+		Handler:        func(ctx request.Context) { handlerCalled = true },
+		ShouldSkipAuth: true,
+		Middleware:     []Middleware{deny},
+	})
+
+	assert.False(t, handlerCalled)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "Maintenance window", body.Error.Message)
+}
+
+func TestRegistry_RecoversMiddlewarePanics(t *testing.T) {
+	code, body := serveErrorRoute(t, nil, Route{
+		Method:         "GET",
+		Path:           "/test",
+		Handler:        func(ctx request.Context) {},
+		ShouldSkipAuth: true,
+		ShouldSkipTxn:  true,
+		Middleware:     []Middleware{func(next HandlerFunc) HandlerFunc { return func(request.Context) { panic("bad middleware") } }},
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, app_error.CodeInternal, body.Error.Code)
+}
+
+func TestMaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewRegistry(engine, nil).AddGroup(RouteGroup{
+		Name:     "test",
+		BasePath: "/api",
+		RouteList: []Route{{
+			Method: "POST",
+			Path:   "/test",
+			Handler: HandleError(func(ctx request.Context) error {
+				payload, err := BindAndValidate[map[string]string](ctx)
+				if err != nil {
+					return err
+				}
+				ctx.JSON(200, payload)
+				return nil
+			}),
+			ShouldSkipAuth: true,
+			ShouldSkipTxn:  true,
+			Middleware:     []Middleware{MaxBodySize(32)},
+		}},
+	})
+
+	tests := []struct {
+		name    string
+		body    string
+		chunked bool
+		code    int
+	}{
+		{"within limit", `{"name":"ada"}`, false, http.StatusOK},
+		{"declared too large", `{"name":"` + strings.Repeat("a", 64) + `"}`, false, http.StatusRequestEntityTooLarge},
+		{"chunked too large", `{"name":"` + strings.Repeat("a", 64) + `"}`, true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/test", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				// Unknown length, so only the body reader can enforce the limit
+				req.Body = io.NopCloser(strings.NewReader(tt.body))
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+		})
+	}
+}
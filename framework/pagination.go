@@ -0,0 +1,58 @@
+package framework
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/app_error"
+)
+
+// PagedResult is one page of search results.
+// Total is only set when the request asked for it, and NextCursor is empty on the last page.
+type PagedResult[T any] struct {
+	Items      []*T   `json:"items"`
+	Total      *int64 `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Take       int    `json:"take,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPagedResult wraps items with the paging fields of req
+func NewPagedResult[T any](items []*T, req *SearchRequest) *PagedResult[T] {
+	if items == nil {
+		items = []*T{}
+	}
+	result := &PagedResult[T]{Items: items}
+	if req != nil {
+		result.Page = req.Page
+		result.Take = req.Take
+	}
+	return result
+}
+
+// WithTotal sets Total
+func (p *PagedResult[T]) WithTotal(total int64) *PagedResult[T] {
+	p.Total = &total
+	return p
+}
+
+const cursorPrefix = "o:"
+
+// EncodeCursor returns an opaque cursor that resumes a search at offset
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor returns the offset encoded by EncodeCursor
+func DecodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return 0, app_error.InvalidArgument("Invalid cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(data), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, app_error.InvalidArgument("Invalid cursor")
+	}
+	return offset, nil
+}
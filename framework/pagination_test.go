@@ -0,0 +1,52 @@
+package framework
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	offset, err := DecodeCursor(EncodeCursor(150))
+	require.NoError(t, err)
+	assert.Equal(t, 150, offset)
+
+	for _, cursor := range []string{"", "not base64!", EncodeCursor(-1), "bzphYmM"} {
+		_, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, app_error.InvalidArgument(""), cursor)
+	}
+}
+
+func TestSearchRequest_Offset(t *testing.T) {
+	offset, err := NewSearchRequest().WithPage(3).WithTake(20).Offset()
+	require.NoError(t, err)
+	assert.Equal(t, 40, offset)
+
+	// A cursor wins over the page number
+	offset, err = NewSearchRequest().WithPage(3).WithTake(20).WithCursor(EncodeCursor(7)).Offset()
+	require.NoError(t, err)
+	assert.Equal(t, 7, offset)
+}
+
+func TestPagedResult_JSON(t *testing.T) {
+	data, err := json.Marshal(NewPagedResult[TestSample](nil, NewSearchRequest()))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items": []}`, string(data))
+
+	page := NewPagedResult([]*TestSample{{Name: "a"}}, NewSearchRequest().WithPage(2).WithTake(1)).WithTotal(0)
+	page.NextCursor = EncodeCursor(2)
+	data, err = json.Marshal(page)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, float64(0), decoded["total"])
+	assert.Equal(t, float64(2), decoded["page"])
+	assert.Equal(t, float64(1), decoded["take"])
+	assert.Equal(t, page.NextCursor, decoded["next_cursor"])
+	assert.Len(t, decoded["items"], 1)
+}
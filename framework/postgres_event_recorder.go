@@ -0,0 +1,229 @@
+package framework
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yadunandan004/scaffold/orm"
+)
+
+// DomainEventsPostgresDDL creates the Postgres table PostgresEventRecorder writes DomainEvent to
+const DomainEventsPostgresDDL = `CREATE TABLE IF NOT EXISTS domain_events (
+	id UUID PRIMARY KEY,
+	table_name VARCHAR(100) NOT NULL,
+	entity_id VARCHAR(255) NOT NULL,
+	action VARCHAR(20) NOT NULL,
+	actor VARCHAR(255) NOT NULL DEFAULT '',
+	xid UUID,
+	trace_id VARCHAR(255) NOT NULL DEFAULT '',
+	diff TEXT NOT NULL DEFAULT '',
+	occurred_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_domain_events_entity ON domain_events (table_name, entity_id, occurred_at)`
+
+// PostgresEventRecorderConfig configures a PostgresEventRecorder
+type PostgresEventRecorderConfig struct {
+	// BatchSize flushes once this many events are buffered (default 500, at most 7000 so one INSERT stays
+	// under the Postgres parameter limit)
+	BatchSize int
+
+	// FlushInterval flushes buffered events periodically (default 1s)
+	FlushInterval time.Duration
+
+	// BufferSize is the capacity of the event channel; events are dropped while it is full (default 10000)
+	BufferSize int
+
+	// Sync inserts events within Record instead of buffering them, for tests that read events back
+	Sync bool
+
+	// OnError is called when events are dropped, either on a failed insert or a full buffer (default logs)
+	OnError func(err error, events int)
+}
+
+// PostgresEventRecorder buffers domain events on a channel and inserts them into domain_events in batches,
+// so recording never adds an INSERT to the write path. Call Close on shutdown to flush what is buffered.
+type PostgresEventRecorder struct {
+	insert  func(ctx context.Context, events []*DomainEvent) error
+	config  PostgresEventRecorderConfig
+	events  chan *DomainEvent
+	flushes chan chan error
+
+	// mu orders Record against Close so no event is sent after the flush loop drains the channel
+	mu     sync.RWMutex
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewPostgresEventRecorder creates a recorder inserting into db and starts its flush loop;
+// create the table with DomainEventsPostgresDDL
+func NewPostgresEventRecorder(db *sql.DB, cfg PostgresEventRecorderConfig) *PostgresEventRecorder {
+	if orm.GetMetadata[DomainEvent]() == nil {
+		orm.RegisterModel[DomainEvent]()
+	}
+	return newPostgresEventRecorder(orm.NewDB[DomainEvent](db).CreateMultiple, cfg)
+}
+
+func newPostgresEventRecorder(insert func(ctx context.Context, events []*DomainEvent) error, cfg PostgresEventRecorderConfig) *PostgresEventRecorder {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.BatchSize > 7000 {
+		cfg.BatchSize = 7000
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 10000
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(err error, events int) {
+			log.Printf("[EventRecorder] dropped %d events: %v", events, err)
+		}
+	}
+
+	r := &PostgresEventRecorder{
+		insert:  insert,
+		config:  cfg,
+		events:  make(chan *DomainEvent, cfg.BufferSize),
+		flushes: make(chan chan error),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if cfg.Sync {
+		r.closed = true
+		close(r.done)
+	} else {
+		go r.flushLoop()
+	}
+	return r
+}
+
+// Record queues events for the next batch. In Sync mode, or after Close, it inserts them directly.
+func (r *PostgresEventRecorder) Record(ctx context.Context, events ...*DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		return r.write(ctx, events)
+	}
+	defer r.mu.RUnlock()
+
+	for i, event := range events {
+		select {
+		case r.events <- event:
+		default:
+			err := fmt.Errorf("event buffer full")
+			r.config.OnError(err, len(events)-i)
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush inserts everything buffered so far
+func (r *PostgresEventRecorder) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case r.flushes <- reply:
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the flush loop after inserting buffered events. Later Records insert synchronously.
+func (r *PostgresEventRecorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.stop)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *PostgresEventRecorder) flushLoop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*DomainEvent, 0, r.config.BatchSize)
+	flush := func() error {
+		// Pick up whatever is queued so a flush covers every event recorded before it
+	drain:
+		for len(batch) < r.config.BatchSize {
+			select {
+			case event := <-r.events:
+				batch = append(batch, event)
+			default:
+				break drain
+			}
+		}
+		err := r.write(context.Background(), batch)
+		batch = make([]*DomainEvent, 0, r.config.BatchSize)
+		return err
+	}
+
+	for {
+		select {
+		case event := <-r.events:
+			batch = append(batch, event)
+			if len(batch) >= r.config.BatchSize {
+				_ = flush()
+			}
+		case <-ticker.C:
+			_ = flush()
+		case reply := <-r.flushes:
+			var err error
+			for {
+				if err = flush(); err != nil || len(r.events) == 0 {
+					break
+				}
+			}
+			reply <- err
+		case <-r.stop:
+			for {
+				_ = flush()
+				if len(r.events) == 0 {
+					return
+				}
+			}
+		}
+	}
+}
+
+// write inserts events, reporting failures through OnError; errors never reach the recorded write
+func (r *PostgresEventRecorder) write(ctx context.Context, events []*DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.insert(ctx, events); err != nil {
+		err = fmt.Errorf("insert %d domain events: %w", len(events), err)
+		r.config.OnError(err, len(events))
+		return err
+	}
+	return nil
+}
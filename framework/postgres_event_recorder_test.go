@@ -0,0 +1,113 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventInserter struct {
+	mu      sync.Mutex
+	batches [][]*DomainEvent
+	err     error
+}
+
+func (f *fakeEventInserter) insert(ctx context.Context, events []*DomainEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.batches = append(f.batches, append([]*DomainEvent(nil), events...))
+	return nil
+}
+
+func (f *fakeEventInserter) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sizes := make([]int, len(f.batches))
+	for i, batch := range f.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func newTestEvents(n int) []*DomainEvent {
+	events := make([]*DomainEvent, n)
+	for i := range events {
+		events[i] = &DomainEvent{ID: uuid.New(), Table: "test_samples", Action: EventCreate}
+	}
+	return events
+}
+
+func TestPostgresEventRecorder_BatchesBySize(t *testing.T) {
+	inserter := &fakeEventInserter{}
+	recorder := newPostgresEventRecorder(inserter.insert, PostgresEventRecorderConfig{BatchSize: 3, FlushInterval: time.Hour})
+	defer recorder.Close(context.Background())
+
+	require.NoError(t, recorder.Record(context.Background(), newTestEvents(7)...))
+	require.NoError(t, recorder.Flush(context.Background()))
+
+	sizes := inserter.batchSizes()
+	total := 0
+	for _, size := range sizes {
+		assert.LessOrEqual(t, size, 3)
+		total += size
+	}
+	assert.Equal(t, 7, total)
+}
+
+func TestPostgresEventRecorder_FlushesOnInterval(t *testing.T) {
+	inserter := &fakeEventInserter{}
+	recorder := newPostgresEventRecorder(inserter.insert, PostgresEventRecorderConfig{FlushInterval: 10 * time.Millisecond})
+	defer recorder.Close(context.Background())
+
+	require.NoError(t, recorder.Record(context.Background(), newTestEvents(2)...))
+	assert.Eventually(t, func() bool { return len(inserter.batchSizes()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestPostgresEventRecorder_CloseFlushesBuffered(t *testing.T) {
+	inserter := &fakeEventInserter{}
+	recorder := newPostgresEventRecorder(inserter.insert, PostgresEventRecorderConfig{FlushInterval: time.Hour})
+
+	require.NoError(t, recorder.Record(context.Background(), newTestEvents(4)...))
+	require.NoError(t, recorder.Close(context.Background()))
+	assert.Equal(t, []int{4}, inserter.batchSizes())
+
+	// After Close, events are written directly
+	require.NoError(t, recorder.Record(context.Background(), newTestEvents(1)...))
+	assert.Equal(t, []int{4, 1}, inserter.batchSizes())
+}
+
+func TestPostgresEventRecorder_SyncMode(t *testing.T) {
+	inserter := &fakeEventInserter{}
+	recorder := newPostgresEventRecorder(inserter.insert, PostgresEventRecorderConfig{Sync: true})
+
+	require.NoError(t, recorder.Record(context.Background(), newTestEvents(2)...))
+	assert.Equal(t, []int{2}, inserter.batchSizes())
+	require.NoError(t, recorder.Close(context.Background()))
+}
+
+func TestPostgresEventRecorder_ReportsDroppedEvents(t *testing.T) {
+	var dropped int
+	var mu sync.Mutex
+	onError := func(err error, events int) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped += events
+	}
+
+	inserter := &fakeEventInserter{err: errors.New("connection refused")}
+	recorder := newPostgresEventRecorder(inserter.insert, PostgresEventRecorderConfig{Sync: true, OnError: onError})
+	assert.Error(t, recorder.Record(context.Background(), newTestEvents(3)...))
+
+	mu.Lock()
+	assert.Equal(t, 3, dropped)
+	mu.Unlock()
+}
@@ -0,0 +1,60 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yadunandan004/scaffold/rate_limiter"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func TestRateLimit_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	limiter := rate_limiter.NewTokenBucketLimiter(local.NewLocalCache(nil))
+
+	ok := func(ctx request.Context) { ctx.JSON(200, gin.H{}) }
+	NewRegistry(engine, nil).AddGroup(RouteGroup{
+		Name:     "test",
+		BasePath: "/api",
+		Middleware: []Middleware{RateLimit(limiter, RateLimitPolicy{
+			Limit: rate_limiter.PerMinute(1, 2),
+			Key:   RateLimitByAPIKey("X-API-Key"),
+			Scope: "api",
+		})},
+		RouteList: []Route{
+			{Method: "GET", Path: "/a", Handler: ok, ShouldSkipAuth: true, ShouldSkipTxn: true},
+			{Method: "GET", Path: "/b", Handler: ok, ShouldSkipAuth: true, ShouldSkipTxn: true},
+		},
+	})
+
+	call := func(path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call("/api/a", "key-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+
+	// The group scope shares one bucket across its routes
+	assert.Equal(t, http.StatusOK, call("/api/b", "key-1").Code)
+
+	w = call("/api/a", "key-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"rate_limited"`)
+
+	// Another API key has its own bucket
+	assert.Equal(t, http.StatusOK, call("/api/a", "key-2").Code)
+}
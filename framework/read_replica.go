@@ -0,0 +1,122 @@
+package framework
+
+import (
+	"strings"
+)
+
+type readOnlyRepositoryConfig struct {
+	replica bool
+}
+
+// ReadOnlyRepositoryOption configures a PostgresReadOnlyRepository
+type ReadOnlyRepositoryOption func(*readOnlyRepositoryConfig)
+
+// WithReadReplica runs every read against postgres.GetReadOnlyDB instead of the primary or the request
+// transaction, failing when no replica is configured, and rejects statements that could write.
+// Reads may lag the primary, so use it for reporting endpoints rather than read-after-write flows.
+func WithReadReplica() ReadOnlyRepositoryOption {
+	return func(c *readOnlyRepositoryConfig) {
+		c.replica = true
+	}
+}
+
+// sqlWriteKeywords mark a statement as modifying data, schema or session state
+var sqlWriteKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "TRUNCATE": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "GRANT": true, "REVOKE": true,
+	"COPY": true, "CALL": true, "DO": true, "LOCK": true, "VACUUM": true, "REINDEX": true,
+	"CLUSTER": true, "COMMENT": true, "REFRESH": true, "INTO": true,
+}
+
+var sqlReadStatements = map[string]bool{
+	"SELECT": true, "WITH": true, "VALUES": true, "TABLE": true, "SHOW": true, "EXPLAIN": true,
+}
+
+// IsReadOnlySQL reports whether query is a single statement that only reads. It is a keyword inspection
+// guarding against mistakes, not a security boundary: it rejects writes, data-modifying CTEs, SELECT INTO,
+// row locks and stacked statements, but can't see side effects inside called functions.
+func IsReadOnlySQL(query string) bool {
+	keywords, single := sqlKeywords(query)
+	if !single || len(keywords) == 0 || !sqlReadStatements[keywords[0]] {
+		return false
+	}
+	for i, keyword := range keywords {
+		if sqlWriteKeywords[keyword] {
+			return false
+		}
+		// FOR SHARE, FOR KEY SHARE and FOR NO KEY UPDATE take row locks a standby can't grant
+		if keyword == "FOR" && i+1 < len(keywords) {
+			switch keywords[i+1] {
+			case "SHARE", "KEY", "NO":
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sqlKeywords returns the upper-cased bare words of query, skipping literals, quoted identifiers and
+// comments, and whether it holds a single statement
+func sqlKeywords(query string) ([]string, bool) {
+	var keywords []string
+	single := true
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return keywords, single
+			}
+			i += end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return keywords, single
+			}
+			i += end + 3
+		case c == '\'' || c == '"':
+			// A doubled quote escapes it inside a literal or identifier
+			for i++; i < len(query); i++ {
+				if query[i] == c {
+					if i+1 < len(query) && query[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case c == '$' && i+1 < len(query) && !isDigit(query[i+1]):
+			// Dollar-quoted string: $tag$ ... $tag$
+			end := strings.IndexByte(query[i+1:], '$')
+			if end < 0 {
+				return keywords, single
+			}
+			tag := query[i : i+end+2]
+			closing := strings.Index(query[i+len(tag):], tag)
+			if closing < 0 {
+				return keywords, single
+			}
+			i += len(tag) + closing + len(tag) - 1
+		case c == ';':
+			if strings.TrimSpace(query[i+1:]) != "" {
+				single = false
+			}
+		case isWordStart(c):
+			start := i
+			for i+1 < len(query) && (isWordStart(query[i+1]) || isDigit(query[i+1]) || query[i+1] == '$') {
+				i++
+			}
+			keywords = append(keywords, strings.ToUpper(query[start:i+1]))
+		}
+	}
+	return keywords, single
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
@@ -0,0 +1,62 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestIsReadOnlySQL(t *testing.T) {
+	readOnly := []string{
+		"SELECT * FROM users WHERE id = $1",
+		"  select count(*) from users;  ",
+		"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent",
+		"SELECT * FROM notes WHERE body = 'please DELETE this; now'",
+		`SELECT "update" FROM t`,
+		"SELECT * FROM t -- DROP TABLE t\n WHERE a = 1",
+		"SELECT $$ INSERT $$ AS text",
+		"EXPLAIN SELECT * FROM users",
+		"SELECT updated_at, deleted_at FROM users",
+	}
+	for _, query := range readOnly {
+		assert.True(t, IsReadOnlySQL(query), query)
+	}
+
+	writes := []string{
+		"",
+		"DELETE FROM users",
+		"UPDATE users SET name = 'x'",
+		"WITH gone AS (DELETE FROM users RETURNING id) SELECT * FROM gone",
+		"SELECT * INTO backup FROM users",
+		"SELECT * FROM users FOR UPDATE",
+		"SELECT * FROM users FOR SHARE",
+		"SELECT 1; DROP TABLE users",
+		"EXPLAIN ANALYZE DELETE FROM users",
+		"/* report */ TRUNCATE users",
+	}
+	for _, query := range writes {
+		assert.False(t, IsReadOnlySQL(query), query)
+	}
+}
+
+func TestReadReplica_RequiresReplicaConnection(t *testing.T) {
+	repo := NewPostgresReadOnlyRepository[TestSample, uuid.UUID](WithReadReplica())
+
+	_, err := repo.GetByID(request.NewTestContext(), uuid.New())
+	assert.ErrorContains(t, err, "no read replica")
+
+	_, err = repo.Search(request.NewTestContext(), NewSearchRequest())
+	assert.ErrorContains(t, err, "no read replica")
+}
+
+func TestFindByQuery_RejectsWrites(t *testing.T) {
+	repo := NewPostgresReadOnlyRepository[TestSample, uuid.UUID]()
+
+	_, err := repo.FindByQuery(request.NewTestContext(), "DELETE FROM test_samples")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+}
@@ -0,0 +1,98 @@
+package framework
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// Transports reported in the panics_recovered_total metric
+const (
+	transportHTTP = "http"
+	transportGRPC = "grpc"
+)
+
+// reportPanic logs a recovered panic with its stack and the request XID, rolls back the request's open
+// transaction so nothing it wrote is committed, and counts it. ctx may be nil when the panic happened
+// before a request.Context existed. It returns the internal error to respond with.
+func reportPanic(ctx request.Context, transport, route string, rec interface{}) *app_error.AppError {
+	stack := debug.Stack()
+	var reqCtx context.Context = context.Background()
+	if ctx != nil {
+		reqCtx = ctx.GetCtx()
+		if tx := ctx.GetPgTxn(); tx != nil {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				log.Printf("[Recovery] xid=%s: rollback after panic failed: %v", ctx.XID(), err)
+			}
+		}
+		log.Printf("[Recovery] xid=%s trace_id=%s %s %s panic: %v\n%s", ctx.XID(), ctx.TraceID(), transport, route, rec, stack)
+	} else {
+		log.Printf("[Recovery] %s %s panic: %v\n%s", transport, route, rec, stack)
+	}
+	metrics.RecordPanic(reqCtx, transport, route)
+	return app_error.Internal(fmt.Errorf("panic: %v", rec))
+}
+
+// Recovery is engine middleware converting panics outside registry routes, such as in other gin
+// middleware, into the standard error envelope. Registry routes recover their own panics.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// The client went away; let net/http suppress it as usual
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			ctx := request.NewApiContextForHttp(c)
+			appErr := reportPanic(ctx, transportHTTP, c.FullPath(), rec)
+			if !c.Writer.Written() {
+				RespondError(ctx, appErr)
+			}
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// GRPCRecoveryUnary converts panics into an internal error. GRPCRegistry installs it outermost and again
+// innermost, where the request.Context is available so the log carries the XID and the transaction rolls back.
+func GRPCRecoveryUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			resp, err = nil, reportPanic(grpcRequestContext(ctx), transportGRPC, info.FullMethod, rec)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// GRPCRecoveryStream is the stream counterpart of GRPCRecoveryUnary
+func GRPCRecoveryStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = reportPanic(grpcRequestContext(ss.Context()), transportGRPC, info.FullMethod, rec)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// grpcRequestContext returns the call's request.Context, or nil before the context interceptor has run
+func grpcRequestContext(ctx context.Context) request.Context {
+	if reqCtx, ok := request.GetGRPCCtx(ctx); ok {
+		return reqCtx
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestRecovery_EngineMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Recovery())
+	engine.Use(func(c *gin.Context) { panic("bad gin middleware") })
+	engine.GET("/test", func(c *gin.Context) { c.JSON(200, gin.H{}) })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var body app_error.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, app_error.CodeInternal, body.Error.Code)
+	assert.NotContains(t, w.Body.String(), "bad gin middleware")
+}
+
+func TestGRPCRecoveryUnary(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	panics := func(ctx context.Context, req interface{}) (interface{}, error) { panic("boom") }
+
+	// Without a request.Context, as when installed outermost
+	_, err := GRPCRecoveryUnary(context.Background(), nil, info, panics)
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// With one, as when installed next to the handler
+	reqCtx := request.NewApiContextForGRPC(context.Background())
+	ctx := context.WithValue(context.Background(), request.GRPCCtxKey, reqCtx)
+	_, err = GRPCRecoveryUnary(ctx, nil, info, panics)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, status.Convert(err).Message(), "boom")
+}
@@ -1,21 +1,29 @@
 package framework
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/yadunandan004/scaffold/app_error"
 	"github.com/yadunandan004/scaffold/auth"
 	"github.com/yadunandan004/scaffold/rate_limiter"
 	"log"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/singleton"
 )
 
 type Registry struct {
 	engine      *gin.Engine
 	auth        *auth.AuthService
 	rateLimiter *rate_limiter.HTTPRateLimiter
+	accessLog   *accessLogger
 }
 
 func NewRegistry(engine *gin.Engine, auth *auth.AuthService) *Registry {
@@ -41,8 +49,13 @@ func (r *Registry) AddGroup(group RouteGroup) {
 			r.rateLimiter.RegisterRoute(pattern, config)
 		}
 
+		// Let a globally installed auth middleware honor ShouldSkipAuth too
+		if r.auth != nil && route.ShouldSkipAuth && len(route.Permissions) == 0 {
+			r.auth.SkipRoute(route.Method, routePattern(group.BasePath, route.Path))
+		}
+
 		// Create handler with rate limiting
-		handler := r.createRouteHandler(route, group.BasePath)
+		handler := r.createRouteHandler(route, group)
 
 		switch route.Method {
 		case "GET":
@@ -64,53 +77,143 @@ func (r *Registry) AddGroup(group RouteGroup) {
 }
 
 // createRouteHandler creates a Gin handler that wraps your custom handler
-func (r *Registry) createRouteHandler(route Route, basePath string) gin.HandlerFunc {
+func (r *Registry) createRouteHandler(route Route, group RouteGroup) gin.HandlerFunc {
+	basePath := group.BasePath
+	middleware := append(append([]Middleware(nil), group.Middleware...), route.Middleware...)
+
 	return func(ginCtx *gin.Context) {
 		// Apply rate limiting first (blocking/throttling)
 		pattern := basePath + route.Path
 		limiter := r.rateLimiter.GetLimiterForRoute(pattern, route.Method)
 
+		start := time.Now()
+		var opts []request.HttpCtxOption
+		ctx := request.NewApiContextForHttp(ginCtx, opts...)
+
+		// The access log covers the whole request, including throttling and rejected auth
+		if r.accessLog != nil {
+			defer r.accessLog.record(ctx, ginCtx, routePattern(basePath, route.Path), start)
+		}
+
 		// Block until rate limit allows (throttling approach)
 		err := limiter.Wait(ginCtx.Request.Context())
 		if err != nil {
 			// Context cancelled while waiting
-			ginCtx.JSON(503, gin.H{"error": "Service unavailable"})
+			RespondError(ctx, app_error.Unavailable(""))
 			return
 		}
 
-		var opts []request.HttpCtxOption
-		ctx := request.NewApiContextForHttp(ginCtx, opts...)
+		// Let auth audit events carry the request XID and trace ID
+		ginCtx.Request = ginCtx.Request.WithContext(auth.ContextWithCorrelation(ginCtx.Request.Context(), ctx.XID(), ctx.TraceID()))
 
 		// Check authentication
-		if !route.ShouldSkipAuth && !r.checkAuth(ctx) {
-			ctx.JSON(401, gin.H{"error": "Unauthorized"})
+		if r.requiresAuth(route, ginCtx) && !r.checkAuth(ctx) {
+			// Authenticate has already responded when it rejected the token
+			if !ginCtx.Writer.Written() {
+				RespondError(ctx, app_error.Unauthenticated(""))
+			}
 			return
 		}
 
-		// Start transaction if not skipped (OPTIONS always skips transaction)
-		if !route.ShouldSkipTxn && route.Method != "OPTIONS" {
-			// Use BeginTransactionForModel with a generic type
-			tx, err := request.BeginTransaction(ctx)
-			if err != nil {
-				log.Printf("[Registry] Failed to start transaction: %v", err)
-				ctx.JSON(500, gin.H{"error": fmt.Sprintf("Failed to start transaction: %v", err)})
-				return
-			}
-			defer func() {
-				// Check if response was successful
-				if ginCtx.Writer.Status() >= 200 && ginCtx.Writer.Status() < 400 {
-					tx.Commit()
-				} else {
-					tx.Rollback()
-				}
-			}()
+		// Check authorization
+		if len(route.Permissions) > 0 && !r.checkPermissions(ctx, route.Permissions) {
+			RespondError(ctx, app_error.PermissionDenied(""))
+			return
+		}
+
+		// Request-scoped values live until the response is written, after the transaction has closed
+		scope := singleton.BeginScope(ctx)
+		defer closeScope(ctx, scope)
+
+		// The timeout reaches the transaction and the ORM queries through the request's context
+		if timeout := routeTimeout(route, group); timeout > 0 {
+			timeoutCtx, cancel := context.WithTimeout(ctx.GetCtx(), timeout)
+			defer cancel()
+			ctx.SetCtx(timeoutCtx)
 		}
 
-		// Handle the request
-		route.Handler(ctx)
+		// Route middleware runs around the transaction and handler; a panic in either becomes an internal error
+		runHandler(ctx, Chain(func(ctx request.Context) { r.runInTransaction(ctx, route) }, middleware...))
+
+		// A handler that gave up on the deadline without responding gets the timeout error
+		if err := ctx.GetCtx().Err(); errors.Is(err, context.DeadlineExceeded) && !ginCtx.Writer.Written() {
+			RespondError(ctx, err)
+		}
+	}
+}
+
+// routeTimeout returns the handler timeout of route, falling back to its group's
+func routeTimeout(route Route, group RouteGroup) time.Duration {
+	if route.Timeout != 0 {
+		return route.Timeout
+	}
+	return group.Timeout
+}
+
+// closeScope disposes the request's scoped values; a failure is logged as the response is already decided
+func closeScope(ctx request.Context, scope *singleton.Scope) {
+	if err := scope.Close(); err != nil {
+		log.Printf("[Registry] xid=%s: closing request scope failed: %v", ctx.XID(), err)
 	}
 }
 
+// runInTransaction calls the route handler, inside a transaction unless the route skips it. The
+// transaction and any the handler opened with BeginNamedTransaction or EnlistTxn are closed together
+// by CloseTxn: committed after a successful response in time, otherwise rolled back.
+func (r *Registry) runInTransaction(ctx request.Context, route Route) {
+	// Start transaction if not skipped (OPTIONS always skips transaction)
+	if !route.ShouldSkipTxn && route.Method != "OPTIONS" {
+		if _, err := request.BeginTransaction(ctx); err != nil {
+			RespondError(ctx, app_error.Internal(fmt.Errorf("failed to start transaction: %w", err)))
+			return
+		}
+	}
+	defer closeRequestTxns(ctx)
+
+	// Handle the request; a panic becomes an internal error response so the transaction rolls back
+	runHandler(ctx, route.Handler)
+}
+
+// closeRequestTxns closes the request's transactions once the handler has returned. A failure is logged,
+// with every transaction's outcome as a *request.TxnCloseError, and becomes the response when the
+// handler hasn't written one.
+func closeRequestTxns(ctx request.Context) {
+	ginCtx := ctx.GetGinContext()
+	var outcome error
+	if status := ginCtx.Writer.Status(); status < 200 || status >= 400 {
+		outcome = fmt.Errorf("response status %d", status)
+	} else if err := ctx.GetCtx().Err(); err != nil {
+		outcome = err
+	}
+
+	err := ctx.CloseTxn(outcome)
+	// A panic has already rolled back the default transaction
+	if err == nil || errors.Is(err, sql.ErrTxDone) {
+		return
+	}
+	var closeErr *request.TxnCloseError
+	if errors.As(err, &closeErr) {
+		log.Printf("[Registry] xid=%s: %v", ctx.XID(), closeErr)
+	} else {
+		log.Printf("[Registry] xid=%s: closing transaction failed: %v", ctx.XID(), err)
+	}
+	if !ginCtx.Writer.Written() {
+		RespondError(ctx, app_error.Internal(err))
+	}
+}
+
+// requiresAuth decides whether a route needs an authenticated caller, from ShouldSkipAuth and the
+// AuthService skip lists. Permission checks need claims even when auth is otherwise skipped.
+func (r *Registry) requiresAuth(route Route, ginCtx *gin.Context) bool {
+	if len(route.Permissions) > 0 {
+		return true
+	}
+	if route.ShouldSkipAuth {
+		return false
+	}
+	return r.auth == nil || !r.auth.ShouldSkipHTTP(ginCtx)
+}
+
 func (r *Registry) checkAuth(ctx request.Context) bool {
 	ginCtx := ctx.GetGinContext()
 	if ginCtx == nil {
@@ -118,36 +221,60 @@ func (r *Registry) checkAuth(ctx request.Context) bool {
 	}
 
 	// Check if auth middleware has already been run
-	claimsRaw, exists := ginCtx.Get("user_claims")
-	if !exists {
-		// Run auth middleware
-		r.auth.HTTPMiddleware()(ginCtx)
-		if ginCtx.IsAborted() {
+	claims, ok := auth.ClaimsFromContext(ginCtx)
+	if !ok {
+		// Authenticate directly; the middleware would honor skip lists this route has already ruled out
+		if r.auth == nil || !r.auth.Authenticate(ginCtx) {
 			return false
 		}
-		claimsRaw, exists = ginCtx.Get("user_claims")
-		if !exists {
+		if claims, ok = auth.ClaimsFromContext(ginCtx); !ok {
 			return false
 		}
 	}
 
-	// Extract user claims and set user info
-	if claims, ok := claimsRaw.(*auth.UserClaims); ok {
-		ginCtx.Set(string(request.UserIDKey), claims.UserID)
-		ginCtx.Set(string(request.UserEmailKey), claims.Email)
-		ginCtx.Set("client_device_id", claims.ClientDeviceID)
+	// Set user info from the claims
+	ginCtx.Set(string(request.UserIDKey), claims.UserID)
+	ginCtx.Set(string(request.UserEmailKey), claims.Email)
+	ginCtx.Set("client_device_id", claims.ClientDeviceID)
 
-		// Update the request's user info
-		if httpCtx, ok := ctx.(*request.HttpCtx); ok {
-			httpCtx.SetUserInfo(claims.UserID, claims.Email)
-			// Note: ClientDeviceID is already set via gin request above
-		}
-		return true
+	// Update the request's user info
+	if httpCtx, ok := ctx.(*request.HttpCtx); ok {
+		httpCtx.SetUserInfo(claims.UserID, claims.Email, claims.Name)
+		// The context was built before auth ran, so copy the device ID and service identity across too
+		*httpCtx.GetUserInfo() = *request.NewPrincipalFromClaims(claims)
 	}
-	return false
+	return true
+}
+
+// checkPermissions reports whether the authenticated caller is granted every permission
+func (r *Registry) checkPermissions(ctx request.Context, permissions []string) bool {
+	ginCtx := ctx.GetGinContext()
+	if ginCtx == nil {
+		return false
+	}
+
+	claims, ok := auth.ClaimsFromContext(ginCtx)
+	if !ok {
+		return false
+	}
+
+	if err := auth.GetPermissionRegistry().Authorize(claims, permissions...); err != nil {
+		log.Printf("[Registry] %s denied: %v", claims.UserID, err)
+		return false
+	}
+	return true
 }
 
 // containsSearchPath checks if the path contains /search
 func containsSearchPath(path string) bool {
 	return strings.Contains(path, "/search")
 }
+
+// routePattern joins a group base path and route path the way Gin does, so it matches gin.Context.FullPath
+func routePattern(basePath, routePath string) string {
+	pattern := path.Join(basePath, routePath)
+	if strings.HasSuffix(routePath, "/") && !strings.HasSuffix(pattern, "/") {
+		pattern += "/"
+	}
+	return pattern
+}
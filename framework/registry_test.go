@@ -1,12 +1,15 @@
 package framework
 
 import (
+	"errors"
+	"github.com/yadunandan004/scaffold/auth"
 	"github.com/yadunandan004/scaffold/request"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,6 +98,77 @@ func TestRegistrySkipTransaction(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 }
 
+// enlistedTxn is a request.TxnResource recording how it was closed
+type enlistedTxn struct {
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (e *enlistedTxn) Commit() error {
+	if e.commitErr != nil {
+		return e.commitErr
+	}
+	e.committed = true
+	return nil
+}
+
+func (e *enlistedTxn) Rollback() error {
+	e.rolledBack = true
+	return nil
+}
+
+func TestRegistryClosesEnlistedTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	registry := NewRegistry(engine, nil)
+
+	var txns []*enlistedTxn
+	route := func(path string, status int, commitErr error) Route {
+		return Route{
+			Method: "POST",
+			Path:   path,
+			Handler: func(ctx request.Context) {
+				txn := &enlistedTxn{commitErr: commitErr}
+				txns = append(txns, txn)
+				if err := request.EnlistTxn(ctx, "analytics", txn); err != nil {
+					t.Errorf("enlist: %v", err)
+				}
+				if status != 0 {
+					ctx.JSON(status, gin.H{})
+				}
+			},
+			ShouldSkipAuth: true,
+			ShouldSkipTxn:  true,
+		}
+	}
+	registry.AddGroup(RouteGroup{
+		Name:     "test",
+		BasePath: "/api",
+		RouteList: []Route{
+			route("/ok", http.StatusOK, nil),
+			route("/fail", http.StatusBadRequest, nil),
+			route("/commit-fails", 0, errors.New("connection lost")),
+		},
+	})
+
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/api/ok"))
+	assert.True(t, txns[0].committed, "a successful response commits")
+
+	assert.Equal(t, http.StatusBadRequest, serve("/api/fail"))
+	assert.True(t, txns[1].rolledBack, "an error response rolls back")
+	assert.False(t, txns[1].committed)
+
+	assert.Equal(t, http.StatusInternalServerError, serve("/api/commit-fails"), "a failed commit becomes the response")
+	assert.False(t, txns[2].committed)
+}
+
 func TestRegistryRouteMatching(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -254,3 +328,103 @@ func TestRegistryTransactionForDifferentMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistryRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	permissions := auth.NewPermissionRegistry()
+	permissions.RegisterPermission("nodes:write", "Create and modify nodes")
+	assert.NoError(t, permissions.DefineRole("operator", "nodes:write"))
+	previous := auth.GetPermissionRegistry()
+	auth.SetPermissionRegistry(permissions)
+	defer auth.SetPermissionRegistry(previous)
+
+	testCases := []struct {
+		name         string
+		claims       *auth.UserClaims
+		expectedCode int
+	}{
+		{"unauthenticated", nil, 401},
+		{"missing permission", &auth.UserClaims{UserID: uuid.New(), Roles: []string{"viewer"}}, 403},
+		{"granted permission", &auth.UserClaims{UserID: uuid.New(), Roles: []string{"operator"}}, 200},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := gin.New()
+			if tc.claims != nil {
+				engine.Use(func(c *gin.Context) { c.Set(auth.UserClaimsKey, tc.claims) })
+			}
+			registry := NewRegistry(engine, &auth.AuthService{})
+			registry.AddGroup(RouteGroup{
+				Name:     "nodes",
+				BasePath: "/api/nodes",
+				RouteList: []Route{
+					Route{
+						Method: "POST",
+						Path:   "",
+						Handler: func(ctx request.Context) {
+							ctx.JSON(200, gin.H{"message": "created"})
+						},
+						ShouldSkipTxn: true,
+					}.RequirePermission("nodes:write"),
+				},
+			})
+
+			req, _ := http.NewRequest("POST", "/api/nodes", nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestRegistryAuthSkipLists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authService := &auth.AuthService{}
+	authService.SkipPaths("/api/public")
+
+	engine := gin.New()
+	// Installed globally, so it must honor ShouldSkipAuth routes registered below
+	engine.Use(authService.HTTPMiddleware())
+	registry := NewRegistry(engine, authService)
+
+	ok := func(ctx request.Context) { ctx.JSON(200, gin.H{"message": "ok"}) }
+	registry.AddGroup(RouteGroup{
+		Name:     "public",
+		BasePath: "/api/public",
+		RouteList: []Route{
+			{Method: "GET", Path: "/status", Handler: ok, ShouldSkipTxn: true},
+		},
+	})
+	registry.AddGroup(RouteGroup{
+		Name:     "items",
+		BasePath: "/api/items",
+		RouteList: []Route{
+			{Method: "GET", Path: "/:id", Handler: ok, ShouldSkipAuth: true, ShouldSkipTxn: true},
+			{Method: "DELETE", Path: "/:id", Handler: ok, ShouldSkipTxn: true},
+		},
+	})
+
+	testCases := []struct {
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{"GET", "/api/public/status", 200},
+		{"GET", "/api/items/1", 200},
+		{"DELETE", "/api/items/1", 401},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
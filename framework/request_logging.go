@@ -0,0 +1,222 @@
+package framework
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yadunandan004/scaffold/logger"
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// redactedValue replaces the value of redacted fields
+const redactedValue = "[REDACTED]"
+
+// maxRedactableBody bounds how much of a body is buffered for redaction; larger bodies are not logged
+const maxRedactableBody = 64 << 10
+
+// DefaultRedactFields are redacted when RequestLogOptions.RedactFields is empty
+var DefaultRedactFields = []string{
+	"password", "new_password", "old_password", "token", "access_token", "refresh_token", "id_token",
+	"secret", "client_secret", "api_key", "authorization", "otp", "pin", "ssn", "card_number", "cvv",
+}
+
+// RequestLog is one logged request and its response
+type RequestLog struct {
+	Method   string
+	Path     string
+	Route    string
+	Status   int
+	Duration time.Duration
+	// Bytes is the response body size
+	Bytes        int
+	RequestBody  string
+	ResponseBody string
+}
+
+// RequestLogOptions configures the RequestLogging middleware
+type RequestLogOptions struct {
+	// MaxBodyBytes truncates logged bodies; defaults to 4096, negative disables body logging
+	MaxBodyBytes int
+	// RedactFields are JSON and form field names, matched case-insensitively, whose values are never logged;
+	// defaults to DefaultRedactFields
+	RedactFields []string
+	// Sink receives each entry; defaults to the logger package, at warn level for 4xx and error level for 5xx
+	Sink func(ctx request.Context, entry RequestLog)
+}
+
+// RequestLogging logs method, path, status, duration and truncated request and response bodies for each
+// request, correlated through the request's XID and TraceID. Sensitive fields in JSON and form bodies are
+// redacted; bodies that can't be parsed for redaction are summarized by size instead of logged.
+func RequestLogging(opts RequestLogOptions) Middleware {
+	if opts.MaxBodyBytes == 0 {
+		opts.MaxBodyBytes = 4096
+	}
+	if len(opts.RedactFields) == 0 {
+		opts.RedactFields = DefaultRedactFields
+	}
+	if opts.Sink == nil {
+		opts.Sink = logRequest
+	}
+	redact := make(map[string]bool, len(opts.RedactFields))
+	for _, field := range opts.RedactFields {
+		redact[strings.ToLower(field)] = true
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx request.Context) {
+			ginCtx := ctx.GetGinContext()
+			if ginCtx == nil {
+				next(ctx)
+				return
+			}
+			start := time.Now()
+
+			entry := RequestLog{
+				Method: ginCtx.Request.Method,
+				Path:   ginCtx.Request.URL.Path,
+				Route:  ginCtx.FullPath(),
+			}
+			captureBodies := opts.MaxBodyBytes > 0
+			if captureBodies {
+				entry.RequestBody = captureRequestBody(ginCtx.Request, redact, opts.MaxBodyBytes)
+			}
+			httpCtx, buffered := ctx.(*request.HttpCtx)
+			if buffered {
+				httpCtx.InstallBufferedWriterLimit(maxRedactableBody + 1)
+			}
+
+			defer func() {
+				entry.Status = ginCtx.Writer.Status()
+				entry.Duration = time.Since(start)
+				entry.Bytes = ginCtx.Writer.Size()
+				if buffered {
+					body, written, _ := httpCtx.BufferedResponse()
+					entry.Bytes = written
+					if captureBodies {
+						entry.ResponseBody = formatLoggedBody(body, written, ginCtx.Writer.Header().Get("Content-Type"), redact, opts.MaxBodyBytes)
+					}
+				}
+				opts.Sink(ctx, entry)
+			}()
+
+			next(ctx)
+		}
+	}
+}
+
+func logRequest(ctx request.Context, entry RequestLog) {
+	level := logwriter.InfoLevel
+	switch {
+	case entry.Status >= http.StatusInternalServerError:
+		level = logwriter.ErrorLevel
+	case entry.Status >= http.StatusBadRequest:
+		level = logwriter.WarnLevel
+	}
+	fields := map[string]interface{}{
+		"method":      entry.Method,
+		"path":        entry.Path,
+		"route":       entry.Route,
+		"status":      entry.Status,
+		"duration_ms": entry.Duration.Milliseconds(),
+		"bytes":       entry.Bytes,
+	}
+	if entry.RequestBody != "" {
+		fields["request_body"] = entry.RequestBody
+	}
+	if entry.ResponseBody != "" {
+		fields["response_body"] = entry.ResponseBody
+	}
+	logger.LogWithFields(ctx, level, fmt.Sprintf("%s %s %d %s", entry.Method, entry.Path, entry.Status, entry.Duration), fields)
+}
+
+// captureRequestBody reads up to maxRedactableBody bytes for logging and restores the body for the handler
+func captureRequestBody(req *http.Request, redact map[string]bool, maxBytes int) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	captured, err := io.ReadAll(io.LimitReader(req.Body, maxRedactableBody+1))
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(captured), req.Body), Closer: req.Body}
+	if err != nil {
+		return ""
+	}
+	size := len(captured)
+	if req.ContentLength > int64(size) {
+		size = int(req.ContentLength)
+	}
+	return formatLoggedBody(captured, size, req.Header.Get("Content-Type"), redact, maxBytes)
+}
+
+// formatLoggedBody redacts and truncates body, a prefix of a size byte body
+func formatLoggedBody(body []byte, size int, contentType string, redact map[string]bool, maxBytes int) string {
+	if size == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	complete := len(body) == size && size <= maxRedactableBody
+
+	var text string
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+		if !complete || json.Unmarshal(body, &value) != nil {
+			return fmt.Sprintf("[%d bytes, not logged]", size)
+		}
+		redacted, err := json.Marshal(redactJSON(value, redact))
+		if err != nil {
+			return fmt.Sprintf("[%d bytes, not logged]", size)
+		}
+		text = string(redacted)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if !complete || err != nil {
+			return fmt.Sprintf("[%d bytes, not logged]", size)
+		}
+		for key := range values {
+			if redact[strings.ToLower(key)] {
+				values[key] = []string{redactedValue}
+			}
+		}
+		text = values.Encode()
+	case strings.HasPrefix(mediaType, "text/"):
+		text = string(body)
+	default:
+		return fmt.Sprintf("[%d bytes %s]", size, mediaType)
+	}
+
+	if len(text) > maxBytes {
+		return text[:maxBytes] + "...(truncated)"
+	}
+	return text
+}
+
+// redactJSON replaces the values of redacted keys at any depth
+func redactJSON(value interface{}, redact map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redact[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(field, redact)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item, redact)
+		}
+	}
+	return value
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
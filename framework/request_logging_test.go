@@ -0,0 +1,84 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestRequestLogging_RedactsAndTruncates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+
+	var logged []RequestLog
+	NewRegistry(engine, nil).AddGroup(RouteGroup{
+		Name:     "test",
+		BasePath: "/api",
+		Middleware: []Middleware{RequestLogging(RequestLogOptions{
+			MaxBodyBytes: 80,
+			Sink:         func(ctx request.Context, entry RequestLog) { logged = append(logged, entry) },
+		})},
+		RouteList: []Route{{
+			Method: "POST",
+			Path:   "/login/:tenant",
+			Handler: func(ctx request.Context) {
+				var body map[string]interface{}
+				require.NoError(t, ctx.GetRequestContext().ShouldBindJSON(&body))
+				ctx.JSON(http.StatusCreated, gin.H{"access_token": "secret-token", "user": body["user"], "padding": strings.Repeat("x", 100)})
+			},
+			ShouldSkipAuth: true,
+			ShouldSkipTxn:  true,
+		}},
+	})
+
+	req := httptest.NewRequest("POST", "/api/login/acme", strings.NewReader(`{"user":"ada","password":"hunter2","profile":{"PIN":"1234"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "secret-token")
+
+	require.Len(t, logged, 1)
+	entry := logged[0]
+	assert.Equal(t, "POST", entry.Method)
+	assert.Equal(t, "/api/login/acme", entry.Path)
+	assert.Equal(t, "/api/login/:tenant", entry.Route)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, w.Body.Len(), entry.Bytes)
+	assert.Equal(t, `{"password":"[REDACTED]","profile":{"PIN":"[REDACTED]"},"user":"ada"}`, entry.RequestBody)
+	assert.NotContains(t, entry.ResponseBody, "secret-token")
+	assert.Contains(t, entry.ResponseBody, `"access_token":"[REDACTED]"`)
+	assert.True(t, strings.HasSuffix(entry.ResponseBody, "...(truncated)"))
+}
+
+func TestFormatLoggedBody(t *testing.T) {
+	redact := map[string]bool{"password": true}
+
+	tests := []struct {
+		name        string
+		body        string
+		size        int
+		contentType string
+		want        string
+	}{
+		{"empty", "", 0, "application/json", ""},
+		{"form", "user=ada&password=hunter2", 25, "application/x-www-form-urlencoded", "password=%5BREDACTED%5D&user=ada"},
+		{"text", "hello", 5, "text/plain; charset=utf-8", "hello"},
+		{"binary", "\x89PNG", 4, "image/png", "[4 bytes image/png]"},
+		{"invalid json", `{"password":`, 12, "application/json", "[12 bytes, not logged]"},
+		{"partial json", `{"password":"x"}`, 100, "application/json", "[100 bytes, not logged]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatLoggedBody([]byte(tt.body), tt.size, tt.contentType, redact, 64))
+		})
+	}
+}
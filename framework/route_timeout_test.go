@@ -0,0 +1,74 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestRegistry_RouteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+
+	var deadline time.Time
+	waitForDeadline := func(ctx request.Context) {
+		deadline, _ = ctx.GetCtx().Deadline()
+		<-ctx.GetCtx().Done()
+	}
+	registry := NewRegistry(engine, nil)
+	registry.AddGroup(RouteGroup{
+		Name:     "reports",
+		BasePath: "/api/reports",
+		Timeout:  20 * time.Millisecond,
+		RouteList: []Route{
+			{Method: "GET", Path: "/silent", Handler: waitForDeadline, ShouldSkipAuth: true, ShouldSkipTxn: true},
+			{
+				Method: "GET",
+				Path:   "/error",
+				Handler: func(ctx request.Context) {
+					<-ctx.GetCtx().Done()
+					RespondError(ctx, ctx.GetCtx().Err())
+				},
+				ShouldSkipAuth: true,
+				ShouldSkipTxn:  true,
+			},
+			{
+				Method: "GET",
+				Path:   "/unbounded",
+				Handler: func(ctx request.Context) {
+					_, hasDeadline := ctx.GetCtx().Deadline()
+					ctx.JSON(http.StatusOK, gin.H{"has_deadline": hasDeadline})
+				},
+				ShouldSkipAuth: true,
+				ShouldSkipTxn:  true,
+				Timeout:        -1,
+			},
+		},
+	})
+
+	for _, path := range []string{"/api/reports/silent", "/api/reports/error"} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		require.Equal(t, http.StatusGatewayTimeout, w.Code, path)
+		var body app_error.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, app_error.CodeTimeout, body.Error.Code, path)
+		assert.NotEmpty(t, body.Error.XID, path)
+	}
+	assert.False(t, deadline.IsZero(), "the group timeout applies to its routes")
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/reports/unbounded", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"has_deadline": false}`, w.Body.String(), "a negative route timeout disables the group's")
+}
@@ -3,6 +3,8 @@ package framework
 import (
 	"fmt"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
 type filterOperator string
@@ -78,33 +80,69 @@ func (f FilterPayload) ToSQL(argCount *int) (string, []interface{}) {
 		clause = fmt.Sprintf("%s NOT LIKE $%d", f.Field, *argCount)
 		args = append(args, f.Values[0])
 		*argCount++
+	case FilterOperator.FullText():
+		// Values holds the query, optionally followed by the text search configuration
+		if len(f.Values) > 1 {
+			clause = fmt.Sprintf("%s @@ plainto_tsquery($%d::regconfig, $%d)", f.Field, *argCount, *argCount+1)
+			args = append(args, f.Values[1], f.Values[0])
+			*argCount += 2
+		} else {
+			clause = fmt.Sprintf("%s @@ plainto_tsquery($%d)", f.Field, *argCount)
+			args = append(args, f.Values[0])
+			*argCount++
+		}
 	}
 
 	return clause, args
 }
 
 func BuildWhereClause(filters []FilterPayload) (string, []interface{}) {
-	if len(filters) == 0 {
+	argCount := 1
+	return buildConditions("WHERE", filters, &argCount)
+}
+
+// BuildHavingClause builds a HAVING clause whose Fields are aggregate expressions such as COUNT(*).
+// Placeholders are numbered from argCount, so it follows the WHERE clause's arguments.
+func BuildHavingClause(filters []FilterPayload, argCount *int) (string, []interface{}) {
+	clause, args := buildConditions("HAVING", filters, argCount)
+	if clause == "" {
 		return "", nil
 	}
+	return " " + clause, args
+}
 
-	var whereClauses []string
-	var allArgs []interface{}
-	argCount := 1
+// BuildGroupByClause groups by the given columns or expressions
+func BuildGroupByClause(columns []string) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	return " GROUP BY " + strings.Join(columns, ", ")
+}
 
+func buildConditions(keyword string, filters []FilterPayload, argCount *int) (string, []interface{}) {
+	conditions, args := joinConditions(filters, argCount)
+	if conditions == "" {
+		return "", nil
+	}
+	return keyword + " " + conditions, args
+}
+
+// joinConditions ANDs the filters' conditions together without a keyword
+func joinConditions(filters []FilterPayload, argCount *int) (string, []interface{}) {
+	var clauses []string
+	var allArgs []interface{}
 	for _, filter := range filters {
-		clause, args := filter.ToSQL(&argCount)
+		clause, args := filter.ToSQL(argCount)
 		if clause != "" {
-			whereClauses = append(whereClauses, clause)
+			clauses = append(clauses, clause)
 			allArgs = append(allArgs, args...)
 		}
 	}
 
-	if len(whereClauses) == 0 {
+	if len(clauses) == 0 {
 		return "", nil
 	}
-
-	return "WHERE " + strings.Join(whereClauses, " AND "), allArgs
+	return strings.Join(clauses, " AND "), allArgs
 }
 
 type SortPayload struct {
@@ -123,6 +161,15 @@ func BuildOrderByClause(sort *SortPayload) string {
 	return " ORDER BY " + strings.Join(sort.Fields, ", ") + " " + direction
 }
 
+// RankExpression returns a ts_rank of the tsvector field against query for sorting. query is embedded
+// as a quoted literal because ORDER BY is built separately from the filter arguments.
+func RankExpression(field string, query string, config ...string) string {
+	if len(config) > 0 {
+		return fmt.Sprintf("ts_rank(%s, plainto_tsquery(%s::regconfig, %s))", field, pq.QuoteLiteral(config[0]), pq.QuoteLiteral(query))
+	}
+	return fmt.Sprintf("ts_rank(%s, plainto_tsquery(%s))", field, pq.QuoteLiteral(query))
+}
+
 func BuildPaginationClause(page, take int) string {
 	if take <= 0 {
 		return ""
@@ -142,6 +189,12 @@ type SearchRequest struct {
 	Page    int      // 1-based page number (default: 0 = no pagination)
 	Take    int      // Page size / limit (default: 0 = no limit)
 	Columns []string // Columns to select (default: empty = SELECT *)
+	Cursor  string   // NextCursor from a previous page; overrides Page when set
+	// IncludeTotal runs a COUNT(*) for PagedResult.Total
+	IncludeTotal bool
+	// GroupBy and Having shape aggregate queries; see Aggregate
+	GroupBy []string
+	Having  []FilterPayload
 }
 
 func NewSearchRequest() *SearchRequest {
@@ -187,6 +240,33 @@ func (r *SearchRequest) AddLessThanOrEqual(field string, value interface{}) *Sea
 	return r.AddFilter(*LessThanOrEqualFilter(field, value))
 }
 
+// AddFullText filters on the tsvector field matching query; see FullTextFilter
+func (r *SearchRequest) AddFullText(field string, query string, config ...string) *SearchRequest {
+	return r.AddFilter(*FullTextFilter(field, query, config...))
+}
+
+// SortByRank orders the best full-text matches of query on the tsvector field first
+func (r *SearchRequest) SortByRank(field string, query string, config ...string) *SearchRequest {
+	return r.SortDesc(RankExpression(field, query, config...))
+}
+
+// AddGroupBy groups rows by columns, for use with aggregate Columns such as CountExpr
+func (r *SearchRequest) AddGroupBy(columns ...string) *SearchRequest {
+	r.GroupBy = append(r.GroupBy, columns...)
+	return r
+}
+
+// AddHaving filters groups; the filter's Field is an aggregate expression, e.g. GreaterThanFilter("SUM(amount)", 100)
+func (r *SearchRequest) AddHaving(filter FilterPayload) *SearchRequest {
+	r.Having = append(r.Having, filter)
+	return r
+}
+
+// IsAggregate reports whether the request groups rows
+func (r *SearchRequest) IsAggregate() bool {
+	return len(r.GroupBy) > 0 || len(r.Having) > 0
+}
+
 func (r *SearchRequest) SortBy(fields []string, direction string) *SearchRequest {
 	r.Sort = &SortPayload{
 		Fields:    fields,
@@ -224,6 +304,29 @@ func (r *SearchRequest) WithTake(pageSize int) *SearchRequest {
 	return r
 }
 
+// WithCursor resumes from a previous page's NextCursor
+func (r *SearchRequest) WithCursor(cursor string) *SearchRequest {
+	r.Cursor = cursor
+	return r
+}
+
+// WithTotal asks Search to count every matching row
+func (r *SearchRequest) WithTotal() *SearchRequest {
+	r.IncludeTotal = true
+	return r
+}
+
+// Offset returns the row offset of the requested page, from Cursor when set and otherwise Page and Take
+func (r *SearchRequest) Offset() (int, error) {
+	if r.Cursor != "" {
+		return DecodeCursor(r.Cursor)
+	}
+	if r.Page > 1 && r.Take > 0 {
+		return (r.Page - 1) * r.Take, nil
+	}
+	return 0, nil
+}
+
 func (r *SearchRequest) AddColumn(column string) *SearchRequest {
 	r.Columns = append(r.Columns, column)
 	return r
@@ -248,7 +351,9 @@ func (r *SearchRequest) GetColumns() []string {
 }
 
 func (r *SearchRequest) ToQuery(baseQuery string) (string, []interface{}) {
-	whereClause, args := BuildWhereClause(r.Filters)
+	argCount := 1
+	whereClause, args := buildConditions("WHERE", r.Filters, &argCount)
+	havingClause, havingArgs := BuildHavingClause(r.Having, &argCount)
 	orderByClause := BuildOrderByClause(r.Sort)
 	paginationClause := BuildPaginationClause(r.Page, r.Take)
 
@@ -256,6 +361,8 @@ func (r *SearchRequest) ToQuery(baseQuery string) (string, []interface{}) {
 	if whereClause != "" {
 		fullQuery += " " + whereClause
 	}
+	fullQuery += BuildGroupByClause(r.GroupBy) + havingClause
+	args = append(args, havingArgs...)
 	if orderByClause != "" {
 		fullQuery += orderByClause
 	}
@@ -324,6 +431,15 @@ func NotLikeFilter(field string, pattern interface{}) *FilterPayload {
 	return baseFilter(field, FilterOperator.NotLike(), pattern)
 }
 
+// FullTextFilter matches rows whose tsvector field matches query, parsed with plainto_tsquery. Without
+// a config the server's default_text_search_config applies; pass the column's tsconfig to match it.
+func FullTextFilter(field string, query string, config ...string) *FilterPayload {
+	if len(config) > 0 {
+		return baseFilter(field, FilterOperator.FullText(), query, config[0])
+	}
+	return baseFilter(field, FilterOperator.FullText(), query)
+}
+
 func (f filterOperator) In() string {
 	return "in"
 }
@@ -375,3 +491,7 @@ func (f filterOperator) Like() string {
 func (f filterOperator) NotLike() string {
 	return "notlike"
 }
+
+func (f filterOperator) FullText() string {
+	return "fulltext"
+}
@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"sync"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/config"
+)
+
+// SearchLimits are the guardrails applied to every search made through a service
+type SearchLimits struct {
+	// DefaultTake is used when a request sets no Take; 0 leaves such requests unlimited
+	DefaultTake int
+	// MaxTake is the largest page size a request may ask for; 0 disables the cap
+	MaxTake int
+	// MaxFilters is the most filters a request may carry; 0 disables the cap
+	MaxFilters int
+}
+
+// DefaultSearchLimits are in effect until SetSearchLimits is called
+var DefaultSearchLimits = SearchLimits{DefaultTake: 50, MaxTake: 1000, MaxFilters: 20}
+
+var (
+	globalSearchLimits     = DefaultSearchLimits
+	globalSearchLimitsLock sync.RWMutex
+)
+
+// SetSearchLimits replaces the limits used by ReadOnlyServiceImpl.Search
+func SetSearchLimits(limits SearchLimits) {
+	globalSearchLimitsLock.Lock()
+	defer globalSearchLimitsLock.Unlock()
+	globalSearchLimits = limits
+}
+
+// GetSearchLimits returns the limits used by ReadOnlyServiceImpl.Search
+func GetSearchLimits() SearchLimits {
+	globalSearchLimitsLock.RLock()
+	defer globalSearchLimitsLock.RUnlock()
+	return globalSearchLimits
+}
+
+// NewSearchLimits builds SearchLimits from the search section of the config
+func NewSearchLimits(cfg config.SearchConfig) SearchLimits {
+	return SearchLimits{DefaultTake: cfg.DefaultTake, MaxTake: cfg.MaxTake, MaxFilters: cfg.MaxFilters}
+}
+
+// Apply returns a copy of req with DefaultTake filled in, or an invalid_argument error when req
+// asks for a larger page or more filters than the limits allow
+func (l SearchLimits) Apply(req *SearchRequest) (*SearchRequest, error) {
+	if req == nil {
+		req = NewSearchRequest()
+	}
+	if req.Take < 0 {
+		return nil, app_error.InvalidArgument("Take must not be negative")
+	}
+	if l.MaxTake > 0 && req.Take > l.MaxTake {
+		return nil, app_error.Newf(app_error.CodeInvalidArgument, "Take %d exceeds the maximum of %d", req.Take, l.MaxTake)
+	}
+	if l.MaxFilters > 0 && len(req.Filters) > l.MaxFilters {
+		return nil, app_error.Newf(app_error.CodeInvalidArgument, "%d filters exceed the maximum of %d", len(req.Filters), l.MaxFilters)
+	}
+
+	limited := *req
+	if limited.Take == 0 {
+		limited.Take = l.DefaultTake
+		if l.MaxTake > 0 && (limited.Take == 0 || limited.Take > l.MaxTake) {
+			limited.Take = l.MaxTake
+		}
+	}
+	return &limited, nil
+}
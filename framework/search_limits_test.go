@@ -0,0 +1,53 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+)
+
+func TestSearchLimits_AppliesDefaultTake(t *testing.T) {
+	limits := SearchLimits{DefaultTake: 25, MaxTake: 100}
+
+	req := NewSearchRequest()
+	limited, err := limits.Apply(req)
+	require.NoError(t, err)
+	assert.Equal(t, 25, limited.Take)
+	assert.Equal(t, 0, req.Take, "the caller's request is left untouched")
+
+	limited, err = limits.Apply(NewSearchRequest().WithTake(80))
+	require.NoError(t, err)
+	assert.Equal(t, 80, limited.Take)
+
+	// Without a default, MaxTake still bounds an unlimited request
+	limited, err = SearchLimits{MaxTake: 100}.Apply(NewSearchRequest())
+	require.NoError(t, err)
+	assert.Equal(t, 100, limited.Take)
+
+	limited, err = SearchLimits{}.Apply(NewSearchRequest())
+	require.NoError(t, err)
+	assert.Equal(t, 0, limited.Take)
+}
+
+func TestSearchLimits_RejectsOversizedRequests(t *testing.T) {
+	limits := SearchLimits{MaxTake: 100, MaxFilters: 2}
+
+	_, err := limits.Apply(NewSearchRequest().WithTake(101))
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+
+	_, err = limits.Apply(NewSearchRequest().WithTake(-1))
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+
+	_, err = limits.Apply(NewSearchRequest().AddEqual("a", 1).AddEqual("b", 2).AddEqual("c", 3))
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+}
+
+func TestSetSearchLimits(t *testing.T) {
+	defer SetSearchLimits(GetSearchLimits())
+
+	SetSearchLimits(SearchLimits{DefaultTake: 10})
+	assert.Equal(t, SearchLimits{DefaultTake: 10}, GetSearchLimits())
+}
@@ -0,0 +1,249 @@
+package framework
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/app_error"
+)
+
+// Reserved query parameters that are never treated as filters
+const (
+	QueryParamFilter = "filter"
+	QueryParamSort   = "sort"
+	QueryParamPage   = "page"
+	QueryParamTake   = "take"
+	QueryParamCursor = "cursor"
+	QueryParamTotal  = "total"
+)
+
+// SearchableModel lets a model declare the columns list endpoints may filter and sort on
+type SearchableModel interface {
+	SearchableColumns() []string
+}
+
+// SearchColumns returns T's searchable columns, or nil when T does not implement SearchableModel
+func SearchColumns[T any]() []string {
+	var model T
+	if searchable, ok := any(model).(SearchableModel); ok {
+		return searchable.SearchableColumns()
+	}
+	if searchable, ok := any(&model).(SearchableModel); ok {
+		return searchable.SearchableColumns()
+	}
+	return nil
+}
+
+// queryOperators maps the operator names accepted in filter[field][op] to filter operators
+var queryOperators = map[string]string{
+	"eq":        FilterOperator.Eq(),
+	"ne":        FilterOperator.Ne(),
+	"gt":        FilterOperator.Gt(),
+	"gte":       FilterOperator.Gte(),
+	"lt":        FilterOperator.Lt(),
+	"lte":       FilterOperator.Lte(),
+	"in":        FilterOperator.In(),
+	"not_in":    FilterOperator.NotIn(),
+	"like":      FilterOperator.Like(),
+	"notlike":   FilterOperator.NotLike(),
+	"isnull":    FilterOperator.IsNull(),
+	"isnotnull": FilterOperator.IsNotNull(),
+	"fulltext":  FilterOperator.FullText(),
+}
+
+// ParseSearchQuery builds a SearchRequest from URL query parameters such as
+// `?filter[status][eq]=active&filter[count][gte]=5&sort=-created_at&page=2&take=50&total=true`.
+//
+// `filter[field]=v` and the shorthand `field=v` mean eq. in and not_in take comma separated values,
+// and isnull/isnotnull ignore the value. cursor resumes from a previous page's next_cursor.
+// Only columns in allowed may be filtered or sorted on; anything else is an invalid_argument error.
+func ParseSearchQuery(query url.Values, allowed []string) (*SearchRequest, error) {
+	columns := make(map[string]bool, len(allowed))
+	for _, column := range allowed {
+		columns[column] = true
+	}
+
+	// Sorted so the generated SQL is stable for the same query
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	req := NewSearchRequest()
+	for _, key := range keys {
+		switch key {
+		case QueryParamSort, QueryParamPage, QueryParamTake, QueryParamCursor, QueryParamTotal:
+			continue
+		}
+		field, op, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if !columns[field] {
+			return nil, app_error.InvalidArgument("Unknown filter field: " + field)
+		}
+		operator, ok := queryOperators[op]
+		if !ok {
+			return nil, app_error.InvalidArgument("Unknown filter operator: " + op)
+		}
+		for _, value := range query[key] {
+			req.AddFilter(*queryFilter(field, operator, value))
+		}
+	}
+
+	if sortParam := query.Get(QueryParamSort); sortParam != "" {
+		direction := ""
+		var fields []string
+		for _, field := range strings.Split(sortParam, ",") {
+			fieldDirection := "ASC"
+			if strings.HasPrefix(field, "-") {
+				field, fieldDirection = field[1:], "DESC"
+			}
+			if !columns[field] {
+				return nil, app_error.InvalidArgument("Unknown sort field: " + field)
+			}
+			if direction != "" && direction != fieldDirection {
+				return nil, app_error.InvalidArgument("Sort fields must share one direction")
+			}
+			direction = fieldDirection
+			fields = append(fields, field)
+		}
+		req.SortBy(fields, direction)
+	}
+
+	var err error
+	if req.Page, err = queryInt(query, QueryParamPage); err != nil {
+		return nil, err
+	}
+	if req.Take, err = queryInt(query, QueryParamTake); err != nil {
+		return nil, err
+	}
+	if cursor := query.Get(QueryParamCursor); cursor != "" {
+		if _, err := DecodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		req.Cursor = cursor
+	}
+	if total := query.Get(QueryParamTotal); total != "" {
+		if req.IncludeTotal, err = strconv.ParseBool(total); err != nil {
+			return nil, app_error.InvalidArgument("Invalid " + QueryParamTotal + ": " + total)
+		}
+	}
+	return req, nil
+}
+
+// ValidateSearchRequest checks a client-supplied SearchRequest, such as a POST /search body, against the
+// same whitelist as ParseSearchQuery: filter fields, sort fields and columns must be in allowed, since
+// they are written into the SQL as given. GroupBy and Having are refused; they belong to Aggregate.
+func ValidateSearchRequest(req *SearchRequest, allowed []string) error {
+	columns := make(map[string]bool, len(allowed))
+	for _, column := range allowed {
+		columns[column] = true
+	}
+	operators := make(map[string]bool, len(queryOperators))
+	for _, operator := range queryOperators {
+		operators[operator] = true
+	}
+
+	for _, filter := range req.Filters {
+		if !columns[filter.Field] {
+			return app_error.InvalidArgument("Unknown filter field: " + filter.Field)
+		}
+		if !operators[filter.Operator] {
+			return app_error.InvalidArgument("Unknown filter operator: " + filter.Operator)
+		}
+		switch filter.Operator {
+		case FilterOperator.IsNull(), FilterOperator.IsNotNull(), FilterOperator.In(), FilterOperator.NotIn():
+		default:
+			if len(filter.Values) == 0 {
+				return app_error.InvalidArgument("Filter on " + filter.Field + " needs a value")
+			}
+		}
+	}
+	if req.Sort != nil {
+		for _, field := range req.Sort.Fields {
+			if !columns[field] {
+				return app_error.InvalidArgument("Unknown sort field: " + field)
+			}
+		}
+		switch strings.ToUpper(req.Sort.Direction) {
+		case "", "ASC", "DESC":
+		default:
+			return app_error.InvalidArgument("Invalid sort direction: " + req.Sort.Direction)
+		}
+	}
+	for _, column := range req.Columns {
+		if !columns[column] {
+			return app_error.InvalidArgument("Unknown column: " + column)
+		}
+	}
+	if req.IsAggregate() {
+		return app_error.InvalidArgument("Search does not group rows")
+	}
+	if req.Cursor != "" {
+		if _, err := DecodeCursor(req.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSearchQueryFor parses query with T's SearchableColumns as the whitelist
+func ParseSearchQueryFor[T any](query url.Values) (*SearchRequest, error) {
+	return ParseSearchQuery(query, SearchColumns[T]())
+}
+
+// parseFilterKey splits `filter[field][op]`, `filter[field]` and `field` into a field and operator name
+func parseFilterKey(key string) (string, string, error) {
+	if !strings.HasPrefix(key, QueryParamFilter+"[") {
+		return key, "eq", nil
+	}
+
+	rest := strings.TrimPrefix(key, QueryParamFilter)
+	var parts []string
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return "", "", app_error.InvalidArgument("Malformed filter: " + key)
+		}
+		parts = append(parts, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return parts[0], "eq", nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	}
+	return "", "", app_error.InvalidArgument("Malformed filter: " + key)
+}
+
+func queryFilter(field, operator, value string) *FilterPayload {
+	switch operator {
+	case FilterOperator.In(), FilterOperator.NotIn():
+		var values []interface{}
+		for _, part := range strings.Split(value, ",") {
+			values = append(values, part)
+		}
+		return baseFilter(field, operator, values...)
+	case FilterOperator.IsNull(), FilterOperator.IsNotNull():
+		return baseFilter(field, operator)
+	}
+	return baseFilter(field, operator, value)
+}
+
+func queryInt(query url.Values, key string) (int, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, app_error.InvalidArgument("Invalid " + key + ": " + raw)
+	}
+	return n, nil
+}
@@ -0,0 +1,138 @@
+package framework
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+)
+
+type searchableSample struct {
+	TestSample
+}
+
+func (searchableSample) SearchableColumns() []string {
+	return []string{"status", "count"}
+}
+
+func TestParseSearchQuery_Filters(t *testing.T) {
+	allowed := []string{"status", "count", "kind", "deleted_at", "name"}
+
+	query, err := url.ParseQuery("filter[status][eq]=active&filter[count][gte]=5&filter[kind][in]=a,b" +
+		"&filter[deleted_at][isnull]=&name=ada&filter[name][like]=a%25")
+	require.NoError(t, err)
+
+	req, err := ParseSearchQuery(query, allowed)
+	require.NoError(t, err)
+	assert.Equal(t, []FilterPayload{
+		*GreaterThanOrEqualFilter("count", "5"),
+		*baseFilter("deleted_at", FilterOperator.IsNull()),
+		*InFilter("kind", "a", "b"),
+		*LikeFilter("name", "a%"),
+		*EqualFilter("status", "active"),
+		*EqualFilter("name", "ada"),
+	}, req.Filters)
+
+	where, args := BuildWhereClause(req.Filters)
+	assert.Equal(t, "WHERE count >= $1 AND deleted_at IS NULL AND kind IN ($2,$3) AND name LIKE $4 AND status = $5 AND name = $6", where)
+	assert.Len(t, args, 6)
+}
+
+func TestParseSearchQuery_SortAndPaging(t *testing.T) {
+	query, err := url.ParseQuery("sort=-created_at,-count&page=2&take=50")
+	require.NoError(t, err)
+
+	req, err := ParseSearchQuery(query, []string{"created_at", "count"})
+	require.NoError(t, err)
+	assert.Equal(t, &SortPayload{Fields: []string{"created_at", "count"}, Direction: "DESC"}, req.Sort)
+	assert.Equal(t, 2, req.Page)
+	assert.Equal(t, 50, req.Take)
+	assert.Empty(t, req.Filters)
+}
+
+func TestParseSearchQuery_Rejects(t *testing.T) {
+	allowed := []string{"status", "name", "created_at"}
+
+	for name, raw := range map[string]string{
+		"unknown filter":   "password=x",
+		"unknown field":    "filter[password][eq]=x",
+		"injected field":   "filter[status%3Bdrop+table+users][eq]=x",
+		"unknown operator": "filter[status][regex]=x",
+		"malformed":        "filter[status=x",
+		"empty field":      "filter[][eq]=x",
+		"unknown sort":     "sort=password",
+		"mixed sort":       "sort=name,-created_at",
+		"bad page":         "page=two",
+		"negative take":    "take=-1",
+	} {
+		query, err := url.ParseQuery(raw)
+		require.NoError(t, err, name)
+		_, err = ParseSearchQuery(query, allowed)
+		assert.ErrorIs(t, err, app_error.InvalidArgument(""), name)
+	}
+}
+
+func TestValidateSearchRequest(t *testing.T) {
+	allowed := []string{"status", "name", "created_at"}
+
+	valid := NewSearchRequest().AddEqual("status", "active").AddIn("name").SortDesc("created_at").AddColumns("name", "status")
+	assert.NoError(t, ValidateSearchRequest(valid, allowed))
+
+	for name, req := range map[string]*SearchRequest{
+		"unknown field":    NewSearchRequest().AddEqual("password", "x"),
+		"injected field":   NewSearchRequest().AddEqual("status = 'x' OR 1=1 OR status", "x"),
+		"unknown operator": NewSearchRequest().AddFilter(FilterPayload{Field: "status", Operator: "regex", Values: []interface{}{"x"}}),
+		"missing value":    NewSearchRequest().AddFilter(FilterPayload{Field: "status", Operator: FilterOperator.Eq()}),
+		"unknown sort":     NewSearchRequest().SortAsc("password"),
+		"injected sort":    NewSearchRequest().SortBy([]string{"name"}, "ASC; DROP TABLE users"),
+		"unknown column":   NewSearchRequest().AddColumns("name", "(SELECT password FROM users LIMIT 1)"),
+		"group by":         NewSearchRequest().AddGroupBy("status"),
+		"bad cursor":       NewSearchRequest().WithCursor("not a cursor"),
+	} {
+		assert.ErrorIs(t, ValidateSearchRequest(req, allowed), app_error.InvalidArgument(""), name)
+	}
+}
+
+func TestParseSearchQueryFor_UsesModelColumns(t *testing.T) {
+	req, err := ParseSearchQueryFor[searchableSample](url.Values{"filter[count][lt]": {"3"}})
+	require.NoError(t, err)
+	assert.Equal(t, []FilterPayload{*LessThanFilter("count", "3")}, req.Filters)
+
+	_, err = ParseSearchQueryFor[searchableSample](url.Values{"name": {"x"}})
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+
+	// Models without SearchableColumns allow no filters at all
+	_, err = ParseSearchQueryFor[TestSample](url.Values{"status": {"active"}})
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+	assert.Nil(t, SearchColumns[TestSample]())
+}
+
+func TestFullTextFilter(t *testing.T) {
+	req := NewSearchRequest().AddEqual("status", "published").AddFullText("search_vector", "foo bar")
+	where, args := BuildWhereClause(req.Filters)
+	assert.Equal(t, "WHERE status = $1 AND search_vector @@ plainto_tsquery($2)", where)
+	assert.Equal(t, []interface{}{"published", "foo bar"}, args)
+
+	req = NewSearchRequest().AddFullText("search_vector", "foo", "simple").AddEqual("status", "published")
+	where, args = BuildWhereClause(req.Filters)
+	assert.Equal(t, "WHERE search_vector @@ plainto_tsquery($1::regconfig, $2) AND status = $3", where)
+	assert.Equal(t, []interface{}{"simple", "foo", "published"}, args)
+}
+
+func TestSortByRank(t *testing.T) {
+	req := NewSearchRequest().SortByRank("search_vector", "it's here", "english")
+	assert.Equal(t, " ORDER BY ts_rank(search_vector, plainto_tsquery('english'::regconfig, 'it''s here')) DESC",
+		BuildOrderByClause(req.Sort))
+}
+
+func TestParseSearchQuery_FullText(t *testing.T) {
+	query, err := url.ParseQuery("filter[search_vector][fulltext]=quarterly+report")
+	require.NoError(t, err)
+
+	req, err := ParseSearchQuery(query, []string{"search_vector"})
+	require.NoError(t, err)
+	assert.Equal(t, []FilterPayload{*FullTextFilter("search_vector", "quarterly report")}, req.Filters)
+}
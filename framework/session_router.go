@@ -0,0 +1,118 @@
+package framework
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// SessionController serves the authenticated user's device sessions
+type SessionController struct {
+	Auth *auth.AuthService
+}
+
+func NewSessionController(authService *auth.AuthService) *SessionController {
+	return &SessionController{Auth: authService}
+}
+
+// principal returns the authenticated user, responding 401 when absent
+func (ctrl *SessionController) principal(ctx request.Context) *request.Principal {
+	user := ctx.GetUserInfo()
+	if user == nil || user.ID == uuid.Nil {
+		RespondError(ctx, app_error.Unauthenticated(""))
+		return nil
+	}
+	return user
+}
+
+func (ctrl *SessionController) HandleList(ctx request.Context) {
+	user := ctrl.principal(ctx)
+	if user == nil {
+		return
+	}
+
+	sessions, err := ctrl.Auth.ListSessions(ctx.GetPgDB(), user.ID, user.ClientDeviceID)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+func (ctrl *SessionController) HandleRevoke(ctx request.Context) {
+	user := ctrl.principal(ctx)
+	if user == nil {
+		return
+	}
+
+	sessionID, err := uuid.Parse(ctx.GetRequestContext().Param("id"))
+	if err != nil {
+		RespondError(ctx, app_error.InvalidArgument("Invalid ID"))
+		return
+	}
+
+	if err := ctrl.Auth.RevokeSession(ctx.GetPgDB(), user.ID, sessionID, &user.ID); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			err = app_error.Wrap(err, app_error.CodeNotFound, "")
+		}
+		RespondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"revoked": sessionID})
+}
+
+func (ctrl *SessionController) HandleRevokeOthers(ctx request.Context) {
+	user := ctrl.principal(ctx)
+	if user == nil {
+		return
+	}
+	if user.ClientDeviceID == "" {
+		RespondError(ctx, app_error.InvalidArgument("Current device unknown"))
+		return
+	}
+
+	revoked, err := ctrl.Auth.RevokeOtherSessions(ctx.GetPgDB(), user.ID, user.ClientDeviceID, auth.RevocationReasonLogout)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// NewSessionRouteGroup returns routes for listing and revoking the caller's sessions:
+//
+//	GET    /api/v1/sessions      list active sessions, flagging the current device
+//	DELETE /api/v1/sessions/:id  revoke one session
+//	DELETE /api/v1/sessions      revoke every session except the current device
+func NewSessionRouteGroup(controller *SessionController) RouteGroup {
+	return RouteGroup{
+		Name:     "sessions",
+		BasePath: "/api/v1/sessions",
+		RouteList: []Route{
+			{
+				Method:        request.HTTPMethod.Get(),
+				Path:          "",
+				Handler:       controller.HandleList,
+				ShouldSkipTxn: true,
+			},
+			{
+				Method:        request.HTTPMethod.Delete(),
+				Path:          "/:id",
+				Handler:       controller.HandleRevoke,
+				ShouldSkipTxn: true,
+			},
+			{
+				Method:        request.HTTPMethod.Delete(),
+				Path:          "",
+				Handler:       controller.HandleRevokeOthers,
+				ShouldSkipTxn: true,
+			},
+		},
+	}
+}
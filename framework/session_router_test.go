@@ -0,0 +1,46 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yadunandan004/scaffold/auth"
+)
+
+func TestSessionRouteGroup_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		deviceID     string
+		expectedCode int
+	}{
+		{"invalid session id", "DELETE", "/api/v1/sessions/not-a-uuid", "device-1", 400},
+		{"revoke others without device", "DELETE", "/api/v1/sessions", "", 400},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := gin.New()
+			claims := &auth.UserClaims{UserID: uuid.New(), ClientDeviceID: tc.deviceID}
+			engine.Use(func(c *gin.Context) { c.Set(auth.UserClaimsKey, claims) })
+
+			authService := &auth.AuthService{}
+			registry := NewRegistry(engine, authService)
+			registry.AddGroup(NewSessionRouteGroup(NewSessionController(authService)))
+
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package framework
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// SSEHandlerFunc serves a Server-Sent Events stream; the stream ends when it returns
+type SSEHandlerFunc func(ctx request.Context, stream *SSEStream)
+
+// WebSocketHandlerFunc serves an upgraded WebSocket connection; the connection closes when it returns
+type WebSocketHandlerFunc func(ctx request.Context, conn *WebSocketConn)
+
+// SSEEvent is a single Server-Sent Event
+type SSEEvent struct {
+	ID    string
+	Event string
+	// Data is written as is when it is a string or []byte and JSON encoded otherwise
+	Data interface{}
+	// Retry asks the client to wait this long before reconnecting
+	Retry time.Duration
+}
+
+// SSEStream writes Server-Sent Events to a client; it is safe for concurrent use
+type SSEStream struct {
+	mu      sync.Mutex
+	writer  http.ResponseWriter
+	flusher http.Flusher
+	request *http.Request
+}
+
+// SSERoute builds a GET route that streams Server-Sent Events.
+// Auth, permissions and rate limiting apply when the stream opens; no transaction is started
+// since the connection is long lived. A comment is sent every keepAlive (0 disables) so proxies
+// keep idle streams open.
+func SSERoute(path string, keepAlive time.Duration, handler SSEHandlerFunc) Route {
+	return Route{
+		Method:        http.MethodGet,
+		Path:          path,
+		ShouldSkipTxn: true,
+		Handler: func(ctx request.Context) {
+			ginCtx := ctx.GetGinContext()
+			flusher, ok := ginCtx.Writer.(http.Flusher)
+			if !ok {
+				RespondError(ctx, app_error.Internal(errors.New("response writer does not support flushing")))
+				return
+			}
+
+			header := ginCtx.Writer.Header()
+			header.Set("Content-Type", "text/event-stream")
+			header.Set("Cache-Control", "no-cache")
+			header.Set("Connection", "keep-alive")
+			header.Set("X-Accel-Buffering", "no")
+			ginCtx.Writer.WriteHeader(http.StatusOK)
+			flusher.Flush()
+
+			stream := &SSEStream{writer: ginCtx.Writer, flusher: flusher, request: ginCtx.Request}
+			done := make(chan struct{})
+			defer close(done)
+			if keepAlive > 0 {
+				go stream.keepAlive(keepAlive, done)
+			}
+
+			handler(ctx, stream)
+		},
+	}
+}
+
+// Done is closed when the client disconnects
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.request.Context().Done()
+}
+
+// LastEventID returns the Last-Event-ID a reconnecting client sent, so the handler can resume
+func (s *SSEStream) LastEventID() string {
+	return s.request.Header.Get("Last-Event-ID")
+}
+
+// Send writes an event named event with data
+func (s *SSEStream) Send(event string, data interface{}) error {
+	return s.SendEvent(SSEEvent{Event: event, Data: data})
+}
+
+// SendEvent writes event and flushes it to the client
+func (s *SSEStream) SendEvent(event SSEEvent) error {
+	var data string
+	switch v := event.Data.(type) {
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode event data: %w", err)
+		}
+		data = string(encoded)
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + sanitizeSSEField(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + sanitizeSSEField(event.Event) + "\n")
+	}
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// Comment writes an SSE comment line, which clients ignore
+func (s *SSEStream) Comment(text string) error {
+	return s.write(": " + sanitizeSSEField(text) + "\n\n")
+}
+
+func (s *SSEStream) write(payload string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.request.Context().Err(); err != nil {
+		return err
+	}
+	if _, err := s.writer.Write([]byte(payload)); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *SSEStream) keepAlive(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.Done():
+			return
+		case <-ticker.C:
+			if err := s.Comment("keep-alive"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sanitizeSSEField strips line breaks, which would end the field early
+func sanitizeSSEField(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// WebSocketConfig configures a WebSocket route
+type WebSocketConfig struct {
+	// AllowedOrigins lists browser origins, e.g. "https://app.example.com", allowed to connect.
+	// "*" allows any origin. When empty only same host origins are allowed.
+	// Requests without an Origin header (non-browser clients) are always allowed.
+	AllowedOrigins []string
+
+	// MaxMessageBytes caps the size of a received message (default 1MB)
+	MaxMessageBytes int
+}
+
+// WebSocketConn is an upgraded WebSocket connection
+type WebSocketConn struct {
+	conn *websocket.Conn
+	done chan struct{}
+	once sync.Once
+}
+
+// WebSocketRoute builds a GET route that upgrades to a WebSocket connection.
+// Auth, permissions and rate limiting apply to the upgrade request and the handler receives
+// that request's Context, so the Principal and XID stay available for the life of the connection.
+// Origins are checked to prevent cross-site WebSocket hijacking with cookie credentials.
+func WebSocketRoute(path string, cfg WebSocketConfig, handler WebSocketHandlerFunc) Route {
+	if cfg.MaxMessageBytes <= 0 {
+		cfg.MaxMessageBytes = 1 << 20
+	}
+
+	return Route{
+		Method:        http.MethodGet,
+		Path:          path,
+		ShouldSkipTxn: true,
+		Handler: func(ctx request.Context) {
+			ginCtx := ctx.GetGinContext()
+			if _, ok := ginCtx.Writer.(http.Hijacker); !ok {
+				RespondError(ctx, app_error.Internal(errors.New("response writer does not support hijacking")))
+				return
+			}
+
+			server := websocket.Server{
+				Handshake: func(config *websocket.Config, req *http.Request) error {
+					return checkWebSocketOrigin(cfg.AllowedOrigins, req)
+				},
+				Handler: func(conn *websocket.Conn) {
+					conn.MaxPayloadBytes = cfg.MaxMessageBytes
+					wsConn := &WebSocketConn{conn: conn, done: make(chan struct{})}
+					defer wsConn.Close()
+					handler(ctx, wsConn)
+				},
+			}
+			server.ServeHTTP(ginCtx.Writer, ginCtx.Request)
+		},
+	}
+}
+
+// checkWebSocketOrigin allows requests without an Origin, from the request's own host or from an allowed origin
+func checkWebSocketOrigin(allowed []string, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return fmt.Errorf("invalid origin %q", origin)
+	}
+	if len(allowed) == 0 {
+		if strings.EqualFold(parsed.Host, req.Host) {
+			return nil
+		}
+		return fmt.Errorf("origin %q not allowed", origin)
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %q not allowed", origin)
+}
+
+// ReadJSON reads the next message and decodes it into v
+func (c *WebSocketConn) ReadJSON(v interface{}) error {
+	return websocket.JSON.Receive(c.conn, v)
+}
+
+// WriteJSON sends v as a JSON text message
+func (c *WebSocketConn) WriteJSON(v interface{}) error {
+	return websocket.JSON.Send(c.conn, v)
+}
+
+// ReadMessage reads the next text or binary message
+func (c *WebSocketConn) ReadMessage() ([]byte, error) {
+	var msg []byte
+	err := websocket.Message.Receive(c.conn, &msg)
+	return msg, err
+}
+
+// WriteText sends a text message
+func (c *WebSocketConn) WriteText(text string) error {
+	return websocket.Message.Send(c.conn, text)
+}
+
+// WriteBinary sends a binary message
+func (c *WebSocketConn) WriteBinary(data []byte) error {
+	return websocket.Message.Send(c.conn, data)
+}
+
+// SetDeadline sets the read and write deadline for the connection
+func (c *WebSocketConn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// Done is closed when the connection is closed
+func (c *WebSocketConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the connection; it is called automatically when the handler returns
+func (c *WebSocketConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+	return err
+}
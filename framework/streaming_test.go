@@ -0,0 +1,134 @@
+package framework
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/yadunandan004/scaffold/auth"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// newStreamingServer serves routes behind a registry whose callers are authenticated as userID
+func newStreamingServer(t *testing.T, userID uuid.UUID, routes ...Route) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set(auth.UserClaimsKey, &auth.UserClaims{UserID: userID, Email: "stream@example.com"})
+		}
+	})
+	registry := NewRegistry(engine, &auth.AuthService{})
+	registry.AddGroup(RouteGroup{Name: "stream", BasePath: "/api/stream", RouteList: routes})
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSSERoute(t *testing.T) {
+	userID := uuid.New()
+	var xid uuid.UUID
+	server := newStreamingServer(t, userID, SSERoute("/events", 0, func(ctx request.Context, stream *SSEStream) {
+		xid = ctx.XID()
+		assert.NoError(t, stream.Send("user", gin.H{"id": ctx.GetUserInfo().ID}))
+		assert.NoError(t, stream.SendEvent(SSEEvent{ID: "2", Event: "note", Data: "line one\nline two"}))
+	}))
+
+	req, _ := http.NewRequest("GET", server.URL+"/api/stream/events", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var body strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		body.WriteString(scanner.Text() + "\n")
+	}
+	assert.Equal(t,
+		"event: user\ndata: {\"id\":\""+userID.String()+"\"}\n\n"+
+			"id: 2\nevent: note\ndata: line one\ndata: line two\n\n",
+		body.String())
+	assert.NotEqual(t, uuid.Nil, xid)
+}
+
+func TestSSERoute_RequiresAuth(t *testing.T) {
+	called := false
+	server := newStreamingServer(t, uuid.New(), SSERoute("/events", 0, func(ctx request.Context, stream *SSEStream) {
+		called = true
+	}))
+
+	resp, err := http.Get(server.URL + "/api/stream/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 401, resp.StatusCode)
+	assert.False(t, called)
+}
+
+func TestWebSocketRoute(t *testing.T) {
+	userID := uuid.New()
+	server := newStreamingServer(t, userID, WebSocketRoute("/ws", WebSocketConfig{}, func(ctx request.Context, conn *WebSocketConn) {
+		var msg map[string]string
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		_ = conn.WriteJSON(map[string]string{"echo": msg["text"], "user": ctx.GetUserInfo().ID.String()})
+	}))
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/stream/ws"
+	config, err := websocket.NewConfig(wsURL, server.URL)
+	require.NoError(t, err)
+	config.Header.Set("Authorization", "Bearer token")
+
+	conn, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	require.NoError(t, websocket.JSON.Send(conn, map[string]string{"text": "hello"}))
+	var reply map[string]string
+	require.NoError(t, websocket.JSON.Receive(conn, &reply))
+	assert.Equal(t, "hello", reply["echo"])
+	assert.Equal(t, userID.String(), reply["user"])
+}
+
+func TestWebSocketRoute_RejectsCrossOrigin(t *testing.T) {
+	server := newStreamingServer(t, uuid.New(), WebSocketRoute("/ws", WebSocketConfig{}, func(ctx request.Context, conn *WebSocketConn) {}))
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/stream/ws"
+	config, err := websocket.NewConfig(wsURL, "https://evil.example.com")
+	require.NoError(t, err)
+	config.Header.Set("Authorization", "Bearer token")
+
+	_, err = websocket.DialConfig(config)
+	assert.Error(t, err)
+}
+
+func TestCheckWebSocketOrigin(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://api.example.com/ws", nil)
+	assert.NoError(t, checkWebSocketOrigin(nil, req))
+
+	req.Header.Set("Origin", "https://api.example.com")
+	assert.NoError(t, checkWebSocketOrigin(nil, req))
+
+	req.Header.Set("Origin", "https://app.example.com")
+	assert.Error(t, checkWebSocketOrigin(nil, req))
+	assert.NoError(t, checkWebSocketOrigin([]string{"https://app.example.com/"}, req))
+	assert.NoError(t, checkWebSocketOrigin([]string{"*"}, req))
+}
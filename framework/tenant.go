@@ -0,0 +1,273 @@
+package framework
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// TenantStrategy selects how a model's rows are isolated between tenants
+type TenantStrategy int
+
+const (
+	// TenantColumnStrategy keeps all tenants in one table, filtered on a tenant column
+	TenantColumnStrategy TenantStrategy = iota + 1
+	// TenantSchemaStrategy keeps each tenant in its own schema, selected per transaction with search_path
+	TenantSchemaStrategy
+)
+
+// ErrTenantRequired is returned when a tenant-scoped model is accessed by a request without a tenant
+var ErrTenantRequired = app_error.PermissionDenied("Tenant required")
+
+// ErrTenantMismatch is returned when an entity names a different tenant than the request
+var ErrTenantMismatch = app_error.PermissionDenied("Entity belongs to another tenant")
+
+// TenantScoped marks a model whose rows belong to a tenant. The Postgres repositories scope every
+// query on such models to the tenant of the request principal.
+type TenantScoped interface {
+	TenantStrategy() TenantStrategy
+}
+
+// TenantColumnModel is implemented by models using TenantColumnStrategy; embed TenantModel for the defaults
+type TenantColumnModel interface {
+	TenantScoped
+	TenantColumn() string
+	GetTenantID() string
+}
+
+type tenantSetter interface {
+	SetTenantID(tenantID string)
+}
+
+// TenantModel adds a tenant_id column and the TenantColumnModel methods to a model
+type TenantModel struct {
+	TenantID string `json:"tenant_id" orm:"column:tenant_id;type:varchar(100);not null"`
+}
+
+func (t TenantModel) TenantStrategy() TenantStrategy {
+	return TenantColumnStrategy
+}
+
+func (t TenantModel) TenantColumn() string {
+	return "tenant_id"
+}
+
+func (t TenantModel) GetTenantID() string {
+	return t.TenantID
+}
+
+func (t *TenantModel) SetTenantID(tenantID string) {
+	t.TenantID = tenantID
+}
+
+// ErrInvalidTenantID is returned when a tenant ID can't name a schema for a TenantSchemaStrategy model
+var ErrInvalidTenantID = app_error.PermissionDenied("Tenant ID is not a valid schema name")
+
+// tenantSchemaID matches tenant IDs the default TenantSchemaName accepts; 56 bytes keeps "tenant_<id>"
+// within Postgres's 63 byte identifier limit, past which names are truncated and could collide
+var tenantSchemaID = regexp.MustCompile(`^[a-z0-9_]{1,56}$`)
+
+// TenantSchemaName maps a tenant to its schema for TenantSchemaStrategy models. The default accepts
+// lowercase letters, digits and underscores only and rejects other IDs with ErrInvalidTenantID, rather
+// than rewriting them, so two tenants can never share a schema.
+var TenantSchemaName = func(tenantID string) (string, error) {
+	if !tenantSchemaID.MatchString(tenantID) {
+		return "", ErrInvalidTenantID
+	}
+	return "tenant_" + tenantID, nil
+}
+
+// TenantID returns the tenant of the request principal, or "" when there is none
+func TenantID(ctx Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if user := ctx.GetUserInfo(); user != nil {
+		return user.TenantID
+	}
+	return ""
+}
+
+// requestSchema returns the schema T's generated SQL is qualified with for the request: the tenant's
+// schema for TenantSchemaStrategy models, otherwise "" so the ORM's default schema applies. Callers
+// resolve scopeTenant first, which rejects tenant IDs TenantSchemaName refuses.
+func requestSchema[T any](ctx Context) string {
+	var model T
+	if scoped, ok := any(model).(TenantScoped); ok && scoped.TenantStrategy() == TenantSchemaStrategy {
+		if tenantID := TenantID(ctx); tenantID != "" {
+			if schema, err := TenantSchemaName(tenantID); err == nil {
+				return schema
+			}
+		}
+	}
+	return ""
+}
+
+// qualifiedTable returns T's table name as the ORM's statements name it for the request, for SQL the
+// repositories build themselves
+func qualifiedTable[T tableModel](ctx Context) string {
+	if metadata := orm.GetMetadata[T](); metadata != nil {
+		return metadata.QualifiedTable(requestSchema[T](ctx))
+	}
+	var model T
+	return model.TableName()
+}
+
+// tenantScope is how one repository call on a tenant-scoped model is restricted
+type tenantScope struct {
+	tenantID string
+	column   string // set for TenantColumnStrategy
+}
+
+// scopeTenant resolves the tenant scope for T, returning nil for models that aren't tenant-scoped.
+// For TenantSchemaStrategy it points the request transaction's search_path at the tenant schema.
+func scopeTenant[T any](ctx Context) (*tenantScope, error) {
+	var model T
+	scoped, ok := any(model).(TenantScoped)
+	if !ok {
+		return nil, nil
+	}
+	tenantID := TenantID(ctx)
+	if tenantID == "" {
+		return nil, ErrTenantRequired
+	}
+
+	switch scoped.TenantStrategy() {
+	case TenantColumnStrategy:
+		columnModel, ok := scoped.(TenantColumnModel)
+		if !ok {
+			return nil, fmt.Errorf("%T uses the tenant column strategy but does not implement TenantColumnModel", model)
+		}
+		return &tenantScope{tenantID: tenantID, column: columnModel.TenantColumn()}, nil
+	case TenantSchemaStrategy:
+		// SET LOCAL only lasts for the transaction; outside one, pooled connections would leak the setting
+		query := ctx.GetPgTxn()
+		if query == nil {
+			return nil, fmt.Errorf("%T uses the tenant schema strategy and needs a request transaction", model)
+		}
+		schema, err := TenantSchemaName(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		searchPath := fmt.Sprintf(`"%s", public`, schema)
+		if _, err := query.Exec("SELECT set_config('search_path', $1, true)", searchPath); err != nil {
+			return nil, fmt.Errorf("set tenant search_path: %w", err)
+		}
+		return &tenantScope{tenantID: tenantID}, nil
+	default:
+		return nil, fmt.Errorf("%T has unknown tenant strategy %d", model, scoped.TenantStrategy())
+	}
+}
+
+// whereClause builds the WHERE clause for filters with the tenant condition, numbering placeholders from
+// argCount. The filters are parenthesized ahead of the tenant conjunct, so an OR among them can't widen
+// the match to other tenants' rows.
+func (s *tenantScope) whereClause(filters []FilterPayload, argCount *int) (string, []interface{}) {
+	conditions, args := joinConditions(filters, argCount)
+	if s == nil || s.column == "" {
+		if conditions == "" {
+			return "", nil
+		}
+		return "WHERE " + conditions, args
+	}
+
+	tenant := fmt.Sprintf("%s = $%d", s.column, *argCount)
+	*argCount++
+	args = append(args, s.tenantID)
+	if conditions == "" {
+		return "WHERE " + tenant, args
+	}
+	return fmt.Sprintf("WHERE (%s) AND %s", conditions, tenant), args
+}
+
+// owns reports whether a loaded entity belongs to the scoped tenant
+func (s *tenantScope) owns(entity interface{}) bool {
+	if s == nil || s.column == "" {
+		return true
+	}
+	model, ok := entity.(TenantColumnModel)
+	return ok && model.GetTenantID() == s.tenantID
+}
+
+// stampTenant sets the scoped tenant on entities without one and rejects entities naming another tenant
+func stampTenant[T any](s *tenantScope, entities ...*T) error {
+	if s == nil || s.column == "" {
+		return nil
+	}
+	for _, entity := range entities {
+		current := any(*entity).(TenantColumnModel).GetTenantID()
+		switch {
+		case current == s.tenantID:
+		case current != "":
+			return ErrTenantMismatch
+		default:
+			setter, ok := any(entity).(tenantSetter)
+			if !ok {
+				return fmt.Errorf("%T has no tenant set and no SetTenantID method", *entity)
+			}
+			setter.SetTenantID(s.tenantID)
+		}
+	}
+	return nil
+}
+
+// ensureTenantOwns fails when any existing row for entities belongs to another tenant, so a write
+// addressed by primary key can't reach across tenants. Rows that don't exist yet are left to the write.
+func ensureTenantOwns[T BaseReadModel[ID], ID IDType](ctx Context, s *tenantScope, entities ...*T) error {
+	if s == nil || s.column == "" || len(entities) == 0 {
+		return nil
+	}
+	metadata := orm.GetMetadata[T]()
+	if metadata == nil {
+		return fmt.Errorf("no orm metadata registered for %T", *entities[0])
+	}
+
+	args := make([]interface{}, 0, len(entities)+1)
+	args = append(args, s.tenantID)
+	placeholders := make([]string, len(entities))
+	for i, entity := range entities {
+		args = append(args, (*entity).GetID())
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s <> $1 AND %s IN (%s)",
+		qualifiedTable[T](ctx), s.column, metadata.IDColumn, strings.Join(placeholders, ","))
+
+	var foreign int
+	var err error
+	if query := ctx.GetPgTxn(); query != nil {
+		foreign, err = query.Count(countQuery, args...)
+	} else {
+		db := postgres.GetDB()
+		if db == nil || db.DB == nil {
+			return fmt.Errorf("no database connection available")
+		}
+		err = db.QueryRowContext(ctx.GetCtx(), countQuery, args...).Scan(&foreign)
+	}
+	if err != nil {
+		return fmt.Errorf("check tenant of %s: %w", metadata.TableName, err)
+	}
+	if foreign > 0 {
+		// Report another tenant's rows as missing rather than revealing they exist
+		return orm.NewNotFoundError(metadata.TableName, nil)
+	}
+	return nil
+}
+
+// upsertOptions guards the DO UPDATE of T's upserts so a conflicting row is only overwritten when it
+// belongs to the scoped tenant, on top of T's own UpsertWhere. ensureTenantOwns only covers the primary
+// key, while OnConflict may name other columns whose values another tenant's row can share.
+func upsertOptions[T tableModel](s *tenantScope, entity T) []orm.UpsertOptions {
+	if s == nil || s.column == "" {
+		return nil
+	}
+	// The existing row is referred to by the unqualified table name, even when the INSERT names a schema
+	where := fmt.Sprintf("%s.%s = EXCLUDED.%s", entity.TableName(), s.column, s.column)
+	if provider, ok := any(entity).(interface{ UpsertWhere() string }); ok && provider.UpsertWhere() != "" {
+		where = fmt.Sprintf("(%s) AND %s", provider.UpsertWhere(), where)
+	}
+	return []orm.UpsertOptions{{Where: where}}
+}
@@ -0,0 +1,139 @@
+package framework
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+type tenantSample struct {
+	TenantModel
+	Name string
+}
+
+type schemaTenantSample struct{}
+
+func (schemaTenantSample) TenantStrategy() TenantStrategy {
+	return TenantSchemaStrategy
+}
+
+type schemaTenantRecord struct {
+	ID string `orm:"column:id;pk"`
+}
+
+func (schemaTenantRecord) TenantStrategy() TenantStrategy {
+	return TenantSchemaStrategy
+}
+
+func (schemaTenantRecord) TableName() string {
+	return "schema_records"
+}
+
+type tenantEvent struct {
+	TenantModel
+	Version int
+}
+
+func (tenantEvent) TableName() string {
+	return "events"
+}
+
+type guardedTenantEvent struct {
+	tenantEvent
+}
+
+func (guardedTenantEvent) UpsertWhere() string {
+	return "EXCLUDED.version > events.version"
+}
+
+func TestScopeTenant_IgnoresUnscopedModels(t *testing.T) {
+	scope, err := scopeTenant[TestSample](request.NewTestContext())
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+}
+
+func TestScopeTenant_RequiresTenant(t *testing.T) {
+	_, err := scopeTenant[tenantSample](request.NewTestContext())
+	assert.ErrorIs(t, err, ErrTenantRequired)
+}
+
+func TestScopeTenant_ScopesWhereClause(t *testing.T) {
+	scope, err := scopeTenant[tenantSample](request.NewTestContext(request.WithTestTenant("acme")))
+	require.NoError(t, err)
+
+	filters := []FilterPayload{
+		{Field: "name", Operator: FilterOperator.Eq(), Values: []interface{}{"x"}},
+		{Field: "status = 'open' OR status", Operator: FilterOperator.Eq(), Values: []interface{}{"closed"}},
+	}
+	argCount := 1
+	where, args := scope.whereClause(filters, &argCount)
+	assert.Equal(t, "WHERE (name = $1 AND status = 'open' OR status = $2) AND tenant_id = $3", where)
+	assert.Equal(t, []interface{}{"x", "closed", "acme"}, args)
+	assert.Equal(t, 4, argCount)
+
+	argCount = 1
+	where, args = scope.whereClause(nil, &argCount)
+	assert.Equal(t, "WHERE tenant_id = $1", where)
+	assert.Equal(t, []interface{}{"acme"}, args)
+}
+
+func TestScopeTenant_SchemaStrategyNeedsTransaction(t *testing.T) {
+	_, err := scopeTenant[schemaTenantSample](request.NewTestContext(request.WithTestTenant("acme")))
+	assert.ErrorContains(t, err, "needs a request transaction")
+}
+
+func TestTenantScope_Owns(t *testing.T) {
+	scope := &tenantScope{tenantID: "acme", column: "tenant_id"}
+	assert.True(t, scope.owns(tenantSample{TenantModel: TenantModel{TenantID: "acme"}}))
+	assert.False(t, scope.owns(tenantSample{TenantModel: TenantModel{TenantID: "globex"}}))
+}
+
+func TestStampTenant(t *testing.T) {
+	scope := &tenantScope{tenantID: "acme", column: "tenant_id"}
+
+	unset := &tenantSample{Name: "a"}
+	require.NoError(t, stampTenant(scope, unset))
+	assert.Equal(t, "acme", unset.TenantID)
+
+	foreign := &tenantSample{TenantModel: TenantModel{TenantID: "globex"}}
+	assert.ErrorIs(t, stampTenant(scope, foreign), ErrTenantMismatch)
+	assert.Equal(t, "globex", foreign.TenantID)
+}
+
+func TestTenantSchemaName(t *testing.T) {
+	schema, err := TenantSchemaName("acme_corp")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_acme_corp", schema)
+
+	for _, tenantID := range []string{"acme-corp", "ACME_corp", "acme.corp", `a";drop table x`, strings.Repeat("a", 57)} {
+		_, err := TenantSchemaName(tenantID)
+		assert.ErrorIs(t, err, ErrInvalidTenantID, tenantID)
+	}
+}
+
+func TestQualifiedTable_UsesTenantSchema(t *testing.T) {
+	orm.RegisterModel[schemaTenantRecord]()
+
+	assert.Equal(t, "tenant_acme.schema_records", qualifiedTable[schemaTenantRecord](request.NewTestContext(request.WithTestTenant("acme"))))
+	assert.Equal(t, "schema_records", qualifiedTable[schemaTenantRecord](request.NewTestContext()))
+
+	orm.SetDefaultSchema("staging")
+	defer orm.SetDefaultSchema("")
+	assert.Equal(t, "staging.schema_records", qualifiedTable[schemaTenantRecord](request.NewTestContext()))
+}
+
+func TestUpsertOptions_GuardsOtherTenantsRows(t *testing.T) {
+	scope := &tenantScope{tenantID: "acme", column: "tenant_id"}
+
+	assert.Nil(t, upsertOptions[tenantEvent](nil, tenantEvent{}))
+	assert.Nil(t, upsertOptions[tenantEvent](&tenantScope{tenantID: "acme"}, tenantEvent{}))
+	assert.Equal(t, []orm.UpsertOptions{{Where: "events.tenant_id = EXCLUDED.tenant_id"}},
+		upsertOptions(scope, tenantEvent{}))
+	assert.Equal(t, []orm.UpsertOptions{{Where: "(EXCLUDED.version > events.version) AND events.tenant_id = EXCLUDED.tenant_id"}},
+		upsertOptions(scope, guardedTenantEvent{}))
+}
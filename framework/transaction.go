@@ -0,0 +1,47 @@
+package framework
+
+import (
+	"fmt"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// WithTxn runs fn inside a transaction begun with request.BeginTransaction, committing when fn returns
+// nil and rolling back when it returns an error or panics; the panic is re-raised after the rollback.
+// When ctx already carries a transaction, such as a registry route's, fn joins it and the owner of
+// that transaction decides whether it commits.
+//
+//	err := framework.WithTxn(ctx, func(txCtx request.Context) error {
+//	    if _, err := orders.Create(txCtx, order); err != nil {
+//	        return err
+//	    }
+//	    return stock.Reserve(txCtx, order.Items)
+//	})
+func WithTxn(ctx request.Context, fn func(txCtx request.Context) error, opts ...request.TxOptions) (err error) {
+	if request.GetQuery(ctx) != nil {
+		return fn(ctx)
+	}
+
+	base := ctx.GetCtx()
+	query, err := request.BeginTransaction(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// Later calls on ctx must not see the finished transaction
+		ctx.SetCtx(base)
+		if rec := recover(); rec != nil {
+			_ = query.Rollback()
+			panic(rec)
+		}
+		if err != nil {
+			_ = query.Rollback()
+			return
+		}
+		if commitErr := query.Commit(); commitErr != nil {
+			err = fmt.Errorf("failed to commit transaction: %w", commitErr)
+		}
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,83 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func newTxnSample(name string) *TestSample {
+	return &TestSample{BaseModelImpl: BaseModelImpl[uuid.UUID]{ID: uuid.New()}, Name: name, Status: "active", Metadata: JSONB{}}
+}
+
+func TestWithTxn_CommitsOnSuccess(t *testing.T) {
+	service := NewBaseService[TestSample](NewTestSampleRepository())
+	ctx := request.NewTestContext()
+	sample := newTxnSample("WithTxn commit")
+
+	err := WithTxn(ctx, func(txCtx request.Context) error {
+		_, err := service.Create(txCtx, sample)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Nil(t, ctx.GetPgTxn(), "the finished transaction is removed from ctx")
+
+	found, err := service.GetByID(request.NewTestContext(), sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sample.Name, found.Name)
+}
+
+func TestWithTxn_RollsBackOnError(t *testing.T) {
+	service := NewBaseService[TestSample](NewTestSampleRepository())
+	sample := newTxnSample("WithTxn rollback")
+	failure := errors.New("reservation failed")
+
+	err := WithTxn(request.NewTestContext(), func(txCtx request.Context) error {
+		if _, err := service.Create(txCtx, sample); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	_, err = service.GetByID(request.NewTestContext(), sample.ID)
+	assert.Error(t, err)
+}
+
+func TestWithTxn_RollsBackOnPanic(t *testing.T) {
+	service := NewBaseService[TestSample](NewTestSampleRepository())
+	sample := newTxnSample("WithTxn panic")
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = WithTxn(request.NewTestContext(), func(txCtx request.Context) error {
+			if _, err := service.Create(txCtx, sample); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+
+	_, err := service.GetByID(request.NewTestContext(), sample.ID)
+	assert.Error(t, err)
+}
+
+func TestWithTxn_JoinsExistingTransaction(t *testing.T) {
+	ctx := request.NewTestContext()
+	outer := &orm.Query{Ctx: context.Background()}
+	ctx.SetCtx(context.WithValue(ctx.GetCtx(), request.QueryKey{}, outer))
+
+	failure := errors.New("failed")
+	err := WithTxn(ctx, func(txCtx request.Context) error {
+		assert.Same(t, outer, txCtx.GetPgTxn())
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Same(t, outer, ctx.GetPgTxn(), "the outer transaction is left to its owner")
+}
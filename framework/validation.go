@@ -0,0 +1,183 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// FieldError describes one failed validation rule, using the field's JSON path
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var (
+	validate     *validator.Validate
+	validateOnce sync.Once
+)
+
+// Validator returns the shared validator, which reads `validate` struct tags and reports JSON field names
+func Validator() *validator.Validate {
+	validateOnce.Do(func() {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	})
+	return validate
+}
+
+// RegisterValidation adds a custom rule usable in `validate` tags
+func RegisterValidation(tag string, fn validator.Func) error {
+	return Validator().RegisterValidation(tag, fn)
+}
+
+// BindAndValidate decodes the JSON body into a new T and validates its `validate` tags.
+// Failures are invalid_argument AppErrors; validation failures list each field under details.fields.
+func BindAndValidate[T any](ctx request.Context) (*T, error) {
+	var payload T
+	if err := ctx.GetRequestContext().ShouldBindJSON(&payload); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return nil, validationError(validationErrs, "")
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, bodyTooLarge(maxBytesErr.Limit)
+		}
+		return nil, app_error.Wrap(err, app_error.CodeInvalidArgument, "Invalid request body: "+err.Error())
+	}
+	if err := Validate(&payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// Validate checks v's `validate` tags; v may be a struct, a pointer to one or a slice of either.
+// It returns an invalid_argument AppError listing every failed field.
+func Validate(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	var fields []FieldError
+	switch value.Kind() {
+	case reflect.Struct:
+		fields = validateStruct(value.Interface(), "")
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			elem := value.Index(i)
+			for elem.Kind() == reflect.Ptr && !elem.IsNil() {
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct {
+				fields = append(fields, validateStruct(elem.Interface(), fmt.Sprintf("[%d]", i))...)
+			}
+		}
+	default:
+		return nil
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return app_error.InvalidArgument("Validation failed").WithDetail("fields", fields)
+}
+
+func validateStruct(v interface{}, prefix string) []FieldError {
+	err := Validator().Struct(v)
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	return fieldErrors(validationErrs, prefix)
+}
+
+func validationError(errs validator.ValidationErrors, prefix string) error {
+	return app_error.InvalidArgument("Validation failed").WithDetail("fields", fieldErrors(errs, prefix))
+}
+
+func fieldErrors(errs validator.ValidationErrors, prefix string) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		field := fe.Namespace()
+		// Drop the top level struct name, e.g. "CreateUser.address.city" -> "address.city"
+		if idx := strings.Index(field, "."); idx >= 0 {
+			field = field[idx+1:]
+		}
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+		fields = append(fields, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	return fields
+}
+
+// fieldErrorMessage describes the common rules in plain words
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "min", "gte":
+		if isLengthKind(fe.Kind()) {
+			return "must be at least " + fe.Param() + " characters or items long"
+		}
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		if isLengthKind(fe.Kind()) {
+			return "must be at most " + fe.Param() + " characters or items long"
+		}
+		return "must be at most " + fe.Param()
+	case "len":
+		return "must be exactly " + fe.Param() + " characters or items long"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
+
+func isLengthKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
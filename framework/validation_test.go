@@ -0,0 +1,92 @@
+package framework
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+type createUserPayload struct {
+	Name    string `json:"name" validate:"required"`
+	Email   string `json:"email" validate:"required,email"`
+	Role    string `json:"role" validate:"omitempty,oneof=admin member"`
+	Address struct {
+		City string `json:"city" validate:"required"`
+	} `json:"address"`
+}
+
+func jsonContext(t *testing.T, body string) request.Context {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request, _ = http.NewRequest("POST", "/users", strings.NewReader(body))
+	ginCtx.Request.Header.Set("Content-Type", "application/json")
+	return request.NewApiContextForHttp(ginCtx)
+}
+
+func validationFields(t *testing.T, err error) []FieldError {
+	t.Helper()
+	var appErr *app_error.AppError
+	require.True(t, errors.As(err, &appErr), "expected AppError, got %v", err)
+	assert.Equal(t, app_error.CodeInvalidArgument, appErr.Code)
+	fields, ok := appErr.Details["fields"].([]FieldError)
+	require.True(t, ok)
+	return fields
+}
+
+func TestBindAndValidate_Valid(t *testing.T) {
+	ctx := jsonContext(t, `{"name":"Ada","email":"ada@example.com","address":{"city":"London"}}`)
+
+	payload, err := BindAndValidate[createUserPayload](ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", payload.Name)
+	assert.Equal(t, "London", payload.Address.City)
+}
+
+func TestBindAndValidate_FieldErrors(t *testing.T) {
+	ctx := jsonContext(t, `{"email":"not-an-email","role":"owner"}`)
+
+	_, err := BindAndValidate[createUserPayload](ctx)
+	fields := validationFields(t, err)
+
+	byField := map[string]FieldError{}
+	for _, f := range fields {
+		byField[f.Field] = f
+	}
+	require.Len(t, byField, 4)
+	assert.Equal(t, "is required", byField["name"].Message)
+	assert.Equal(t, "email", byField["email"].Rule)
+	assert.Equal(t, "must be one of: admin, member", byField["role"].Message)
+	assert.Equal(t, "required", byField["address.city"].Rule)
+}
+
+func TestBindAndValidate_MalformedBody(t *testing.T) {
+	ctx := jsonContext(t, `{"name":`)
+
+	_, err := BindAndValidate[createUserPayload](ctx)
+	var appErr *app_error.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, app_error.CodeInvalidArgument, appErr.Code)
+	assert.Contains(t, appErr.Message, "Invalid request body")
+}
+
+func TestValidate_SliceIndexesFields(t *testing.T) {
+	valid := createUserPayload{Name: "Ada", Email: "ada@example.com"}
+	valid.Address.City = "London"
+	invalid := valid
+	invalid.Email = ""
+
+	fields := validationFields(t, Validate([]*createUserPayload{&valid, &invalid}))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "[1].email", fields[0].Field)
+	assert.NoError(t, Validate([]*createUserPayload{&valid}))
+}
@@ -0,0 +1,101 @@
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/orm"
+)
+
+// whereClauseFor builds the WHERE clause of a DeleteWhere or UpdateWhere. A request whose own filters
+// match every row is refused, so a missing filter can't rewrite or empty the whole table.
+func whereClauseFor(scope *tenantScope, req *SearchRequest) (string, []interface{}, error) {
+	if req == nil {
+		return "", nil, app_error.InvalidArgument("At least one filter is required")
+	}
+	if clause, _ := BuildWhereClause(req.Filters); clause == "" {
+		return "", nil, app_error.InvalidArgument("At least one filter is required")
+	}
+	argCount := 1
+	clause, args := scope.whereClause(req.Filters, &argCount)
+	return clause, args, nil
+}
+
+func buildDeleteWhereSQL(table, whereClause string) string {
+	return fmt.Sprintf("DELETE FROM %s %s RETURNING *", table, whereClause)
+}
+
+// buildUpdateWhereSQL returns the UPDATE for changes, numbering its placeholders after the whereArgs
+// already bound by whereClause. Only T's columns may be changed, and never its ID, created_at or tenant
+// column; updated_at is set to now unless changes sets it.
+func buildUpdateWhereSQL[T any](table, whereClause string, whereArgs []interface{}, changes map[string]interface{}) (string, []interface{}, error) {
+	var model T
+	if len(changes) == 0 {
+		return "", nil, app_error.InvalidArgument("No changes to apply")
+	}
+	metadata := orm.GetMetadata[T]()
+	if metadata == nil {
+		return "", nil, fmt.Errorf("no orm metadata registered for %T", model)
+	}
+
+	immutable := map[string]bool{metadata.IDColumn: true, "created_at": true}
+	if tenantModel, ok := any(model).(TenantColumnModel); ok {
+		immutable[tenantModel.TenantColumn()] = true
+	}
+	columns := make(map[string]bool, len(metadata.Fields))
+	for _, field := range metadata.Fields {
+		columns[field.Column] = true
+		if field.IsReadOnly {
+			immutable[field.Column] = true
+		}
+	}
+
+	values := make(map[string]interface{}, len(changes)+1)
+	for column, value := range changes {
+		if !columns[column] {
+			return "", nil, app_error.InvalidArgument("Unknown column: " + column)
+		}
+		if immutable[column] {
+			return "", nil, app_error.InvalidArgument("Column can't be changed: " + column)
+		}
+		values[column] = value
+	}
+	if _, ok := values["updated_at"]; !ok && columns["updated_at"] {
+		values["updated_at"] = time.Now()
+	}
+
+	// Sorted so the generated SQL is stable for the same changes
+	names := make([]string, 0, len(values))
+	for column := range values {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+
+	args := append([]interface{}{}, whereArgs...)
+	assignments := make([]string, len(names))
+	for i, column := range names {
+		args = append(args, values[column])
+		assignments[i] = fmt.Sprintf("%s = $%d", column, len(args))
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s %s RETURNING *", table, strings.Join(assignments, ", "), whereClause)
+	return query, args, nil
+}
+
+// queryWhere runs a data-modifying statement with RETURNING * and scans the returned rows
+func queryWhere[T any](ctx Context, query string, args ...interface{}) ([]*T, error) {
+	executor := getExecutor[T](ctx)
+	if executor == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+	if tx, ok := executor.(*orm.Transaction[T]); ok {
+		return tx.FindByQuery(ctx.GetPgTxn(), query, args...)
+	}
+	db, ok := executor.(*orm.DB[T])
+	if !ok || db == nil {
+		return nil, fmt.Errorf("invalid database executor")
+	}
+	return db.FindByQuery(ctx.GetCtx(), query, args...)
+}
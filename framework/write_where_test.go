@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/app_error"
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestWhereClauseFor_RequiresFilter(t *testing.T) {
+	_, _, err := whereClauseFor(nil, nil)
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+
+	_, _, err = whereClauseFor(nil, NewSearchRequest())
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+
+	// An empty IN list adds no condition, so it doesn't count as a filter
+	_, _, err = whereClauseFor(nil, NewSearchRequest().AddIn("status"))
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+
+	where, args, err := whereClauseFor(nil, NewSearchRequest().AddEqual("status", "stale"))
+	require.NoError(t, err)
+	assert.Equal(t, "WHERE status = $1", where)
+	assert.Equal(t, []interface{}{"stale"}, args)
+}
+
+func TestWhereClauseFor_ScopesTenant(t *testing.T) {
+	scope, err := scopeTenant[tenantSample](request.NewTestContext(request.WithTestTenant("acme")))
+	require.NoError(t, err)
+
+	where, args, err := whereClauseFor(scope, NewSearchRequest().AddEqual("name", "x"))
+	require.NoError(t, err)
+	assert.Equal(t, "WHERE (name = $1) AND tenant_id = $2", where)
+	assert.Equal(t, []interface{}{"x", "acme"}, args)
+
+	// The tenant condition alone doesn't satisfy the filter requirement
+	_, _, err = whereClauseFor(scope, NewSearchRequest())
+	assert.ErrorIs(t, err, app_error.InvalidArgument(""))
+}
+
+func TestBuildDeleteWhereSQL(t *testing.T) {
+	assert.Equal(t, "DELETE FROM test_samples WHERE status = $1 RETURNING *", buildDeleteWhereSQL("test_samples", "WHERE status = $1"))
+}
+
+func TestBuildUpdateWhereSQL(t *testing.T) {
+	if orm.GetMetadata[TestSample]() == nil {
+		orm.RegisterModel[TestSample]()
+	}
+
+	query, args, err := buildUpdateWhereSQL[TestSample]("test_samples", "WHERE status = $1", []interface{}{"stale"},
+		map[string]any{"status": "archived", "is_active": false})
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE test_samples SET is_active = $2, status = $3, updated_at = $4 WHERE status = $1 RETURNING *", query)
+	require.Len(t, args, 4)
+	assert.Equal(t, []interface{}{"stale", false, "archived"}, args[:3])
+
+	rejected := []map[string]any{
+		nil,
+		{"missing": 1},
+		{"id": "x"},
+		{"created_at": "x"},
+	}
+	for _, changes := range rejected {
+		_, _, err := buildUpdateWhereSQL[TestSample]("test_samples", "WHERE status = $1", nil, changes)
+		assert.ErrorIs(t, err, app_error.InvalidArgument(""), changes)
+	}
+}
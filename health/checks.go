@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/store/analytics/clickhouse"
+	"github.com/yadunandan004/scaffold/store/cache/redis"
+	"github.com/yadunandan004/scaffold/store/object_storage"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// PostgresCheck pings the global Postgres connection
+func PostgresCheck() Check {
+	return Check{Name: "postgres", Check: postgres.Ping}
+}
+
+// RedisCheck pings the global Redis client
+func RedisCheck() Check {
+	return Check{Name: "redis", Check: redis.Ping}
+}
+
+// ClickHouseCheck pings the global ClickHouse client
+func ClickHouseCheck() Check {
+	return Check{Name: "clickhouse", Check: clickhouse.Ping}
+}
+
+// ObjectStorageCheck verifies storage is reachable by looking up an object in bucket
+func ObjectStorageCheck(name string, storage object_storage.ObjectStorage, bucket string) Check {
+	return Check{Name: name, Check: func(ctx context.Context) error {
+		if storage == nil {
+			return fmt.Errorf("object storage not configured")
+		}
+		_, err := storage.Exists(ctx, bucket, ".health")
+		return err
+	}}
+}
+
+// ClickHouseMigrationsCheck fails until every migration has been applied with an unchanged checksum
+func ClickHouseMigrationsCheck(migrator *clickhouse.Migrator, migrations []clickhouse.Migration) Check {
+	return Check{Name: "clickhouse_migrations", Check: func(ctx context.Context) error {
+		applied, err := migrator.Applied(ctx)
+		if err != nil {
+			return err
+		}
+		var pending []string
+		for _, m := range migrations {
+			checksum, ok := applied[m.Version]
+			if !ok {
+				pending = append(pending, m.Version)
+				continue
+			}
+			if checksum != m.Checksum {
+				return fmt.Errorf("%w: %s", clickhouse.ErrChecksumMismatch, m.Version)
+			}
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,227 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yadunandan004/scaffold/store/readiness"
+)
+
+// Status of a check or of a whole report
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+	// StatusDegraded reports a failing non-critical check; the service still serves traffic
+	StatusDegraded Status = "degraded"
+)
+
+// CheckFunc returns nil when the dependency is healthy
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named dependency probe
+type Check struct {
+	Name  string
+	Check CheckFunc
+	// Liveness includes the check in /healthz as well as /readyz. Keep liveness checks to the process
+	// itself: a failing liveness probe restarts the instance, which won't fix a database outage.
+	Liveness bool
+	// NonCritical checks report degraded instead of failing the probe
+	NonCritical bool
+	// Timeout bounds one run of the check (default CheckerConfig.Timeout)
+	Timeout time.Duration
+	// CacheTTL reuses the last result for this long (default CheckerConfig.CacheTTL)
+	CacheTTL time.Duration
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status    Status    `json:"status"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report aggregates the results of a probe
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckerConfig configures a Checker
+type CheckerConfig struct {
+	// Timeout bounds each check run (default 2s)
+	Timeout time.Duration
+	// CacheTTL reuses results so frequent probes don't hit dependencies on every request (default 1s)
+	CacheTTL time.Duration
+}
+
+// Checker runs registered checks concurrently and caches their results
+type Checker struct {
+	config CheckerConfig
+	mu     sync.RWMutex
+	checks []*registeredCheck
+}
+
+type registeredCheck struct {
+	Check
+	mu     sync.Mutex
+	result CheckResult
+}
+
+// NewChecker creates a checker with no checks
+func NewChecker(cfg CheckerConfig) *Checker {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = time.Second
+	}
+	return &Checker{config: cfg}
+}
+
+// Register adds checks, replacing any existing check with the same name
+func (c *Checker) Register(checks ...Check) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, check := range checks {
+		if check.Name == "" || check.Check == nil {
+			return fmt.Errorf("health check needs a name and a check function")
+		}
+		if check.Timeout <= 0 {
+			check.Timeout = c.config.Timeout
+		}
+		if check.CacheTTL <= 0 {
+			check.CacheTTL = c.config.CacheTTL
+		}
+		replaced := false
+		for i, existing := range c.checks {
+			if existing.Name == check.Name {
+				c.checks[i] = &registeredCheck{Check: check}
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.checks = append(c.checks, &registeredCheck{Check: check})
+		}
+	}
+	return nil
+}
+
+// Liveness runs the checks marked Liveness
+func (c *Checker) Liveness(ctx context.Context) Report {
+	return c.run(ctx, true)
+}
+
+// Readiness runs every check
+func (c *Checker) Readiness(ctx context.Context) Report {
+	return c.run(ctx, false)
+}
+
+// WaitForReady polls Readiness with backoff until no critical check is down, ctx ends or timeout passes,
+// so bootstrap can hold off serving until its dependencies answer. Degraded counts as ready.
+func (c *Checker) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	return readiness.Poll(ctx, timeout, "dependencies", func(ctx context.Context) error {
+		report := c.Readiness(ctx)
+		if report.Status != StatusDown {
+			return nil
+		}
+		var failing []string
+		for name, result := range report.Checks {
+			if result.Status == StatusDown {
+				failing = append(failing, fmt.Sprintf("%s: %s", name, result.Error))
+			}
+		}
+		sort.Strings(failing)
+		return fmt.Errorf("%s", strings.Join(failing, "; "))
+	})
+}
+
+func (c *Checker) run(ctx context.Context, livenessOnly bool) Report {
+	c.mu.RLock()
+	var checks []*registeredCheck
+	for _, check := range c.checks {
+		if !livenessOnly || check.Liveness {
+			checks = append(checks, check)
+		}
+	}
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = check.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checks))}
+	for i, check := range checks {
+		result := results[i]
+		if result.Status == StatusDown && check.NonCritical {
+			result.Status = StatusDegraded
+		}
+		report.Checks[check.Name] = result
+		switch {
+		case result.Status == StatusDown:
+			report.Status = StatusDown
+		case result.Status == StatusDegraded && report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run returns the cached result while it is fresh; concurrent probes share one run
+func (r *registeredCheck) run(ctx context.Context) CheckResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.result.CheckedAt.IsZero() && time.Since(r.result.CheckedAt) < r.CacheTTL {
+		return r.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runCheck(checkCtx, r.Check.Check)
+	result := CheckResult{
+		Status:    StatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	r.result = result
+	return result
+}
+
+// runCheck returns when the check does or the timeout expires, whichever is first,
+// so a check that ignores its context can't hang the probe
+func runCheck(ctx context.Context, check CheckFunc) (err error) {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- check(ctx)
+	}()
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
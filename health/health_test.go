@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Statuses(t *testing.T) {
+	checker := NewChecker(CheckerConfig{})
+	require.NoError(t, checker.Register(
+		Check{Name: "process", Check: func(context.Context) error { return nil }, Liveness: true},
+		Check{Name: "postgres", Check: func(context.Context) error { return nil }},
+		Check{Name: "search", Check: func(context.Context) error { return errors.New("unreachable") }, NonCritical: true},
+	))
+
+	live := checker.Liveness(context.Background())
+	assert.Equal(t, StatusUp, live.Status)
+	assert.Len(t, live.Checks, 1)
+
+	ready := checker.Readiness(context.Background())
+	assert.Equal(t, StatusDegraded, ready.Status)
+	assert.Equal(t, StatusUp, ready.Checks["postgres"].Status)
+	assert.Equal(t, StatusDegraded, ready.Checks["search"].Status)
+	assert.Equal(t, "unreachable", ready.Checks["search"].Error)
+
+	require.NoError(t, checker.Register(Check{Name: "postgres", Check: func(context.Context) error { return errors.New("refused") }}))
+	ready = checker.Readiness(context.Background())
+	assert.Equal(t, StatusDown, ready.Status)
+	assert.Len(t, ready.Checks, 3)
+}
+
+func TestChecker_CachesResults(t *testing.T) {
+	var calls atomic.Int32
+	checker := NewChecker(CheckerConfig{CacheTTL: time.Hour})
+	require.NoError(t, checker.Register(Check{Name: "redis", Check: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}}))
+
+	for i := 0; i < 3; i++ {
+		checker.Readiness(context.Background())
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestChecker_TimesOutAndRecovers(t *testing.T) {
+	checker := NewChecker(CheckerConfig{Timeout: 20 * time.Millisecond})
+	block := make(chan struct{})
+	defer close(block)
+	require.NoError(t, checker.Register(
+		Check{Name: "stuck", Check: func(context.Context) error { <-block; return nil }},
+		Check{Name: "panics", Check: func(context.Context) error { panic("boom") }},
+	))
+
+	report := checker.Readiness(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Contains(t, report.Checks["stuck"].Error, "timed out")
+	assert.Contains(t, report.Checks["panics"].Error, "boom")
+}
+
+func TestChecker_RegisterValidates(t *testing.T) {
+	assert.Error(t, NewChecker(CheckerConfig{}).Register(Check{Name: "nameless check func"}))
+}
+
+func TestChecker_WaitForReady(t *testing.T) {
+	var calls atomic.Int32
+	checker := NewChecker(CheckerConfig{CacheTTL: time.Millisecond})
+	require.NoError(t, checker.Register(Check{Name: "postgres", Check: func(context.Context) error {
+		if calls.Add(1) < 3 {
+			return errors.New("refused")
+		}
+		return nil
+	}}))
+	assert.NoError(t, checker.WaitForReady(context.Background(), 5*time.Second))
+
+	require.NoError(t, checker.Register(Check{Name: "redis", Check: func(context.Context) error { return errors.New("refused") }}))
+	err := checker.WaitForReady(context.Background(), 150*time.Millisecond)
+	assert.ErrorContains(t, err, "redis: refused")
+}
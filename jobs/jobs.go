@@ -0,0 +1,177 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// DefaultQueue is used when a job is enqueued without WithQueue
+const DefaultQueue = "default"
+
+// Job statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusDead      = "dead"
+)
+
+// JobsDDL creates the jobs table and the index workers poll
+const JobsDDL = `CREATE TABLE IF NOT EXISTS jobs (
+	id UUID PRIMARY KEY,
+	queue VARCHAR(100) NOT NULL,
+	job_type VARCHAR(255) NOT NULL,
+	payload TEXT NOT NULL DEFAULT '{}',
+	status VARCHAR(20) NOT NULL,
+	priority INTEGER NOT NULL DEFAULT 0,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL,
+	run_at TIMESTAMPTZ NOT NULL,
+	locked_at TIMESTAMPTZ,
+	locked_by VARCHAR(255) NOT NULL DEFAULT '',
+	last_error TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	completed_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_poll ON jobs (queue, status, run_at)`
+
+var (
+	ErrNoDatabase = errors.New("no database connection")
+	ErrJobNotDead = errors.New("job not found in dead letter queue")
+)
+
+// Job is a unit of background work stored in the jobs table
+type Job struct {
+	ID          uuid.UUID  `json:"id" orm:"column:id;pk"`
+	Queue       string     `json:"queue" orm:"column:queue"`
+	Type        string     `json:"job_type" orm:"column:job_type"`
+	Payload     string     `json:"payload" orm:"column:payload"`
+	Status      string     `json:"status" orm:"column:status"`
+	Priority    int        `json:"priority" orm:"column:priority"`
+	Attempts    int        `json:"attempts" orm:"column:attempts"`
+	MaxAttempts int        `json:"max_attempts" orm:"column:max_attempts"`
+	RunAt       time.Time  `json:"run_at" orm:"column:run_at"`
+	LockedAt    *time.Time `json:"locked_at" orm:"column:locked_at"`
+	LockedBy    string     `json:"locked_by" orm:"column:locked_by"`
+	LastError   string     `json:"last_error" orm:"column:last_error"`
+	CreatedAt   time.Time  `json:"created_at" orm:"column:created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" orm:"column:updated_at"`
+	CompletedAt *time.Time `json:"completed_at" orm:"column:completed_at"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(j.Payload), v)
+}
+
+// EnqueueOption customises an enqueued job
+type EnqueueOption func(job *Job)
+
+// WithQueue places the job on a named queue, so it is only picked up by workers for that queue
+func WithQueue(queue string) EnqueueOption {
+	return func(job *Job) {
+		job.Queue = queue
+	}
+}
+
+// WithDelay defers the first attempt by d
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(job *Job) {
+		job.RunAt = time.Now().Add(d)
+	}
+}
+
+// WithRunAt defers the first attempt until t
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(job *Job) {
+		job.RunAt = t
+	}
+}
+
+// WithMaxAttempts sets how many times the job runs before it is dead lettered (default 5)
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(job *Job) {
+		job.MaxAttempts = n
+	}
+}
+
+// WithPriority runs the job ahead of lower priority jobs that are due
+func WithPriority(priority int) EnqueueOption {
+	return func(job *Job) {
+		job.Priority = priority
+	}
+}
+
+// NewJob builds a pending job with payload marshalled to JSON
+func NewJob(jobType string, payload interface{}, opts ...EnqueueOption) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload for job %s: %w", jobType, err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.New(),
+		Queue:       DefaultQueue,
+		Type:        jobType,
+		Payload:     string(data),
+		Status:      StatusPending,
+		MaxAttempts: 5,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = 1
+	}
+	return job, nil
+}
+
+// Enqueue inserts a job in the request's transaction, so it is only visible to workers once the
+// business data written alongside it commits and is discarded on rollback.
+// Without a transaction in ctx the job is inserted immediately.
+func Enqueue(ctx request.Context, jobType string, payload interface{}, opts ...EnqueueOption) (*Job, error) {
+	job, err := NewJob(jobType, payload, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ensureModel()
+	if query := request.GetQuery(ctx); query != nil {
+		if err := orm.NewTransaction[Job]().Create(query, job); err != nil {
+			return nil, fmt.Errorf("failed to enqueue job %s: %w", jobType, err)
+		}
+		return job, nil
+	}
+
+	db := ctx.GetPgDB()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+	if err := orm.NewDB[Job](db).Create(ctx.GetCtx(), job); err != nil {
+		return nil, fmt.Errorf("failed to enqueue job %s: %w", jobType, err)
+	}
+	return job, nil
+}
+
+// ensureModel registers Job with the ORM on first use
+func ensureModel() {
+	if orm.GetMetadata[Job]() == nil {
+		orm.RegisterModel[Job]()
+	}
+}
@@ -0,0 +1,282 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+var testContainer testcontainers.Container
+
+func TestMain(m *testing.M) {
+	container, err := postgres.NewMockConnection()
+	if err != nil {
+		panic("Failed to start test container: " + err.Error())
+	}
+	testContainer = container
+
+	if _, err := postgres.GetDB().Exec(JobsDDL); err != nil {
+		panic("Failed to create jobs table: " + err.Error())
+	}
+
+	m.Run()
+	if testContainer != nil {
+		testContainer.Terminate(context.Background())
+	}
+}
+
+type emailPayload struct {
+	To string `json:"to"`
+}
+
+// testQueue isolates each test's jobs from the others
+func testQueue() string {
+	return "test-" + uuid.NewString()[:8]
+}
+
+func getJob(t *testing.T, id uuid.UUID) *Job {
+	t.Helper()
+	var job Job
+	row := postgres.GetDB().QueryRow(`SELECT status, attempts, last_error, run_at FROM jobs WHERE id = $1`, id)
+	require.NoError(t, row.Scan(&job.Status, &job.Attempts, &job.LastError, &job.RunAt))
+	return &job
+}
+
+func TestNewJob_Options(t *testing.T) {
+	runAt := time.Now().Add(time.Hour)
+	job, err := NewJob("send_email", emailPayload{To: "a@example.com"},
+		WithQueue("mail"), WithRunAt(runAt), WithMaxAttempts(3), WithPriority(10))
+	require.NoError(t, err)
+
+	assert.Equal(t, "mail", job.Queue)
+	assert.Equal(t, "send_email", job.Type)
+	assert.Equal(t, StatusPending, job.Status)
+	assert.Equal(t, 3, job.MaxAttempts)
+	assert.Equal(t, 10, job.Priority)
+	assert.True(t, job.RunAt.Equal(runAt))
+
+	var payload emailPayload
+	require.NoError(t, job.Decode(&payload))
+	assert.Equal(t, "a@example.com", payload.To)
+}
+
+func TestNewJob_Defaults(t *testing.T) {
+	job, err := NewJob("noop", nil, WithMaxAttempts(0))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultQueue, job.Queue)
+	assert.Equal(t, 1, job.MaxAttempts)
+
+	_, err = NewJob("bad", make(chan int))
+	assert.Error(t, err)
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	first := DefaultBackoff(1)
+	assert.GreaterOrEqual(t, first, 9*time.Second)
+	assert.LessOrEqual(t, first, 11*time.Second)
+
+	third := DefaultBackoff(3)
+	assert.GreaterOrEqual(t, third, 36*time.Second)
+	assert.LessOrEqual(t, third, 44*time.Second)
+
+	assert.LessOrEqual(t, DefaultBackoff(50), time.Hour+6*time.Minute)
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("bad input")
+	err := Permanent(cause)
+
+	var permanent *permanentError
+	assert.True(t, errors.As(err, &permanent))
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "bad input", err.Error())
+}
+
+func TestEnqueue_RollbackDiscardsJob(t *testing.T) {
+	ctx := request.NewTestContext()
+	_, err := request.BeginTransaction(ctx)
+	require.NoError(t, err)
+
+	job, err := Enqueue(ctx, "send_email", emailPayload{To: "a@example.com"}, WithQueue(testQueue()))
+	require.NoError(t, err)
+	require.NoError(t, ctx.Rollback())
+
+	var count int
+	require.NoError(t, postgres.GetDB().QueryRow(`SELECT COUNT(*) FROM jobs WHERE id = $1`, job.ID).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestWorker_CompletesJob(t *testing.T) {
+	queue := testQueue()
+	ctx := request.NewTestContext()
+	_, err := request.BeginTransaction(ctx)
+	require.NoError(t, err)
+	job, err := Enqueue(ctx, "send_email", emailPayload{To: "a@example.com"}, WithQueue(queue))
+	require.NoError(t, err)
+	require.NoError(t, ctx.CloseTxn(nil))
+
+	worker := NewWorker(WorkerConfig{Queue: queue})
+	var received emailPayload
+	worker.Register("send_email", func(ctx request.Context, job *Job) error {
+		return job.Decode(&received)
+	})
+
+	processed, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, "a@example.com", received.To)
+	assert.Equal(t, StatusCompleted, getJob(t, job.ID).Status)
+
+	processed, err = worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestWorker_RetriesThenDeadLetters(t *testing.T) {
+	queue := testQueue()
+	job, err := Enqueue(request.NewTestContext(), "flaky", nil, WithQueue(queue), WithMaxAttempts(2))
+	require.NoError(t, err)
+
+	var deadLettered *Job
+	worker := NewWorker(WorkerConfig{
+		Queue:        queue,
+		Backoff:      func(int) time.Duration { return 0 },
+		OnDeadLetter: func(job *Job, err error) { deadLettered = job },
+	})
+	worker.Register("flaky", func(ctx request.Context, job *Job) error {
+		return errors.New("upstream unavailable")
+	})
+
+	processed, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	stored := getJob(t, job.ID)
+	assert.Equal(t, StatusPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "upstream unavailable", stored.LastError)
+	assert.Nil(t, deadLettered)
+
+	processed, err = worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	stored = getJob(t, job.ID)
+	assert.Equal(t, StatusDead, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+	require.NotNil(t, deadLettered)
+	assert.Equal(t, job.ID, deadLettered.ID)
+
+	dead, err := DeadJobs(context.Background(), queue, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+
+	require.NoError(t, RetryDead(context.Background(), job.ID))
+	stored = getJob(t, job.ID)
+	assert.Equal(t, StatusPending, stored.Status)
+	assert.Equal(t, 0, stored.Attempts)
+
+	assert.ErrorIs(t, RetryDead(context.Background(), job.ID), ErrJobNotDead)
+}
+
+func TestWorker_BackoffDelaysRetry(t *testing.T) {
+	queue := testQueue()
+	job, err := Enqueue(request.NewTestContext(), "flaky", nil, WithQueue(queue))
+	require.NoError(t, err)
+
+	worker := NewWorker(WorkerConfig{Queue: queue, Backoff: func(int) time.Duration { return time.Hour }})
+	worker.Register("flaky", func(ctx request.Context, job *Job) error {
+		return errors.New("try later")
+	})
+
+	processed, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.True(t, getJob(t, job.ID).RunAt.After(time.Now().Add(50*time.Minute)))
+
+	processed, err = worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestWorker_PermanentAndUnknownJobsDeadLetter(t *testing.T) {
+	queue := testQueue()
+	permanent, err := Enqueue(request.NewTestContext(), "invalid", nil, WithQueue(queue))
+	require.NoError(t, err)
+	unknown, err := Enqueue(request.NewTestContext(), "unregistered", nil, WithQueue(queue))
+	require.NoError(t, err)
+
+	worker := NewWorker(WorkerConfig{Queue: queue, OnDeadLetter: func(*Job, error) {}})
+	worker.Register("invalid", func(ctx request.Context, job *Job) error {
+		return Permanent(errors.New("malformed payload"))
+	})
+
+	for i := 0; i < 2; i++ {
+		processed, err := worker.RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.True(t, processed)
+	}
+
+	assert.Equal(t, StatusDead, getJob(t, permanent.ID).Status)
+	assert.Equal(t, 1, getJob(t, permanent.ID).Attempts)
+	assert.Equal(t, StatusDead, getJob(t, unknown.ID).Status)
+}
+
+func TestWorker_HandlerWritesRollBackOnFailure(t *testing.T) {
+	queue := testQueue()
+	_, err := postgres.GetDB().Exec(`CREATE TABLE IF NOT EXISTS job_side_effects (id UUID PRIMARY KEY)`)
+	require.NoError(t, err)
+
+	job, err := Enqueue(request.NewTestContext(), "side_effect", nil, WithQueue(queue))
+	require.NoError(t, err)
+
+	worker := NewWorker(WorkerConfig{Queue: queue})
+	worker.Register("side_effect", func(ctx request.Context, job *Job) error {
+		if _, err := request.GetQuery(ctx).Exec(`INSERT INTO job_side_effects (id) VALUES ($1)`, job.ID); err != nil {
+			return err
+		}
+		panic("boom")
+	})
+
+	processed, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	stored := getJob(t, job.ID)
+	assert.Equal(t, StatusPending, stored.Status)
+	assert.Contains(t, stored.LastError, "boom")
+
+	var count int
+	require.NoError(t, postgres.GetDB().QueryRow(`SELECT COUNT(*) FROM job_side_effects WHERE id = $1`, job.ID).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestWorker_TimedOutJobDeadLetters(t *testing.T) {
+	queue := testQueue()
+	job, err := Enqueue(request.NewTestContext(), "hangs", nil, WithQueue(queue), WithMaxAttempts(1))
+	require.NoError(t, err)
+
+	worker := NewWorker(WorkerConfig{Queue: queue, JobTimeout: 50 * time.Millisecond, OnDeadLetter: func(*Job, error) {}})
+	worker.Register("hangs", func(ctx request.Context, job *Job) error {
+		<-ctx.GetCtx().Done()
+		return ctx.GetCtx().Err()
+	})
+
+	processed, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	stored := getJob(t, job.ID)
+	assert.Equal(t, StatusDead, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Contains(t, stored.LastError, "deadline exceeded")
+}
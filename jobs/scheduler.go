@@ -0,0 +1,350 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// Scheduled run statuses
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	RunSkipped   = "skipped"
+)
+
+var (
+	ErrDuplicateTask = errors.New("task already registered")
+	ErrTaskNotFound  = errors.New("task not found")
+)
+
+// Task is a function run by the Scheduler
+type Task func(ctx request.Context) error
+
+// Locker acquires cluster wide locks; cache.CacheService implementations satisfy it
+type Locker interface {
+	Lock(ctx context.Context, key string, ttl time.Duration) (cache.Unlocker, error)
+}
+
+// Run records one activation of a scheduled task on this instance
+type Run struct {
+	Task        string        `json:"task"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"duration"`
+	Status      string        `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	Instance    string        `json:"instance"`
+}
+
+// TaskInfo describes a registered task for introspection
+type TaskInfo struct {
+	Name    string    `json:"name"`
+	Spec    string    `json:"spec"`
+	NextRun time.Time `json:"next_run"`
+	Running bool      `json:"running"`
+	LastRun *Run      `json:"last_run,omitempty"`
+}
+
+// SchedulerConfig configures a Scheduler
+type SchedulerConfig struct {
+	// Locker elects one instance per tick; nil runs every tick on every instance
+	Locker Locker
+
+	// KeyPrefix namespaces lock keys (default "scheduler")
+	KeyPrefix string
+
+	// Location cron expressions are evaluated in (default time.Local)
+	Location *time.Location
+
+	// HistorySize is the number of runs kept per task (default 20)
+	HistorySize int
+
+	// InstanceID identifies this instance in run history (default hostname plus a random suffix)
+	InstanceID string
+}
+
+type scheduledTask struct {
+	name     string
+	spec     string
+	schedule Schedule
+	fn       Task
+	next     time.Time
+	running  bool
+	history  []Run
+}
+
+// Scheduler runs registered tasks on cron schedules. With a Locker configured, each tick is guarded
+// by a lock keyed on the task and tick time, so only one instance in a cluster executes it.
+type Scheduler struct {
+	config SchedulerConfig
+	mu     sync.RWMutex
+	tasks  map[string]*scheduledTask
+	wake   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler; register tasks and then call Start
+func NewScheduler(cfg SchedulerConfig) *Scheduler {
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "scheduler"
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.HistorySize <= 0 {
+		cfg.HistorySize = 20
+	}
+	if cfg.InstanceID == "" {
+		hostname, _ := os.Hostname()
+		cfg.InstanceID = fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+	}
+
+	return &Scheduler{
+		config: cfg,
+		tasks:  make(map[string]*scheduledTask),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Register adds a task run on spec, a cron expression accepted by ParseCron
+func (s *Scheduler) Register(name, spec string, task Task) error {
+	schedule, err := ParseCronIn(spec, s.config.Location)
+	if err != nil {
+		return err
+	}
+	return s.RegisterSchedule(name, spec, schedule, task)
+}
+
+// RegisterSchedule adds a task run on a custom schedule; spec is only used for introspection
+func (s *Scheduler) RegisterSchedule(name, spec string, schedule Schedule, task Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tasks[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateTask, name)
+	}
+	s.tasks[name] = &scheduledTask{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		fn:       task,
+		next:     schedule.Next(time.Now()),
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start runs due tasks until ctx is cancelled; use Wait to block until running tasks finish
+func (s *Scheduler) Start(ctx context.Context) {
+	s.wg.Add(1)
+	metrics.TraceGoroutine(ctx, "scheduler", "loop", func() {
+		defer s.wg.Done()
+
+		timer := time.NewTimer(time.Hour)
+		defer timer.Stop()
+
+		for {
+			// With nothing registered, sleep until Register wakes the loop
+			timer.Stop()
+			fire := (<-chan time.Time)(nil)
+			if next := s.earliest(); !next.IsZero() {
+				timer.Reset(time.Until(next))
+				fire = timer.C
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-fire:
+				s.dispatch(ctx, time.Now())
+			}
+		}
+	})
+}
+
+// Wait blocks until the scheduler loop and every running task have returned
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// earliest returns the soonest next run across tasks
+func (s *Scheduler) earliest() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var earliest time.Time
+	for _, task := range s.tasks {
+		if !task.next.IsZero() && (earliest.IsZero() || task.next.Before(earliest)) {
+			earliest = task.next
+		}
+	}
+	return earliest
+}
+
+// dispatch starts every task due at now and advances its next run
+func (s *Scheduler) dispatch(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, task := range s.tasks {
+		if task.next.IsZero() || task.next.After(now) {
+			continue
+		}
+		scheduledAt := task.next
+		task.next = task.schedule.Next(now)
+
+		t := task
+		s.wg.Add(1)
+		metrics.TraceGoroutine(ctx, "scheduler", t.name, func() {
+			defer s.wg.Done()
+			s.execute(ctx, t, scheduledAt)
+		})
+	}
+}
+
+// execute runs one tick of task if this instance wins its lock
+func (s *Scheduler) execute(ctx context.Context, task *scheduledTask, scheduledAt time.Time) {
+	run := Run{Task: task.name, ScheduledAt: scheduledAt, StartedAt: time.Now(), Instance: s.config.InstanceID}
+
+	if !s.markRunning(task) {
+		run.Status, run.Error = RunSkipped, "previous run still in progress"
+		s.record(ctx, task, run)
+		return
+	}
+	defer s.markDone(task)
+
+	if s.config.Locker != nil {
+		// The lock is left to expire rather than released so an instance whose clock lags
+		// cannot run the same tick after the winner finishes. It expires by the next tick, and
+		// Lock keeps no other state, so each task has at most about one lock key at a time.
+		key := fmt.Sprintf("%s:%s:%d", s.config.KeyPrefix, task.name, scheduledAt.Unix())
+		_, err := s.config.Locker.Lock(ctx, key, s.lockTTL(task, scheduledAt))
+		if errors.Is(err, cache.ErrLockNotAcquired) {
+			run.Status, run.Error = RunSkipped, "tick claimed by another instance"
+			s.record(ctx, task, run)
+			return
+		}
+		if err != nil {
+			run.Status, run.Error = RunFailed, "failed to acquire lock: "+err.Error()
+			s.record(ctx, task, run)
+			return
+		}
+	}
+
+	err := s.invoke(ctx, task)
+	run.Duration = time.Since(run.StartedAt)
+	run.Status = RunSucceeded
+	if err != nil {
+		run.Status, run.Error = RunFailed, err.Error()
+		log.Printf("[Scheduler] task %s failed: %v", task.name, err)
+	}
+	s.record(ctx, task, run)
+}
+
+// lockTTL holds a tick's lock until the following tick, with a one second floor
+func (s *Scheduler) lockTTL(task *scheduledTask, scheduledAt time.Time) time.Duration {
+	ttl := time.Second
+	if next := task.schedule.Next(scheduledAt); !next.IsZero() && next.Sub(scheduledAt) > ttl {
+		ttl = next.Sub(scheduledAt)
+	}
+	return ttl
+}
+
+// invoke calls the task with a fresh request context, converting panics into errors
+func (s *Scheduler) invoke(ctx context.Context, task *scheduledTask) (err error) {
+	taskCtx := request.NewWorkerContext(ctx, request.WorkerOptions{})
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+		_ = taskCtx.Close(err)
+	}()
+	return task.fn(taskCtx)
+}
+
+func (s *Scheduler) markRunning(task *scheduledTask) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task.running {
+		return false
+	}
+	task.running = true
+	return true
+}
+
+func (s *Scheduler) markDone(task *scheduledTask) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task.running = false
+}
+
+// record appends run to the task's history, dropping the oldest beyond HistorySize
+func (s *Scheduler) record(ctx context.Context, task *scheduledTask, run Run) {
+	metrics.RecordWorkflow(ctx, "schedule:"+task.name, run.Status)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task.history = append(task.history, run)
+	if len(task.history) > s.config.HistorySize {
+		task.history = task.history[len(task.history)-s.config.HistorySize:]
+	}
+}
+
+// History returns the recorded runs of a task on this instance, oldest first
+func (s *Scheduler) History(name string) ([]Run, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := s.tasks[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	return append([]Run(nil), task.history...), nil
+}
+
+// NextRun returns when a task is next due
+func (s *Scheduler) NextRun(name string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := s.tasks[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	return task.next, nil
+}
+
+// Tasks describes every registered task, ordered by name
+func (s *Scheduler) Tasks() []TaskInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]TaskInfo, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		info := TaskInfo{Name: task.name, Spec: task.spec, NextRun: task.next, Running: task.running}
+		if len(task.history) > 0 {
+			last := task.history[len(task.history)-1]
+			info.LastRun = &last
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
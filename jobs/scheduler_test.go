@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func mustParse(t *testing.T, spec string) Schedule {
+	t.Helper()
+	schedule, err := ParseCronIn(spec, time.UTC)
+	require.NoError(t, err)
+	return schedule
+}
+
+func TestParseCron_Next(t *testing.T) {
+	from := time.Date(2024, time.March, 15, 10, 7, 30, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, time.March, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * mon", time.Date(2024, time.March, 18, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"5,10 8 * JAN,jun *", time.Date(2024, time.June, 1, 8, 5, 0, 0, time.UTC)},
+		{"0 0 1 * 1", time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2024, time.March, 15, 10, 9, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			assert.Equal(t, tt.want, mustParse(t, tt.spec).Next(from))
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "10-5 * * * *", "@every 10ms", "@fortnightly"} {
+		_, err := ParseCron(spec)
+		assert.ErrorIs(t, err, ErrInvalidSchedule, spec)
+	}
+}
+
+// everySecond fires on each whole second so tests don't wait a minute
+type everySecond struct{}
+
+func (everySecond) Next(t time.Time) time.Time {
+	return t.Truncate(time.Second).Add(time.Second)
+}
+
+func TestScheduler_RunsAndRecordsHistory(t *testing.T) {
+	scheduler := NewScheduler(SchedulerConfig{HistorySize: 2})
+
+	var calls atomic.Int32
+	require.NoError(t, scheduler.RegisterSchedule("tick", "every second", everySecond{}, func(ctx request.Context) error {
+		if calls.Add(1) == 1 {
+			return errors.New("first run fails")
+		}
+		return nil
+	}))
+	assert.ErrorIs(t, scheduler.RegisterSchedule("tick", "", everySecond{}, nil), ErrDuplicateTask)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+	require.Eventually(t, func() bool { return calls.Load() >= 3 }, 5*time.Second, 50*time.Millisecond)
+	cancel()
+	scheduler.Wait()
+
+	history, err := scheduler.History("tick")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, RunSucceeded, history[1].Status)
+	assert.Equal(t, scheduler.config.InstanceID, history[1].Instance)
+
+	next, err := scheduler.NextRun("tick")
+	require.NoError(t, err)
+	assert.False(t, next.IsZero())
+
+	tasks := scheduler.Tasks()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "tick", tasks[0].Name)
+	require.NotNil(t, tasks[0].LastRun)
+
+	_, err = scheduler.History("missing")
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestScheduler_OneInstancePerTick(t *testing.T) {
+	locker := local.NewLocalCache(&cache.CacheOptions{})
+	defer locker.Close()
+
+	var calls atomic.Int32
+	task := func(ctx request.Context) error {
+		calls.Add(1)
+		return nil
+	}
+
+	first := NewScheduler(SchedulerConfig{Locker: locker, InstanceID: "a"})
+	second := NewScheduler(SchedulerConfig{Locker: locker, InstanceID: "b"})
+	require.NoError(t, first.RegisterSchedule("report", "", everySecond{}, task))
+	require.NoError(t, second.RegisterSchedule("report", "", everySecond{}, task))
+
+	scheduledAt := time.Now().Truncate(time.Second)
+	first.execute(context.Background(), first.tasks["report"], scheduledAt)
+	second.execute(context.Background(), second.tasks["report"], scheduledAt)
+	assert.Equal(t, int32(1), calls.Load())
+
+	history, err := second.History("report")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, RunSkipped, history[0].Status)
+
+	second.execute(context.Background(), second.tasks["report"], scheduledAt.Add(time.Second))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestScheduler_RecoversPanics(t *testing.T) {
+	scheduler := NewScheduler(SchedulerConfig{})
+	require.NoError(t, scheduler.Register("panics", "@daily", func(ctx request.Context) error {
+		panic("boom")
+	}))
+
+	scheduler.execute(context.Background(), scheduler.tasks["panics"], time.Now())
+
+	history, err := scheduler.History("panics")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, RunFailed, history[0].Status)
+	assert.Contains(t, history[0].Error, "boom")
+}
@@ -0,0 +1,314 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// Handler processes a job. ctx carries the transaction holding the job's row lock, so database writes
+// made through it commit together with the job being marked completed and roll back if it fails.
+type Handler func(ctx request.Context, job *Job) error
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job is dead lettered immediately instead of retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// WorkerConfig configures a Worker
+type WorkerConfig struct {
+	// Queue is the queue this worker consumes (default DefaultQueue)
+	Queue string
+
+	// Concurrency is the number of jobs processed in parallel (default 1)
+	Concurrency int
+
+	// PollInterval is how long an idle worker waits before polling again (default 1s)
+	PollInterval time.Duration
+
+	// JobTimeout cancels a job handler's context after this long (default none)
+	JobTimeout time.Duration
+
+	// Backoff returns the delay before retrying after the given attempt (default DefaultBackoff)
+	Backoff func(attempt int) time.Duration
+
+	// OnDeadLetter is called after a job exhausts its attempts or fails permanently (default logs)
+	OnDeadLetter func(job *Job, err error)
+}
+
+// Worker polls a queue and runs registered handlers, one transaction per job.
+// Jobs are claimed with SELECT ... FOR UPDATE SKIP LOCKED, so any number of workers can share a queue.
+type Worker struct {
+	id       string
+	config   WorkerConfig
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wg       sync.WaitGroup
+}
+
+// NewWorker creates a worker using the database from postgres.GetDB
+func NewWorker(cfg WorkerConfig) *Worker {
+	if cfg.Queue == "" {
+		cfg.Queue = DefaultQueue
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = DefaultBackoff
+	}
+	if cfg.OnDeadLetter == nil {
+		cfg.OnDeadLetter = func(job *Job, err error) {
+			log.Printf("[Jobs] job %s (%s) dead lettered after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	ensureModel()
+	return &Worker{
+		id:       fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8]),
+		config:   cfg,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register sets the handler for jobType
+func (w *Worker) Register(jobType string, handler Handler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[jobType] = handler
+}
+
+func (w *Worker) handler(jobType string) (Handler, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	handler, ok := w.handlers[jobType]
+	return handler, ok
+}
+
+// Start runs the worker until ctx is cancelled; use Wait to block until in-flight jobs finish
+func (w *Worker) Start(ctx context.Context) {
+	for i := 0; i < w.config.Concurrency; i++ {
+		w.wg.Add(1)
+		metrics.TraceGoroutine(ctx, "jobs", w.config.Queue, func() {
+			defer w.wg.Done()
+			w.loop(ctx)
+		})
+	}
+}
+
+// Wait blocks until every worker goroutine has returned
+func (w *Worker) Wait() {
+	w.wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for {
+		processed, err := w.RunOnce(ctx)
+		if err != nil {
+			log.Printf("[Jobs] worker %s: %v", w.id, err)
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+// RunOnce claims and runs the next due job, reporting whether one was found
+func (w *Worker) RunOnce(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	// The transaction lives on a context without the job timeout, so a handler that times out can still
+	// have its failure recorded; database/sql rolls a transaction back once its context ends.
+	jobCtx := request.NewWorkerContext(ctx, request.WorkerOptions{})
+	defer jobCtx.Close(nil)
+
+	query, err := jobCtx.BeginTxn()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	job, err := w.claim(query)
+	if err != nil || job == nil {
+		_ = query.Rollback()
+		return false, err
+	}
+
+	// A savepoint lets a failed handler's writes be undone while keeping the row lock to record the failure
+	if _, err := query.Exec("SAVEPOINT job_handler"); err != nil {
+		_ = query.Rollback()
+		return true, err
+	}
+
+	// The handler context derives from jobCtx's, so it shares the transaction; Cancel leaves it open
+	handlerCtx := request.NewWorkerContext(jobCtx.GetCtx(), request.WorkerOptions{
+		Timeout: w.config.JobTimeout,
+		TraceID: jobCtx.TraceID(),
+	})
+	start := time.Now()
+	runErr := w.run(handlerCtx, job)
+	handlerCtx.Cancel()
+	now := time.Now()
+	job.Attempts++
+	job.UpdatedAt = now
+
+	if runErr == nil {
+		job.Status = StatusCompleted
+		job.CompletedAt = &now
+		job.LastError = ""
+	} else {
+		if _, err := query.Exec("ROLLBACK TO SAVEPOINT job_handler"); err != nil {
+			_ = query.Rollback()
+			return true, err
+		}
+		job.LastError = runErr.Error()
+
+		var permanent *permanentError
+		if job.Attempts >= job.MaxAttempts || errors.As(runErr, &permanent) {
+			job.Status = StatusDead
+			failed := *job
+			query.OnCommit(func() { w.config.OnDeadLetter(&failed, runErr) })
+		} else {
+			job.Status = StatusPending
+			job.RunAt = now.Add(w.config.Backoff(job.Attempts))
+		}
+	}
+
+	if err := orm.NewTransaction[Job]().Update(query, job); err != nil {
+		_ = query.Rollback()
+		return true, fmt.Errorf("failed to update job %s: %w", job.ID, err)
+	}
+	if err := query.Commit(); err != nil {
+		return true, fmt.Errorf("failed to commit job %s: %w", job.ID, err)
+	}
+
+	metrics.RecordWorkflow(ctx, "job:"+job.Type, job.Status)
+	if runErr != nil {
+		log.Printf("[Jobs] job %s (%s) attempt %d failed after %s: %v", job.ID, job.Type, job.Attempts, time.Since(start).Round(time.Millisecond), runErr)
+	}
+	return true, nil
+}
+
+// claim locks the next due job on the worker's queue, skipping jobs locked by other workers
+func (w *Worker) claim(query *orm.Query) (*Job, error) {
+	now := time.Now()
+	jobs, err := orm.NewTransaction[Job]().FindByQuery(query,
+		selectJobsSQL()+` WHERE queue = $1 AND status = $2 AND run_at <= $3
+		ORDER BY priority DESC, run_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`,
+		w.config.Queue, StatusPending, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	job := jobs[0]
+	job.Status = StatusRunning
+	job.LockedAt = &now
+	job.LockedBy = w.id
+	return job, nil
+}
+
+// run calls the job's handler, converting panics into errors
+func (w *Worker) run(ctx request.Context, job *Job) (err error) {
+	handler, ok := w.handler(job.Type)
+	if !ok {
+		return Permanent(fmt.Errorf("no handler registered for job type %s", job.Type))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// DefaultBackoff doubles the delay from 10s per attempt up to 1h, with 20% jitter
+func DefaultBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := time.Hour
+	if attempt <= 9 {
+		delay = min(10*time.Second<<(attempt-1), time.Hour)
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay - delay/10 + jitter
+}
+
+// DeadJobs returns up to limit dead lettered jobs on queue, most recent first
+func DeadJobs(ctx context.Context, queue string, limit int) ([]*Job, error) {
+	db := postgres.GetDB()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+	ensureModel()
+	return orm.NewDB[Job](db.DB).FindByQuery(ctx,
+		selectJobsSQL()+` WHERE queue = $1 AND status = $2 ORDER BY updated_at DESC LIMIT $3`,
+		queue, StatusDead, limit)
+}
+
+// RetryDead moves a dead lettered job back to pending with its attempts reset
+func RetryDead(ctx context.Context, id uuid.UUID) error {
+	db := postgres.GetDB()
+	if db == nil {
+		return ErrNoDatabase
+	}
+	result, err := db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = $1, attempts = 0, run_at = $2, last_error = '', updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, StatusPending, time.Now(), id, StatusDead)
+	if err != nil {
+		return fmt.Errorf("failed to retry job %s: %w", id, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retry job %s: %w", id, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", ErrJobNotDead, id)
+	}
+	return nil
+}
+
+// selectJobsSQL returns the ORM's SELECT of every Job column, ready for a WHERE clause
+func selectJobsSQL() string {
+	return strings.TrimSpace(orm.GetMetadata[Job]().Templates("").SelectAll)
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// Logger writes entries correlated with one request and carrying a set of bound fields, so call
+// sites don't pass ctx and repeat fields on every line. Its methods are safe for concurrent use.
+type Logger struct {
+	ctx    request.Context
+	fields []Field
+}
+
+type loggerKey struct{}
+
+// With returns a Logger bound to ctx: each entry carries the request's XID, TraceID and user, read
+// when it is written, plus the service name and the given fields. ctx may be nil for background work.
+func With(ctx request.Context, fields ...Field) *Logger {
+	bound := make([]Field, 0, len(fields)+1)
+	bound = append(bound, String("service", serviceName))
+	return &Logger{ctx: ctx, fields: append(bound, fields...)}
+}
+
+// FromContext returns the Logger attached to ctx with Attach, or a new one bound to ctx
+func FromContext(ctx request.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.GetCtx().Value(loggerKey{}).(*Logger); ok {
+			return l
+		}
+	}
+	return With(ctx)
+}
+
+// Attach stores l on ctx, so later FromContext calls with ctx, such as in the service and
+// repository layers, carry the fields a middleware or handler bound
+func Attach(ctx request.Context, l *Logger) {
+	ctx.SetCtx(context.WithValue(ctx.GetCtx(), loggerKey{}, l))
+}
+
+// With returns a child Logger carrying l's fields followed by fields
+func (l *Logger) With(fields ...Field) *Logger {
+	bound := make([]Field, 0, len(l.fields)+len(fields))
+	bound = append(bound, l.fields...)
+	return &Logger{ctx: l.ctx, fields: append(bound, fields...)}
+}
+
+func (l *Logger) Debug(msg string, fields ...Field) {
+	logKV(l.ctx, logwriter.DebugLevel, msg, l.withFields(fields))
+}
+
+func (l *Logger) Info(msg string, fields ...Field) {
+	logKV(l.ctx, logwriter.InfoLevel, msg, l.withFields(fields))
+}
+
+func (l *Logger) Warn(msg string, fields ...Field) {
+	logKV(l.ctx, logwriter.WarnLevel, msg, l.withFields(fields))
+}
+
+func (l *Logger) Error(msg string, fields ...Field) {
+	logKV(l.ctx, logwriter.ErrorLevel, msg, l.withFields(fields))
+}
+
+func (l *Logger) withFields(fields []Field) []Field {
+	if len(fields) == 0 {
+		return l.fields
+	}
+	all := make([]Field, 0, len(l.fields)+len(fields))
+	all = append(all, l.fields...)
+	return append(all, fields...)
+}
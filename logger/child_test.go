@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestWith_BindsCorrelationAndFields(t *testing.T) {
+	capture := useCaptureWriter(t)
+	ctx := request.NewTestContext()
+
+	log := With(ctx, String("component", "checkout"))
+	log.With(Int("attempt", 2)).Warn("retrying", String("reason", "timeout"))
+	log.Info("done")
+
+	require.Len(t, capture.entries, 2)
+	retry := capture.entries[0]
+	assert.Equal(t, logwriter.WarnLevel, retry.Level)
+	assert.Equal(t, ctx.XID().String(), retry.RequestID)
+	assert.Equal(t, ctx.TraceID(), retry.TraceID)
+	assert.Equal(t, ctx.GetUserInfo().GetID().String(), retry.UserID)
+	assert.Contains(t, retry.Caller, "logger/child_test.go")
+	assert.Equal(t, map[string]interface{}{
+		"service":   serviceName,
+		"component": "checkout",
+		"attempt":   int64(2),
+		"reason":    "timeout",
+	}, retry.Fields)
+
+	// The child's fields don't leak into the parent
+	assert.Equal(t, map[string]interface{}{"service": serviceName, "component": "checkout"}, capture.entries[1].Fields)
+}
+
+func TestFromContext(t *testing.T) {
+	capture := useCaptureWriter(t)
+	ctx := request.NewTestContext()
+
+	FromContext(ctx).Info("unattached")
+	Attach(ctx, With(ctx, String("tenant", "acme")))
+	FromContext(ctx).Info("attached")
+
+	require.Len(t, capture.entries, 2)
+	assert.NotContains(t, capture.entries[0].Fields, "tenant")
+	assert.Equal(t, "acme", capture.entries[1].Fields["tenant"])
+	assert.Equal(t, ctx.XID().String(), capture.entries[1].RequestID)
+}
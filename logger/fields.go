@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a typed key-value pair attached to a log entry. Fields keep their types through zap
+// and the log writers, so Loki and OpenSearch can query them instead of parsing messages.
+type Field = zap.Field
+
+// Field constructors, the same as zap's
+var (
+	String   = zap.String
+	Int      = zap.Int
+	Int64    = zap.Int64
+	Float64  = zap.Float64
+	Bool     = zap.Bool
+	Duration = zap.Duration
+	Time     = zap.Time
+	Any      = zap.Any
+)
+
+// Err is a field holding err under the "error" key; log writers receive it as the entry's Error
+func Err(err error) Field {
+	return zap.Error(err)
+}
+
+// LogDebugKV writes msg at debug level with typed fields
+func LogDebugKV(ctx request.Context, msg string, fields ...Field) {
+	logKV(ctx, logwriter.DebugLevel, msg, fields)
+}
+
+// LogInfoKV writes msg at info level with typed fields:
+//
+//	logger.LogInfoKV(ctx, "order placed", logger.String("order_id", id), logger.Int("items", n))
+func LogInfoKV(ctx request.Context, msg string, fields ...Field) {
+	logKV(ctx, logwriter.InfoLevel, msg, fields)
+}
+
+// LogWarnKV writes msg at warn level with typed fields
+func LogWarnKV(ctx request.Context, msg string, fields ...Field) {
+	logKV(ctx, logwriter.WarnLevel, msg, fields)
+}
+
+// LogErrorKV writes msg at error level with typed fields; pass the error with Err
+func LogErrorKV(ctx request.Context, msg string, fields ...Field) {
+	logKV(ctx, logwriter.ErrorLevel, msg, fields)
+}
+
+func logKV(ctx request.Context, level logwriter.LogLevel, msg string, fields []Field) {
+	caller := getCaller(3)
+	represents := admit(level, caller, msg)
+	if represents == 0 {
+		return
+	}
+	entry := logwriter.LogEntry{
+		Timestamp: time.Now(),
+		Level:     level,
+		Message:   msg,
+		Caller:    caller,
+	}
+	var zapFields []zap.Field
+	if ctx != nil {
+		withRequest(&entry, ctx)
+		zapFields = requestFields(entry)
+	}
+	entry.Fields, entry.Error = fieldValues(fields)
+	sampled := sampledFields(&entry, represents)
+
+	writer.Write(entry)
+
+	zapFields = append(append(zapFields, fields...), sampled...)
+	if checked := log.WithOptions(zap.AddCallerSkip(1)).Check(zapLevel(level), msg); checked != nil {
+		checked.Write(zapFields...)
+	}
+}
+
+// fieldValues encodes fields into plain values for the log writers. The first error field becomes
+// the entry's error rather than a field.
+func fieldValues(fields []Field) (map[string]interface{}, string) {
+	if len(fields) == 0 {
+		return nil, ""
+	}
+	encoder := zapcore.NewMapObjectEncoder()
+	var errMsg string
+	for _, field := range fields {
+		if field.Type == zapcore.ErrorType && errMsg == "" {
+			if err, ok := field.Interface.(error); ok {
+				errMsg = err.Error()
+				continue
+			}
+		}
+		field.AddTo(encoder)
+	}
+	if len(encoder.Fields) == 0 {
+		return nil, errMsg
+	}
+	return encoder.Fields, errMsg
+}
+
+func zapLevel(level logwriter.LogLevel) zapcore.Level {
+	switch level {
+	case logwriter.DebugLevel:
+		return zapcore.DebugLevel
+	case logwriter.WarnLevel:
+		return zapcore.WarnLevel
+	case logwriter.ErrorLevel:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// captureWriter records the entries written to it
+type captureWriter struct {
+	mu      sync.Mutex
+	entries []logwriter.LogEntry
+}
+
+func (w *captureWriter) Write(entry logwriter.LogEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.entries = append(w.entries, entry)
+	return nil
+}
+
+func (w *captureWriter) Flush() error { return nil }
+
+func useCaptureWriter(t *testing.T) *captureWriter {
+	t.Helper()
+	previous := writer
+	capture := &captureWriter{}
+	SetWriter(capture)
+	t.Cleanup(func() { SetWriter(previous) })
+	return capture
+}
+
+func TestLogInfoKV_CarriesTypedFields(t *testing.T) {
+	capture := useCaptureWriter(t)
+	ctx := request.NewTestContext()
+
+	LogInfoKV(ctx, "order placed",
+		String("order_id", "o-1"),
+		Int("items", 3),
+		Bool("gift", true),
+		Duration("took", 1500*time.Millisecond),
+	)
+
+	require.Len(t, capture.entries, 1)
+	entry := capture.entries[0]
+	assert.Equal(t, logwriter.InfoLevel, entry.Level)
+	assert.Equal(t, "order placed", entry.Message)
+	assert.Equal(t, ctx.XID().String(), entry.RequestID)
+	assert.Equal(t, ctx.TraceID(), entry.TraceID)
+	assert.Contains(t, entry.Caller, "logger/fields_test.go")
+	assert.Equal(t, map[string]interface{}{
+		"order_id": "o-1",
+		"items":    int64(3),
+		"gift":     true,
+		"took":     1500 * time.Millisecond,
+	}, entry.Fields)
+}
+
+func TestLogErrorKV_ErrorField(t *testing.T) {
+	capture := useCaptureWriter(t)
+
+	LogErrorKV(nil, "payment failed", Err(errors.New("card declined")), String("provider", "stripe"))
+
+	require.Len(t, capture.entries, 1)
+	entry := capture.entries[0]
+	assert.Equal(t, logwriter.ErrorLevel, entry.Level)
+	assert.Equal(t, "card declined", entry.Error)
+	assert.Equal(t, map[string]interface{}{"provider": "stripe"}, entry.Fields)
+	assert.Empty(t, entry.RequestID)
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelConfig is the global log level and the per-module levels overriding it. A module is the
+// directory of the calling file, such as "orm" or "framework".
+type LevelConfig struct {
+	Level   string            `json:"level,omitempty"`
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+type levelState struct {
+	global  zap.AtomicLevel
+	mu      sync.RWMutex
+	modules map[string]zapcore.Level
+}
+
+var levels = &levelState{
+	global:  zap.NewAtomicLevelAt(zapcore.DebugLevel),
+	modules: make(map[string]zapcore.Level),
+}
+
+// SetLevel changes the global level at runtime
+func SetLevel(level string) error {
+	parsed, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.global.SetLevel(parsed)
+	return nil
+}
+
+// SetModuleLevel sets the level for one module, overriding the global level; an empty level
+// removes the override
+func SetModuleLevel(module, level string) error {
+	if level == "" {
+		levels.mu.Lock()
+		delete(levels.modules, module)
+		levels.mu.Unlock()
+		return nil
+	}
+	parsed, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.mu.Lock()
+	levels.modules[module] = parsed
+	levels.mu.Unlock()
+	return nil
+}
+
+// ApplyLevels validates cfg and then applies it: the global level when set, and each module level,
+// where an empty level removes that module's override. Nothing changes when any level is invalid.
+func ApplyLevels(cfg LevelConfig) error {
+	if cfg.Level != "" {
+		if _, err := parseLevel(cfg.Level); err != nil {
+			return err
+		}
+	}
+	for module, level := range cfg.Modules {
+		if level == "" {
+			continue
+		}
+		if _, err := parseLevel(level); err != nil {
+			return fmt.Errorf("module %s: %w", module, err)
+		}
+	}
+
+	if cfg.Level != "" {
+		_ = SetLevel(cfg.Level)
+	}
+	for module, level := range cfg.Modules {
+		_ = SetModuleLevel(module, level)
+	}
+	return nil
+}
+
+// Levels returns the current global and module levels
+func Levels() LevelConfig {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	cfg := LevelConfig{Level: levels.global.Level().String()}
+	if len(levels.modules) > 0 {
+		cfg.Modules = make(map[string]string, len(levels.modules))
+		for module, level := range levels.modules {
+			cfg.Modules[module] = level.String()
+		}
+	}
+	return cfg
+}
+
+// ReloadLevelsOnSIGHUP applies the levels returned by load each time the process receives SIGHUP,
+// until ctx is done. load typically reloads the config file and reads the logger section.
+func ReloadLevelsOnSIGHUP(ctx context.Context, load func() (LevelConfig, error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				cfg, err := load()
+				if err == nil {
+					err = ApplyLevels(cfg)
+				}
+				if err != nil {
+					log.Error("Failed to reload log levels", zap.Error(err))
+					continue
+				}
+				log.Info("Reloaded log levels", zap.Any("levels", Levels()))
+			}
+		}
+	}()
+}
+
+// enabled reports whether an entry at level from caller ("module/file.go:line") should be written
+func enabled(level logwriter.LogLevel, caller string) bool {
+	threshold := levels.global.Level()
+	if module, _, found := strings.Cut(caller, "/"); found {
+		levels.mu.RLock()
+		if moduleLevel, ok := levels.modules[module]; ok {
+			threshold = moduleLevel
+		}
+		levels.mu.RUnlock()
+	}
+	return zapLevel(level) >= threshold
+}
+
+// parseModuleLevels reads "module=level" pairs separated by commas, as in LOG_MODULE_LEVELS
+func parseModuleLevels(spec string) map[string]string {
+	modules := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		module, level, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if found && module != "" {
+			modules[module] = level
+		}
+	}
+	return modules
+}
+
+func parseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "warn":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	return zapcore.InfoLevel, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// restoreLevels puts the levels back as they were when the test ends
+func restoreLevels(t *testing.T) {
+	t.Helper()
+	previous := Levels()
+	t.Cleanup(func() {
+		levels.mu.Lock()
+		levels.modules = make(map[string]zapcore.Level)
+		levels.mu.Unlock()
+		require.NoError(t, ApplyLevels(previous))
+	})
+}
+
+func TestSetLevel_FiltersBelowThreshold(t *testing.T) {
+	restoreLevels(t)
+	capture := useCaptureWriter(t)
+
+	require.NoError(t, SetLevel("warn"))
+	LogInfoKV(nil, "hidden")
+	LogWarnKV(nil, "shown")
+
+	require.Len(t, capture.entries, 1)
+	assert.Equal(t, "shown", capture.entries[0].Message)
+	assert.Equal(t, "warn", Levels().Level)
+}
+
+func TestSetModuleLevel_OverridesGlobal(t *testing.T) {
+	restoreLevels(t)
+	capture := useCaptureWriter(t)
+
+	require.NoError(t, SetLevel("error"))
+	require.NoError(t, SetModuleLevel("logger", "debug"))
+	LogDebugKV(nil, "from logger")
+	require.Len(t, capture.entries, 1)
+
+	require.NoError(t, SetModuleLevel("logger", ""))
+	LogDebugKV(nil, "from logger")
+	assert.Len(t, capture.entries, 1)
+	assert.Empty(t, Levels().Modules)
+}
+
+func TestApplyLevels_RejectsInvalidWithoutChanges(t *testing.T) {
+	restoreLevels(t)
+	require.NoError(t, SetLevel("info"))
+
+	err := ApplyLevels(LevelConfig{Level: "debug", Modules: map[string]string{"orm": "loud"}})
+
+	assert.Error(t, err)
+	assert.Equal(t, LevelConfig{Level: "info"}, Levels())
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	assert.Equal(t, map[string]string{"orm": "debug", "cache": "warn"}, parseModuleLevels("orm=debug, cache=warn,bogus"))
+	assert.Empty(t, parseModuleLevels(""))
+}
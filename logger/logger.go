@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +18,8 @@ import (
 var (
 	log    *zap.Logger
 	writer logwriter.LogWriter
+	// serviceName is bound to every Logger created with With
+	serviceName string
 )
 
 func init() {
@@ -29,6 +33,7 @@ func InitializeLogger() {
 	logLevel := getEnvOrDefault("LOG_LEVEL", "debug")
 	logWriter := getEnvOrDefault("LOG_WRITER", "local")
 	environment := getEnvOrDefault("ENV", "development")
+	serviceName = getEnvOrDefault("SERVICE_NAME", "app")
 
 	// Configure zap logger
 	var config zapcore.EncoderConfig
@@ -51,25 +56,20 @@ func InitializeLogger() {
 		encoder = zapcore.NewConsoleEncoder(config)
 	}
 
-	// Set log level
-	var level zapcore.Level
-	switch strings.ToLower(logLevel) {
-	case "debug":
-		level = zapcore.DebugLevel
-	case "info":
-		level = zapcore.InfoLevel
-	case "warn":
-		level = zapcore.WarnLevel
-	case "error":
-		level = zapcore.ErrorLevel
-	default:
+	// Set log level; entries are filtered by enabled, so the core accepts every level
+	level, err := parseLevel(logLevel)
+	if err != nil {
 		level = zapcore.InfoLevel
 	}
+	levels.global.SetLevel(level)
+	for module, moduleLevel := range parseModuleLevels(os.Getenv("LOG_MODULE_LEVELS")) {
+		_ = SetModuleLevel(module, moduleLevel)
+	}
 
 	core := zapcore.NewCore(
 		encoder,
 		zapcore.AddSync(os.Stdout),
-		level,
+		zapcore.DebugLevel,
 	)
 
 	log = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
@@ -87,20 +87,52 @@ func initializeLogWriter(writerType, environment string) {
 
 		// Create labels for this instance
 		labels := map[string]string{
-			"service":     getEnvOrDefault("SERVICE_NAME", "app"),
+			"service":     serviceName,
 			"environment": environment,
 			"node_id":     getEnvOrDefault("NODE_ID", "unknown"),
 			"cluster_id":  getEnvOrDefault("CLUSTER_ID", "unknown"),
 		}
 
-		writer = logwriter.NewLokiWriter(lokiEndpoint, labels)
+		writer = asyncIfEnabled(logwriter.NewLokiWriter(lokiEndpoint, labels))
 
 	case "opensearch":
 		// Get OpenSearch configuration from environment
 		opensearchEndpoint := getEnvOrDefault("OPENSEARCH_ENDPOINT", "http://opensearch:9200")
 		indexName := getEnvOrDefault("OPENSEARCH_INDEX", "app-logs")
 
-		writer = logwriter.NewOpenSearchWriter(opensearchEndpoint, indexName)
+		writer = asyncIfEnabled(logwriter.NewOpenSearchWriter(opensearchEndpoint, indexName))
+
+	case "file":
+		fileWriter, err := logwriter.NewRotatingFileWriter(logwriter.FileWriterOptions{
+			Path:        getEnvOrDefault("LOG_FILE_PATH", "logs/app.log"),
+			MaxSize:     int64(getEnvInt("LOG_FILE_MAX_SIZE_MB", 100)) << 20,
+			RotateEvery: getEnvDuration("LOG_FILE_ROTATE_EVERY", 24*time.Hour),
+			MaxBackups:  getEnvInt("LOG_FILE_MAX_BACKUPS", 7),
+		})
+		if err != nil {
+			log.Error("Falling back to the local log writer", zap.Error(err))
+			writer = logwriter.NewLocalWriter()
+			return
+		}
+		writer = fileWriter
+
+	case "kafka":
+		restEndpoint := getEnvOrDefault("KAFKA_REST_ENDPOINT", "http://kafka-rest:8082")
+		topic := getEnvOrDefault("KAFKA_LOG_TOPIC", "app-logs")
+
+		writer = asyncIfEnabled(logwriter.NewKafkaWriter(restEndpoint, topic))
+
+	case "cloudwatch":
+		group := getEnvOrDefault("CLOUDWATCH_LOG_GROUP", "app")
+		stream := getEnvOrDefault("CLOUDWATCH_LOG_STREAM", getEnvOrDefault("NODE_ID", "default"))
+
+		cloudWatchWriter, err := logwriter.NewCloudWatchWriter(context.Background(), group, stream)
+		if err != nil {
+			log.Error("Falling back to the local log writer", zap.Error(err))
+			writer = logwriter.NewLocalWriter()
+			return
+		}
+		writer = asyncIfEnabled(cloudWatchWriter)
 
 	default:
 		// Default to local writer
@@ -108,6 +140,14 @@ func initializeLogWriter(writerType, environment string) {
 	}
 }
 
+// asyncIfEnabled ships a remote writer's entries in the background unless LOG_ASYNC is "false"
+func asyncIfEnabled(w logwriter.LogWriter) logwriter.LogWriter {
+	if getEnvOrDefault("LOG_ASYNC", "true") == "false" {
+		return w
+	}
+	return logwriter.NewAsyncWriter(w, logwriter.AsyncOptions{})
+}
+
 // getEnvOrDefault gets environment variable or returns default value
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -116,6 +156,20 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if parsed, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return parsed
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if parsed, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return parsed
+	}
+	return defaultValue
+}
+
 func getCaller(skip int) string {
 	_, file, line, ok := runtime.Caller(skip)
 	if !ok {
@@ -128,105 +182,191 @@ func getCaller(skip int) string {
 	return fmt.Sprintf("%s:%d", file, line)
 }
 
+// withRequest tags entry with ctx's XID and user, and with its trace. The span active in ctx, when
+// there is one, supplies the trace and span IDs; a caller's span carried in only supplies the trace.
+func withRequest(entry *logwriter.LogEntry, ctx request.Context) {
+	entry.RequestID = ctx.XID().String()
+	entry.TraceID = ctx.TraceID()
+	if spanCtx := request.SpanContext(ctx); spanCtx.IsValid() {
+		entry.TraceID = spanCtx.TraceID().String()
+		if !spanCtx.IsRemote() {
+			entry.SpanID = spanCtx.SpanID().String()
+		}
+	}
+	if userInfo := ctx.GetUserInfo(); userInfo != nil {
+		entry.UserID = userInfo.GetID().String()
+		entry.UserEmail = userInfo.GetEmail()
+	}
+}
+
+// requestFields returns the request tags withRequest put on entry as zap fields
+func requestFields(entry logwriter.LogEntry) []zap.Field {
+	fields := []zap.Field{
+		zap.String("requestID", entry.RequestID),
+		zap.String("traceID", entry.TraceID),
+		zap.String("userID", entry.UserID),
+	}
+	if entry.SpanID != "" {
+		fields = append(fields, zap.String("spanID", entry.SpanID))
+	}
+	return fields
+}
+
 func LogInfo(_ request.Context, format string, args ...interface{}) {
+	caller := getCaller(2)
+	represents := admit(logwriter.InfoLevel, caller, format)
+	if represents == 0 {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 	entry := logwriter.LogEntry{
 		Timestamp: time.Now(),
 		Level:     logwriter.InfoLevel,
 		Message:   msg,
-		Caller:    getCaller(2),
+		Caller:    caller,
+	}
+	sampled := sampledFields(&entry, represents)
+	writer.Write(entry)
+	log.Info(msg, sampled...)
+}
+
+func LogDebug(_ request.Context, format string, args ...interface{}) {
+	caller := getCaller(2)
+	represents := admit(logwriter.DebugLevel, caller, format)
+	if represents == 0 {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	entry := logwriter.LogEntry{
+		Timestamp: time.Now(),
+		Level:     logwriter.DebugLevel,
+		Message:   msg,
+		Caller:    caller,
 	}
+	sampled := sampledFields(&entry, represents)
 	writer.Write(entry)
-	log.Info(msg)
+	log.Debug(msg, sampled...)
 }
 
 func LogInfoWithContext(ctx request.Context, format string, args ...interface{}) {
+	caller := getCaller(2)
+	represents := admit(logwriter.InfoLevel, caller, format)
+	if represents == 0 {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 	entry := logwriter.LogEntry{
 		Timestamp: time.Now(),
 		Level:     logwriter.InfoLevel,
 		Message:   msg,
-		Caller:    getCaller(2),
-		RequestID: ctx.XID().String(),
-		TraceID:   ctx.TraceID(),
+		Caller:    caller,
 	}
+	withRequest(&entry, ctx)
 
-	if userInfo := ctx.GetUserInfo(); userInfo != nil {
-		entry.UserID = userInfo.GetID().String()
-		entry.UserEmail = userInfo.GetEmail()
+	sampled := sampledFields(&entry, represents)
+	writer.Write(entry)
+	log.Info(msg, append(requestFields(entry), sampled...)...)
+}
+
+// LogWithFields writes msg at level with structured fields, correlated with the request's XID and TraceID
+func LogWithFields(ctx request.Context, level logwriter.LogLevel, msg string, fields map[string]interface{}) {
+	caller := getCaller(2)
+	represents := admit(level, caller, msg)
+	if represents == 0 {
+		return
 	}
+	entry := logwriter.LogEntry{
+		Timestamp: time.Now(),
+		Level:     level,
+		Message:   msg,
+		Caller:    caller,
+		Fields:    fields,
+	}
+	withRequest(&entry, ctx)
 
+	sampled := sampledFields(&entry, represents)
 	writer.Write(entry)
-	log.Info(msg,
-		zap.String("requestID", entry.RequestID),
-		zap.String("traceID", entry.TraceID),
-		zap.String("userID", entry.UserID),
-	)
+
+	zapFields := requestFields(entry)
+	for key, value := range fields {
+		zapFields = append(zapFields, zap.Any(key, value))
+	}
+	zapFields = append(zapFields, sampled...)
+	switch level {
+	case logwriter.DebugLevel:
+		log.Debug(msg, zapFields...)
+	case logwriter.WarnLevel:
+		log.Warn(msg, zapFields...)
+	case logwriter.ErrorLevel:
+		log.Error(msg, zapFields...)
+	default:
+		log.Info(msg, zapFields...)
+	}
 }
 
 func LogError(ctx request.Context, err error) {
 	if err == nil {
 		return
 	}
+	caller := getCaller(2)
+	represents := admit(logwriter.ErrorLevel, caller, "Error occurred")
+	if represents == 0 {
+		return
+	}
 
 	entry := logwriter.LogEntry{
 		Timestamp: time.Now(),
 		Level:     logwriter.ErrorLevel,
 		Message:   "Error occurred",
 		Error:     err.Error(),
-		Caller:    getCaller(2),
-		RequestID: ctx.XID().String(),
-		TraceID:   ctx.TraceID(),
-	}
-
-	if userInfo := ctx.GetUserInfo(); userInfo != nil {
-		entry.UserID = userInfo.GetID().String()
-		entry.UserEmail = userInfo.GetEmail()
+		Caller:    caller,
 	}
+	withRequest(&entry, ctx)
 
+	sampled := sampledFields(&entry, represents)
 	writer.Write(entry)
-	log.Error("Error occurred", zap.Error(err))
+	log.Error("Error occurred", append(sampled, zap.Error(err))...)
 }
 
 func LogEnter(ctx request.Context, format string, args ...interface{}) {
+	caller := getCaller(2)
+	represents := admit(logwriter.DebugLevel, caller, format)
+	if represents == 0 {
+		return
+	}
 	msg := fmt.Sprintf("→ ENTER: "+format, args...)
 	entry := logwriter.LogEntry{
 		Timestamp: time.Now(),
 		Level:     logwriter.DebugLevel,
 		Message:   msg,
-		Caller:    getCaller(2),
-		RequestID: ctx.XID().String(),
-		TraceID:   ctx.TraceID(),
-	}
-
-	if userInfo := ctx.GetUserInfo(); userInfo != nil {
-		entry.UserID = userInfo.GetID().String()
-		entry.UserEmail = userInfo.GetEmail()
+		Caller:    caller,
 	}
+	withRequest(&entry, ctx)
 
+	sampled := sampledFields(&entry, represents)
 	writer.Write(entry)
-	log.Debug(msg)
+	log.Debug(msg, sampled...)
 }
 
 func LogExit(ctx request.Context, startTime time.Time) {
+	caller := getCaller(2)
+	represents := admit(logwriter.DebugLevel, caller, "← EXIT")
+	if represents == 0 {
+		return
+	}
 	duration := time.Since(startTime)
 	entry := logwriter.LogEntry{
 		Timestamp: time.Now(),
 		Level:     logwriter.DebugLevel,
 		Message:   "← EXIT",
-		Caller:    getCaller(2),
-		RequestID: ctx.XID().String(),
-		TraceID:   ctx.TraceID(),
+		Caller:    caller,
 		Duration:  &duration,
 	}
+	withRequest(&entry, ctx)
 
-	if userInfo := ctx.GetUserInfo(); userInfo != nil {
-		entry.UserID = userInfo.GetID().String()
-		entry.UserEmail = userInfo.GetEmail()
-	}
-
+	sampled := sampledFields(&entry, represents)
 	writer.Write(entry)
-	log.Debug("← EXIT", zap.Duration("duration", duration))
+	log.Debug("← EXIT", append(sampled, zap.Duration("duration", duration))...)
 }
 
 func SetWriter(w logwriter.LogWriter) {
@@ -0,0 +1,202 @@
+package logwriter
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchWriter is implemented by writers that can ship a batch of entries in one call without
+// buffering them, which lets AsyncWriter retry a failed batch without writing it twice
+type BatchWriter interface {
+	WriteBatch(entries []LogEntry) error
+}
+
+// AsyncOptions configures an AsyncWriter. Zero values use the defaults noted on each field.
+type AsyncOptions struct {
+	// QueueSize bounds the entries waiting to be shipped; when full the oldest is dropped. Default 10000.
+	QueueSize int
+	// BatchSize is how many entries are shipped together. Default 100.
+	BatchSize int
+	// FlushInterval ships a partial batch once it has waited this long. Default 1s.
+	FlushInterval time.Duration
+	// MaxRetries is how often a failed batch is retried before it is dropped. Default 5; a negative
+	// value disables retries.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for each one up to MaxBackoff.
+	// Defaults 100ms and 10s.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+func (o AsyncOptions) withDefaults() AsyncOptions {
+	if o.QueueSize <= 0 {
+		o.QueueSize = 10000
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	} else if o.MaxRetries == 0 {
+		o.MaxRetries = 5
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = 100 * time.Millisecond
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 10 * time.Second
+	}
+	return o
+}
+
+// AsyncWriter queues entries and ships them to another writer in batches from a background
+// goroutine, so logging never waits on the network. A batch is shipped when BatchSize entries are
+// queued or FlushInterval passes. When the queue is full the oldest entries are dropped, and a
+// batch still failing after its retries is dropped too; Dropped counts both.
+type AsyncWriter struct {
+	inner LogWriter
+	opts  AsyncOptions
+
+	mu    sync.Mutex
+	queue []LogEntry
+
+	wake      chan struct{}
+	flushes   chan chan error
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	dropped   atomic.Uint64
+}
+
+// NewAsyncWriter starts an AsyncWriter shipping to inner. Close it to ship what is queued and stop.
+func NewAsyncWriter(inner LogWriter, opts AsyncOptions) *AsyncWriter {
+	w := &AsyncWriter{
+		inner:   inner,
+		opts:    opts.withDefaults(),
+		wake:    make(chan struct{}, 1),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues entry and returns immediately
+func (w *AsyncWriter) Write(entry LogEntry) error {
+	w.mu.Lock()
+	if len(w.queue) >= w.opts.QueueSize {
+		w.queue = w.queue[1:]
+		w.dropped.Add(1)
+	}
+	w.queue = append(w.queue, entry)
+	full := len(w.queue) >= w.opts.BatchSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush ships every queued entry and returns the last shipping error
+func (w *AsyncWriter) Flush() error {
+	reply := make(chan error, 1)
+	select {
+	case w.flushes <- reply:
+		return <-reply
+	case <-w.stopped:
+		return nil
+	}
+}
+
+// Close ships the queued entries, stops the background goroutine and flushes the inner writer
+func (w *AsyncWriter) Close() error {
+	w.closeOnce.Do(func() { close(w.done) })
+	<-w.stopped
+	return w.inner.Flush()
+}
+
+// Dropped returns how many entries were dropped because the queue was full or shipping failed
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			w.shipQueued()
+			return
+		case reply := <-w.flushes:
+			reply <- w.shipQueued()
+		case <-w.wake:
+			w.shipQueued()
+		case <-ticker.C:
+			w.shipQueued()
+		}
+	}
+}
+
+// shipQueued ships the queue in batches and returns the last error
+func (w *AsyncWriter) shipQueued() error {
+	var lastErr error
+	for {
+		w.mu.Lock()
+		n := min(len(w.queue), w.opts.BatchSize)
+		batch := w.queue[:n:n]
+		w.queue = w.queue[n:]
+		w.mu.Unlock()
+
+		if n == 0 {
+			return lastErr
+		}
+		if err := w.ship(batch); err != nil {
+			lastErr = err
+		}
+	}
+}
+
+func (w *AsyncWriter) ship(batch []LogEntry) error {
+	batchWriter, isBatchWriter := w.inner.(BatchWriter)
+	if !isBatchWriter {
+		// Other writers buffer what they're given, so only their flush is retried
+		for _, entry := range batch {
+			_ = w.inner.Write(entry)
+		}
+	}
+
+	backoff := w.opts.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if isBatchWriter {
+			err = batchWriter.WriteBatch(batch)
+		} else {
+			err = w.inner.Flush()
+		}
+		if err == nil {
+			return nil
+		}
+		if attempt == w.opts.MaxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, w.opts.MaxBackoff)
+	}
+
+	w.dropped.Add(uint64(len(batch)))
+	log.Printf("[LogWriter] dropped %d entries after %d retries: %v", len(batch), w.opts.MaxRetries, err)
+	return err
+}
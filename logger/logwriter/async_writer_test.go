@@ -0,0 +1,137 @@
+package logwriter
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBatchWriter records shipped batches and fails the first failures calls
+type recordingBatchWriter struct {
+	mu       sync.Mutex
+	batches  [][]LogEntry
+	failures int
+	calls    int
+}
+
+func (w *recordingBatchWriter) Write(LogEntry) error { return errors.New("use WriteBatch") }
+func (w *recordingBatchWriter) Flush() error         { return nil }
+
+func (w *recordingBatchWriter) WriteBatch(entries []LogEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls++
+	if w.calls <= w.failures {
+		return errors.New("endpoint unavailable")
+	}
+	w.batches = append(w.batches, append([]LogEntry(nil), entries...))
+	return nil
+}
+
+func (w *recordingBatchWriter) shipped() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var messages []string
+	for _, batch := range w.batches {
+		for _, entry := range batch {
+			messages = append(messages, entry.Message)
+		}
+	}
+	return messages
+}
+
+func entry(message string) LogEntry {
+	return LogEntry{Timestamp: time.Now(), Level: InfoLevel, Message: message}
+}
+
+func TestAsyncWriter_ShipsFullBatches(t *testing.T) {
+	inner := &recordingBatchWriter{}
+	w := NewAsyncWriter(inner, AsyncOptions{BatchSize: 2, FlushInterval: time.Hour})
+	defer w.Close()
+
+	require.NoError(t, w.Write(entry("a")))
+	require.NoError(t, w.Write(entry("b")))
+	assert.Eventually(t, func() bool { return len(inner.shipped()) == 2 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, w.Write(entry("c")))
+	require.NoError(t, w.Flush())
+	assert.Equal(t, []string{"a", "b", "c"}, inner.shipped())
+}
+
+func TestAsyncWriter_FlushesOnInterval(t *testing.T) {
+	inner := &recordingBatchWriter{}
+	w := NewAsyncWriter(inner, AsyncOptions{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer w.Close()
+
+	require.NoError(t, w.Write(entry("a")))
+	assert.Eventually(t, func() bool { return len(inner.shipped()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestAsyncWriter_DropsOldestWhenFull(t *testing.T) {
+	inner := &recordingBatchWriter{}
+	w := NewAsyncWriter(inner, AsyncOptions{QueueSize: 2, BatchSize: 100, FlushInterval: time.Hour})
+
+	for _, message := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, w.Write(entry(message)))
+	}
+	require.NoError(t, w.Close())
+	assert.Equal(t, []string{"c", "d"}, inner.shipped())
+	assert.Equal(t, uint64(2), w.Dropped())
+}
+
+func TestAsyncWriter_RetriesWithBackoff(t *testing.T) {
+	inner := &recordingBatchWriter{failures: 2}
+	w := NewAsyncWriter(inner, AsyncOptions{FlushInterval: time.Hour, RetryBackoff: time.Millisecond})
+	defer w.Close()
+
+	require.NoError(t, w.Write(entry("a")))
+	require.NoError(t, w.Flush())
+	assert.Equal(t, []string{"a"}, inner.shipped())
+	assert.Equal(t, 3, inner.calls)
+	assert.Zero(t, w.Dropped())
+}
+
+func TestAsyncWriter_DropsBatchAfterRetries(t *testing.T) {
+	inner := &recordingBatchWriter{failures: 10}
+	w := NewAsyncWriter(inner, AsyncOptions{FlushInterval: time.Hour, MaxRetries: 2, RetryBackoff: time.Millisecond})
+	defer w.Close()
+
+	require.NoError(t, w.Write(entry("a")))
+	assert.EqualError(t, w.Flush(), "endpoint unavailable")
+	assert.Equal(t, 3, inner.calls)
+	assert.Equal(t, uint64(1), w.Dropped())
+}
+
+// bufferingWriter stands in for a writer without WriteBatch
+type bufferingWriter struct {
+	buffered []LogEntry
+	flushed  []LogEntry
+}
+
+func (w *bufferingWriter) Write(entry LogEntry) error {
+	w.buffered = append(w.buffered, entry)
+	return nil
+}
+
+func (w *bufferingWriter) Flush() error {
+	w.flushed = append(w.flushed, w.buffered...)
+	w.buffered = nil
+	return nil
+}
+
+func TestAsyncWriter_PlainWriter(t *testing.T) {
+	inner := &bufferingWriter{}
+	w := NewAsyncWriter(inner, AsyncOptions{FlushInterval: time.Hour})
+
+	require.NoError(t, w.Write(entry("a")))
+	require.NoError(t, w.Close())
+	require.Len(t, inner.flushed, 1)
+	assert.Equal(t, "a", inner.flushed[0].Message)
+
+	// Flush after Close is a no-op
+	assert.NoError(t, w.Flush())
+}
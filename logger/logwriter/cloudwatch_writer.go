@@ -0,0 +1,188 @@
+package logwriter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// CloudWatchWriter sends entries to a CloudWatch Logs stream through the PutLogEvents API, creating
+// the stream on first use. Credentials and region come from the default AWS configuration chain.
+type CloudWatchWriter struct {
+	client      *http.Client
+	endpoint    string
+	region      string
+	group       string
+	stream      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	mu          sync.Mutex
+	buffer      []LogEntry
+	bufferMax   int
+	streamReady bool
+}
+
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// cloudWatchError is the error body returned by the CloudWatch Logs JSON API
+type cloudWatchError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func (e *cloudWatchError) Error() string {
+	return fmt.Sprintf("cloudwatch %s: %s", e.Type, e.Message)
+}
+
+// NewCloudWatchWriter creates a writer for the given log group and stream. The group must exist.
+func NewCloudWatchWriter(ctx context.Context, group, stream string) (*CloudWatchWriter, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	if awsCfg.Region == "" {
+		return nil, fmt.Errorf("no AWS region configured for CloudWatch Logs")
+	}
+
+	return &CloudWatchWriter{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		endpoint:    fmt.Sprintf("https://logs.%s.amazonaws.com", awsCfg.Region),
+		region:      awsCfg.Region,
+		group:       group,
+		stream:      stream,
+		credentials: awsCfg.Credentials,
+		signer:      v4.NewSigner(),
+		buffer:      make([]LogEntry, 0, 100),
+		bufferMax:   100,
+	}, nil
+}
+
+func (w *CloudWatchWriter) Write(entry LogEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer = append(w.buffer, entry)
+
+	if len(w.buffer) >= w.bufferMax {
+		return w.flush()
+	}
+
+	return nil
+}
+
+func (w *CloudWatchWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+func (w *CloudWatchWriter) flush() error {
+	if err := w.send(w.buffer); err != nil {
+		return err
+	}
+
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// WriteBatch sends entries at once, bypassing the buffer
+func (w *CloudWatchWriter) WriteBatch(entries []LogEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.send(entries)
+}
+
+// send puts entries in one PutLogEvents call; callers hold mu, which guards streamReady
+func (w *CloudWatchWriter) send(entries []LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !w.streamReady {
+		err := w.call(ctx, "CreateLogStream", map[string]string{
+			"logGroupName":  w.group,
+			"logStreamName": w.stream,
+		})
+		var apiErr *cloudWatchError
+		if err != nil && !(errors.As(err, &apiErr) && strings.HasSuffix(apiErr.Type, "ResourceAlreadyExistsException")) {
+			return err
+		}
+		w.streamReady = true
+	}
+
+	// PutLogEvents requires events in chronological order
+	events := make([]cloudWatchEvent, len(entries))
+	for i, entry := range entries {
+		message, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal log entry: %w", err)
+		}
+		events[i] = cloudWatchEvent{Timestamp: entry.Timestamp.UnixMilli(), Message: string(message)}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp < events[j].Timestamp })
+
+	return w.call(ctx, "PutLogEvents", map[string]interface{}{
+		"logGroupName":  w.group,
+		"logStreamName": w.stream,
+		"logEvents":     events,
+	})
+}
+
+func (w *CloudWatchWriter) call(ctx context.Context, action string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal CloudWatch request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", w.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create CloudWatch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+
+	creds, err := w.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := w.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "logs", w.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign CloudWatch request: %w", err)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send logs to CloudWatch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &cloudWatchError{}
+		respBody, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(respBody, apiErr) != nil || apiErr.Type == "" {
+			return fmt.Errorf("cloudwatch %s failed with status: %d", action, resp.StatusCode)
+		}
+		return apiErr
+	}
+	return nil
+}
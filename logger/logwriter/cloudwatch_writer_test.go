@@ -0,0 +1,77 @@
+package logwriter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudWatchWriter_CreatesStreamAndPutsSortedEvents(t *testing.T) {
+	var actions []string
+	var events []cloudWatchEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256"))
+		action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "Logs_20140328.")
+		actions = append(actions, action)
+		switch action {
+		case "CreateLogStream":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type": "com.amazonaws.logs#ResourceAlreadyExistsException", "message": "exists"}`))
+		case "PutLogEvents":
+			var body struct {
+				LogEvents []cloudWatchEvent `json:"logEvents"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			events = append(events, body.LogEvents...)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	w := &CloudWatchWriter{
+		client:      server.Client(),
+		endpoint:    server.URL,
+		region:      "us-east-1",
+		group:       "app",
+		stream:      "node-1",
+		credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		signer:      v4.NewSigner(),
+		bufferMax:   100,
+	}
+
+	later := entry("later")
+	earlier := entry("earlier")
+	earlier.Timestamp = later.Timestamp.Add(-time.Second)
+	require.NoError(t, w.WriteBatch([]LogEntry{later, earlier}))
+	require.NoError(t, w.WriteBatch([]LogEntry{entry("next")}))
+
+	assert.Equal(t, []string{"CreateLogStream", "PutLogEvents", "PutLogEvents"}, actions)
+	require.Len(t, events, 3)
+	assert.Contains(t, events[0].Message, `"message":"earlier"`)
+	assert.Contains(t, events[1].Message, `"message":"later"`)
+}
+
+func TestCloudWatchWriter_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type": "ResourceNotFoundException", "message": "log group does not exist"}`))
+	}))
+	defer server.Close()
+
+	w := &CloudWatchWriter{
+		client:      server.Client(),
+		endpoint:    server.URL,
+		region:      "us-east-1",
+		credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		signer:      v4.NewSigner(),
+	}
+	assert.EqualError(t, w.WriteBatch([]LogEntry{entry("a")}), "cloudwatch ResourceNotFoundException: log group does not exist")
+}
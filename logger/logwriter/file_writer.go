@@ -0,0 +1,163 @@
+package logwriter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeLayout names rotated files so they sort oldest first
+const backupTimeLayout = "20060102T150405.000"
+
+// FileWriterOptions configures a RotatingFileWriter
+type FileWriterOptions struct {
+	Path string
+	// MaxSize rotates the file before it grows past this many bytes; 0 disables size rotation
+	MaxSize int64
+	// RotateEvery rotates the file once it has been written to for this long; 0 disables it
+	RotateEvery time.Duration
+	// MaxBackups is how many rotated files are kept, oldest removed first; 0 keeps them all
+	MaxBackups int
+}
+
+// RotatingFileWriter writes entries as JSON lines to a file, moving it aside to
+// "<name>-<timestamp><ext>" when it reaches MaxSize or RotateEvery passes
+type RotatingFileWriter struct {
+	mu       sync.Mutex
+	opts     FileWriterOptions
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewRotatingFileWriter opens opts.Path for appending, creating it and its directory if needed
+func NewRotatingFileWriter(opts FileWriterOptions) (*RotatingFileWriter, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+	w := &RotatingFileWriter{opts: opts, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFileWriter) Write(entry LogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.shouldRotate(int64(len(line))) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// WriteBatch writes entries in one call; the file isn't buffered, so it is the same as writing each
+func (w *RotatingFileWriter) WriteBatch(entries []LogEntry) error {
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *RotatingFileWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Sync()
+}
+
+// Close closes the current file
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+func (w *RotatingFileWriter) shouldRotate(incoming int64) bool {
+	if w.size == 0 {
+		// Never rotate an empty file, even for a line larger than MaxSize
+		return false
+	}
+	if w.opts.MaxSize > 0 && w.size+incoming > w.opts.MaxSize {
+		return true
+	}
+	return w.opts.RotateEvery > 0 && w.now().Sub(w.openedAt) >= w.opts.RotateEvery
+}
+
+func (w *RotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	return nil
+}
+
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	ext := filepath.Ext(w.opts.Path)
+	base := strings.TrimSuffix(w.opts.Path, ext)
+	backup := fmt.Sprintf("%s-%s%s", base, w.now().Format(backupTimeLayout), ext)
+	if err := os.Rename(w.opts.Path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.removeOldBackups(base, ext)
+}
+
+func (w *RotatingFileWriter) removeOldBackups(base, ext string) error {
+	if w.opts.MaxBackups <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return err
+	}
+	// Only count files this writer named, not others that happen to share the prefix
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, base+"-"), ext)
+		if _, err := time.Parse(backupTimeLayout, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > w.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("failed to remove old log file: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package logwriter
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLines(t *testing.T, path string) []LogEntry {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry LogEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRotatingFileWriter_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "app.log")
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	w, err := NewRotatingFileWriter(FileWriterOptions{Path: path, MaxSize: 150, MaxBackups: 2})
+	require.NoError(t, err)
+	w.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+	defer w.Close()
+
+	for _, message := range []string{"one", "two", "three", "four", "five"} {
+		require.NoError(t, w.Write(entry(message)))
+	}
+	require.NoError(t, w.Flush())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "logs", "app-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2, "older backups are removed")
+
+	current := readLines(t, path)
+	require.Len(t, current, 1)
+	assert.Equal(t, "five", current[0].Message)
+}
+
+func TestRotatingFileWriter_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	w, err := NewRotatingFileWriter(FileWriterOptions{Path: path, RotateEvery: time.Hour})
+	require.NoError(t, err)
+	w.now = func() time.Time { return clock }
+	w.openedAt = clock
+	defer w.Close()
+
+	require.NoError(t, w.Write(entry("before")))
+	clock = clock.Add(time.Hour)
+	require.NoError(t, w.Write(entry("after")))
+
+	backup := filepath.Join(filepath.Dir(path), "app-20260102T010000.000.log")
+	assert.Equal(t, "before", readLines(t, backup)[0].Message)
+	assert.Equal(t, "after", readLines(t, path)[0].Message)
+}
+
+func TestRotatingFileWriter_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	first, err := NewRotatingFileWriter(FileWriterOptions{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, first.Write(entry("first")))
+	require.NoError(t, first.Close())
+
+	second, err := NewRotatingFileWriter(FileWriterOptions{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, second.WriteBatch([]LogEntry{entry("second")}))
+	require.NoError(t, second.Close())
+
+	assert.Len(t, readLines(t, path), 2)
+}
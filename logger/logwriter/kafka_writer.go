@@ -0,0 +1,123 @@
+package logwriter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// KafkaWriter produces entries to a Kafka topic through a Kafka REST Proxy (the Confluent v2 API).
+// Entries are keyed by request ID, so one request's entries stay in order on one partition.
+type KafkaWriter struct {
+	client    *http.Client
+	endpoint  string
+	topic     string
+	mu        sync.Mutex
+	buffer    []LogEntry
+	bufferMax int
+}
+
+type kafkaRecord struct {
+	Key   string   `json:"key,omitempty"`
+	Value LogEntry `json:"value"`
+}
+
+type kafkaPayload struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+func NewKafkaWriter(restEndpoint, topic string) *KafkaWriter {
+	return &KafkaWriter{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		endpoint:  restEndpoint,
+		topic:     topic,
+		buffer:    make([]LogEntry, 0, 100),
+		bufferMax: 100,
+	}
+}
+
+func (w *KafkaWriter) Write(entry LogEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer = append(w.buffer, entry)
+
+	if len(w.buffer) >= w.bufferMax {
+		return w.flush()
+	}
+
+	return nil
+}
+
+func (w *KafkaWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+func (w *KafkaWriter) flush() error {
+	if err := w.send(w.buffer); err != nil {
+		return err
+	}
+
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// WriteBatch produces entries at once, bypassing the buffer
+func (w *KafkaWriter) WriteBatch(entries []LogEntry) error {
+	return w.send(entries)
+}
+
+func (w *KafkaWriter) send(entries []LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	payload := kafkaPayload{Records: make([]kafkaRecord, len(entries))}
+	for i, entry := range entries {
+		payload.Records[i] = kafkaRecord{Key: entry.RequestID, Value: entry}
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Kafka payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/topics/%s", w.endpoint, url.PathEscape(w.topic)), bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send logs to Kafka: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka produce failed with status: %d", resp.StatusCode)
+	}
+
+	// The proxy reports per-record failures in a 200 response
+	var result struct {
+		Offsets []struct {
+			Error string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
+		for _, offset := range result.Offsets {
+			if offset.Error != "" {
+				return fmt.Errorf("kafka produce failed: %s", offset.Error)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package logwriter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaWriter_ProducesKeyedRecords(t *testing.T) {
+	var received kafkaPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/app-logs", r.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"offsets": [{"partition": 0, "offset": 1}, {"partition": 0, "offset": 2}]}`))
+	}))
+	defer server.Close()
+
+	w := NewKafkaWriter(server.URL, "app-logs")
+	first := entry("a")
+	first.RequestID = "req-1"
+	require.NoError(t, w.Write(first))
+	require.NoError(t, w.Write(entry("b")))
+	require.NoError(t, w.Flush())
+
+	require.Len(t, received.Records, 2)
+	assert.Equal(t, "req-1", received.Records[0].Key)
+	assert.Equal(t, "a", received.Records[0].Value.Message)
+	assert.Empty(t, received.Records[1].Key)
+}
+
+func TestKafkaWriter_RecordError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"offsets": [{"error_code": 40403, "error": "topic not found"}]}`))
+	}))
+	defer server.Close()
+
+	w := NewKafkaWriter(server.URL, "missing")
+	assert.EqualError(t, w.WriteBatch([]LogEntry{entry("a")}), "kafka produce failed: topic not found")
+}
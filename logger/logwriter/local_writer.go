@@ -50,6 +50,9 @@ func (w *LocalWriter) Write(entry LogEntry) error {
 	if entry.TraceID != "" {
 		fields = append(fields, fmt.Sprintf("trace_id=%s%s%s", colorBlue, entry.TraceID, colorReset))
 	}
+	if entry.SpanID != "" {
+		fields = append(fields, fmt.Sprintf("span_id=%s%s%s", colorBlue, entry.SpanID, colorReset))
+	}
 	if entry.UserID != "" {
 		fields = append(fields, fmt.Sprintf("user_id=%s%s%s", colorPurple, entry.UserID, colorReset))
 	}
@@ -19,6 +19,7 @@ type LogEntry struct {
 	Message   string                 `json:"message"`
 	RequestID string                 `json:"request_id,omitempty"`
 	TraceID   string                 `json:"trace_id,omitempty"`
+	SpanID    string                 `json:"span_id,omitempty"`
 	UserID    string                 `json:"user_id,omitempty"`
 	UserEmail string                 `json:"user_email,omitempty"`
 	Fields    map[string]interface{} `json:"fields,omitempty"`
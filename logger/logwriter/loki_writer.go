@@ -69,14 +69,29 @@ func (w *LokiWriter) Flush() error {
 }
 
 func (w *LokiWriter) flush() error {
-	if len(w.buffer) == 0 {
+	if err := w.send(w.buffer); err != nil {
+		return err
+	}
+
+	// Clear buffer
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// WriteBatch pushes entries to Loki at once, bypassing the buffer
+func (w *LokiWriter) WriteBatch(entries []LogEntry) error {
+	return w.send(entries)
+}
+
+func (w *LokiWriter) send(entries []LogEntry) error {
+	if len(entries) == 0 {
 		return nil
 	}
 
 	// Group log entries by their label combination
 	streamMap := make(map[string]*LokiStream)
 
-	for _, entry := range w.buffer {
+	for _, entry := range entries {
 		// Create stream labels for this entry
 		streamLabels := w.createStreamLabels(entry)
 
@@ -130,8 +145,6 @@ func (w *LokiWriter) flush() error {
 		return fmt.Errorf("Loki push failed with status: %d", resp.StatusCode)
 	}
 
-	// Clear buffer
-	w.buffer = w.buffer[:0]
 	return nil
 }
 
@@ -184,6 +197,15 @@ func (w *LokiWriter) formatLogEntry(entry LogEntry) string {
 		logData["caller"] = entry.Caller
 	}
 
+	// The trace is in the line as well as the labels, so Grafana can link the entry to its span
+	if entry.TraceID != "" {
+		logData["trace_id"] = entry.TraceID
+	}
+
+	if entry.SpanID != "" {
+		logData["span_id"] = entry.SpanID
+	}
+
 	if entry.Error != "" {
 		logData["error"] = entry.Error
 	}
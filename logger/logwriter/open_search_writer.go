@@ -50,12 +50,26 @@ func (w *OpenSearchWriter) Flush() error {
 }
 
 func (w *OpenSearchWriter) flush() error {
-	if len(w.buffer) == 0 {
+	if err := w.send(w.buffer); err != nil {
+		return err
+	}
+
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// WriteBatch bulk-indexes entries at once, bypassing the buffer
+func (w *OpenSearchWriter) WriteBatch(entries []LogEntry) error {
+	return w.send(entries)
+}
+
+func (w *OpenSearchWriter) send(entries []LogEntry) error {
+	if len(entries) == 0 {
 		return nil
 	}
 
 	var bulkBody bytes.Buffer
-	for _, entry := range w.buffer {
+	for _, entry := range entries {
 		meta := map[string]interface{}{
 			"index": map[string]string{
 				"_index": w.indexName,
@@ -87,6 +101,5 @@ func (w *OpenSearchWriter) flush() error {
 		return fmt.Errorf("opensearch bulk insert failed with status: %d", resp.StatusCode)
 	}
 
-	w.buffer = w.buffer[:0]
 	return nil
 }
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yadunandan004/scaffold/config"
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"go.uber.org/zap"
+)
+
+// SampledField is added to an entry that stands for entries dropped by sampling, counting itself and
+// the dropped ones, so the sum of its values over the kept entries is the number logged
+const SampledField = "sampled"
+
+type sampler struct {
+	mu     sync.Mutex
+	rules  map[logwriter.LogLevel]config.LogSamplingRule
+	counts map[string]*sampleCount
+	now    func() time.Time
+}
+
+// sampleCount tracks one message key within the current one-second window
+type sampleCount struct {
+	window  time.Time
+	seen    int
+	skipped int
+}
+
+var sampling = &sampler{now: time.Now}
+
+// ConfigureSampling replaces the sampling rules, typically with config.GetLoggerConfig(resolver).Sampling.
+// Entries are counted per level, call site and message, where the message of the printf-style
+// functions is their format, so LogEnter calls with different arguments share one count.
+func ConfigureSampling(cfg config.LogSamplingConfig) {
+	rules := make(map[logwriter.LogLevel]config.LogSamplingRule)
+	for level, rule := range map[logwriter.LogLevel]config.LogSamplingRule{
+		logwriter.DebugLevel: cfg.Debug,
+		logwriter.InfoLevel:  cfg.Info,
+		logwriter.WarnLevel:  cfg.Warn,
+		logwriter.ErrorLevel: cfg.Error,
+	} {
+		if rule.PerSecond > 0 || rule.OneIn > 1 {
+			rules[level] = rule
+		}
+	}
+
+	sampling.mu.Lock()
+	defer sampling.mu.Unlock()
+	sampling.rules = rules
+	sampling.counts = make(map[string]*sampleCount)
+}
+
+// admit returns how many entries one at level from caller with message stands for after the level
+// and sampling are applied, or 0 when it is not written
+func admit(level logwriter.LogLevel, caller, message string) int {
+	if !enabled(level, caller) {
+		return 0
+	}
+	return sampling.sample(level, caller, message)
+}
+
+// sample returns how many entries the entry at level from caller with message stands for: 0 when it
+// is dropped, otherwise 1 plus the entries with the same key dropped since the last one kept
+func (s *sampler) sample(level logwriter.LogLevel, caller, message string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, ok := s.rules[level]
+	if !ok {
+		return 1
+	}
+
+	key := string(level) + "|" + caller + "|" + message
+	count := s.counts[key]
+	if count == nil {
+		count = &sampleCount{}
+		s.counts[key] = count
+	}
+	now := s.now()
+	if now.Sub(count.window) >= time.Second {
+		count.window = now
+		count.seen = 0
+	}
+	count.seen++
+
+	keep := count.seen <= rule.PerSecond
+	if !keep && rule.OneIn > 0 {
+		keep = (count.seen-rule.PerSecond)%rule.OneIn == 0
+	}
+	if !keep {
+		count.skipped++
+		return 0
+	}
+	represents := count.skipped + 1
+	count.skipped = 0
+	return represents
+}
+
+// sampledFields records on entry, and returns as zap fields, how many entries it stands for when
+// that is more than itself
+func sampledFields(entry *logwriter.LogEntry, represents int) []zap.Field {
+	if represents <= 1 {
+		return nil
+	}
+	// Copy the fields rather than add to a map the caller owns
+	fields := make(map[string]interface{}, len(entry.Fields)+1)
+	for key, value := range entry.Fields {
+		fields[key] = value
+	}
+	fields[SampledField] = represents
+	entry.Fields = fields
+	return []zap.Field{zap.Int(SampledField, represents)}
+}
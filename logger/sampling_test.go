@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/config"
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// useSampling applies cfg with a clock the test moves, and turns sampling off when the test ends
+func useSampling(t *testing.T, cfg config.LogSamplingConfig) *time.Time {
+	t.Helper()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampling.mu.Lock()
+	sampling.now = func() time.Time { return now }
+	sampling.mu.Unlock()
+	ConfigureSampling(cfg)
+	t.Cleanup(func() {
+		ConfigureSampling(config.LogSamplingConfig{})
+		sampling.mu.Lock()
+		sampling.now = time.Now
+		sampling.mu.Unlock()
+	})
+	return &now
+}
+
+func TestSampling_PerSecondThenDropped(t *testing.T) {
+	now := useSampling(t, config.LogSamplingConfig{Debug: config.LogSamplingRule{PerSecond: 2}})
+	capture := useCaptureWriter(t)
+
+	hotPath := func() { LogDebugKV(nil, "hot path") }
+	for i := 0; i < 5; i++ {
+		hotPath()
+	}
+	require.Len(t, capture.entries, 2)
+	assert.Nil(t, capture.entries[1].Fields)
+
+	*now = now.Add(time.Second)
+	hotPath()
+
+	require.Len(t, capture.entries, 3)
+	assert.Equal(t, 4, capture.entries[2].Fields[SampledField], "the kept entry stands for the 3 dropped ones too")
+}
+
+func TestSampling_OneIn(t *testing.T) {
+	useSampling(t, config.LogSamplingConfig{Debug: config.LogSamplingRule{PerSecond: 1, OneIn: 3}})
+	capture := useCaptureWriter(t)
+
+	for i := 0; i < 7; i++ {
+		LogDebugKV(nil, "hot path", String("attempt", "x"))
+	}
+
+	require.Len(t, capture.entries, 3)
+	assert.Nil(t, capture.entries[0].Fields[SampledField])
+	assert.Equal(t, 3, capture.entries[1].Fields[SampledField])
+	assert.Equal(t, 3, capture.entries[2].Fields[SampledField])
+	assert.Equal(t, "x", capture.entries[2].Fields["attempt"])
+}
+
+func TestSampling_KeyedByLevelAndMessage(t *testing.T) {
+	useSampling(t, config.LogSamplingConfig{Debug: config.LogSamplingRule{PerSecond: 1}})
+	capture := useCaptureWriter(t)
+
+	for i := 0; i < 3; i++ {
+		LogDebugKV(nil, "first")
+		LogDebugKV(nil, "second")
+		LogInfoKV(nil, "unsampled")
+	}
+
+	assert.Len(t, capture.entries, 5)
+}
+
+func TestSampling_LeavesCallerFieldsUntouched(t *testing.T) {
+	useSampling(t, config.LogSamplingConfig{Info: config.LogSamplingRule{OneIn: 2}})
+	useCaptureWriter(t)
+
+	ctx := request.NewTestContext()
+	fields := map[string]interface{}{"order_id": "42"}
+	for i := 0; i < 3; i++ {
+		LogWithFields(ctx, logwriter.InfoLevel, "order placed", fields)
+	}
+
+	assert.Equal(t, map[string]interface{}{"order_id": "42"}, fields)
+}
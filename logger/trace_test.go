@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yadunandan004/scaffold/request"
+)
+
+func TestLog_ActiveSpanSuppliesTraceAndSpanIDs(t *testing.T) {
+	capture := useCaptureWriter(t)
+	ctx := request.NewTestContext()
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx.SetCtx(trace.ContextWithSpanContext(ctx.GetCtx(), spanCtx))
+
+	LogInfoKV(ctx, "with span")
+	LogInfoWithContext(ctx, "with span too")
+
+	require.Len(t, capture.entries, 2)
+	for _, entry := range capture.entries {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", entry.SpanID)
+	}
+}
+
+func TestLog_NoSpanKeepsContextTraceID(t *testing.T) {
+	capture := useCaptureWriter(t)
+	ctx := request.NewTestContext()
+
+	LogInfoKV(ctx, "without span")
+
+	require.Len(t, capture.entries, 1)
+	assert.Equal(t, ctx.TraceID(), capture.entries[0].TraceID)
+	assert.Empty(t, capture.entries[0].SpanID)
+}
+
+func TestLog_RemoteSpanSuppliesOnlyTraceID(t *testing.T) {
+	capture := useCaptureWriter(t)
+	ctx := request.NewTestContext()
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Remote:  true,
+	})
+	ctx.SetCtx(trace.ContextWithRemoteSpanContext(ctx.GetCtx(), spanCtx))
+
+	LogInfoKV(ctx, "from a traced caller")
+
+	require.Len(t, capture.entries, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", capture.entries[0].TraceID)
+	assert.Empty(t, capture.entries[0].SpanID, "the span belongs to the caller")
+}
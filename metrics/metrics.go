@@ -156,9 +156,44 @@ func RecordCacheMiss(ctx context.Context, cacheType string) {
 	globalStdMetrics.RecordCacheMiss(ctx, cacheType)
 }
 
+func RecordCacheLookup(ctx context.Context, cacheType, operation, keyPrefix string, hit bool) {
+	if globalStdMetrics == nil {
+		return
+	}
+	globalStdMetrics.RecordCacheLookup(ctx, cacheType, operation, keyPrefix, hit)
+}
+
+func RecordCacheOperation(ctx context.Context, cacheType, operation, keyPrefix string, duration time.Duration, failed bool) {
+	if globalStdMetrics == nil {
+		return
+	}
+	globalStdMetrics.RecordCacheOperation(ctx, cacheType, operation, keyPrefix, duration.Seconds(), failed)
+}
+
 func RecordWorkflow(ctx context.Context, workflowType, status string) {
 	if globalStdMetrics == nil {
 		return
 	}
 	globalStdMetrics.RecordWorkflow(ctx, workflowType, status)
 }
+
+func RecordClickHouseFlush(ctx context.Context, table string, rows int, duration time.Duration, success bool) {
+	if globalStdMetrics == nil {
+		return
+	}
+	globalStdMetrics.RecordClickHouseFlush(ctx, table, rows, duration.Seconds(), success)
+}
+
+func RecordPurge(ctx context.Context, table string, rows int64, duration time.Duration, success bool) {
+	if globalStdMetrics == nil {
+		return
+	}
+	globalStdMetrics.RecordPurge(ctx, table, rows, duration.Seconds(), success)
+}
+
+func RecordPanic(ctx context.Context, transport, route string) {
+	if globalStdMetrics == nil {
+		return
+	}
+	globalStdMetrics.RecordPanic(ctx, transport, route)
+}
@@ -11,7 +11,8 @@ import (
 // GinMiddleware creates a Gin middleware for OpenTelemetry metrics and tracing
 func GinMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.Request.URL.Path == "/metrics" || c.Request.URL.Path == "/health" {
+		if c.Request.URL.Path == "/metrics" || c.Request.URL.Path == "/health" ||
+			c.Request.URL.Path == "/healthz" || c.Request.URL.Path == "/readyz" {
 			c.Next()
 			return
 		}
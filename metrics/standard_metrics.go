@@ -20,7 +20,16 @@ type StandardMetrics struct {
 	goroutineFinished      providers.Counter
 	cacheHitCounter        providers.Counter
 	cacheMissCounter       providers.Counter
+	cacheOpDuration        providers.Histogram
+	cacheErrorCounter      providers.Counter
 	workflowCounter        providers.Counter
+	chFlushDuration        providers.Histogram
+	chFlushCounter         providers.Counter
+	chFlushedRows          providers.Counter
+	purgeDuration          providers.Histogram
+	purgeCounter           providers.Counter
+	purgedRows             providers.Counter
+	panicCounter           providers.Counter
 	mu                     sync.RWMutex
 }
 
@@ -90,11 +99,56 @@ func initStandardMetrics(registry *MetricRegistry) *StandardMetrics {
 				"Total number of cache misses",
 				"1",
 			),
+			cacheOpDuration: registry.MustRegisterHistogram(
+				"cache_operation_duration_seconds",
+				"Duration of cache operations in seconds",
+				"s",
+			),
+			cacheErrorCounter: registry.MustRegisterCounter(
+				"cache_errors_total",
+				"Total number of failed cache operations",
+				"1",
+			),
 			workflowCounter: registry.MustRegisterCounter(
 				"workflows_total",
 				"Total number of workflows executed",
 				"1",
 			),
+			chFlushDuration: registry.MustRegisterHistogram(
+				"clickhouse_flush_duration_seconds",
+				"Duration of ClickHouse batch flushes in seconds",
+				"s",
+			),
+			chFlushCounter: registry.MustRegisterCounter(
+				"clickhouse_flushes_total",
+				"Total number of ClickHouse batch flushes",
+				"1",
+			),
+			chFlushedRows: registry.MustRegisterCounter(
+				"clickhouse_flushed_rows_total",
+				"Total number of rows flushed to ClickHouse",
+				"1",
+			),
+			purgeDuration: registry.MustRegisterHistogram(
+				"purge_duration_seconds",
+				"Duration of scheduled purges of stale rows in seconds",
+				"s",
+			),
+			purgeCounter: registry.MustRegisterCounter(
+				"purges_total",
+				"Total number of scheduled purges of stale rows",
+				"1",
+			),
+			purgedRows: registry.MustRegisterCounter(
+				"purged_rows_total",
+				"Total number of stale rows deleted by scheduled purges",
+				"1",
+			),
+			panicCounter: registry.MustRegisterCounter(
+				"panics_recovered_total",
+				"Total number of panics recovered from request handlers",
+				"1",
+			),
 		}
 	})
 	return standardMetrics
@@ -172,6 +226,49 @@ func (sm *StandardMetrics) RecordCacheMiss(ctx context.Context, cacheType string
 	sm.cacheMissCounter.Inc(ctx, providers.Labels("cache_type", cacheType)...)
 }
 
+func (sm *StandardMetrics) RecordCacheLookup(ctx context.Context, cacheType, operation, keyPrefix string, hit bool) {
+	labels := providers.Labels("cache_type", cacheType, "operation", operation, "key_prefix", keyPrefix)
+	if hit {
+		sm.cacheHitCounter.Inc(ctx, labels...)
+	} else {
+		sm.cacheMissCounter.Inc(ctx, labels...)
+	}
+}
+
+func (sm *StandardMetrics) RecordCacheOperation(ctx context.Context, cacheType, operation, keyPrefix string, duration float64, failed bool) {
+	labels := providers.Labels("cache_type", cacheType, "operation", operation, "key_prefix", keyPrefix)
+	sm.cacheOpDuration.Record(ctx, duration, labels...)
+	if failed {
+		sm.cacheErrorCounter.Inc(ctx, labels...)
+	}
+}
+
 func (sm *StandardMetrics) RecordWorkflow(ctx context.Context, workflowType, status string) {
 	sm.workflowCounter.Inc(ctx, providers.Labels("workflow_type", workflowType, "status", status)...)
 }
+
+func (sm *StandardMetrics) RecordClickHouseFlush(ctx context.Context, table string, rows int, duration float64, success bool) {
+	status := "success"
+	if !success {
+		status = "failure"
+	}
+	labels := providers.Labels("table", table, "status", status)
+	sm.chFlushDuration.Record(ctx, duration, labels...)
+	sm.chFlushCounter.Inc(ctx, labels...)
+	sm.chFlushedRows.Add(ctx, int64(rows), labels...)
+}
+
+func (sm *StandardMetrics) RecordPurge(ctx context.Context, table string, rows int64, duration float64, success bool) {
+	status := "success"
+	if !success {
+		status = "failure"
+	}
+	labels := providers.Labels("table", table, "status", status)
+	sm.purgeDuration.Record(ctx, duration, labels...)
+	sm.purgeCounter.Inc(ctx, labels...)
+	sm.purgedRows.Add(ctx, rows, labels...)
+}
+
+func (sm *StandardMetrics) RecordPanic(ctx context.Context, transport, route string) {
+	sm.panicCounter.Inc(ctx, providers.Labels("transport", transport, "route", route)...)
+}
@@ -0,0 +1,94 @@
+package orm
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is matched, with errors.Is, by the errors of lookups that found no row
+	ErrNotFound = errors.New("not found")
+	// ErrNoRowsAffected is matched by the errors of updates and deletes that changed fewer rows than
+	// they addressed, because the rows don't exist or were deleted concurrently
+	ErrNoRowsAffected = errors.New("no rows affected")
+)
+
+// NotFoundError reports that no row of Table has primary key PK. It matches both ErrNotFound and
+// sql.ErrNoRows, so existing sql.ErrNoRows checks keep working.
+type NotFoundError struct {
+	Table string
+	// PK is the primary key looked up; nil when the lookup wasn't by a single key
+	PK interface{}
+}
+
+// NewNotFoundError returns the error for a lookup of pk in table that found no row
+func NewNotFoundError(table string, pk interface{}) error {
+	return &NotFoundError{Table: table, PK: pk}
+}
+
+func (e *NotFoundError) Error() string {
+	if e.PK == nil {
+		return fmt.Sprintf("%s: not found", e.Table)
+	}
+	return fmt.Sprintf("%s %v: not found", e.Table, e.PK)
+}
+
+func (e *NotFoundError) Unwrap() []error {
+	return []error{ErrNotFound, sql.ErrNoRows}
+}
+
+// notFound converts sql.ErrNoRows from a lookup of pk in table into a NotFoundError
+func notFound(err error, table string, pk interface{}) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewNotFoundError(table, pk)
+	}
+	return err
+}
+
+// RowsAffectedError reports that an update or delete of Table changed Affected rows out of Expected.
+// It matches ErrNoRowsAffected, and ErrNotFound and sql.ErrNoRows as the missing rows weren't found.
+type RowsAffectedError struct {
+	// Op is "update" or "delete"
+	Op    string
+	Table string
+	// PK is the primary key written; nil when the write addressed several rows
+	PK       interface{}
+	Expected int64
+	Affected int64
+}
+
+func (e *RowsAffectedError) Error() string {
+	if e.PK != nil {
+		return fmt.Sprintf("%s of %s %v affected no rows", e.Op, e.Table, e.PK)
+	}
+	return fmt.Sprintf("%s of %s affected %d of %d rows", e.Op, e.Table, e.Affected, e.Expected)
+}
+
+func (e *RowsAffectedError) Unwrap() []error {
+	return []error{ErrNoRowsAffected, ErrNotFound, sql.ErrNoRows}
+}
+
+// checkAffected returns a RowsAffectedError when the write's result changed fewer than expected rows
+func checkAffected(result sql.Result, err error, op, table string, pk interface{}, expected int64) error {
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected < expected {
+		return &RowsAffectedError{Op: op, Table: table, PK: pk, Expected: expected, Affected: affected}
+	}
+	return nil
+}
+
+// distinctCount returns how many different values ids holds, the rows an IN list can address
+func distinctCount(ids []interface{}) int64 {
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		seen[fmt.Sprint(id)] = struct{}{}
+	}
+	return int64(len(seen))
+}
@@ -0,0 +1,88 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFoundError_Matches(t *testing.T) {
+	err := NewNotFoundError("users", 42)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "existing sql.ErrNoRows checks keep working")
+	assert.Equal(t, "users 42: not found", err.Error())
+	assert.Equal(t, "users: not found", NewNotFoundError("users", nil).Error())
+
+	var notFound *NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "users", notFound.Table)
+	assert.Equal(t, 42, notFound.PK)
+}
+
+func TestFindByPK_NotFound(t *testing.T) {
+	missing := uuid.New()
+
+	query := beginTxn(t)
+	defer query.Rollback()
+	var node TestNode
+	err := NewTransaction[TestNode]().FindByPK(query, &node, missing)
+
+	var notFound *NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "test_nodes", notFound.Table)
+	assert.Equal(t, missing, notFound.PK)
+
+	err = NewDB[TestNode](scannerTestDB).FindByPK(context.Background(), &node, missing)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateDelete_NoRowsAffected(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+	missing := &TestNode{ID: uuid.New(), Name: "missing"}
+
+	query := beginTxn(t)
+	defer query.Rollback()
+	txn := NewTransaction[TestNode]()
+
+	err := txn.Update(query, missing)
+	var affected *RowsAffectedError
+	assert.ErrorAs(t, err, &affected)
+	assert.Equal(t, "update", affected.Op)
+	assert.Equal(t, missing.ID, affected.PK)
+	assert.ErrorIs(t, err, ErrNoRowsAffected)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.ErrorIs(t, txn.Delete(query, missing), ErrNoRowsAffected)
+	assert.ErrorIs(t, NewDB[TestNode](scannerTestDB).Delete(context.Background(), missing), ErrNoRowsAffected)
+}
+
+func TestDeleteMultiple_ReportsMissingRows(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+	existing := &TestNode{ID: uuid.New(), Name: "existing", Tags: []string{}, Metadata: map[string]interface{}{},
+		Attributes: map[string]float64{}, Items: []TestItem{}}
+
+	query := beginTxn(t)
+	defer query.Rollback()
+	txn := NewTransaction[TestNode]()
+	assert.NoError(t, txn.Create(query, existing))
+
+	err := txn.DeleteMultiple(query, []*TestNode{existing, existing, {ID: uuid.New()}})
+
+	var affected *RowsAffectedError
+	assert.ErrorAs(t, err, &affected)
+	assert.Equal(t, int64(2), affected.Expected, "a repeated ID addresses one row")
+	assert.Equal(t, int64(1), affected.Affected)
+	assert.Equal(t, "delete of test_nodes affected 1 of 2 rows", err.Error())
+}
+
+func TestRowsAffectedError_Message(t *testing.T) {
+	err := &RowsAffectedError{Op: "update", Table: "users", PK: 42, Expected: 1}
+
+	assert.Equal(t, "update of users 42 affected no rows", err.Error())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,206 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Plan is a statement's plan as reported by EXPLAIN (FORMAT JSON). The timings are in milliseconds
+// and only set by ExplainAnalyze.
+type Plan struct {
+	Root          PlanNode `json:"Plan"`
+	PlanningTime  float64  `json:"Planning Time,omitempty"`
+	ExecutionTime float64  `json:"Execution Time,omitempty"`
+}
+
+// PlanNode is one step of a Plan; the Actual fields are only set by ExplainAnalyze
+type PlanNode struct {
+	NodeType        string     `json:"Node Type"`
+	RelationName    string     `json:"Relation Name,omitempty"`
+	IndexName       string     `json:"Index Name,omitempty"`
+	Filter          string     `json:"Filter,omitempty"`
+	IndexCond       string     `json:"Index Cond,omitempty"`
+	StartupCost     float64    `json:"Startup Cost"`
+	TotalCost       float64    `json:"Total Cost"`
+	PlanRows        float64    `json:"Plan Rows"`
+	ActualTotalTime float64    `json:"Actual Total Time,omitempty"`
+	ActualRows      float64    `json:"Actual Rows,omitempty"`
+	ActualLoops     float64    `json:"Actual Loops,omitempty"`
+	Plans           []PlanNode `json:"Plans,omitempty"`
+}
+
+// String renders the plan as an indented tree, one node per line
+func (p *Plan) String() string {
+	var b strings.Builder
+	p.Root.write(&b, 0)
+	if p.ExecutionTime > 0 {
+		fmt.Fprintf(&b, "Planning Time: %.3f ms\nExecution Time: %.3f ms\n", p.PlanningTime, p.ExecutionTime)
+	}
+	return b.String()
+}
+
+func (n *PlanNode) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(n.NodeType)
+	if n.IndexName != "" {
+		fmt.Fprintf(b, " using %s", n.IndexName)
+	}
+	if n.RelationName != "" {
+		fmt.Fprintf(b, " on %s", n.RelationName)
+	}
+	fmt.Fprintf(b, " (cost=%.2f..%.2f rows=%.0f)", n.StartupCost, n.TotalCost, n.PlanRows)
+	if n.ActualLoops > 0 {
+		fmt.Fprintf(b, " (actual time=%.3f rows=%.0f loops=%.0f)", n.ActualTotalTime, n.ActualRows, n.ActualLoops)
+	}
+	for _, cond := range []struct{ label, value string }{{"Index Cond", n.IndexCond}, {"Filter", n.Filter}} {
+		if cond.value != "" {
+			fmt.Fprintf(b, "\n%s  %s: %s", strings.Repeat("  ", depth), cond.label, cond.value)
+		}
+	}
+	b.WriteString("\n")
+	for i := range n.Plans {
+		n.Plans[i].write(b, depth+1)
+	}
+}
+
+// Explain returns the plan of query in the transaction without running it
+func (q *Query) Explain(query string, args ...interface{}) (*Plan, error) {
+	plan, err := explain(q.ctx(), q.Txn, false, query, args)
+	return plan, q.wrapErr(err)
+}
+
+// ExplainAnalyze runs query under EXPLAIN ANALYZE inside a savepoint that is rolled back, so the plan
+// has actual timings and any writes are undone while the transaction stays usable
+func (q *Query) ExplainAnalyze(query string, args ...interface{}) (*Plan, error) {
+	plan, err := q.explainInSavepoint(true, query, args)
+	return plan, q.wrapErr(err)
+}
+
+// explainInSavepoint explains query inside a savepoint that is rolled back, so neither the analyzed
+// statement's writes nor a failed EXPLAIN, which would abort the transaction, outlive the call
+func (q *Query) explainInSavepoint(analyze bool, query string, args []interface{}) (*Plan, error) {
+	ctx := q.ctx()
+	if _, err := q.Txn.ExecContext(ctx, "SAVEPOINT orm_explain"); err != nil {
+		return nil, err
+	}
+	plan, err := explain(ctx, q.Txn, analyze, query, args)
+	if _, rbErr := q.Txn.ExecContext(ctx, "ROLLBACK TO SAVEPOINT orm_explain"); rbErr != nil && err == nil {
+		err = rbErr
+	}
+	if _, relErr := q.Txn.ExecContext(ctx, "RELEASE SAVEPOINT orm_explain"); relErr != nil && err == nil {
+		err = relErr
+	}
+	return plan, err
+}
+
+// Explain returns the plan of query on db without running it
+func Explain(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*Plan, error) {
+	return explain(ctx, db, false, query, args)
+}
+
+// ExplainAnalyze runs query under EXPLAIN ANALYZE in a transaction that is always rolled back, so the
+// plan has actual timings and any writes are undone
+func ExplainAnalyze(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*Plan, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return explain(ctx, tx, true, query, args)
+}
+
+// explainable reports whether query is a SELECT, INSERT, UPDATE or DELETE, possibly behind WITH, which
+// EXPLAIN accepts; it rejects statements such as SAVEPOINT, DDL and REFRESH MATERIALIZED VIEW
+func explainable(query string) bool {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(query, "--"):
+			end := strings.IndexByte(query, '\n')
+			if end < 0 {
+				return false
+			}
+			query = query[end+1:]
+		case strings.HasPrefix(query, "/*"):
+			end := strings.Index(query, "*/")
+			if end < 0 {
+				return false
+			}
+			query = query[end+2:]
+		default:
+			end := strings.IndexFunc(query, func(r rune) bool {
+				return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+			})
+			if end < 0 {
+				end = len(query)
+			}
+			switch strings.ToUpper(query[:end]) {
+			case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH":
+				return true
+			}
+			return false
+		}
+	}
+}
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func explain(ctx context.Context, db rowQuerier, analyze bool, query string, args []interface{}) (*Plan, error) {
+	prefix := "EXPLAIN (FORMAT JSON) "
+	if analyze {
+		prefix = "EXPLAIN (ANALYZE, FORMAT JSON) "
+	}
+	var raw []byte
+	if err := db.QueryRowContext(ctx, prefix+query, args...).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("explain: %w", err)
+	}
+	return parsePlan(raw)
+}
+
+func parsePlan(raw []byte) (*Plan, error) {
+	var plans []Plan
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("parse plan: empty result")
+	}
+	return &plans[0], nil
+}
+
+var slowQueryThreshold atomic.Int64
+
+// SetSlowQueryExplain logs the plan of every Query SELECT, INSERT, UPDATE or DELETE slower than threshold,
+// for development. The plan comes from a plain EXPLAIN, so the statement doesn't run again. Zero turns it off.
+func SetSlowQueryExplain(threshold time.Duration) {
+	slowQueryThreshold.Store(int64(threshold))
+}
+
+// explainIfSlow logs the plan of a statement that succeeded but took at least the slow query threshold.
+// Query and QueryRowRaw leave rows open on the connection, so only statements already read are explained.
+// Only statements EXPLAIN accepts are explained, and in a savepoint, so the caller's transaction is
+// never aborted by it.
+func (q *Query) explainIfSlow(start time.Time, err error, query string, args []interface{}) {
+	threshold := time.Duration(slowQueryThreshold.Load())
+	if threshold <= 0 || err != nil {
+		return
+	}
+	elapsed := time.Since(start)
+	if elapsed < threshold || !explainable(query) {
+		return
+	}
+	plan, explainErr := q.explainInSavepoint(false, query, args)
+	if explainErr != nil {
+		log.Printf("[ORM] Slow query (%s), %v: %s", elapsed, explainErr, query)
+		return
+	}
+	log.Printf("[ORM] Slow query (%s): %s\n%s", elapsed, query, plan)
+}
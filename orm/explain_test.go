@@ -0,0 +1,116 @@
+package orm
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlan(t *testing.T) {
+	plan, err := parsePlan([]byte(`[{"Plan": {"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey",
+		"Index Cond": "(id = $1)", "Startup Cost": 0.15, "Total Cost": 8.17, "Plan Rows": 1,
+		"Actual Total Time": 0.02, "Actual Rows": 1, "Actual Loops": 1},
+		"Planning Time": 0.1, "Execution Time": 0.05}]`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Index Scan", plan.Root.NodeType)
+	assert.Equal(t, 0.05, plan.ExecutionTime)
+	assert.Equal(t, "Index Scan using users_pkey on users (cost=0.15..8.17 rows=1) (actual time=0.020 rows=1 loops=1)\n"+
+		"  Index Cond: (id = $1)\nPlanning Time: 0.100 ms\nExecution Time: 0.050 ms\n", plan.String())
+
+	_, err = parsePlan([]byte(`[]`))
+	assert.Error(t, err)
+}
+
+func TestExplain(t *testing.T) {
+	plan, err := Explain(context.Background(), scannerTestDB, "SELECT * FROM test_nodes WHERE name = $1", "x")
+	require.NoError(t, err)
+	assert.NotEmpty(t, plan.Root.NodeType)
+	assert.Zero(t, plan.ExecutionTime, "a plain explain doesn't run the statement")
+}
+
+func TestQuery_ExplainAnalyzeRollsBack(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+	query := beginTxn(t)
+	defer query.Rollback()
+
+	id := uuid.New()
+	plan, err := query.ExplainAnalyze("INSERT INTO test_nodes (id, name, config) VALUES ($1, 'explained', '{}')", id)
+	require.NoError(t, err)
+	assert.Equal(t, "ModifyTable", plan.Root.NodeType)
+	assert.Positive(t, plan.ExecutionTime)
+
+	exists, err := query.Exists("SELECT 1 FROM test_nodes WHERE id = $1", id)
+	require.NoError(t, err)
+	assert.False(t, exists, "the analyzed insert is rolled back and the transaction stays usable")
+}
+
+func TestSlowQueryExplain_LogsPlan(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	SetSlowQueryExplain(time.Millisecond)
+	defer SetSlowQueryExplain(0)
+
+	query := beginTxn(t)
+	defer query.Rollback()
+	_, err := query.Exec("SELECT pg_sleep(0.01)")
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "[ORM] Slow query")
+	assert.Contains(t, buf.String(), "Result")
+}
+
+func TestSlowQueryExplain_SkipsOtherStatements(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	SetSlowQueryExplain(time.Nanosecond)
+	defer SetSlowQueryExplain(0)
+
+	query := beginTxn(t)
+	defer query.Rollback()
+	for _, statement := range []string{
+		"SAVEPOINT before_ddl",
+		"CREATE TEMP TABLE slow_explain (id int) ON COMMIT DROP",
+		"RELEASE SAVEPOINT before_ddl",
+	} {
+		_, err := query.Exec(statement)
+		require.NoError(t, err, statement)
+	}
+	assert.NotContains(t, buf.String(), "[ORM] Slow query")
+
+	_, err := query.Exec("INSERT INTO slow_explain (id) VALUES (1)")
+	require.NoError(t, err)
+	count, err := query.Count("SELECT COUNT(*) FROM slow_explain")
+	require.NoError(t, err, "explaining slow statements leaves the transaction usable")
+	assert.Equal(t, 1, count)
+	assert.Contains(t, buf.String(), "[ORM] Slow query")
+}
+
+func TestExplainable(t *testing.T) {
+	for query, want := range map[string]bool{
+		"SELECT 1":                                  true,
+		"  select * from users":                     true,
+		"(SELECT 1) UNION (SELECT 2)":               true,
+		"-- lookup\nUPDATE users SET name = $1":     true,
+		"/* job */ DELETE FROM users":               true,
+		"WITH gone AS (DELETE FROM users) SELECT 1": true,
+		"INSERT INTO users (id) VALUES ($1)":        true,
+		"SAVEPOINT sp":                              false,
+		"REFRESH MATERIALIZED VIEW totals":          false,
+		"CREATE INDEX ON users (name)":              false,
+		"SELECT set_config('a', 'b', true)":         true,
+		"LOCK TABLE users":                          false,
+		"":                                          false,
+	} {
+		assert.Equal(t, want, explainable(query), query)
+	}
+}
@@ -9,6 +9,12 @@ type FieldOptions struct {
 	Column          string
 	IsPK            bool
 	IsAutoIncrement bool
+	// IsReadOnly fields are scanned but never written, such as generated columns and DB-maintained counters
+	IsReadOnly bool
+	// TSVectorSources are the columns a generated tsvector column indexes; see FullTextDDL
+	TSVectorSources []string
+	// TSConfig is the text search configuration of a tsvector column (default "english")
+	TSConfig string
 }
 
 func parseFields(typ reflect.Type) ([]FieldMetadata, []uintptr, []reflect.Type, []string, int) {
@@ -66,6 +72,9 @@ func parseFieldsRecursive(
 			Offset:          baseOffset + field.Offset,
 			IsPK:            opts.IsPK,
 			IsAutoIncrement: opts.IsAutoIncrement,
+			IsReadOnly:      opts.IsReadOnly,
+			TSVectorSources: opts.TSVectorSources,
+			TSConfig:        opts.TSConfig,
 			Index:           i,
 		})
 
@@ -92,6 +101,14 @@ func parseORMTag(fieldName string, tag string) FieldOptions {
 			opts.IsPK = true
 		} else if part == "auto" {
 			opts.IsAutoIncrement = true
+		} else if part == "readonly" || part == "generated" {
+			opts.IsReadOnly = true
+		} else if strings.HasPrefix(part, "tsvector:") {
+			// The database generates the column, so it is never written
+			opts.TSVectorSources = strings.Split(strings.TrimPrefix(part, "tsvector:"), ",")
+			opts.IsReadOnly = true
+		} else if strings.HasPrefix(part, "tsconfig:") {
+			opts.TSConfig = strings.TrimPrefix(part, "tsconfig:")
 		}
 	}
 
@@ -115,7 +132,7 @@ func filterInsertFields(fields []FieldMetadata, columnNames []string) ([]string,
 	insertIndices := make([]int, 0, len(columnNames))
 
 	for i, field := range fields {
-		if field.IsAutoIncrement {
+		if field.IsAutoIncrement || field.IsReadOnly {
 			continue
 		}
 		insertColumns = append(insertColumns, columnNames[i])
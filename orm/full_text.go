@@ -0,0 +1,55 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DefaultTSConfig is the text search configuration of tsvector columns without a tsconfig tag
+const DefaultTSConfig = "english"
+
+// FullTextDDL returns the statements adding each tsvector column of the model, generated from its source
+// columns, and a GIN index on it. The table is qualified for schema as the model's other statements are.
+// Every statement is idempotent, so they can run at each startup.
+func FullTextDDL(metadata *ModelMetadata, schema string) []string {
+	table := metadata.QualifiedTable(schema)
+
+	var statements []string
+	for _, field := range metadata.Fields {
+		if len(field.TSVectorSources) == 0 {
+			continue
+		}
+		config := field.TSConfig
+		if config == "" {
+			config = DefaultTSConfig
+		}
+		sources := make([]string, len(field.TSVectorSources))
+		for i, col := range field.TSVectorSources {
+			sources[i] = fmt.Sprintf("coalesce(%s, '')", strings.TrimSpace(col))
+		}
+
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s tsvector GENERATED ALWAYS AS (to_tsvector('%s', %s)) STORED",
+				table, field.Column, config, strings.Join(sources, " || ' ' || ")),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s USING GIN (%s)",
+				metadata.TableName, field.Column, table, field.Column))
+	}
+	return statements
+}
+
+// MigrateFullText adds T's tsvector columns and their indexes with FullTextDDL
+func MigrateFullText[T any](ctx context.Context, db *sql.DB) error {
+	metadata := GetMetadata[T]()
+	if metadata == nil {
+		var model T
+		return fmt.Errorf("no orm metadata registered for %T", model)
+	}
+	for _, statement := range FullTextDDL(metadata, "") {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migrate full text search of %s: %w", metadata.TableName, err)
+		}
+	}
+	return nil
+}
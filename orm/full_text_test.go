@@ -0,0 +1,56 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fullTextNode struct {
+	ID           uuid.UUID `orm:"column:id;pk"`
+	Title        string    `orm:"column:title"`
+	Body         string    `orm:"column:body"`
+	SearchVector string    `orm:"column:search_vector;tsvector:title,body;tsconfig:simple"`
+}
+
+func (fullTextNode) TableName() string {
+	return "test_full_text_nodes"
+}
+
+func TestFullTextDDL(t *testing.T) {
+	RegisterModel[fullTextNode]()
+	metadata := GetMetadata[fullTextNode]()
+
+	assert.Equal(t, []string{
+		"ALTER TABLE test_full_text_nodes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS " +
+			"(to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(body, ''))) STORED",
+		"CREATE INDEX IF NOT EXISTS idx_test_full_text_nodes_search_vector ON test_full_text_nodes USING GIN (search_vector)",
+	}, FullTextDDL(metadata, ""))
+	assert.NotContains(t, metadata.SQLTemplates.Insert, "search_vector", "the generated column is never written")
+}
+
+func TestMigrateFullText(t *testing.T) {
+	_, err := scannerTestDB.Exec(`CREATE TABLE IF NOT EXISTS test_full_text_nodes (
+		id UUID PRIMARY KEY,
+		title TEXT NOT NULL,
+		body TEXT
+	)`)
+	require.NoError(t, err)
+	RegisterModel[fullTextNode]()
+
+	ctx := context.Background()
+	require.NoError(t, MigrateFullText[fullTextNode](ctx, scannerTestDB))
+	require.NoError(t, MigrateFullText[fullTextNode](ctx, scannerTestDB), "migrating twice is a no-op")
+
+	db := NewDB[fullTextNode](scannerTestDB)
+	require.NoError(t, db.Create(ctx, &fullTextNode{ID: uuid.New(), Title: "Quarterly report", Body: "revenue grew"}))
+	require.NoError(t, db.Create(ctx, &fullTextNode{ID: uuid.New(), Title: "Team offsite", Body: "agenda"}))
+
+	found, err := db.FindByQuery(ctx, "SELECT * FROM test_full_text_nodes WHERE search_vector @@ plainto_tsquery('simple', $1)", "revenue report")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Quarterly report", found[0].Title)
+}
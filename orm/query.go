@@ -3,61 +3,80 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 )
 
 // Query provides simple helpers for raw SQL queries
 // Leverages RawScanner for flexible type handling
+// Every statement runs under Ctx: when it is cancelled or its deadline passes, the running statement
+// is cancelled and the error wraps context.Canceled or context.DeadlineExceeded
 type Query struct {
 	Ctx     context.Context
 	Txn     *sql.Tx
 	Scanner *RawScanner
+
+	afterCommit []func()
 }
 
 // Count executes a COUNT query and returns the integer result
 // Example: count, err := q.Count("SELECT COUNT(*) FROM users WHERE active = $1", true)
 func (q *Query) Count(query string, args ...interface{}) (int, error) {
+	start := time.Now()
 	var count int
-	err := q.Txn.QueryRowContext(q.Ctx, query, args...).Scan(&count)
-	return count, err
+	err := q.Txn.QueryRowContext(q.ctx(), query, args...).Scan(&count)
+	q.explainIfSlow(start, err, query, args)
+	return count, q.wrapErr(err)
 }
 
 // Exists checks if a query returns any rows
 // Wraps the query in SELECT EXISTS(...) for efficiency
 func (q *Query) Exists(query string, args ...interface{}) (bool, error) {
+	start := time.Now()
 	var exists bool
 	checkQuery := fmt.Sprintf("SELECT EXISTS(%s)", query)
-	err := q.Txn.QueryRowContext(q.Ctx, checkQuery, args...).Scan(&exists)
-	return exists, err
+	err := q.Txn.QueryRowContext(q.ctx(), checkQuery, args...).Scan(&exists)
+	q.explainIfSlow(start, err, checkQuery, args)
+	return exists, q.wrapErr(err)
 }
 
 // QueryRow executes a query expecting a single row and scans into dest
 // Uses RawScanner for flexible destination types (struct, slice, map, primitive)
 // Returns sql.ErrNoRows if no rows found
 func (q *Query) QueryRow(query string, dest interface{}, args ...interface{}) error {
-	rows, err := q.Txn.QueryContext(q.Ctx, query, args...)
+	start := time.Now()
+	rows, err := q.Txn.QueryContext(q.ctx(), query, args...)
 	if err != nil {
-		return err
+		return q.wrapErr(err)
 	}
-	defer rows.Close()
-	return q.Scanner.ScanRow(rows, dest)
+	err = q.Scanner.ScanRow(rows, dest)
+	rows.Close()
+	q.explainIfSlow(start, err, query, args)
+	return q.wrapErr(err)
 }
 
 // QueryRows executes a query expecting multiple rows and scans into dest slice
 // Uses RawScanner for flexible destination types
 // dest must be a pointer to a slice
 func (q *Query) QueryRows(query string, dest interface{}, args ...interface{}) error {
-	rows, err := q.Txn.QueryContext(q.Ctx, query, args...)
+	start := time.Now()
+	rows, err := q.Txn.QueryContext(q.ctx(), query, args...)
 	if err != nil {
-		return err
+		return q.wrapErr(err)
 	}
-	defer rows.Close()
-	return q.Scanner.ScanRaw(rows, dest)
+	err = q.Scanner.ScanRaw(rows, dest)
+	rows.Close()
+	q.explainIfSlow(start, err, query, args)
+	return q.wrapErr(err)
 }
 
 // Exec executes a command (INSERT/UPDATE/DELETE) and returns the result
 func (q *Query) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return q.Txn.ExecContext(q.Ctx, query, args...)
+	start := time.Now()
+	result, err := q.Txn.ExecContext(q.ctx(), query, args...)
+	q.explainIfSlow(start, err, query, args)
+	return result, q.wrapErr(err)
 }
 
 // Tx returns the underlying sql.Tx for advanced use cases
@@ -66,24 +85,64 @@ func (q *Query) Tx() *sql.Tx {
 	return q.Txn
 }
 
-// Commit commits the transaction
+// OnCommit registers fn to run after the transaction commits successfully
+// Hooks run in registration order and are discarded on rollback
+func (q *Query) OnCommit(fn func()) {
+	q.afterCommit = append(q.afterCommit, fn)
+}
+
+// Commit commits the transaction and runs OnCommit hooks
 func (q *Query) Commit() error {
-	return q.Txn.Commit()
+	hooks := q.afterCommit
+	q.afterCommit = nil
+
+	if err := q.Txn.Commit(); err != nil {
+		return err
+	}
+
+	for _, fn := range hooks {
+		fn()
+	}
+	return nil
 }
 
-// Rollback rolls back the transaction
+// Rollback rolls back the transaction, discarding OnCommit hooks
 func (q *Query) Rollback() error {
+	q.afterCommit = nil
 	return q.Txn.Rollback()
 }
 
 // Query executes a query that returns rows (for manual iteration)
 // Returns *sql.Rows for custom scanning logic
 func (q *Query) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	return q.Txn.QueryContext(q.Ctx, query, args...)
+	rows, err := q.Txn.QueryContext(q.ctx(), query, args...)
+	return rows, q.wrapErr(err)
 }
 
 // QueryRowRaw executes a query expecting a single row
 // Returns *sql.Row for manual Scan() - use for simple cases
+// Scan returns the driver's error on cancellation; check Ctx.Err() to tell it apart
 func (q *Query) QueryRowRaw(query string, args ...interface{}) *sql.Row {
-	return q.Txn.QueryRowContext(q.Ctx, query, args...)
+	return q.Txn.QueryRowContext(q.ctx(), query, args...)
+}
+
+// ctx returns the context statements run under, Background when none was set
+func (q *Query) ctx() context.Context {
+	if q.Ctx == nil {
+		return context.Background()
+	}
+	return q.Ctx
+}
+
+// wrapErr adds the context's error to a statement error caused by cancellation, as drivers report
+// the cancelled statement in their own terms, so callers can match context.DeadlineExceeded
+func (q *Query) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	ctxErr := q.ctx().Err()
+	if ctxErr == nil || errors.Is(err, ctxErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ctxErr, err)
 }
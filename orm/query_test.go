@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_StatementCancelledOnDeadline(t *testing.T) {
+	query := beginTxn(t)
+	defer query.Rollback()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	query.Ctx = ctx
+
+	start := time.Now()
+	_, err := query.Exec("SELECT pg_sleep(5)")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second, "the statement stops at the deadline")
+}
+
+func TestQuery_MethodsHonorCancelledContext(t *testing.T) {
+	query := beginTxn(t)
+	defer query.Rollback()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	query.Ctx = ctx
+
+	_, err := query.Count("SELECT COUNT(*) FROM test_nodes")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = query.Exists("SELECT 1 FROM test_nodes")
+	assert.ErrorIs(t, err, context.Canceled)
+	var names []string
+	assert.ErrorIs(t, query.QueryRows("SELECT name FROM test_nodes", &names), context.Canceled)
+	var name string
+	assert.ErrorIs(t, query.QueryRow("SELECT name FROM test_nodes LIMIT 1", &name), context.Canceled)
+	_, err = query.Query("SELECT name FROM test_nodes")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = query.Exec("DELETE FROM test_nodes")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQuery_NilContextRuns(t *testing.T) {
+	query := beginTxn(t)
+	defer query.Rollback()
+	query.Ctx = nil
+
+	count, err := query.Count("SELECT 1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -23,6 +23,9 @@ type ModelMetadata struct {
 	IDColumn      string
 	IDType        reflect.Type
 	SetID         func(entity interface{}, id interface{})
+
+	// templates caches SQLTemplates per runtime schema; see Templates
+	templates sync.Map
 }
 
 type FieldMetadata struct {
@@ -35,6 +38,9 @@ type FieldMetadata struct {
 	IsUnique        bool
 	IsNullable      bool
 	IsAutoIncrement bool
+	IsReadOnly      bool
+	TSVectorSources []string
+	TSConfig        string
 	Default         string
 	Index           int
 }
@@ -264,11 +264,14 @@ func (r *RawScanner) scanMapRow(rows *sql.Rows, destValue reflect.Value) error {
 }
 
 // findFieldByName finds a struct field by column name (case insensitive)
+// Fields promoted from embedded structs are matched too; the returned Index is the full path
 func (r *RawScanner) findFieldByName(t reflect.Type, name string) *reflect.StructField {
 	name = strings.ToLower(name)
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
 
 		// Check field name
 		if strings.ToLower(field.Name) == name {
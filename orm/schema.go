@@ -0,0 +1,56 @@
+package orm
+
+import "sync/atomic"
+
+var defaultSchema atomic.Value
+
+// SetDefaultSchema qualifies the generated SQL of every model whose TableName names no schema, so one
+// binary can target a per-environment schema. An empty schema restores unqualified names, which resolve
+// through the connection's search_path.
+func SetDefaultSchema(schema string) {
+	defaultSchema.Store(schema)
+}
+
+// DefaultSchema returns the schema set with SetDefaultSchema
+func DefaultSchema() string {
+	schema, _ := defaultSchema.Load().(string)
+	return schema
+}
+
+// ResolveSchema returns the schema the model's statements run against when the caller asks for schema.
+// A schema named in TableName always wins; otherwise schema, then the default schema, applies. An
+// empty result leaves the table unqualified.
+func (m *ModelMetadata) ResolveSchema(schema string) string {
+	if m.Schema != "" && m.Schema != "public" {
+		return m.Schema
+	}
+	if schema != "" {
+		return schema
+	}
+	return DefaultSchema()
+}
+
+// QualifiedTable returns the table name prefixed with the schema resolved for schema
+func (m *ModelMetadata) QualifiedTable(schema string) string {
+	return m.Templates(schema).TableName
+}
+
+// Templates returns the model's statements qualified with the schema resolved for schema. They are
+// built once per schema.
+func (m *ModelMetadata) Templates(schema string) SQLTemplates {
+	resolved := m.ResolveSchema(schema)
+	if resolved == "" || resolved == m.Schema {
+		return m.SQLTemplates
+	}
+	if cached, ok := m.templates.Load(resolved); ok {
+		return cached.(SQLTemplates)
+	}
+
+	columnNames := make([]string, len(m.Fields))
+	for i, field := range m.Fields {
+		columnNames[i] = field.Column
+	}
+	templates := buildSQLTemplates(resolved, m.TableName, insertColumns(m), columnNames, m.IDColumn)
+	cached, _ := m.templates.LoadOrStore(resolved, templates)
+	return cached.(SQLTemplates)
+}
@@ -0,0 +1,62 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaNode struct {
+	ID   uuid.UUID `orm:"column:id;pk"`
+	Name string    `orm:"column:name"`
+}
+
+func (schemaNode) TableName() string {
+	return "schema_nodes"
+}
+
+type pinnedSchemaNode struct {
+	ID uuid.UUID `orm:"column:id;pk"`
+}
+
+func (pinnedSchemaNode) TableName() string {
+	return "audit.pinned_nodes"
+}
+
+func TestTemplates_QualifyWithRuntimeSchema(t *testing.T) {
+	RegisterModel[schemaNode]()
+	metadata := GetMetadata[schemaNode]()
+
+	assert.Equal(t, "schema_nodes", metadata.QualifiedTable(""))
+	assert.Equal(t, "INSERT INTO tenant_acme.schema_nodes (id,name) VALUES ($1,$2)", metadata.Templates("tenant_acme").Insert)
+	assert.Equal(t, "DELETE FROM tenant_acme.schema_nodes WHERE id=$1", metadata.Templates("tenant_acme").Delete)
+	assert.Equal(t, "INSERT INTO schema_nodes (id,name) VALUES ($1,$2)", metadata.SQLTemplates.Insert, "registered templates are unchanged")
+
+	SetDefaultSchema("staging")
+	defer SetDefaultSchema("")
+	assert.Equal(t, "staging.schema_nodes", metadata.QualifiedTable(""))
+	assert.Equal(t, "tenant_acme.schema_nodes", metadata.QualifiedTable("tenant_acme"), "an explicit schema beats the default")
+	assert.Contains(t, buildSelectByColumnsSQL(metadata, metadata.Templates(""), []string{"name"}), "FROM staging.schema_nodes WHERE")
+	assert.Contains(t, buildUpsertMultipleSQL(metadata, metadata.Templates(""), 1, []string{"id"}, nil, "EXCLUDED.name <> schema_nodes.name"),
+		"INSERT INTO staging.schema_nodes")
+}
+
+func TestTemplates_DeclaredSchemaWins(t *testing.T) {
+	RegisterModel[pinnedSchemaNode]()
+	metadata := GetMetadata[pinnedSchemaNode]()
+
+	SetDefaultSchema("staging")
+	defer SetDefaultSchema("")
+	assert.Equal(t, "audit.pinned_nodes", metadata.QualifiedTable(""))
+	assert.Equal(t, "audit.pinned_nodes", metadata.QualifiedTable("tenant_acme"))
+}
+
+func TestWithSchema_ReturnsCopy(t *testing.T) {
+	RegisterModel[schemaNode]()
+	tx := NewTransaction[schemaNode]()
+
+	scoped := tx.WithSchema("tenant_acme")
+	assert.Equal(t, "tenant_acme.schema_nodes", scoped.sql().TableName)
+	assert.Equal(t, "schema_nodes", tx.sql().TableName)
+}
@@ -16,9 +16,10 @@ func buildSQLTemplates(schema, tableName string, insertColumns, columnNames []st
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
 	}
 
+	// Only written columns are updated, so auto-increment and read-only columns keep their values
 	updatePairs := make([]string, 0)
 	updateIdx := 2
-	for _, col := range columnNames {
+	for _, col := range insertColumns {
 		if col != pkColumn && col != "created_at" {
 			updatePairs = append(updatePairs, fmt.Sprintf("%s=$%d", col, updateIdx))
 			updateIdx++
@@ -93,3 +94,124 @@ func buildBatchInsertFunc(tableName string, columns []string) func(int) string {
 		)
 	}
 }
+
+// buildUpsertSQL builds a single-row INSERT ... ON CONFLICT statement returning every column.
+// updateColumns nil overwrites every column but the conflict columns and created_at; an empty, non-nil
+// slice does nothing on conflict. where, when set, guards the update. A conflicting row that isn't
+// updated isn't returned.
+func buildUpsertSQL(metadata *ModelMetadata, templates SQLTemplates, conflictColumns, updateColumns []string, where string) string {
+	returnColumns := make([]string, 0, len(metadata.Fields))
+	for _, field := range metadata.Fields {
+		returnColumns = append(returnColumns, field.Column)
+	}
+
+	if updateColumns != nil && len(updateColumns) == 0 {
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING RETURNING %s",
+			templates.Insert,
+			strings.Join(conflictColumns, ","),
+			strings.Join(returnColumns, ","))
+	}
+
+	if updateColumns == nil {
+		for _, col := range insertColumns(metadata) {
+			if col != "created_at" && !containsColumn(conflictColumns, col) {
+				updateColumns = append(updateColumns, col)
+			}
+		}
+	}
+	updatePairs := make([]string, len(updateColumns))
+	for i, col := range updateColumns {
+		updatePairs[i] = fmt.Sprintf("%s=EXCLUDED.%s", col, col)
+	}
+
+	upsertSQL := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s",
+		templates.Insert,
+		strings.Join(conflictColumns, ","),
+		strings.Join(updatePairs, ","))
+	if where != "" {
+		upsertSQL += " WHERE " + where
+	}
+	return upsertSQL + " RETURNING " + strings.Join(returnColumns, ",")
+}
+
+// buildSelectByColumnsSQL selects every column of the rows matching columns, bound to $1, $2, ...
+func buildSelectByColumnsSQL(metadata *ModelMetadata, templates SQLTemplates, columns []string) string {
+	returnColumns := make([]string, 0, len(metadata.Fields))
+	for _, field := range metadata.Fields {
+		returnColumns = append(returnColumns, field.Column)
+	}
+	conditions := make([]string, len(columns))
+	for i, col := range columns {
+		conditions[i] = fmt.Sprintf("%s = $%d", col, i+1)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		strings.Join(returnColumns, ","),
+		templates.TableName,
+		strings.Join(conditions, " AND "))
+}
+
+// buildUpsertMultipleSQL builds one multi-row INSERT ... ON CONFLICT statement returning every column and
+// whether each row was inserted. updateColumns limits the columns overwritten on conflict; an empty,
+// non-nil slice keeps existing rows unchanged while still returning them. where, when set, decides per
+// row whether the columns take the new values; rejected rows keep theirs but are still returned.
+func buildUpsertMultipleSQL(metadata *ModelMetadata, templates SQLTemplates, count int, conflictColumns, updateColumns []string, where string) string {
+	var updatePairs []string
+	switch {
+	case updateColumns == nil:
+		for _, col := range insertColumns(metadata) {
+			// The stored row keeps its primary key; RETURNING copies it back onto the entity
+			if col != "created_at" && col != metadata.IDColumn && !containsColumn(conflictColumns, col) {
+				updatePairs = append(updatePairs, upsertAssignment(metadata.TableName, col, where))
+			}
+		}
+	default:
+		for _, col := range updateColumns {
+			updatePairs = append(updatePairs, upsertAssignment(metadata.TableName, col, where))
+		}
+	}
+	if len(updatePairs) == 0 {
+		// DO NOTHING would return no row for existing entities; a no-op update returns them unchanged
+		updatePairs = append(updatePairs, fmt.Sprintf("%s=EXCLUDED.%s", conflictColumns[0], conflictColumns[0]))
+	}
+
+	returnColumns := make([]string, 0, len(metadata.Fields)+1)
+	for _, field := range metadata.Fields {
+		returnColumns = append(returnColumns, field.Column)
+	}
+	returnColumns = append(returnColumns, "(xmax = 0)")
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s RETURNING %s",
+		templates.BatchInsert(count),
+		strings.Join(conflictColumns, ","),
+		strings.Join(updatePairs, ","),
+		strings.Join(returnColumns, ","))
+}
+
+// upsertAssignment sets col to its new value, or when where is set only for rows where it holds; a
+// WHERE on DO UPDATE would drop the other rows from RETURNING. table is the unqualified name, which is
+// how Postgres refers to the existing row even when the INSERT names a schema.
+func upsertAssignment(table, col, where string) string {
+	if where == "" {
+		return fmt.Sprintf("%s=EXCLUDED.%s", col, col)
+	}
+	return fmt.Sprintf("%s=CASE WHEN (%s) THEN EXCLUDED.%s ELSE %s.%s END", col, where, col, table, col)
+}
+
+// insertColumns returns the columns of the model's INSERT statement
+func insertColumns(metadata *ModelMetadata) []string {
+	insertSQL := metadata.SQLTemplates.Insert
+	columns := strings.Split(insertSQL[strings.Index(insertSQL, "(")+1:strings.Index(insertSQL, ")")], ",")
+	for i, col := range columns {
+		columns[i] = strings.TrimSpace(col)
+	}
+	return columns
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -10,6 +11,7 @@ import (
 // Transaction[T] is now stateless - it's just a helper with metadata
 type Transaction[T any] struct {
 	metadata *ModelMetadata
+	schema   string
 }
 
 func NewTransaction[T any]() *Transaction[T] {
@@ -18,12 +20,24 @@ func NewTransaction[T any]() *Transaction[T] {
 	}
 }
 
+// WithSchema returns a copy whose statements are qualified with schema, unless the model's TableName
+// names its own. An empty schema falls back to the default schema.
+func (t *Transaction[T]) WithSchema(schema string) *Transaction[T] {
+	scoped := *t
+	scoped.schema = schema
+	return &scoped
+}
+
+func (t *Transaction[T]) sql() SQLTemplates {
+	return t.metadata.Templates(t.schema)
+}
+
 func (t *Transaction[T]) Create(query *Query, entity *T) error {
 	if query == nil {
 		return fmt.Errorf("no transaction in request")
 	}
 	values := t.metadata.ExtractValues(entity)
-	_, err := query.Exec(t.metadata.SQLTemplates.Insert, values...)
+	_, err := query.Exec(t.sql().Insert, values...)
 	return err
 }
 
@@ -31,19 +45,11 @@ func (t *Transaction[T]) Update(query *Query, entity *T) error {
 	if query == nil {
 		return fmt.Errorf("no transaction in request")
 	}
-	values := t.metadata.ExtractValues(entity)
 	id := t.metadata.ExtractID(entity)
+	updateValues := updateArgs(t.metadata, entity, id)
 
-	pkColumn := t.metadata.IDColumn
-	updateValues := []interface{}{id}
-	for i, field := range t.metadata.Fields {
-		if field.Column != pkColumn && field.Column != "created_at" {
-			updateValues = append(updateValues, values[i])
-		}
-	}
-
-	_, err := query.Exec(t.metadata.SQLTemplates.Update, updateValues...)
-	return err
+	result, err := query.Exec(t.sql().Update, updateValues...)
+	return checkAffected(result, err, "update", t.metadata.TableName, id, 1)
 }
 
 func (t *Transaction[T]) Delete(query *Query, entity *T) error {
@@ -51,8 +57,8 @@ func (t *Transaction[T]) Delete(query *Query, entity *T) error {
 		return fmt.Errorf("no transaction in request")
 	}
 	id := t.metadata.ExtractID(entity)
-	_, err := query.Exec(t.metadata.SQLTemplates.Delete, id)
-	return err
+	result, err := query.Exec(t.sql().Delete, id)
+	return checkAffected(result, err, "delete", t.metadata.TableName, id, 1)
 }
 
 func (t *Transaction[T]) CreateMultiple(query *Query, entities []*T) error {
@@ -70,7 +76,7 @@ func (t *Transaction[T]) CreateMultiple(query *Query, entities []*T) error {
 		allValues = append(allValues, values...)
 	}
 
-	batchSQL := t.metadata.SQLTemplates.BatchInsert(len(entities))
+	batchSQL := t.sql().BatchInsert(len(entities))
 
 	if t.metadata.IDColumn != "" {
 		batchSQL += fmt.Sprintf(" RETURNING %s", t.metadata.IDColumn)
@@ -127,20 +133,21 @@ func (t *Transaction[T]) DeleteMultiple(query *Query, entities []*T) error {
 	}
 
 	deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)",
-		t.metadata.TableName,
+		t.sql().TableName,
 		t.metadata.IDColumn,
 		strings.Join(placeholders, ","))
 
-	_, err := query.Exec(deleteSQL, ids...)
-	return err
+	result, err := query.Exec(deleteSQL, ids...)
+	return checkAffected(result, err, "delete", t.metadata.TableName, nil, distinctCount(ids))
 }
 
+// FindByPK scans the row with primary key pk into dest, returning a *NotFoundError when there is none
 func (t *Transaction[T]) FindByPK(query *Query, dest *T, pk interface{}) error {
 	if query == nil {
 		return fmt.Errorf("no transaction in request")
 	}
-	row := query.QueryRowRaw(t.metadata.SQLTemplates.SelectByPK, pk)
-	return t.metadata.ScanRow(row, dest)
+	row := query.QueryRowRaw(t.sql().SelectByPK, pk)
+	return notFound(t.metadata.ScanRow(row, dest), t.metadata.TableName, pk)
 }
 
 func (t *Transaction[T]) FindByQuery(query *Query, querySQL string, args ...interface{}) ([]*T, error) {
@@ -169,7 +176,7 @@ func (t *Transaction[T]) FindAll(query *Query) ([]*T, error) {
 	if query == nil {
 		return nil, fmt.Errorf("no transaction in request")
 	}
-	rows, err := query.Query(t.metadata.SQLTemplates.SelectAll)
+	rows, err := query.Query(t.sql().SelectAll)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +193,11 @@ func (t *Transaction[T]) FindAll(query *Query) ([]*T, error) {
 	return results, rows.Err()
 }
 
-func (t *Transaction[T]) Upsert(query *Query, entity *T, conflictColumns []string) error {
+// Upsert inserts entity, or on a conflict on conflictColumns updates the existing row, and scans the
+// stored row back into entity. Without conflict columns it is a plain Create. When the model's or
+// opts' UpdateColumns is empty, or the Where predicate rejects the update, the existing row is left
+// unchanged and scanned back instead.
+func (t *Transaction[T]) Upsert(query *Query, entity *T, conflictColumns []string, opts ...UpsertOptions) error {
 	if len(conflictColumns) == 0 {
 		return t.Create(query, entity)
 	}
@@ -194,90 +205,30 @@ func (t *Transaction[T]) Upsert(query *Query, entity *T, conflictColumns []strin
 	if query == nil {
 		return fmt.Errorf("no transaction in request")
 	}
+	return upsertOne(t.metadata, t.sql(), entity, conflictColumns, opts, query.QueryRowRaw)
+}
 
-	values := t.metadata.ExtractValues(entity)
-
-	var returnCols []string
-	for _, field := range t.metadata.Fields {
-		returnCols = append(returnCols, field.Column)
+// UpsertMultiple inserts entities in one statement, updating rows that conflict on conflictColumns, and
+// scans the stored rows back into entities. The returned slice reports, per entity, whether it was
+// inserted. Entities must not share conflict values, which Postgres rejects within one statement.
+func (t *Transaction[T]) UpsertMultiple(query *Query, entities []*T, conflictColumns []string, opts ...UpsertOptions) ([]bool, error) {
+	if len(entities) == 0 {
+		return nil, nil
 	}
-
-	var explicitUpdateCols []string
-	if provider, ok := any(*entity).(interface{ UpdateColumns() []string }); ok {
-		explicitUpdateCols = provider.UpdateColumns()
+	if query == nil {
+		return nil, fmt.Errorf("no transaction in request")
 	}
-
-	if explicitUpdateCols != nil && len(explicitUpdateCols) == 0 {
-		upsertSQL := fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING RETURNING %s",
-			t.metadata.SQLTemplates.Insert,
-			strings.Join(conflictColumns, ","),
-			strings.Join(returnCols, ","))
-
-		row := query.QueryRowRaw(upsertSQL, values...)
-		err := t.metadata.ScanRow(row, entity)
-		if err == nil {
-			return nil
-		}
-		if err == sql.ErrNoRows {
-			whereConditions := make([]string, len(conflictColumns))
-			for i, col := range conflictColumns {
-				whereConditions[i] = fmt.Sprintf("%s = $%d", col, i+1)
-			}
-			selectSQL := fmt.Sprintf("SELECT %s FROM %s WHERE %s",
-				strings.Join(returnCols, ","),
-				t.metadata.TableName,
-				strings.Join(whereConditions, " AND "))
-
-			conflictValues := make([]interface{}, len(conflictColumns))
-			for i, col := range conflictColumns {
-				for j, field := range t.metadata.Fields {
-					if field.Column == col {
-						conflictValues[i] = values[j]
-						break
-					}
-				}
-			}
-
-			row = query.QueryRowRaw(selectSQL, conflictValues...)
-			return t.metadata.ScanRow(row, entity)
-		}
-		return err
+	if len(conflictColumns) == 0 {
+		return nil, fmt.Errorf("upsert of %s needs conflict columns", t.metadata.TableName)
 	}
 
-	var updateCols []string
-	if len(explicitUpdateCols) > 0 {
-		for _, col := range explicitUpdateCols {
-			updateCols = append(updateCols, fmt.Sprintf("%s=EXCLUDED.%s", col, col))
-		}
-	} else {
-		insertSQL := t.metadata.SQLTemplates.Insert
-		startIdx := strings.Index(insertSQL, "(")
-		endIdx := strings.Index(insertSQL, ")")
-		cols := strings.Split(insertSQL[startIdx+1:endIdx], ",")
-
-		for _, col := range cols {
-			colName := strings.TrimSpace(col)
-			isConflict := false
-			for _, cc := range conflictColumns {
-				if colName == cc {
-					isConflict = true
-					break
-				}
-			}
-			if !isConflict && colName != "created_at" {
-				updateCols = append(updateCols, fmt.Sprintf("%s=EXCLUDED.%s", colName, colName))
-			}
-		}
+	upsertSQL, args := upsertMultipleArgs(t.metadata, t.sql(), entities, conflictColumns, opts)
+	rows, err := query.Query(upsertSQL, args...)
+	if err != nil {
+		return nil, err
 	}
-
-	upsertSQL := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s RETURNING %s",
-		t.metadata.SQLTemplates.Insert,
-		strings.Join(conflictColumns, ","),
-		strings.Join(updateCols, ","),
-		strings.Join(returnCols, ","))
-
-	row := query.QueryRowRaw(upsertSQL, values...)
-	return t.metadata.ScanRow(row, entity)
+	defer rows.Close()
+	return scanUpserted(t.metadata, rows, entities)
 }
 
 // Commit commits the transaction
@@ -299,6 +250,7 @@ func (t *Transaction[T]) Rollback(query *Query) error {
 type DB[T any] struct {
 	db       *sql.DB
 	metadata *ModelMetadata
+	schema   string
 }
 
 func NewDB[T any](db *sql.DB) *DB[T] {
@@ -311,32 +263,36 @@ func NewDB[T any](db *sql.DB) *DB[T] {
 	}
 }
 
+// WithSchema returns a copy whose statements are qualified with schema, unless the model's TableName
+// names its own. An empty schema falls back to the default schema.
+func (d *DB[T]) WithSchema(schema string) *DB[T] {
+	scoped := *d
+	scoped.schema = schema
+	return &scoped
+}
+
+func (d *DB[T]) sql() SQLTemplates {
+	return d.metadata.Templates(d.schema)
+}
+
 func (d *DB[T]) Create(ctx context.Context, entity *T) error {
 	values := d.metadata.ExtractValues(entity)
-	_, err := d.db.ExecContext(ctx, d.metadata.SQLTemplates.Insert, values...)
+	_, err := d.db.ExecContext(ctx, d.sql().Insert, values...)
 	return err
 }
 
 func (d *DB[T]) Update(ctx context.Context, entity *T) error {
-	values := d.metadata.ExtractValues(entity)
 	id := d.metadata.ExtractID(entity)
+	updateValues := updateArgs(d.metadata, entity, id)
 
-	pkColumn := d.metadata.IDColumn
-	updateValues := []interface{}{id}
-	for i, field := range d.metadata.Fields {
-		if field.Column != pkColumn && field.Column != "created_at" {
-			updateValues = append(updateValues, values[i])
-		}
-	}
-
-	_, err := d.db.ExecContext(ctx, d.metadata.SQLTemplates.Update, updateValues...)
-	return err
+	result, err := d.db.ExecContext(ctx, d.sql().Update, updateValues...)
+	return checkAffected(result, err, "update", d.metadata.TableName, id, 1)
 }
 
 func (d *DB[T]) Delete(ctx context.Context, entity *T) error {
 	id := d.metadata.ExtractID(entity)
-	_, err := d.db.ExecContext(ctx, d.metadata.SQLTemplates.Delete, id)
-	return err
+	result, err := d.db.ExecContext(ctx, d.sql().Delete, id)
+	return checkAffected(result, err, "delete", d.metadata.TableName, id, 1)
 }
 
 func (d *DB[T]) CreateMultiple(ctx context.Context, entities []*T) error {
@@ -350,7 +306,7 @@ func (d *DB[T]) CreateMultiple(ctx context.Context, entities []*T) error {
 		allValues = append(allValues, values...)
 	}
 
-	batchSQL := d.metadata.SQLTemplates.BatchInsert(len(entities))
+	batchSQL := d.sql().BatchInsert(len(entities))
 
 	// For tables with auto-generated IDs, we need to get them back
 	if d.metadata.IDColumn != "" {
@@ -404,17 +360,18 @@ func (d *DB[T]) DeleteMultiple(ctx context.Context, entities []*T) error {
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)",
-		d.metadata.TableName,
+		d.sql().TableName,
 		d.metadata.IDColumn,
 		strings.Join(placeholders, ","))
 
-	_, err := d.db.ExecContext(ctx, query, ids...)
-	return err
+	result, err := d.db.ExecContext(ctx, query, ids...)
+	return checkAffected(result, err, "delete", d.metadata.TableName, nil, distinctCount(ids))
 }
 
+// FindByPK scans the row with primary key pk into dest, returning a *NotFoundError when there is none
 func (d *DB[T]) FindByPK(ctx context.Context, dest *T, pk interface{}) error {
-	row := d.db.QueryRowContext(ctx, d.metadata.SQLTemplates.SelectByPK, pk)
-	return d.metadata.ScanRow(row, dest)
+	row := d.db.QueryRowContext(ctx, d.sql().SelectByPK, pk)
+	return notFound(d.metadata.ScanRow(row, dest), d.metadata.TableName, pk)
 }
 
 func (d *DB[T]) FindByQuery(ctx context.Context, querySQL string, args ...interface{}) ([]*T, error) {
@@ -435,8 +392,15 @@ func (d *DB[T]) FindByQuery(ctx context.Context, querySQL string, args ...interf
 	return results, rows.Err()
 }
 
+// Count executes a COUNT query and returns the integer result
+func (d *DB[T]) Count(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var count int
+	err := d.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
 func (d *DB[T]) FindAll(ctx context.Context) ([]*T, error) {
-	rows, err := d.db.QueryContext(ctx, d.metadata.SQLTemplates.SelectAll)
+	rows, err := d.db.QueryContext(ctx, d.sql().SelectAll)
 	if err != nil {
 		return nil, err
 	}
@@ -453,44 +417,111 @@ func (d *DB[T]) FindAll(ctx context.Context) ([]*T, error) {
 	return results, rows.Err()
 }
 
-func (d *DB[T]) Upsert(ctx context.Context, entity *T, conflictColumns []string) error {
+// Upsert is the non-transactional form of Transaction.Upsert
+func (d *DB[T]) Upsert(ctx context.Context, entity *T, conflictColumns []string, opts ...UpsertOptions) error {
 	if len(conflictColumns) == 0 {
 		return d.Create(ctx, entity)
 	}
+	return upsertOne(d.metadata, d.sql(), entity, conflictColumns, opts, func(query string, args ...interface{}) *sql.Row {
+		return d.db.QueryRowContext(ctx, query, args...)
+	})
+}
 
-	values := d.metadata.ExtractValues(entity)
+// UpsertMultiple is the non-transactional form of Transaction.UpsertMultiple
+func (d *DB[T]) UpsertMultiple(ctx context.Context, entities []*T, conflictColumns []string, opts ...UpsertOptions) ([]bool, error) {
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	if len(conflictColumns) == 0 {
+		return nil, fmt.Errorf("upsert of %s needs conflict columns", d.metadata.TableName)
+	}
 
-	var updateCols []string
-	insertSQL := d.metadata.SQLTemplates.Insert
-	startIdx := strings.Index(insertSQL, "(")
-	endIdx := strings.Index(insertSQL, ")")
-	cols := strings.Split(insertSQL[startIdx+1:endIdx], ",")
-
-	for _, col := range cols {
-		colName := strings.TrimSpace(col)
-		isConflict := false
-		for _, cc := range conflictColumns {
-			if colName == cc {
-				isConflict = true
-				break
-			}
+	upsertSQL, args := upsertMultipleArgs(d.metadata, d.sql(), entities, conflictColumns, opts)
+	rows, err := d.db.QueryContext(ctx, upsertSQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUpserted(d.metadata, rows, entities)
+}
+
+// updateArgs returns the arguments of the Update template for entity: id, then the written columns
+// other than the primary key and created_at
+func updateArgs(metadata *ModelMetadata, entity interface{}, id interface{}) []interface{} {
+	values := metadata.ExtractValues(entity)
+	args := make([]interface{}, 1, len(values)+1)
+	args[0] = id
+	for i, col := range insertColumns(metadata) {
+		if col != metadata.IDColumn && col != "created_at" {
+			args = append(args, values[i])
+		}
+	}
+	return args
+}
+
+// UpsertOptions adjusts an upsert's conflict handling for one call
+type UpsertOptions struct {
+	// UpdateColumns overrides the model's UpdateColumns; an empty, non-nil slice does nothing on conflict
+	UpdateColumns []string
+	// Where guards the update of a conflicting row, such as "EXCLUDED.updated_at > events.updated_at";
+	// it overrides the model's UpsertWhere. The existing row is referred to by the table name.
+	Where string
+}
+
+// upsertConflict returns the columns to update and the predicate guarding the update, from opts or
+// else the model's UpdateColumns and UpsertWhere methods
+func upsertConflict(entity any, opts []UpsertOptions) (updateColumns []string, where string) {
+	if provider, ok := entity.(interface{ UpdateColumns() []string }); ok {
+		updateColumns = provider.UpdateColumns()
+	}
+	if provider, ok := entity.(interface{ UpsertWhere() string }); ok {
+		where = provider.UpsertWhere()
+	}
+	if len(opts) > 0 {
+		if opts[0].UpdateColumns != nil {
+			updateColumns = opts[0].UpdateColumns
 		}
-		if !isConflict && colName != "created_at" {
-			updateCols = append(updateCols, fmt.Sprintf("%s=EXCLUDED.%s", colName, colName))
+		if opts[0].Where != "" {
+			where = opts[0].Where
 		}
 	}
+	return updateColumns, where
+}
+
+// upsertOne runs the single-row upsert of entity with queryRow. When nothing was written, because the
+// upsert does nothing on conflict or Where rejected the update, the existing row is read back instead.
+func upsertOne[T any](metadata *ModelMetadata, templates SQLTemplates, entity *T, conflictColumns []string, opts []UpsertOptions,
+	queryRow func(query string, args ...interface{}) *sql.Row) error {
+	updateColumns, where := upsertConflict(*entity, opts)
+	values := metadata.ExtractValues(entity)
+
+	row := queryRow(buildUpsertSQL(metadata, templates, conflictColumns, updateColumns, where), values...)
+	err := metadata.ScanRow(row, entity)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
 
-	var returnCols []string
-	for _, field := range d.metadata.Fields {
-		returnCols = append(returnCols, field.Column)
+	conflictValues := make([]interface{}, len(conflictColumns))
+	for i, col := range conflictColumns {
+		for j, field := range metadata.Fields {
+			if field.Column == col {
+				conflictValues[i] = values[j]
+				break
+			}
+		}
 	}
+	row = queryRow(buildSelectByColumnsSQL(metadata, templates, conflictColumns), conflictValues...)
+	return metadata.ScanRow(row, entity)
+}
 
-	upsertSQL := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s RETURNING %s",
-		d.metadata.SQLTemplates.Insert,
-		strings.Join(conflictColumns, ","),
-		strings.Join(updateCols, ","),
-		strings.Join(returnCols, ","))
+// upsertMultipleArgs builds the upsert statement and its arguments, honouring the model's UpdateColumns
+// and UpsertWhere and opts
+func upsertMultipleArgs[T any](metadata *ModelMetadata, templates SQLTemplates, entities []*T, conflictColumns []string, opts []UpsertOptions) (string, []interface{}) {
+	updateColumns, where := upsertConflict(*entities[0], opts)
 
-	row := d.db.QueryRowContext(ctx, upsertSQL, values...)
-	return d.metadata.ScanRow(row, entity)
+	var args []interface{}
+	for _, entity := range entities {
+		args = append(args, metadata.ExtractValues(entity)...)
+	}
+	return buildUpsertMultipleSQL(metadata, templates, len(entities), conflictColumns, updateColumns, where), args
 }
@@ -913,3 +913,165 @@ func TestDB_FindByQuery(t *testing.T) {
 	assert.Equal(t, "purple", results[0].Settings.Theme)
 	assert.Equal(t, "orange", results[1].Settings.Theme)
 }
+
+func TestTransaction_UpsertMultiple(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+
+	existing := &TestNode{
+		ID:        uuid.New(),
+		Name:      "upsert-multi-existing",
+		Config:    TestConfig{Mode: "old"},
+		CreatedAt: time.Now().Truncate(time.Microsecond),
+		UpdatedAt: time.Now().Truncate(time.Microsecond),
+	}
+	query := beginTxn(t)
+	txn := NewTransaction[TestNode]()
+	require.NoError(t, txn.Create(query, existing))
+	require.NoError(t, query.Commit())
+
+	existing.Config = TestConfig{Mode: "updated"}
+	fresh := &TestNode{
+		ID:        uuid.New(),
+		Name:      "upsert-multi-new",
+		Config:    TestConfig{Mode: "new"},
+		CreatedAt: time.Now().Truncate(time.Microsecond),
+		UpdatedAt: time.Now().Truncate(time.Microsecond),
+	}
+
+	query2 := beginTxn(t)
+	inserted, err := txn.UpsertMultiple(query2, []*TestNode{existing, fresh}, []string{"name"})
+	require.NoError(t, err)
+	require.NoError(t, query2.Commit())
+	assert.Equal(t, []bool{false, true}, inserted)
+
+	var mode string
+	err = scannerTestDB.QueryRow("SELECT config->>'mode' FROM test_nodes WHERE name = $1", "upsert-multi-existing").Scan(&mode)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", mode)
+
+	var count int
+	err = scannerTestDB.QueryRow("SELECT COUNT(*) FROM test_nodes").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestDB_UpsertMultiple(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+
+	nodes := []*TestNode{
+		{ID: uuid.New(), Name: "db-upsert-a", Config: TestConfig{Mode: "a"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: uuid.New(), Name: "db-upsert-b", Config: TestConfig{Mode: "b"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+	db := NewDB[TestNode](scannerTestDB)
+
+	inserted, err := db.UpsertMultiple(context.Background(), nodes, []string{"name"})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, inserted)
+
+	nodes[1].Config = TestConfig{Mode: "b2"}
+	inserted, err = db.UpsertMultiple(context.Background(), nodes, []string{"name"})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, false}, inserted)
+	assert.Equal(t, "b2", nodes[1].Config.Mode)
+}
+
+func TestDB_Upsert_DoNothing(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+
+	db := NewDB[TestNode](scannerTestDB)
+	original := &TestNode{ID: uuid.New(), Name: "db-upsert-nothing", Config: TestConfig{Mode: "first"}, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, db.Upsert(context.Background(), original, []string{"name"}))
+
+	duplicate := &TestNode{ID: uuid.New(), Name: "db-upsert-nothing", Config: TestConfig{Mode: "second"}, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	err := db.Upsert(context.Background(), duplicate, []string{"name"}, UpsertOptions{UpdateColumns: []string{}})
+
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, duplicate.ID, "the existing row is scanned back")
+	assert.Equal(t, "first", duplicate.Config.Mode)
+}
+
+func TestUpsert_WherePredicate(t *testing.T) {
+	cleanupTestNodesForTxn(t)
+
+	older := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	newer := time.Now().Truncate(time.Microsecond)
+	stored := &TestNode{ID: uuid.New(), Name: "upsert-where", Config: TestConfig{Mode: "current"}, CreatedAt: newer, UpdatedAt: newer}
+	db := NewDB[TestNode](scannerTestDB)
+	require.NoError(t, db.Create(context.Background(), stored))
+	onlyNewer := UpsertOptions{Where: "EXCLUDED.updated_at > test_nodes.updated_at"}
+
+	stale := &TestNode{ID: stored.ID, Name: "upsert-where", Config: TestConfig{Mode: "stale"}, CreatedAt: older, UpdatedAt: older}
+	require.NoError(t, db.Upsert(context.Background(), stale, []string{"name"}, onlyNewer))
+	assert.Equal(t, "current", stale.Config.Mode, "a stale write keeps the stored row")
+
+	query := beginTxn(t)
+	defer query.Rollback()
+	inserted, err := NewTransaction[TestNode]().UpsertMultiple(query, []*TestNode{stale}, []string{"name"}, onlyNewer)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, inserted)
+	assert.Equal(t, "current", stale.Config.Mode)
+
+	fresh := &TestNode{ID: stored.ID, Name: "upsert-where", Config: TestConfig{Mode: "fresh"}, CreatedAt: newer, UpdatedAt: newer.Add(time.Minute)}
+	require.NoError(t, NewTransaction[TestNode]().Upsert(query, fresh, []string{"name"}, onlyNewer))
+	assert.Equal(t, "fresh", fresh.Config.Mode)
+}
+
+func TestBuildUpsertSQL(t *testing.T) {
+	metadata := GetMetadata[TestNode]()
+
+	doNothing := buildUpsertSQL(metadata, metadata.SQLTemplates, []string{"name"}, []string{}, "")
+	assert.Contains(t, doNothing, "ON CONFLICT (name) DO NOTHING RETURNING")
+
+	guarded := buildUpsertSQL(metadata, metadata.SQLTemplates, []string{"name"}, []string{"config"}, "EXCLUDED.updated_at > test_nodes.updated_at")
+	assert.Contains(t, guarded, "DO UPDATE SET config=EXCLUDED.config WHERE EXCLUDED.updated_at > test_nodes.updated_at RETURNING")
+
+	multi := buildUpsertMultipleSQL(metadata, metadata.SQLTemplates, 2, []string{"name"}, []string{"config"}, "EXCLUDED.updated_at > test_nodes.updated_at")
+	assert.Contains(t, multi, "config=CASE WHEN (EXCLUDED.updated_at > test_nodes.updated_at) THEN EXCLUDED.config ELSE test_nodes.config END")
+}
+
+type generatedNode struct {
+	ID        uuid.UUID `orm:"column:id;pk"`
+	Name      string    `orm:"column:name"`
+	NameUpper string    `orm:"column:name_upper;generated"`
+	Views     int       `orm:"column:views;readonly"`
+	CreatedAt time.Time `orm:"column:created_at"`
+	UpdatedAt time.Time `orm:"column:updated_at"`
+}
+
+func (generatedNode) TableName() string {
+	return "test_generated_nodes"
+}
+
+func TestReadOnlyFields_ExcludedFromWrites(t *testing.T) {
+	_, err := scannerTestDB.Exec(`
+	CREATE TABLE IF NOT EXISTS test_generated_nodes (
+		id UUID PRIMARY KEY,
+		name TEXT NOT NULL,
+		name_upper TEXT GENERATED ALWAYS AS (upper(name)) STORED,
+		views INT NOT NULL DEFAULT 7,
+		created_at TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	)`)
+	require.NoError(t, err)
+	RegisterModel[generatedNode]()
+	metadata := GetMetadata[generatedNode]()
+
+	assert.NotContains(t, metadata.SQLTemplates.Insert, "name_upper")
+	assert.NotContains(t, metadata.SQLTemplates.Update, "views")
+	assert.Contains(t, metadata.SQLTemplates.SelectByPK, "name_upper")
+
+	db := NewDB[generatedNode](scannerTestDB)
+	node := &generatedNode{ID: uuid.New(), Name: "ada", NameUpper: "ignored", Views: 99, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, db.Create(context.Background(), node))
+
+	node.Name = "grace"
+	require.NoError(t, db.Update(context.Background(), node))
+
+	var stored generatedNode
+	require.NoError(t, db.FindByPK(context.Background(), &stored, node.ID))
+	assert.Equal(t, "GRACE", stored.NameUpper, "generated columns are scanned on read")
+	assert.Equal(t, 7, stored.Views, "read-only columns keep the database's value")
+
+	require.NoError(t, db.Upsert(context.Background(), node, []string{"id"}))
+	assert.Equal(t, "GRACE", node.NameUpper)
+}
@@ -10,12 +10,12 @@ import (
 // ValidateSchema validates model metadata against actual database schema
 func ValidateSchema(db *sql.DB, metadata *ModelMetadata) error {
 	// Check if table exists
-	exists, err := tableExists(db, metadata.Schema, metadata.TableName)
+	exists, err := tableExists(db, validationSchema(metadata), metadata.TableName)
 	if err != nil {
 		return fmt.Errorf("failed to check table existence: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("table %s.%s does not exist in database", metadata.Schema, metadata.TableName)
+		return fmt.Errorf("table %s.%s does not exist in database", validationSchema(metadata), metadata.TableName)
 	}
 
 	// Validate columns
@@ -58,7 +58,7 @@ func validateColumns(db *sql.DB, metadata *ModelMetadata) error {
 		ORDER BY ordinal_position
 	`
 
-	rows, err := db.Query(query, metadata.Schema, metadata.TableName)
+	rows, err := db.Query(query, validationSchema(metadata), metadata.TableName)
 	if err != nil {
 		return fmt.Errorf("failed to query columns: %w", err)
 	}
@@ -84,7 +84,7 @@ func validateColumns(db *sql.DB, metadata *ModelMetadata) error {
 		if !exists {
 			// Log warning but don't fail - field might be computed or virtual
 			log.Printf("[ORM] ⚠ Warning: column '%s' not found in table %s.%s",
-				field.Column, metadata.Schema, metadata.TableName)
+				field.Column, validationSchema(metadata), metadata.TableName)
 			continue
 		}
 
@@ -123,7 +123,7 @@ func validatePrimaryKeys(db *sql.DB, metadata *ModelMetadata) error {
 		ORDER BY kcu.ordinal_position
 	`
 
-	rows, err := db.Query(query, metadata.Schema, metadata.TableName)
+	rows, err := db.Query(query, validationSchema(metadata), metadata.TableName)
 	if err != nil {
 		return fmt.Errorf("failed to query primary keys: %w", err)
 	}
@@ -141,7 +141,7 @@ func validatePrimaryKeys(db *sql.DB, metadata *ModelMetadata) error {
 	// If no PKs defined in model, use DB PKs
 	if len(metadata.PKFields) == 0 && len(dbPKColumns) > 0 {
 		log.Printf("[ORM] Using database primary keys for %s.%s: %v",
-			metadata.Schema, metadata.TableName, dbPKColumns)
+			validationSchema(metadata), metadata.TableName, dbPKColumns)
 		metadata.PKFields = dbPKColumns
 
 		// Update field metadata
@@ -211,3 +211,12 @@ func isTypeCompatible(goType, sqlType string) bool {
 	// If not found in map, log it but don't fail
 	return true
 }
+
+// validationSchema returns the schema the model's statements resolve to, treating an unqualified table
+// as living in public
+func validationSchema(metadata *ModelMetadata) string {
+	if schema := metadata.ResolveSchema(""); schema != "" {
+		return schema
+	}
+	return "public"
+}
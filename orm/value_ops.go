@@ -2,6 +2,7 @@ package orm
 
 import (
 	"database/sql"
+	"fmt"
 	"reflect"
 	"unsafe"
 
@@ -132,3 +133,32 @@ func makeSetID[T any](fieldOffsets []uintptr, fieldTypes []reflect.Type, pkIndex
 		}
 	}
 }
+
+// scanUpserted scans the rows returned by an upsert statement back into entities, in order,
+// and reports for each whether it was inserted rather than updated
+func scanUpserted[T any](metadata *ModelMetadata, rows *sql.Rows, entities []*T) ([]bool, error) {
+	inserted := make([]bool, 0, len(entities))
+	for rows.Next() {
+		if len(inserted) >= len(entities) {
+			return nil, fmt.Errorf("upsert returned more rows than entities")
+		}
+		base := unsafe.Pointer(entities[len(inserted)])
+		targets := make([]interface{}, 0, len(metadata.Fields)+1)
+		for _, field := range metadata.Fields {
+			targets = append(targets, createScanTarget(unsafe.Add(base, field.Offset), field.Type))
+		}
+		var wasInserted bool
+		targets = append(targets, &wasInserted)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, wasInserted)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(inserted) != len(entities) {
+		return nil, fmt.Errorf("upsert returned %d rows for %d entities", len(inserted), len(entities))
+	}
+	return inserted, nil
+}
@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// Bus delivers outbox events to consumers. Implement it over Kafka, NATS or another broker;
+// the relay retries failed publishes, so Publish may see the same event more than once.
+type Bus interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// BusFunc adapts a function to Bus
+type BusFunc func(ctx context.Context, event *Event) error
+
+func (f BusFunc) Publish(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+// RedisStreamBus appends events to a Redis stream per aggregate type, named Prefix + aggregate type
+type RedisStreamBus struct {
+	client redis.UniversalClient
+	prefix string
+	maxLen int64
+}
+
+// NewRedisStreamBus creates a bus writing to streams named prefix + aggregate type.
+// maxLen approximately caps each stream's length; 0 leaves streams uncapped.
+func NewRedisStreamBus(client redis.UniversalClient, prefix string, maxLen int64) *RedisStreamBus {
+	return &RedisStreamBus{client: client, prefix: prefix, maxLen: maxLen}
+}
+
+func (b *RedisStreamBus) Publish(ctx context.Context, event *Event) error {
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.prefix + event.AggregateType,
+		MaxLen: b.maxLen,
+		Approx: b.maxLen > 0,
+		Values: map[string]interface{}{
+			"id":             event.ID.String(),
+			"sequence":       strconv.FormatInt(event.Sequence, 10),
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID,
+			"event_type":     event.Type,
+			"payload":        event.Payload,
+			"headers":        event.Headers,
+		},
+	}).Err()
+}
+
+// CacheBus publishes events as JSON on a pub/sub channel per aggregate type, named Prefix + aggregate type.
+// Pub/sub does not retain messages, so subscribers that are offline miss events.
+type CacheBus struct {
+	cache  cache.CacheService
+	prefix string
+}
+
+func NewCacheBus(cacheService cache.CacheService, prefix string) *CacheBus {
+	return &CacheBus{cache: cacheService, prefix: prefix}
+}
+
+func (b *CacheBus) Publish(ctx context.Context, event *Event) error {
+	return b.cache.Publish(ctx, b.prefix+event.AggregateType, event)
+}
+
+// Handler consumes events delivered by a MemoryBus
+type Handler func(ctx context.Context, event *Event) error
+
+// MemoryBus dispatches events to in-process handlers by event type, for tests and single instance deployments.
+// A handler error fails the publish so the relay retries the event.
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers handler for eventType; "*" receives every event
+func (b *MemoryBus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, event *Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.Type]...), b.handlers["*"]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ Bus = BusFunc(nil)
+	_ Bus = (*RedisStreamBus)(nil)
+	_ Bus = (*CacheBus)(nil)
+	_ Bus = (*MemoryBus)(nil)
+)
@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// OutboxDDL creates the outbox table and the index the relay polls
+const OutboxDDL = `CREATE TABLE IF NOT EXISTS outbox_events (
+	id UUID PRIMARY KEY,
+	sequence BIGSERIAL NOT NULL UNIQUE,
+	aggregate_type VARCHAR(100) NOT NULL,
+	aggregate_id VARCHAR(255) NOT NULL,
+	event_type VARCHAR(255) NOT NULL,
+	payload TEXT NOT NULL DEFAULT '{}',
+	headers TEXT NOT NULL DEFAULT '{}',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	next_attempt_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	published_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (sequence) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate ON outbox_events (aggregate_type, aggregate_id, sequence) WHERE published_at IS NULL`
+
+// Headers added to every event written from a request
+const (
+	HeaderXID     = "xid"
+	HeaderTraceID = "trace_id"
+)
+
+var ErrNoTransaction = errors.New("outbox events must be written inside a transaction")
+
+// Event is a domain event stored in the outbox until the relay publishes it.
+// Events sharing an AggregateType and AggregateID are published in Sequence order.
+type Event struct {
+	ID            uuid.UUID  `json:"id" orm:"column:id;pk"`
+	Sequence      int64      `json:"sequence" orm:"column:sequence;auto"`
+	AggregateType string     `json:"aggregate_type" orm:"column:aggregate_type"`
+	AggregateID   string     `json:"aggregate_id" orm:"column:aggregate_id"`
+	Type          string     `json:"event_type" orm:"column:event_type"`
+	Payload       string     `json:"payload" orm:"column:payload"`
+	Headers       string     `json:"headers" orm:"column:headers"`
+	Attempts      int        `json:"attempts" orm:"column:attempts"`
+	LastError     string     `json:"last_error,omitempty" orm:"column:last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" orm:"column:next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" orm:"column:created_at"`
+	PublishedAt   *time.Time `json:"published_at,omitempty" orm:"column:published_at"`
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// Decode unmarshals the event payload into v
+func (e *Event) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(e.Payload), v)
+}
+
+// HeaderMap returns the event headers
+func (e *Event) HeaderMap() map[string]string {
+	headers := make(map[string]string)
+	_ = json.Unmarshal([]byte(e.Headers), &headers)
+	return headers
+}
+
+// NewEvent builds an event with payload marshalled to JSON
+func NewEvent(aggregateType, aggregateID, eventType string, payload interface{}) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload for event %s: %w", eventType, err)
+	}
+
+	now := time.Now()
+	return &Event{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Type:          eventType,
+		Payload:       string(data),
+		Headers:       "{}",
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Write stores events in the request's transaction, so they are published only if it commits.
+// The request XID and trace ID are added to each event's headers.
+func Write(ctx request.Context, events ...*Event) error {
+	query := request.GetQuery(ctx)
+	if query == nil {
+		return ErrNoTransaction
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	ensureModel()
+	for _, event := range events {
+		headers := event.HeaderMap()
+		if _, ok := headers[HeaderXID]; !ok && ctx.XID() != uuid.Nil {
+			headers[HeaderXID] = ctx.XID().String()
+		}
+		if _, ok := headers[HeaderTraceID]; !ok && ctx.TraceID() != "" {
+			headers[HeaderTraceID] = ctx.TraceID()
+		}
+		data, err := json.Marshal(headers)
+		if err != nil {
+			return fmt.Errorf("failed to encode headers for event %s: %w", event.Type, err)
+		}
+		event.Headers = string(data)
+	}
+
+	if err := orm.NewTransaction[Event]().CreateMultiple(query, events); err != nil {
+		return fmt.Errorf("failed to write outbox events: %w", err)
+	}
+	return nil
+}
+
+// Publish builds an event and writes it to the outbox in the request's transaction
+func Publish(ctx request.Context, aggregateType, aggregateID, eventType string, payload interface{}) (*Event, error) {
+	event, err := NewEvent(aggregateType, aggregateID, eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := Write(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ensureModel registers Event with the ORM on first use
+func ensureModel() {
+	if orm.GetMetadata[Event]() == nil {
+		orm.RegisterModel[Event]()
+	}
+}
@@ -0,0 +1,231 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+var testContainer testcontainers.Container
+
+func TestMain(m *testing.M) {
+	container, err := postgres.NewMockConnection()
+	if err != nil {
+		panic("Failed to start test container: " + err.Error())
+	}
+	testContainer = container
+
+	if _, err := postgres.GetDB().Exec(OutboxDDL); err != nil {
+		panic("Failed to create outbox table: " + err.Error())
+	}
+	ensureModel()
+
+	m.Run()
+	if testContainer != nil {
+		testContainer.Terminate(context.Background())
+	}
+}
+
+type orderPlaced struct {
+	Total int `json:"total"`
+}
+
+// recordingBus captures published events and fails those listed in failures
+type recordingBus struct {
+	mu        sync.Mutex
+	published []*Event
+	failures  map[uuid.UUID]int
+}
+
+func (b *recordingBus) Publish(ctx context.Context, event *Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures[event.ID] > 0 {
+		b.failures[event.ID]--
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *recordingBus) types() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	types := make([]string, len(b.published))
+	for i, event := range b.published {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// resetOutbox clears events left by earlier tests
+func resetOutbox(t *testing.T) {
+	t.Helper()
+	_, err := postgres.GetDB().Exec(`DELETE FROM outbox_events`)
+	require.NoError(t, err)
+}
+
+func writeEvents(t *testing.T, events ...*Event) {
+	t.Helper()
+	ctx := request.NewTestContext()
+	_, err := request.BeginTransaction(ctx)
+	require.NoError(t, err)
+	require.NoError(t, Write(ctx, events...))
+	require.NoError(t, ctx.CloseTxn(nil))
+}
+
+func mustEvent(t *testing.T, aggregateID, eventType string) *Event {
+	t.Helper()
+	event, err := NewEvent("order", aggregateID, eventType, orderPlaced{Total: 10})
+	require.NoError(t, err)
+	return event
+}
+
+func TestNewEvent(t *testing.T) {
+	event, err := NewEvent("order", "42", "order.placed", orderPlaced{Total: 10})
+	require.NoError(t, err)
+
+	var payload orderPlaced
+	require.NoError(t, event.Decode(&payload))
+	assert.Equal(t, 10, payload.Total)
+	assert.Empty(t, event.HeaderMap())
+
+	_, err = NewEvent("order", "42", "bad", make(chan int))
+	assert.Error(t, err)
+}
+
+func TestGroupByAggregate(t *testing.T) {
+	a1 := &Event{AggregateType: "order", AggregateID: "a", Sequence: 1}
+	b1 := &Event{AggregateType: "order", AggregateID: "b", Sequence: 2}
+	a2 := &Event{AggregateType: "order", AggregateID: "a", Sequence: 3}
+	other := &Event{AggregateType: "user", AggregateID: "a", Sequence: 4}
+
+	groups := groupByAggregate([]*Event{a1, b1, a2, other})
+	require.Len(t, groups, 3)
+	assert.Equal(t, []*Event{a1, a2}, groups[0])
+	assert.Equal(t, []*Event{b1}, groups[1])
+	assert.Equal(t, []*Event{other}, groups[2])
+}
+
+func TestMemoryBus(t *testing.T) {
+	bus := NewMemoryBus()
+	var received []string
+	bus.Subscribe("order.placed", func(ctx context.Context, event *Event) error {
+		received = append(received, "placed")
+		return nil
+	})
+	bus.Subscribe("*", func(ctx context.Context, event *Event) error {
+		received = append(received, "any:"+event.Type)
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(context.Background(), &Event{Type: "order.placed"}))
+	require.NoError(t, bus.Publish(context.Background(), &Event{Type: "order.shipped"}))
+	assert.Equal(t, []string{"placed", "any:order.placed", "any:order.shipped"}, received)
+}
+
+func TestWrite_RequiresTransaction(t *testing.T) {
+	err := Write(request.NewTestContext(), mustEvent(t, "1", "order.placed"))
+	assert.ErrorIs(t, err, ErrNoTransaction)
+}
+
+func TestWrite_RollbackDiscardsEvents(t *testing.T) {
+	resetOutbox(t)
+	ctx := request.NewTestContext()
+	_, err := request.BeginTransaction(ctx)
+	require.NoError(t, err)
+
+	event, err := Publish(ctx, "order", "1", "order.placed", orderPlaced{Total: 5})
+	require.NoError(t, err)
+	assert.Equal(t, ctx.XID().String(), event.HeaderMap()[HeaderXID])
+	require.NoError(t, ctx.Rollback())
+
+	bus := &recordingBus{}
+	published, err := NewRelay(bus, RelayConfig{}).RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+}
+
+func TestRelay_PublishesInOrderOnce(t *testing.T) {
+	resetOutbox(t)
+	writeEvents(t, mustEvent(t, "1", "order.placed"), mustEvent(t, "2", "order.placed"))
+	writeEvents(t, mustEvent(t, "1", "order.paid"), mustEvent(t, "1", "order.shipped"))
+
+	bus := &recordingBus{}
+	relay := NewRelay(bus, RelayConfig{})
+
+	published, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, published)
+	assert.Equal(t, []string{"order.placed", "order.paid", "order.shipped", "order.placed"}, bus.types())
+	assert.Equal(t, "2", bus.published[3].AggregateID)
+
+	published, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+}
+
+func TestRelay_FailureHoldsBackAggregate(t *testing.T) {
+	resetOutbox(t)
+	placed := mustEvent(t, "1", "order.placed")
+	writeEvents(t, placed, mustEvent(t, "1", "order.paid"), mustEvent(t, "2", "order.placed"))
+
+	bus := &recordingBus{failures: map[uuid.UUID]int{placed.ID: 1}}
+	relay := NewRelay(bus, RelayConfig{Backoff: func(int) time.Duration { return 0 }})
+
+	// Only the other aggregate gets through while order 1's first event is failing
+	published, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	require.Len(t, bus.published, 1)
+	assert.Equal(t, "2", bus.published[0].AggregateID)
+
+	var attempts int
+	var lastError string
+	require.NoError(t, postgres.GetDB().QueryRow(`SELECT attempts, last_error FROM outbox_events WHERE id = $1`, placed.ID).Scan(&attempts, &lastError))
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, "broker unavailable", lastError)
+
+	published, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{"order.placed", "order.placed", "order.paid"}, bus.types())
+}
+
+func TestRelay_BackoffDelaysRetry(t *testing.T) {
+	resetOutbox(t)
+	placed := mustEvent(t, "1", "order.placed")
+	writeEvents(t, placed, mustEvent(t, "1", "order.paid"))
+
+	bus := &recordingBus{failures: map[uuid.UUID]int{placed.ID: 1}}
+	relay := NewRelay(bus, RelayConfig{Backoff: func(int) time.Duration { return time.Hour }})
+
+	for i := 0; i < 2; i++ {
+		published, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, published)
+	}
+	assert.Empty(t, bus.published)
+}
+
+func TestPurgePublished(t *testing.T) {
+	resetOutbox(t)
+	writeEvents(t, mustEvent(t, "1", "order.placed"), mustEvent(t, "2", "order.placed"))
+
+	_, err := NewRelay(&recordingBus{}, RelayConfig{}).RelayOnce(context.Background())
+	require.NoError(t, err)
+
+	deleted, err := PurgePublished(context.Background(), postgres.GetDB().DB, -time.Minute, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
@@ -0,0 +1,247 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/request"
+)
+
+// RelayConfig configures a Relay
+type RelayConfig struct {
+	// BatchSize caps events claimed per poll (default 100)
+	BatchSize int
+
+	// PollInterval is how long an idle relay waits before polling again (default 1s)
+	PollInterval time.Duration
+
+	// Backoff returns the delay before retrying an event after the given failed attempt
+	// (default doubling from 1s up to 5m)
+	Backoff func(attempt int) time.Duration
+}
+
+// Relay publishes committed outbox events to a Bus with at-least-once delivery.
+// Events of one aggregate are published in order: a failed event holds back the later events
+// of its aggregate until it succeeds. Several relays may run at once; a transaction scoped
+// advisory lock per aggregate keeps them from publishing the same aggregate concurrently.
+type Relay struct {
+	bus    Bus
+	config RelayConfig
+	wg     sync.WaitGroup
+}
+
+// NewRelay creates a relay using the database from postgres.GetDB
+func NewRelay(bus Bus, cfg RelayConfig) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = defaultBackoff
+	}
+	ensureModel()
+	return &Relay{bus: bus, config: cfg}
+}
+
+// Start relays events until ctx is cancelled; use Wait to block until the current batch finishes
+func (r *Relay) Start(ctx context.Context) {
+	r.wg.Add(1)
+	metrics.TraceGoroutine(ctx, "outbox", "relay", func() {
+		defer r.wg.Done()
+
+		for {
+			published, err := r.RelayOnce(ctx)
+			if err != nil {
+				log.Printf("[Outbox] relay failed: %v", err)
+			}
+			if published > 0 && err == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.config.PollInterval):
+			}
+		}
+	})
+}
+
+// Wait blocks until the relay loop has returned
+func (r *Relay) Wait() {
+	r.wg.Wait()
+}
+
+// RelayOnce publishes one batch of due events and returns how many were published.
+// Events are marked published in the same transaction that claimed them, so a crash
+// between publishing and committing redelivers them.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	relayCtx := request.NewWorkerContext(ctx, request.WorkerOptions{})
+	defer relayCtx.Close(nil)
+
+	query, err := relayCtx.BeginTxn()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	now := time.Now()
+	events, err := orm.NewTransaction[Event]().FindByQuery(query,
+		selectEventsSQL()+` WHERE published_at IS NULL AND next_attempt_at <= $1
+		ORDER BY sequence
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`,
+		now, r.config.BatchSize)
+	if err != nil {
+		_ = query.Rollback()
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	published := 0
+	for _, group := range groupByAggregate(events) {
+		ok, err := r.claimAggregate(query, group[0])
+		if err != nil {
+			_ = query.Rollback()
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+
+		for _, event := range group {
+			if err := r.bus.Publish(ctx, event); err != nil {
+				if err := r.markFailed(query, event, err); err != nil {
+					_ = query.Rollback()
+					return 0, err
+				}
+				break
+			}
+			if _, err := query.Exec(`UPDATE outbox_events SET published_at = $1, attempts = attempts + 1, last_error = '' WHERE id = $2`, time.Now(), event.ID); err != nil {
+				_ = query.Rollback()
+				return 0, fmt.Errorf("failed to mark event %s published: %w", event.ID, err)
+			}
+			published++
+			metrics.RecordWorkflow(ctx, "outbox:"+event.AggregateType, "published")
+		}
+	}
+
+	if err := query.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+	return published, nil
+}
+
+// claimAggregate takes the aggregate's advisory lock and confirms first is its oldest unpublished event.
+// It reports false when another relay holds the aggregate or an earlier event is locked or backing off.
+func (r *Relay) claimAggregate(query *orm.Query, first *Event) (bool, error) {
+	var locked bool
+	if err := query.QueryRowRaw(`SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))`,
+		first.AggregateType+":"+first.AggregateID).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to lock aggregate %s/%s: %w", first.AggregateType, first.AggregateID, err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	var head sql.NullInt64
+	if err := query.QueryRowRaw(`
+		SELECT MIN(sequence) FROM outbox_events
+		WHERE aggregate_type = $1 AND aggregate_id = $2 AND published_at IS NULL
+	`, first.AggregateType, first.AggregateID).Scan(&head); err != nil {
+		return false, fmt.Errorf("failed to check aggregate %s/%s: %w", first.AggregateType, first.AggregateID, err)
+	}
+	return head.Valid && head.Int64 == first.Sequence, nil
+}
+
+// markFailed records a failed publish and schedules the retry
+func (r *Relay) markFailed(query *orm.Query, event *Event, publishErr error) error {
+	attempts := event.Attempts + 1
+	_, err := query.Exec(`UPDATE outbox_events SET attempts = $1, last_error = $2, next_attempt_at = $3 WHERE id = $4`,
+		attempts, publishErr.Error(), time.Now().Add(r.config.Backoff(attempts)), event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record publish failure for event %s: %w", event.ID, err)
+	}
+	metrics.RecordWorkflow(context.Background(), "outbox:"+event.AggregateType, "failed")
+	log.Printf("[Outbox] publishing event %s (%s) failed on attempt %d: %v", event.ID, event.Type, attempts, publishErr)
+	return nil
+}
+
+// PurgePublished deletes events published before olderThan in batches and returns the number deleted
+func PurgePublished(ctx context.Context, db *sql.DB, olderThan time.Duration, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	cutoff := time.Now().Add(-olderThan)
+
+	query := `
+		DELETE FROM outbox_events
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE published_at < $1
+			LIMIT $2
+		)
+	`
+
+	start := time.Now()
+	var total int64
+	for {
+		result, err := db.ExecContext(ctx, query, cutoff, batchSize)
+		if err != nil {
+			metrics.RecordPurge(ctx, "outbox_events", total, time.Since(start), false)
+			return total, fmt.Errorf("failed to purge outbox events: %w", err)
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			metrics.RecordPurge(ctx, "outbox_events", total, time.Since(start), false)
+			return total, fmt.Errorf("failed to purge outbox events: %w", err)
+		}
+		total += deleted
+		if deleted < int64(batchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	metrics.RecordPurge(ctx, "outbox_events", total, time.Since(start), true)
+	return total, nil
+}
+
+// groupByAggregate splits events into per aggregate runs, keeping sequence order within each
+func groupByAggregate(events []*Event) [][]*Event {
+	index := make(map[string]int)
+	var groups [][]*Event
+	for _, event := range events {
+		key := event.AggregateType + ":" + event.AggregateID
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], event)
+	}
+	return groups
+}
+
+// defaultBackoff doubles the delay from 1s per attempt up to 5m
+func defaultBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 9 {
+		return 5 * time.Minute
+	}
+	return min(time.Second<<(attempt-1), 5*time.Minute)
+}
+
+// selectEventsSQL returns the ORM's SELECT of every Event column, ready for a WHERE clause
+func selectEventsSQL() string {
+	return strings.TrimSpace(orm.GetMetadata[Event]().Templates("").SelectAll)
+}
@@ -0,0 +1,223 @@
+package rate_limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// tokenBucketPrefix namespaces bucket state and locks in the cache
+const tokenBucketPrefix = "ratelimit:"
+
+// ErrLimiterBusy is returned when a bucket stays locked by other instances for every retry
+var ErrLimiterBusy = errors.New("rate limiter busy")
+
+// BucketLimit is a token bucket refilled at Rate tokens per second up to Burst tokens
+type BucketLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerSecond returns a limit of n requests per second with the given burst
+func PerSecond(n float64, burst int) BucketLimit {
+	return BucketLimit{Rate: n, Burst: burst}
+}
+
+// PerMinute returns a limit of n requests per minute with the given burst
+func PerMinute(n float64, burst int) BucketLimit {
+	return BucketLimit{Rate: n / 60, Burst: burst}
+}
+
+// Decision is the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available, set when the request is not allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// takeScript refills and takes from the bucket hash at KEYS[1] by Redis server time, returning whether
+// the take was allowed and the tokens left. ARGV is the rate per second and the burst.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = redis.call("TIME")
+local tokens = burst
+local state = redis.call("HMGET", KEYS[1], "tokens", "sec", "usec")
+if state[1] then
+	local elapsed = (tonumber(now[1]) - tonumber(state[2])) + (tonumber(now[2]) - tonumber(state[3])) / 1000000
+	tokens = math.min(burst, tonumber(state[1]) + math.max(0, elapsed) * rate)
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "sec", now[1], "usec", now[2])
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// TokenBucketLimiter keeps token buckets in a CacheService so every instance sharing the cache
+// enforces one limit per key. On Redis each take is a single Lua script; other caches do a
+// read-modify-write under a short cache lock.
+type TokenBucketLimiter struct {
+	cache   cache.CacheService
+	client  redis.UniversalClient
+	now     func() time.Time
+	retries int
+	backoff time.Duration
+}
+
+// NewTokenBucketLimiter creates a limiter storing bucket state in c
+func NewTokenBucketLimiter(c cache.CacheService) *TokenBucketLimiter {
+	return &TokenBucketLimiter{cache: c, client: redisClient(c), now: time.Now, retries: 5, backoff: 2 * time.Millisecond}
+}
+
+// redisClient returns the Redis client behind c, looking through decorators, or nil for other caches
+func redisClient(c cache.CacheService) redis.UniversalClient {
+	for {
+		switch v := c.(type) {
+		case interface{ Client() redis.UniversalClient }:
+			return v.Client()
+		case interface{ Unwrap() cache.CacheService }:
+			c = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// Take removes one token from key's bucket, reporting whether the request is allowed
+func (l *TokenBucketLimiter) Take(ctx context.Context, key string, limit BucketLimit) (Decision, error) {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return Decision{}, fmt.Errorf("invalid bucket limit: rate %v, burst %d", limit.Rate, limit.Burst)
+	}
+
+	stateKey := tokenBucketPrefix + key
+	if l.client != nil {
+		return l.takeScripted(ctx, stateKey, limit)
+	}
+
+	unlock, err := l.lock(ctx, stateKey+":lock")
+	if err != nil {
+		return Decision{}, err
+	}
+	defer unlock()
+
+	now := l.now()
+	tokens := float64(limit.Burst)
+	if raw, err := l.cache.Get(ctx, stateKey); err == nil {
+		if stored, last, ok := parseBucket(raw); ok {
+			elapsed := now.Sub(last).Seconds()
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			tokens = math.Min(float64(limit.Burst), stored+elapsed*limit.Rate)
+		}
+	} else if !errors.Is(err, cache.ErrKeyNotFound) {
+		return Decision{}, err
+	}
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	decision := newDecision(limit, tokens, allowed)
+
+	// A full bucket needs no state, so let it expire once refilled
+	ttl := decision.Reset + time.Second
+	if err := l.cache.Set(ctx, stateKey, formatBucket(tokens, now), ttl); err != nil {
+		return Decision{}, err
+	}
+	return decision, nil
+}
+
+// takeScripted takes a token with takeScript, in one round trip and without a lock
+func (l *TokenBucketLimiter) takeScripted(ctx context.Context, stateKey string, limit BucketLimit) (Decision, error) {
+	result, err := takeScript.Run(ctx, l.client, []string{stateKey}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(result) != 2 {
+		return Decision{}, fmt.Errorf("unexpected token bucket result %v", result)
+	}
+	allowed, _ := result[0].(int64)
+	tokenText, _ := result[1].(string)
+	tokens, err := strconv.ParseFloat(tokenText, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("unexpected token bucket result %v", result)
+	}
+	return newDecision(limit, tokens, allowed == 1), nil
+}
+
+// newDecision reports a take that left tokens in the bucket
+func newDecision(limit BucketLimit, tokens float64, allowed bool) Decision {
+	decision := Decision{Allowed: allowed, Limit: limit.Burst}
+	if !allowed {
+		decision.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	decision.Remaining = int(math.Floor(tokens))
+	decision.Reset = secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate)
+	return decision
+}
+
+func (l *TokenBucketLimiter) lock(ctx context.Context, key string) (func(), error) {
+	for attempt := 0; ; attempt++ {
+		unlocker, err := l.cache.Lock(ctx, key, time.Second)
+		if err == nil {
+			return func() { _ = unlocker.Unlock(context.WithoutCancel(ctx)) }, nil
+		}
+		if !errors.Is(err, cache.ErrLockNotAcquired) {
+			return nil, err
+		}
+		if attempt >= l.retries {
+			return nil, ErrLimiterBusy
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(l.backoff << attempt):
+		}
+	}
+}
+
+// formatBucket encodes the token count and refill time as "tokens|unix_nanos"
+func formatBucket(tokens float64, at time.Time) string {
+	return strconv.FormatFloat(tokens, 'f', -1, 64) + "|" + strconv.FormatInt(at.UnixNano(), 10)
+}
+
+func parseBucket(raw interface{}) (float64, time.Time, bool) {
+	s, ok := raw.(string)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	tokenPart, timePart, ok := strings.Cut(s, "|")
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	tokens, err := strconv.ParseFloat(tokenPart, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(timePart, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return tokens, time.Unix(0, nanos), true
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
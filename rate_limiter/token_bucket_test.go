@@ -0,0 +1,86 @@
+package rate_limiter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+func newTestLimiter(now *time.Time) *TokenBucketLimiter {
+	limiter := NewTokenBucketLimiter(local.NewLocalCache(nil))
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestTokenBucketLimiter_BurstThenRefill(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := newTestLimiter(&now)
+	ctx := context.Background()
+	limit := PerSecond(2, 3)
+
+	for i := 0; i < 3; i++ {
+		decision, err := limiter.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 2-i, decision.Remaining)
+	}
+
+	decision, err := limiter.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, decision.Reset)
+
+	// Other keys have their own bucket
+	decision, err = limiter.Take(ctx, "user:2", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	// Half a second refills one token
+	now = now.Add(500 * time.Millisecond)
+	decision, err = limiter.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+
+	// Refill never exceeds the burst
+	now = now.Add(time.Hour)
+	decision, err = limiter.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, decision.Remaining)
+}
+
+func TestTokenBucketLimiter_ConcurrentTakes(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := newTestLimiter(&now)
+	limiter.retries = 50
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decision, err := limiter.Take(context.Background(), "shared", PerMinute(1, 5))
+			if err == nil && decision.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(5), allowed.Load())
+}
+
+func TestTokenBucketLimiter_InvalidLimit(t *testing.T) {
+	now := time.Now()
+	_, err := newTestLimiter(&now).Take(context.Background(), "k", BucketLimit{})
+	assert.Error(t, err)
+}
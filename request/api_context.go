@@ -9,15 +9,49 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/yadunandan004/scaffold/auth"
 	"github.com/yadunandan004/scaffold/orm"
 )
 
-// Principal represents the authenticated user information
+// Principal types
+const (
+	PrincipalUser    = "user"
+	PrincipalService = "service"
+)
+
+// Principal represents the authenticated user or service information
 type Principal struct {
 	ID             uuid.UUID
 	Email          string
 	DisplayName    string
 	ClientDeviceID string // UUID of the client device from JWT claims
+	Type           string // PrincipalUser or PrincipalService
+	ClientID       string // Service client ID, set for service principals
+	Scopes         []string
+	TenantID       string // Tenant the principal belongs to; empty when not multi-tenant
+}
+
+// NewPrincipalFromClaims builds the principal for verified token claims
+func NewPrincipalFromClaims(claims *auth.UserClaims) *Principal {
+	principal := &Principal{
+		ID:             claims.UserID,
+		Email:          claims.Email,
+		DisplayName:    claims.Name,
+		ClientDeviceID: claims.ClientDeviceID,
+		Type:           PrincipalUser,
+		TenantID:       claims.TenantID,
+	}
+	if claims.IsService() {
+		principal.Type = PrincipalService
+		principal.ClientID = claims.ClientID
+		principal.Scopes = claims.Scopes
+	}
+	return principal
+}
+
+// IsService reports whether the principal is a service authenticated with client credentials
+func (p *Principal) IsService() bool {
+	return p.Type == PrincipalService
 }
 
 func (p *Principal) GetID() uuid.UUID {
@@ -38,8 +72,9 @@ type BaseCtx struct {
 // HttpCtx represents the HTTP API request
 type HttpCtx struct {
 	BaseCtx
-	ginCtx     *gin.Context
-	pathParams map[string]string
+	ginCtx         *gin.Context
+	pathParams     map[string]string
+	bufferedWriter *bufferedResponseWriter
 }
 
 type RequestContext interface {
@@ -121,22 +156,33 @@ type bufferedResponseWriter struct {
 	gin.ResponseWriter
 	body       *bytes.Buffer
 	statusCode int
+	// maxBytes caps how much of the body is kept; 0 keeps all of it
+	maxBytes int
+	written  int
 }
 
 func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
 	// Capture the data
-	w.body.Write(data)
+	w.capture(data)
 	// Also write to the original writer
 	return w.ResponseWriter.Write(data)
 }
 
 func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
 	// Capture the string
-	w.body.WriteString(s)
+	w.capture([]byte(s))
 	// Also write to the original writer
 	return w.ResponseWriter.WriteString(s)
 }
 
+func (w *bufferedResponseWriter) capture(data []byte) {
+	w.written += len(data)
+	if w.maxBytes > 0 {
+		data = data[:min(len(data), max(w.maxBytes-w.body.Len(), 0))]
+	}
+	w.body.Write(data)
+}
+
 func (w *bufferedResponseWriter) WriteHeader(code int) {
 	w.statusCode = code
 	w.ResponseWriter.WriteHeader(code)
@@ -162,6 +208,8 @@ type Context interface {
 	GetPgTxn() *orm.Query
 	GetPgDB() *sql.DB
 	CloseTxn(err error) error
+	// AfterCommit runs fn once the transaction commits; see the AfterCommit function
+	AfterCommit(fn func(ctx context.Context))
 	// HTTP methods - will be no-op for gRPC
 	GetGinContext() *gin.Context
 	SetPathParams(params map[string]string)
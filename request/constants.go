@@ -34,6 +34,10 @@ const (
 	GRPCCtxKey   ContextKey = "grpc_ctx"
 )
 
+// XIDMetadataKey is the gRPC metadata key carrying the request's XID, in response headers and
+// in calls made with OutgoingGRPCContext
+const XIDMetadataKey = "xid"
+
 // TransactionKey is used to store transaction in request
 // Exported to allow ORM package to use the same key for compatibility
 type TransactionKey struct{}
@@ -132,6 +132,19 @@ func GetQuery(ctx Context) *orm.Query {
 	return nil
 }
 
+// AfterCommit runs fn once the request's transaction commits, and never if it rolls back, so side
+// effects such as cache invalidation or event publishing only follow writes that persisted. Without
+// a transaction fn runs immediately. fn gets the request's context without its cancellation, as
+// the request may have ended by the time the transaction closes.
+func AfterCommit(ctx Context, fn func(ctx context.Context)) {
+	if query := GetQuery(ctx); query != nil {
+		bg := context.WithoutCancel(ctx.GetCtx())
+		query.OnCommit(func() { fn(bg) })
+		return
+	}
+	fn(ctx.GetCtx())
+}
+
 // CommitRawTransaction commits a raw transaction from request
 func CommitRawTransaction(ctx Context) error {
 	tx := GetRawTransaction(ctx)
@@ -169,6 +182,10 @@ func CreateCustomContext(opts ...ContextOption) Context {
 		baseCtx = context.Background()
 	}
 
+	if customCtx.traceID == "" {
+		customCtx.traceID = resolveTraceID(baseCtx, nil)
+	}
+
 	if customCtx.timeout > 0 {
 		customCtx.ctx, customCtx.cancel = context.WithTimeout(baseCtx, customCtx.timeout)
 	} else {
@@ -268,16 +285,14 @@ func (c *CustomContext) GetPgTxn() *orm.Query {
 	return nil
 }
 
-// CloseTxn commits or rolls back the transaction based on the error
+// CloseTxn commits or rolls back the transaction, and any named transactions, based on the error
 func (c *CustomContext) CloseTxn(err error) error {
-	query := c.GetPgTxn()
-	if query == nil {
-		return nil
-	}
-	if err != nil {
-		return query.Rollback()
-	}
-	return query.Commit()
+	return closeTxns(c, err)
+}
+
+// AfterCommit runs fn once the transaction commits, or immediately when there is none
+func (c *CustomContext) AfterCommit(fn func(ctx context.Context)) {
+	AfterCommit(c, fn)
 }
 
 // GetPgDB returns the raw PostgreSQL database connection
@@ -0,0 +1,21 @@
+package request
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCommit_RunsImmediatelyWithoutTransaction(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	defer ctx.Close(nil)
+	SetValue(ctx, localeKey, "de-DE")
+
+	var got string
+	ctx.AfterCommit(func(c context.Context) {
+		got, _ = c.Value(localeKey).(string)
+	})
+
+	assert.Equal(t, "de-DE", got, "the hook gets the request's context")
+}
@@ -6,11 +6,15 @@ import (
 	"github.com/yadunandan004/scaffold/auth"
 	"github.com/yadunandan004/scaffold/store/postgres"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	"github.com/yadunandan004/scaffold/orm"
 )
@@ -36,24 +40,21 @@ func NewApiContextForGRPC(ctx context.Context) Context {
 		},
 		ctx: ctx,
 	}
-	var claims *auth.UserClaims
-	if rawClaims, ok := ctx.Value("user_claims").(*auth.UserClaims); ok {
-		claims = rawClaims
-	}
-	if claims == nil {
-		return nil
-	}
-	grpcCtx.user = &Principal{
-		ID:             claims.UserID,
-		Email:          claims.Email,
-		ClientDeviceID: claims.ClientDeviceID,
+	// Methods exempt from auth get a context without a user
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		grpcCtx.user = NewPrincipalFromClaims(claims)
 	}
+	var carrier propagation.TextMapCarrier
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		grpcCtx.metadata = md
-		if traceIDs := md.Get("traceid"); len(traceIDs) > 0 {
-			grpcCtx.traceID = traceIDs[0]
+		carrier = metadataCarrier(md)
+		// Carry the caller's traceparent so spans and calls made from this context join its trace
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			grpcCtx.ctx = traceContext.Extract(ctx, carrier)
 		}
 	}
+	// Take the trace ID from the active span or the traceparent metadata, or start a new trace
+	grpcCtx.traceID = resolveTraceID(grpcCtx.ctx, carrier)
 	return grpcCtx
 }
 
@@ -94,6 +95,37 @@ func (ctx *GRPCCtx) GetMetadata() metadata.MD {
 	return ctx.metadata
 }
 
+// Deadline returns when the caller's deadline expires; ok is false when the call has none
+func (ctx *GRPCCtx) Deadline() (deadline time.Time, ok bool) {
+	return ctx.ctx.Deadline()
+}
+
+// Peer returns the caller's address and auth info, or nil when unknown
+func (ctx *GRPCCtx) Peer() *peer.Peer {
+	p, _ := peer.FromContext(ctx.ctx)
+	return p
+}
+
+// SetHeader adds md to the response headers, which are sent with the first response message
+func (ctx *GRPCCtx) SetHeader(md metadata.MD) error {
+	return grpc.SetHeader(ctx.ctx, md)
+}
+
+// SendHeader sends the response headers set so far, together with md
+func (ctx *GRPCCtx) SendHeader(md metadata.MD) error {
+	return grpc.SendHeader(ctx.ctx, md)
+}
+
+// SetXIDHeader returns the request's XID to the caller in the response headers
+func (ctx *GRPCCtx) SetXIDHeader() error {
+	return ctx.SetHeader(metadata.Pairs(XIDMetadataKey, ctx.xid.String()))
+}
+
+// SetTrailer adds md to the trailers sent when the call completes
+func (ctx *GRPCCtx) SetTrailer(md metadata.MD) error {
+	return grpc.SetTrailer(ctx.ctx, md)
+}
+
 // RequestContext methods - minimal implementation for gRPC request
 func (ctx *GRPCCtx) JSON(code int, obj interface{}) {
 	// Not applicable for gRPC - would use stream.Send() instead
@@ -166,6 +198,17 @@ func (ctx *GRPCCtx) TraceID() string {
 	return ctx.traceID
 }
 
+// OutgoingGRPCContext returns ctx's context with the traceparent and XID added to the outgoing
+// metadata, for calls to other services made while handling ctx
+func OutgoingGRPCContext(ctx Context) context.Context {
+	outgoing := ctx.GetCtx()
+	md, _ := metadata.FromOutgoingContext(outgoing)
+	md = md.Copy()
+	traceContext.Inject(outgoing, metadataCarrier(md))
+	md.Set(XIDMetadataKey, ctx.XID().String())
+	return metadata.NewOutgoingContext(outgoing, md)
+}
+
 // GRPCUnaryInterceptor creates a unary server interceptor that injects GRPCCtx
 func GRPCUnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -173,9 +216,10 @@ func GRPCUnaryInterceptor() grpc.UnaryServerInterceptor {
 			log.Printf("Closing grpc request with method: %s\n", info.FullMethod)
 		}()
 		grpcCtx := NewApiContextForGRPC(ctx)
+		returnXID(grpcCtx)
 
 		// Add to request
-		newCtx := context.WithValue(ctx, GRPCCtxKey, grpcCtx)
+		newCtx := context.WithValue(grpcCtx.GetCtx(), GRPCCtxKey, grpcCtx)
 
 		// Call handler with new request
 		return handler(newCtx, req)
@@ -187,11 +231,12 @@ func GRPCStreamInterceptor() grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		// Create GRPCCtx
 		grpcCtx := NewApiContextForGRPC(ss.Context())
+		returnXID(grpcCtx)
 
 		// Create wrapped stream with new request
 		wrapped := &wrappedServerStream{
 			ServerStream: ss,
-			ctx:          context.WithValue(ss.Context(), GRPCCtxKey, grpcCtx),
+			ctx:          context.WithValue(grpcCtx.GetCtx(), GRPCCtxKey, grpcCtx),
 		}
 
 		// Call handler with wrapped stream
@@ -199,6 +244,15 @@ func GRPCStreamInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
+// returnXID sets the XID response header, logging rather than failing the call when it can't
+func returnXID(ctx Context) {
+	if grpcCtx, ok := ctx.(*GRPCCtx); ok {
+		if err := grpcCtx.SetXIDHeader(); err != nil {
+			log.Printf("[GRPC] xid=%s: setting the xid header failed: %v", grpcCtx.XID(), err)
+		}
+	}
+}
+
 // wrappedServerStream wraps grpc.ServerStream to override request
 type wrappedServerStream struct {
 	grpc.ServerStream
@@ -228,16 +282,14 @@ func (ctx *GRPCCtx) GetPgDB() *sql.DB {
 	return db.DB
 }
 
-// CloseTxn commits or rolls back the transaction based on the error
+// CloseTxn commits or rolls back the transaction, and any named transactions, based on the error
 func (ctx *GRPCCtx) CloseTxn(err error) error {
-	query := ctx.GetPgTxn()
-	if query == nil {
-		return nil
-	}
-	if err != nil {
-		return query.Rollback()
-	}
-	return query.Commit()
+	return closeTxns(ctx, err)
+}
+
+// AfterCommit runs fn once the transaction commits, or immediately when there is none
+func (ctx *GRPCCtx) AfterCommit(fn func(ctx context.Context)) {
+	AfterCommit(ctx, fn)
 }
 
 // GetGRPCCtx extracts GRPCCtx from request
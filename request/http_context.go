@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/yadunandan004/scaffold/orm"
 )
@@ -21,10 +22,8 @@ func NewApiContextForHttp(c *gin.Context, opts ...HttpCtxOption) Context {
 		ginCtx: c,
 	}
 
-	// Extract TraceID from header
-	if traceID := c.GetHeader("TraceID"); traceID != "" {
-		ctx.traceID = traceID
-	}
+	// Take the trace ID from the active span or the traceparent header, or start a new trace
+	ctx.traceID = resolveTraceID(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
 
 	// Extract user info from gin request if available
 	if userID, exists := c.Get(UserIDKey.String()); exists {
@@ -135,14 +134,34 @@ func (ctx *HttpCtx) GetGinContext() *gin.Context {
 
 // InstallBufferedWriter replaces the gin writer with a buffered one to capture error responses
 func (g *HttpCtx) InstallBufferedWriter() {
+	g.InstallBufferedWriterLimit(0)
+}
+
+// InstallBufferedWriterLimit installs the buffered writer keeping at most maxBytes of the body (0 keeps all).
+// It does nothing if a buffered writer is already installed.
+func (g *HttpCtx) InstallBufferedWriterLimit(maxBytes int) {
+	if g.bufferedWriter != nil {
+		return
+	}
 	// Create a new buffered writer that wraps the existing one
 	bw := &bufferedResponseWriter{
 		ResponseWriter: g.ginCtx.Writer,
 		body:           &bytes.Buffer{},
 		statusCode:     0,
+		maxBytes:       maxBytes,
 	}
 	// Replace the writer
 	g.ginCtx.Writer = bw
+	g.bufferedWriter = bw
+}
+
+// BufferedResponse returns the captured body and the total number of body bytes written.
+// ok is false when no buffered writer is installed.
+func (g *HttpCtx) BufferedResponse() (body []byte, written int, ok bool) {
+	if g.bufferedWriter == nil {
+		return nil, 0, false
+	}
+	return g.bufferedWriter.body.Bytes(), g.bufferedWriter.written, true
 }
 
 // GetUserInfo returns the user information from request
@@ -235,14 +254,12 @@ func (ctx *HttpCtx) GetPgDB() *sql.DB {
 	return db.DB
 }
 
-// CloseTxn commits or rolls back the transaction based on the error
+// CloseTxn commits or rolls back the transaction, and any named transactions, based on the error
 func (ctx *HttpCtx) CloseTxn(err error) error {
-	query := ctx.GetPgTxn()
-	if query == nil {
-		return nil
-	}
-	if err != nil {
-		return query.Rollback()
-	}
-	return query.Commit()
+	return closeTxns(ctx, err)
+}
+
+// AfterCommit runs fn once the transaction commits, or immediately when there is none
+func (ctx *HttpCtx) AfterCommit(fn func(ctx context.Context)) {
+	AfterCommit(ctx, fn)
 }
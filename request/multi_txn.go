@@ -0,0 +1,229 @@
+package request
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yadunandan004/scaffold/orm"
+)
+
+// DefaultTxnName names the transaction opened by BeginTransaction in TxnCloseError outcomes
+const DefaultTxnName = "default"
+
+// TxnResource is a transaction-like unit of work closed together with the request's transactions,
+// such as a ClickHouse session or a transaction on another database
+type TxnResource interface {
+	Commit() error
+	Rollback() error
+}
+
+// namedTxn is a transaction opened or enlisted under a name
+type namedTxn struct {
+	name     string
+	resource TxnResource
+}
+
+// txnSet holds the named transactions of a request in the order they were opened
+type txnSet struct {
+	mu   sync.Mutex
+	txns []namedTxn
+}
+
+type txnSetKey struct{}
+
+// txnsOf returns the txnSet of ctx, attaching a new one when create is set
+func txnsOf(ctx Context, create bool) *txnSet {
+	if set, ok := ctx.GetCtx().Value(txnSetKey{}).(*txnSet); ok {
+		return set
+	}
+	if !create {
+		return nil
+	}
+	set := &txnSet{}
+	ctx.SetCtx(context.WithValue(ctx.GetCtx(), txnSetKey{}, set))
+	return set
+}
+
+func (s *txnSet) get(name string) TxnResource {
+	for _, txn := range s.txns {
+		if txn.name == name {
+			return txn.resource
+		}
+	}
+	return nil
+}
+
+// BeginNamedTransaction opens a transaction on db under name, or returns the one already open under
+// it. Use it for databases other than the sentinel, such as a node from postgres.GetDBForPartition.
+// The transaction is closed by the context's CloseTxn along with the default one.
+func BeginNamedTransaction(ctx Context, name string, db *sql.DB, opts ...TxOptions) (*orm.Query, error) {
+	if name == DefaultTxnName {
+		return nil, fmt.Errorf("transaction name %q is reserved for BeginTransaction", name)
+	}
+	if db == nil {
+		return nil, fmt.Errorf("no database connection for transaction %q", name)
+	}
+
+	set := txnsOf(ctx, true)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if existing := set.get(name); existing != nil {
+		if q, ok := existing.(*orm.Query); ok {
+			return q, nil
+		}
+		return nil, fmt.Errorf("transaction %q is not a database transaction", name)
+	}
+
+	txOpts := &sql.TxOptions{}
+	if len(opts) > 0 && opts[0].ReadOnly {
+		txOpts.ReadOnly = true
+	}
+	sqlTx, err := db.BeginTx(ctx.GetCtx(), txOpts)
+	if err != nil {
+		return nil, err
+	}
+	query := &orm.Query{
+		Ctx:     ctx.GetCtx(),
+		Txn:     sqlTx,
+		Scanner: &orm.RawScanner{},
+	}
+	set.txns = append(set.txns, namedTxn{name: name, resource: query})
+	return query, nil
+}
+
+// EnlistTxn adds resource under name to the transactions closed by the context's CloseTxn
+func EnlistTxn(ctx Context, name string, resource TxnResource) error {
+	if name == DefaultTxnName {
+		return fmt.Errorf("transaction name %q is reserved for BeginTransaction", name)
+	}
+	set := txnsOf(ctx, true)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if set.get(name) != nil {
+		return fmt.Errorf("transaction %q is already open", name)
+	}
+	set.txns = append(set.txns, namedTxn{name: name, resource: resource})
+	return nil
+}
+
+// GetNamedQuery returns the Query of the transaction opened under name, or nil
+func GetNamedQuery(ctx Context, name string) *orm.Query {
+	if name == DefaultTxnName {
+		return GetQuery(ctx)
+	}
+	set := txnsOf(ctx, false)
+	if set == nil {
+		return nil
+	}
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	q, _ := set.get(name).(*orm.Query)
+	return q
+}
+
+// TxnOutcome is how one transaction was closed
+type TxnOutcome struct {
+	Name      string
+	Committed bool
+	// Err is the error committing or rolling back the transaction
+	Err error
+}
+
+// TxnCloseError reports the outcome of every transaction when closing a request with named
+// transactions failed. Commits aren't atomic across databases: Partial reports whether some
+// transactions committed before one failed.
+type TxnCloseError struct {
+	Outcomes []TxnOutcome
+}
+
+func (e *TxnCloseError) Error() string {
+	var failed []string
+	for _, outcome := range e.Outcomes {
+		if outcome.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", outcome.Name, outcome.Err))
+		}
+	}
+	msg := "failed to close transactions: " + strings.Join(failed, "; ")
+	if e.Partial() {
+		msg += " (" + strings.Join(e.Committed(), ", ") + " committed)"
+	}
+	return msg
+}
+
+// Unwrap returns the errors of the failed transactions
+func (e *TxnCloseError) Unwrap() []error {
+	var errs []error
+	for _, outcome := range e.Outcomes {
+		if outcome.Err != nil {
+			errs = append(errs, outcome.Err)
+		}
+	}
+	return errs
+}
+
+// Committed returns the names of the transactions that committed
+func (e *TxnCloseError) Committed() []string {
+	var names []string
+	for _, outcome := range e.Outcomes {
+		if outcome.Committed {
+			names = append(names, outcome.Name)
+		}
+	}
+	return names
+}
+
+// Partial reports whether some transactions committed and others didn't
+func (e *TxnCloseError) Partial() bool {
+	return len(e.Committed()) > 0 && len(e.Committed()) < len(e.Outcomes)
+}
+
+// closeTxns closes the default transaction of ctx, then its named transactions in the order they were
+// opened. When err is nil they are committed until one fails, and the rest are rolled back; otherwise
+// all are rolled back. With only the default transaction its error is returned as is, else a
+// *TxnCloseError when any failed.
+func closeTxns(ctx Context, err error) error {
+	var txns []namedTxn
+	if query := GetQuery(ctx); query != nil {
+		txns = append(txns, namedTxn{name: DefaultTxnName, resource: query})
+	}
+	if set := txnsOf(ctx, false); set != nil {
+		set.mu.Lock()
+		txns = append(txns, set.txns...)
+		set.txns = nil
+		set.mu.Unlock()
+	}
+
+	if len(txns) == 1 && txns[0].name == DefaultTxnName {
+		if err != nil {
+			return txns[0].resource.Rollback()
+		}
+		return txns[0].resource.Commit()
+	}
+
+	outcomes := make([]TxnOutcome, len(txns))
+	failed := false
+	commit := err == nil
+	for i, txn := range txns {
+		outcomes[i].Name = txn.name
+		if commit {
+			if outcomes[i].Err = txn.resource.Commit(); outcomes[i].Err != nil {
+				failed, commit = true, false
+				continue
+			}
+			outcomes[i].Committed = true
+			continue
+		}
+		if rbErr := txn.resource.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			outcomes[i].Err = rbErr
+			failed = true
+		}
+	}
+	if failed {
+		return &TxnCloseError{Outcomes: outcomes}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTxn struct {
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (f *fakeTxn) Commit() error {
+	if f.commitErr != nil {
+		return f.commitErr
+	}
+	f.committed = true
+	return nil
+}
+
+func (f *fakeTxn) Rollback() error {
+	f.rolledBack = true
+	return nil
+}
+
+func TestCloseTxn_CommitsAllNamed(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	primary, analytics := &fakeTxn{}, &fakeTxn{}
+	require.NoError(t, EnlistTxn(ctx, "primary", primary))
+	require.NoError(t, EnlistTxn(ctx, "analytics", analytics))
+
+	require.NoError(t, ctx.Close(nil))
+
+	assert.True(t, primary.committed)
+	assert.True(t, analytics.committed)
+	assert.NoError(t, ctx.CloseTxn(nil), "closed transactions aren't closed again")
+}
+
+func TestCloseTxn_RollsBackAllOnError(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	primary, analytics := &fakeTxn{}, &fakeTxn{}
+	require.NoError(t, EnlistTxn(ctx, "primary", primary))
+	require.NoError(t, EnlistTxn(ctx, "analytics", analytics))
+
+	require.NoError(t, ctx.Close(errors.New("handler failed")))
+
+	assert.True(t, primary.rolledBack)
+	assert.True(t, analytics.rolledBack)
+	assert.False(t, primary.committed)
+}
+
+func TestCloseTxn_ReportsPartialFailure(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	commitErr := errors.New("connection lost")
+	primary, node, analytics := &fakeTxn{}, &fakeTxn{commitErr: commitErr}, &fakeTxn{}
+	require.NoError(t, EnlistTxn(ctx, "primary", primary))
+	require.NoError(t, EnlistTxn(ctx, "node", node))
+	require.NoError(t, EnlistTxn(ctx, "analytics", analytics))
+
+	err := ctx.Close(nil)
+
+	var closeErr *TxnCloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.ErrorIs(t, err, commitErr)
+	assert.True(t, closeErr.Partial())
+	assert.Equal(t, []string{"primary"}, closeErr.Committed())
+	assert.True(t, analytics.rolledBack, "transactions after the failed commit are rolled back")
+	assert.Contains(t, err.Error(), "node: connection lost")
+}
+
+func TestEnlistTxn_Names(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	defer ctx.Close(nil)
+
+	assert.Error(t, EnlistTxn(ctx, DefaultTxnName, &fakeTxn{}))
+	require.NoError(t, EnlistTxn(ctx, "analytics", &fakeTxn{}))
+	assert.Error(t, EnlistTxn(ctx, "analytics", &fakeTxn{}))
+	assert.Nil(t, GetNamedQuery(ctx, "analytics"), "an enlisted resource isn't a Query")
+
+	_, err := BeginNamedTransaction(ctx, "node", nil)
+	assert.Error(t, err)
+}
@@ -36,6 +36,15 @@ func WithTestReadWriteTxn() TestContextOption {
 	}
 }
 
+// WithTestTenant sets the tenant of the test user
+func WithTestTenant(tenantID string) TestContextOption {
+	return func(ctx *TestContext) {
+		if ctx.user != nil {
+			ctx.user.TenantID = tenantID
+		}
+	}
+}
+
 // NewTestContext creates a new test request with a database transaction
 func NewTestContext(opts ...TestContextOption) *TestContext {
 	ctx := context.Background()
@@ -170,16 +179,14 @@ func (ctx *TestContext) GetPgDB() *sql.DB {
 	return db.DB
 }
 
-// CloseTxn commits or rolls back the transaction based on the error
+// CloseTxn commits or rolls back the transaction, and any named transactions, based on the error
 func (ctx *TestContext) CloseTxn(err error) error {
-	query := ctx.GetPgTxn()
-	if query == nil {
-		return nil
-	}
-	if err != nil {
-		return query.Rollback()
-	}
-	return query.Commit()
+	return closeTxns(ctx, err)
+}
+
+// AfterCommit runs fn once the transaction commits, or immediately when there is none
+func (ctx *TestContext) AfterCommit(fn func(ctx context.Context)) {
+	AfterCommit(ctx, fn)
 }
 
 // Rollback rolls back the current transaction
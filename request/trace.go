@@ -0,0 +1,63 @@
+package request
+
+import (
+	"context"
+	"crypto/rand"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// traceContext reads and writes the W3C traceparent and tracestate headers
+var traceContext = propagation.TraceContext{}
+
+// SpanContext returns the span active in ctx, which is invalid when there is none
+func SpanContext(ctx Context) trace.SpanContext {
+	if ctx == nil {
+		return trace.SpanContext{}
+	}
+	return trace.SpanContextFromContext(ctx.GetCtx())
+}
+
+// resolveTraceID returns the trace ID of the span active in ctx, else of the traceparent carried by
+// the incoming request, else a new random one in the same format
+func resolveTraceID(ctx context.Context, carrier propagation.TextMapCarrier) string {
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		return spanCtx.TraceID().String()
+	}
+	if carrier != nil {
+		if spanCtx := trace.SpanContextFromContext(traceContext.Extract(context.Background(), carrier)); spanCtx.HasTraceID() {
+			return spanCtx.TraceID().String()
+		}
+	}
+	return newTraceID()
+}
+
+func newTraceID() string {
+	var id trace.TraceID
+	_, _ = rand.Read(id[:])
+	return id.String()
+}
+
+// metadataCarrier lets the propagator read gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,55 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestNewApiContextForHttp_TraceparentHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request, _ = http.NewRequest("GET", "/", nil)
+	ginCtx.Request.Header.Set("traceparent", traceparent)
+
+	ctx := NewApiContextForHttp(ginCtx)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ctx.TraceID())
+}
+
+func TestNewApiContextForGRPC_TraceparentMetadata(t *testing.T) {
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+
+	ctx := NewApiContextForGRPC(incoming)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ctx.TraceID())
+}
+
+func TestResolveTraceID_PrefersActiveSpan(t *testing.T) {
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	})
+	active := trace.ContextWithSpanContext(context.Background(), spanCtx)
+	header := propagation.HeaderCarrier(http.Header{"Traceparent": []string{traceparent}})
+
+	assert.Equal(t, spanCtx.TraceID().String(), resolveTraceID(active, header))
+}
+
+func TestResolveTraceID_GeneratesWhenAbsent(t *testing.T) {
+	first := resolveTraceID(context.Background(), nil)
+	second := resolveTraceID(context.Background(), propagation.HeaderCarrier(http.Header{}))
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+	assert.NotEmpty(t, CreateCustomContext().TraceID())
+}
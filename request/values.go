@@ -0,0 +1,35 @@
+package request
+
+import "context"
+
+// Key identifies a request-scoped value of type T. Keys compare by identity, so two keys never
+// collide even with the same name; declare each one once as a package-level variable.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new key for values of type T; name is only used for debugging
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key's name
+func (k *Key[T]) String() string {
+	return "request.Key(" + k.name + ")"
+}
+
+// SetValue stores v under key on ctx, so later GetValue calls with ctx, such as in the service and
+// repository layers, see it. Setting a key again replaces its value.
+func SetValue[T any](ctx Context, key *Key[T], v T) {
+	ctx.SetCtx(context.WithValue(ctx.GetCtx(), key, v))
+}
+
+// GetValue returns the value stored under key on ctx, and whether there was one
+func GetValue[T any](ctx Context, key *Key[T]) (T, bool) {
+	if ctx == nil {
+		var zero T
+		return zero, false
+	}
+	v, ok := ctx.GetCtx().Value(key).(T)
+	return v, ok
+}
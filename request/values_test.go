@@ -0,0 +1,69 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type featureFlags struct {
+	Beta bool
+}
+
+var (
+	localeKey = NewKey[string]("locale")
+	flagsKey  = NewKey[*featureFlags]("flags")
+)
+
+func TestValues_SetAndGet(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	defer ctx.Close(nil)
+
+	_, ok := GetValue(ctx, localeKey)
+	assert.False(t, ok)
+
+	SetValue(ctx, localeKey, "de-DE")
+	SetValue(ctx, flagsKey, &featureFlags{Beta: true})
+
+	locale, ok := GetValue(ctx, localeKey)
+	assert.True(t, ok)
+	assert.Equal(t, "de-DE", locale)
+	flags, ok := GetValue(ctx, flagsKey)
+	assert.True(t, ok)
+	assert.True(t, flags.Beta)
+
+	SetValue(ctx, localeKey, "fr-FR")
+	locale, _ = GetValue(ctx, localeKey)
+	assert.Equal(t, "fr-FR", locale)
+}
+
+func TestValues_KeysWithSameNameDontCollide(t *testing.T) {
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{})
+	defer ctx.Close(nil)
+	other := NewKey[string]("locale")
+
+	SetValue(ctx, localeKey, "de-DE")
+
+	_, ok := GetValue(ctx, other)
+	assert.False(t, ok)
+}
+
+func TestValues_HttpContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request, _ = http.NewRequest("GET", "/", nil)
+	ctx := NewApiContextForHttp(ginCtx)
+
+	SetValue(ctx, localeKey, "de-DE")
+
+	// A context built later for the same request, as a handler would, sees the value
+	locale, ok := GetValue(NewApiContextForHttp(ginCtx), localeKey)
+	assert.True(t, ok)
+	assert.Equal(t, "de-DE", locale)
+	_, ok = GetValue(nil, localeKey)
+	assert.False(t, ok)
+}
@@ -0,0 +1,172 @@
+package request
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/yadunandan004/scaffold/orm"
+	"github.com/yadunandan004/scaffold/store/postgres"
+)
+
+// SystemPrincipalName is the display name and client ID of SystemPrincipal
+const SystemPrincipalName = "system"
+
+// SystemPrincipal returns the synthetic principal for work done on behalf of the service itself
+func SystemPrincipal() *Principal {
+	return &Principal{
+		DisplayName: SystemPrincipalName,
+		Type:        PrincipalService,
+		ClientID:    SystemPrincipalName,
+	}
+}
+
+// WorkerOptions configures NewWorkerContext
+type WorkerOptions struct {
+	// Principal is who the work is done for; nil runs without one, SystemPrincipal() as the service
+	Principal *Principal
+	// Timeout cancels the context after this long; 0 leaves it to Deadline and the parent
+	Timeout time.Duration
+	// Deadline cancels the context at this time; zero leaves it to Timeout and the parent
+	Deadline time.Time
+	// TraceID joins the work to an existing trace; by default it is taken from the parent's span or generated
+	TraceID string
+}
+
+// WorkerCtx is the request.Context of a background job or CLI command
+type WorkerCtx struct {
+	BaseCtx
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Ensure WorkerCtx satisfies the Context interface
+var _ Context = (*WorkerCtx)(nil)
+
+// NewWorkerContext creates the context for a background job or CLI command, with a new XID, derived
+// from parent so cancelling parent stops the work. Close it when the work is done.
+func NewWorkerContext(parent context.Context, opts WorkerOptions) *WorkerCtx {
+	if parent == nil {
+		parent = context.Background()
+	}
+	w := &WorkerCtx{
+		BaseCtx: BaseCtx{
+			xid:     uuid.New(),
+			traceID: opts.TraceID,
+			user:    opts.Principal,
+		},
+	}
+	if w.traceID == "" {
+		w.traceID = resolveTraceID(parent, nil)
+	}
+
+	deadline := opts.Deadline
+	if opts.Timeout > 0 {
+		if timeoutAt := time.Now().Add(opts.Timeout); deadline.IsZero() || timeoutAt.Before(deadline) {
+			deadline = timeoutAt
+		}
+	}
+	if deadline.IsZero() {
+		w.ctx, w.cancel = context.WithCancel(parent)
+	} else {
+		w.ctx, w.cancel = context.WithDeadline(parent, deadline)
+	}
+	return w
+}
+
+// BeginTxn opens a transaction on the context, or returns the one already open
+func (w *WorkerCtx) BeginTxn(opts ...TxOptions) (*orm.Query, error) {
+	return BeginTransaction(w, opts...)
+}
+
+// Close ends the work: an open transaction is committed when err is nil and rolled back otherwise,
+// then the context is cancelled. It returns the error closing the transaction.
+func (w *WorkerCtx) Close(err error) error {
+	defer w.cancel()
+	return w.CloseTxn(err)
+}
+
+// Cancel cancels the context without closing the transaction
+func (w *WorkerCtx) Cancel() {
+	w.cancel()
+}
+
+// GetRequestContext returns a minimal RequestContext, as a worker has no request
+func (w *WorkerCtx) GetRequestContext() RequestContext {
+	return &customRequestContext{ctx: w.ctx}
+}
+
+// GetUserInfo returns the principal the work is done for, or nil
+func (w *WorkerCtx) GetUserInfo() *Principal {
+	return w.user
+}
+
+// XID returns the work's ID
+func (w *WorkerCtx) XID() uuid.UUID {
+	return w.xid
+}
+
+// TraceID returns the trace ID
+func (w *WorkerCtx) TraceID() string {
+	return w.traceID
+}
+
+// GetCtx returns the underlying context
+func (w *WorkerCtx) GetCtx() context.Context {
+	return w.ctx
+}
+
+// SetCtx replaces the underlying context (for transaction storage)
+func (w *WorkerCtx) SetCtx(ctx context.Context) {
+	w.ctx = ctx
+}
+
+// GetPgTxn returns the PostgreSQL Query object from context
+func (w *WorkerCtx) GetPgTxn() *orm.Query {
+	if val := w.ctx.Value(QueryKey{}); val != nil {
+		if q, ok := val.(*orm.Query); ok {
+			return q
+		}
+	}
+	return nil
+}
+
+// GetPgDB returns the raw PostgreSQL database connection
+func (w *WorkerCtx) GetPgDB() *sql.DB {
+	db := postgres.GetDB()
+	if db == nil {
+		return nil
+	}
+	return db.DB
+}
+
+// CloseTxn commits or rolls back the transaction, and any named transactions, based on the error
+func (w *WorkerCtx) CloseTxn(err error) error {
+	return closeTxns(w, err)
+}
+
+// AfterCommit runs fn once the transaction commits, or immediately when there is none
+func (w *WorkerCtx) AfterCommit(fn func(ctx context.Context)) {
+	AfterCommit(w, fn)
+}
+
+// GetGinContext returns nil, as a worker has no HTTP request
+func (w *WorkerCtx) GetGinContext() *gin.Context {
+	return nil
+}
+
+// SetPathParams is a no-op for workers
+func (w *WorkerCtx) SetPathParams(params map[string]string) {
+}
+
+// GetPathParam returns "" for workers
+func (w *WorkerCtx) GetPathParam(name string) string {
+	return ""
+}
+
+// JSON is a no-op for workers
+func (w *WorkerCtx) JSON(code int, obj interface{}) {
+}
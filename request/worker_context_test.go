@@ -0,0 +1,66 @@
+package request
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewWorkerContext_Identity(t *testing.T) {
+	first := NewWorkerContext(context.Background(), WorkerOptions{})
+	defer first.Close(nil)
+	second := NewWorkerContext(context.Background(), WorkerOptions{Principal: SystemPrincipal()})
+	defer second.Close(nil)
+
+	assert.NotEqual(t, uuid.Nil, first.XID())
+	assert.NotEqual(t, first.XID(), second.XID())
+	assert.Len(t, first.TraceID(), 32)
+	assert.Nil(t, first.GetUserInfo())
+	assert.Equal(t, PrincipalService, second.GetUserInfo().Type)
+	assert.Equal(t, SystemPrincipalName, second.GetUserInfo().DisplayName)
+}
+
+func TestNewWorkerContext_TraceFromParentSpan(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	ctx := NewWorkerContext(parent, WorkerOptions{})
+	defer ctx.Close(nil)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ctx.TraceID())
+
+	explicit := NewWorkerContext(parent, WorkerOptions{TraceID: "batch-42"})
+	defer explicit.Close(nil)
+	assert.Equal(t, "batch-42", explicit.TraceID())
+}
+
+func TestNewWorkerContext_Deadline(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	ctx := NewWorkerContext(context.Background(), WorkerOptions{Deadline: deadline, Timeout: time.Minute})
+	defer ctx.Close(nil)
+
+	got, ok := ctx.GetCtx().Deadline()
+	assert.True(t, ok)
+	assert.True(t, got.Before(deadline), "the earlier of Timeout and Deadline applies")
+
+	unbounded := NewWorkerContext(context.Background(), WorkerOptions{})
+	defer unbounded.Close(nil)
+	_, ok = unbounded.GetCtx().Deadline()
+	assert.False(t, ok)
+}
+
+func TestWorkerContext_Cancellation(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx := NewWorkerContext(parent, WorkerOptions{})
+	cancel()
+	assert.Error(t, ctx.GetCtx().Err(), "cancelling the parent stops the work")
+
+	closed := NewWorkerContext(context.Background(), WorkerOptions{})
+	assert.NoError(t, closed.Close(nil), "closing without a transaction is a no-op")
+	assert.Error(t, closed.GetCtx().Err())
+}
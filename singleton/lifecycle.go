@@ -0,0 +1,94 @@
+package singleton
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Startable is implemented by injected components that need starting once built, such as a worker
+// pool or a cache warmer
+type Startable interface {
+	Start(ctx context.Context) error
+}
+
+// Stoppable is implemented by injected components that release resources on shutdown
+type Stoppable interface {
+	Stop(ctx context.Context) error
+}
+
+// lifecycleState records instances in the order their builds finished. A builder injecting its
+// dependencies finishes after them, so this order starts dependencies first.
+type lifecycleState struct {
+	mu        sync.Mutex
+	instances []any
+	// started counts the leading instances already started
+	started int
+	running []any
+}
+
+var lifecycle = &lifecycleState{}
+
+func (l *lifecycleState) built(instance any) {
+	if instance == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.instances = append(l.instances, instance)
+}
+
+// Start builds every registered singleton that hasn't been built yet, then starts the Startable ones
+// in dependency order. Components already started are skipped, so Start may be called again after
+// more are registered. When one fails, those started by this call are stopped and the error returned.
+func Start(ctx context.Context) error {
+	singletons.Range(func(_, value any) bool {
+		value.(*SingletonEntry).get()
+		return true
+	})
+
+	lifecycle.mu.Lock()
+	pending := append([]any(nil), lifecycle.instances[lifecycle.started:]...)
+	lifecycle.mu.Unlock()
+
+	for i, instance := range pending {
+		if startable, ok := instance.(Startable); ok {
+			if err := startable.Start(ctx); err != nil {
+				_ = stopAll(ctx, pending[:i])
+				return fmt.Errorf("start %T: %w", instance, err)
+			}
+		}
+	}
+
+	lifecycle.mu.Lock()
+	lifecycle.started += len(pending)
+	lifecycle.running = append(lifecycle.running, pending...)
+	lifecycle.mu.Unlock()
+	return nil
+}
+
+// Stop stops started components implementing Stoppable in reverse start order, so each stops before
+// the dependencies it uses. Every component is asked to stop; the first error is returned.
+func Stop(ctx context.Context) error {
+	lifecycle.mu.Lock()
+	running := lifecycle.running
+	lifecycle.running = nil
+	lifecycle.started = 0
+	lifecycle.mu.Unlock()
+
+	return stopAll(ctx, running)
+}
+
+func stopAll(ctx context.Context, instances []any) error {
+	var firstErr error
+	for i := len(instances) - 1; i >= 0; i-- {
+		stoppable, ok := instances[i].(Stoppable)
+		if !ok {
+			continue
+		}
+		if err := stoppable.Stop(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("stop %T: %w", instances[i], err)
+		}
+	}
+	return firstErr
+}
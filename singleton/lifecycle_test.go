@@ -0,0 +1,100 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var lifecycleEvents []string
+
+type database struct{}
+
+func (*database) Start(ctx context.Context) error {
+	lifecycleEvents = append(lifecycleEvents, "start database")
+	return nil
+}
+
+func (*database) Stop(ctx context.Context) error {
+	lifecycleEvents = append(lifecycleEvents, "stop database")
+	return nil
+}
+
+type databaseBuilder struct{}
+
+func (databaseBuilder) Build() *database { return &database{} }
+
+// consumer depends on database, so it starts after it and stops before it
+type consumer struct {
+	db *database
+}
+
+func (*consumer) Start(ctx context.Context) error {
+	lifecycleEvents = append(lifecycleEvents, "start consumer")
+	return nil
+}
+
+func (*consumer) Stop(ctx context.Context) error {
+	lifecycleEvents = append(lifecycleEvents, "stop consumer")
+	return nil
+}
+
+type consumerBuilder struct{}
+
+func (consumerBuilder) Build() *consumer {
+	return &consumer{db: Inject[databaseBuilder, *database]()}
+}
+
+func TestStartStop_FollowsDependencyOrder(t *testing.T) {
+	lifecycleEvents = nil
+	Inject[consumerBuilder, *consumer]()
+
+	require.NoError(t, Start(context.Background()))
+	assert.Equal(t, []string{"start database", "start consumer"}, lifecycleEvents)
+
+	// Already started components aren't started again
+	require.NoError(t, Start(context.Background()))
+	assert.Len(t, lifecycleEvents, 2)
+
+	require.NoError(t, Stop(context.Background()))
+	assert.Equal(t, []string{"start database", "start consumer", "stop consumer", "stop database"}, lifecycleEvents)
+}
+
+// brokenComponent fails to start while failStart is set
+type brokenComponent struct{}
+
+var failStart bool
+
+func (*brokenComponent) Start(ctx context.Context) error {
+	if failStart {
+		return errors.New("port in use")
+	}
+	return nil
+}
+
+type brokenBuilder struct{}
+
+func (brokenBuilder) Build() *brokenComponent { return &brokenComponent{} }
+
+func TestStart_StopsStartedComponentsOnFailure(t *testing.T) {
+	Inject[consumerBuilder, *consumer]()
+	require.NoError(t, Stop(context.Background()))
+	lifecycleEvents = nil
+
+	failStart = true
+	t.Cleanup(func() { failStart = false })
+
+	// Registered but not yet built components are built by Start, here after the consumer
+	Register[brokenBuilder, *brokenComponent]("broken")
+	err := Start(context.Background())
+	assert.ErrorContains(t, err, "port in use")
+	assert.Equal(t, []string{"start database", "start consumer", "stop consumer", "stop database"}, lifecycleEvents)
+
+	// Nothing is left running to stop
+	lifecycleEvents = nil
+	require.NoError(t, Stop(context.Background()))
+	assert.Empty(t, lifecycleEvents)
+}
@@ -0,0 +1,90 @@
+package singleton
+
+import (
+	"reflect"
+	"sync"
+)
+
+// bindingSet holds the named builders registered for one type, in registration order
+type bindingSet struct {
+	mu      sync.RWMutex
+	names   []string
+	entries map[string]*SingletonEntry
+}
+
+var bindings sync.Map
+
+func bindingsFor[T any]() *bindingSet {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	set, _ := bindings.LoadOrStore(typ, &bindingSet{entries: make(map[string]*SingletonEntry)})
+	return set.(*bindingSet)
+}
+
+// Register binds builder B to T under name, so several implementations of one interface can live side
+// by side, such as a "local" and a "redis" cache.CacheService. The instance is built on first use and is
+// the same one Inject[B, T] returns. Registering a name again replaces its builder.
+func Register[B Builder[T], T any](name string) {
+	builder := *new(B)
+	builderType := reflect.TypeOf(builder)
+	entry, _ := singletons.LoadOrStore(builderType, &SingletonEntry{
+		builder: &genericBuilderWrapper[T]{builder: builder},
+	})
+
+	set := bindingsFor[T]()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if _, exists := set.entries[name]; !exists {
+		set.names = append(set.names, name)
+	}
+	set.entries[name] = entry.(*SingletonEntry)
+}
+
+// InjectNamed returns the T registered under name, building it on first use, and false when no
+// builder is registered under that name
+func InjectNamed[T any](name string) (T, bool) {
+	if impl, ok := overridden[T](); ok {
+		return impl, true
+	}
+	set := bindingsFor[T]()
+	set.mu.RLock()
+	entry, ok := set.entries[name]
+	set.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return build[T](entry), true
+}
+
+// InjectAll returns every T registered with Register, built on first use, in registration order
+func InjectAll[T any]() []T {
+	if impl, ok := overridden[T](); ok {
+		return []T{impl}
+	}
+	set := bindingsFor[T]()
+	set.mu.RLock()
+	entries := make([]*SingletonEntry, len(set.names))
+	for i, name := range set.names {
+		entries[i] = set.entries[name]
+	}
+	set.mu.RUnlock()
+
+	instances := make([]T, len(entries))
+	for i, entry := range entries {
+		instances[i] = build[T](entry)
+	}
+	return instances
+}
+
+// Names returns the names registered for T in registration order
+func Names[T any]() []string {
+	set := bindingsFor[T]()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return append([]string(nil), set.names...)
+}
+
+func build[T any](entry *SingletonEntry) T {
+	instance, _ := entry.get().(T)
+	return instance
+}
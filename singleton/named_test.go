@@ -0,0 +1,48 @@
+package singleton
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type store interface {
+	Name() string
+}
+
+type memoryStore struct{}
+
+func (*memoryStore) Name() string { return "memory" }
+
+type diskStore struct{}
+
+func (*diskStore) Name() string { return "disk" }
+
+type memoryStoreBuilder struct{}
+
+func (memoryStoreBuilder) Build() store { return &memoryStore{} }
+
+type diskStoreBuilder struct{}
+
+func (diskStoreBuilder) Build() store { return &diskStore{} }
+
+func TestRegister_ResolvesByName(t *testing.T) {
+	Register[memoryStoreBuilder, store]("memory")
+	Register[diskStoreBuilder, store]("disk")
+
+	memory, ok := InjectNamed[store]("memory")
+	assert.True(t, ok)
+	assert.Equal(t, "memory", memory.Name())
+
+	again, _ := InjectNamed[store]("memory")
+	assert.Same(t, memory, again)
+	assert.Same(t, memory, Inject[memoryStoreBuilder, store](), "named and builder injection share the instance")
+
+	_, ok = InjectNamed[store]("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"memory", "disk"}, Names[store]())
+	all := InjectAll[store]()
+	assert.Len(t, all, 2)
+	assert.Equal(t, "disk", all[1].Name())
+}
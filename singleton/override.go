@@ -0,0 +1,58 @@
+package singleton
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	overrides     = make(map[reflect.Type]any)
+	overridesLock sync.RWMutex
+)
+
+// Override makes every injection of T return impl, whichever builder or name is asked for, until
+// the returned restore func runs. Overrides nest: restoring brings back the previous one.
+func Override[T any](impl T) (restore func()) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	overridesLock.Lock()
+	previous, hadPrevious := overrides[typ]
+	overrides[typ] = impl
+	overridesLock.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			overridesLock.Lock()
+			defer overridesLock.Unlock()
+			if hadPrevious {
+				overrides[typ] = previous
+			} else {
+				delete(overrides, typ)
+			}
+		})
+	}
+}
+
+// Cleaner is the part of testing.TB OverrideForTest needs
+type Cleaner interface {
+	Cleanup(func())
+}
+
+// OverrideForTest overrides T with impl until tb and its subtests finish, e.g.
+// singleton.OverrideForTest[cache.CacheService](t, fakeCache)
+func OverrideForTest[T any](tb Cleaner, impl T) {
+	tb.Cleanup(Override(impl))
+}
+
+// overridden returns the override for T, if any
+func overridden[T any]() (T, bool) {
+	overridesLock.RLock()
+	defer overridesLock.RUnlock()
+	impl, ok := overrides[reflect.TypeOf((*T)(nil)).Elem()]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return impl.(T), true
+}
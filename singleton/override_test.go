@@ -0,0 +1,47 @@
+package singleton
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct{}
+
+func (*fakeStore) Name() string { return "fake" }
+
+func TestOverride_ReplacesEveryInjection(t *testing.T) {
+	Register[memoryStoreBuilder, store]("memory")
+	fake := &fakeStore{}
+
+	restore := Override[store](fake)
+	assert.Same(t, fake, Inject[memoryStoreBuilder, store]())
+	named, ok := InjectNamed[store]("anything")
+	assert.True(t, ok)
+	assert.Same(t, fake, named)
+	assert.Equal(t, []store{fake}, InjectAll[store]())
+
+	restore()
+	assert.Equal(t, "memory", Inject[memoryStoreBuilder, store]().Name())
+}
+
+func TestOverride_Nests(t *testing.T) {
+	outer, inner := &fakeStore{}, &fakeStore{}
+	restoreOuter := Override[store](outer)
+	defer restoreOuter()
+
+	restoreInner := Override[store](inner)
+	assert.Same(t, inner, Inject[memoryStoreBuilder, store]())
+	restoreInner()
+	restoreInner()
+	assert.Same(t, outer, Inject[memoryStoreBuilder, store]())
+}
+
+func TestOverrideForTest_RestoresOnCleanup(t *testing.T) {
+	fake := &auditTrail{name: "fake"}
+	t.Run("overridden", func(t *testing.T) {
+		OverrideForTest[*auditTrail](t, fake)
+		assert.Same(t, fake, InjectScoped[auditTrailBuilder](newTestContext()))
+	})
+	assert.NotSame(t, fake, InjectScoped[auditTrailBuilder](newTestContext()))
+}
@@ -0,0 +1,124 @@
+package singleton
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Context is what a scope needs from a request context; request.Context satisfies it
+type Context interface {
+	GetCtx() context.Context
+	SetCtx(ctx context.Context)
+}
+
+// ScopedBuilder builds a value once per request scope, from that request's context C, typically
+// request.Context, which gives the builder the caller's Principal and transaction.
+type ScopedBuilder[C Context, U any] interface {
+	BuildScoped(ctx C) U
+}
+
+// Scope is a child container holding the values built for one request. Values implementing io.Closer
+// are closed, newest first, when the scope closes. Singletons are still resolved with Inject.
+type Scope struct {
+	ctx     Context
+	mu      sync.Mutex
+	entries map[reflect.Type]*scopedEntry
+	built   []any
+	closed  bool
+}
+
+type scopedEntry struct {
+	instance any
+	once     sync.Once
+}
+
+type scopeKey struct{}
+
+// BeginScope creates a scope for ctx and attaches it, so InjectScoped calls with ctx share its values.
+// The registry begins one for every route and gRPC call; other callers must Close the scope they begin.
+func BeginScope(ctx Context) *Scope {
+	scope := &Scope{
+		ctx:     ctx,
+		entries: make(map[reflect.Type]*scopedEntry),
+	}
+	ctx.SetCtx(context.WithValue(ctx.GetCtx(), scopeKey{}, scope))
+	return scope
+}
+
+// ScopeFrom returns the scope attached to ctx, or nil when there is none
+func ScopeFrom(ctx Context) *Scope {
+	scope, _ := ctx.GetCtx().Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// InjectScoped returns the value B builds for ctx's scope, building it on first use. Without an open
+// scope the value is built for this call only. ctx must be of the type B's BuildScoped takes.
+func InjectScoped[B ScopedBuilder[C, T], C Context, T any](ctx Context) T {
+	if impl, ok := overridden[T](); ok {
+		return impl
+	}
+	builder := *new(B)
+	callCtx, ok := ctx.(C)
+	if !ok {
+		panic(fmt.Sprintf("singleton: %T can't build from a %T", builder, ctx))
+	}
+	scope := ScopeFrom(ctx)
+	if scope == nil {
+		return builder.BuildScoped(callCtx)
+	}
+
+	builderType := reflect.TypeOf(builder)
+	scope.mu.Lock()
+	if scope.closed {
+		scope.mu.Unlock()
+		return builder.BuildScoped(callCtx)
+	}
+	entry, ok := scope.entries[builderType]
+	if !ok {
+		entry = &scopedEntry{}
+		scope.entries[builderType] = entry
+	}
+	scope.mu.Unlock()
+
+	// Built outside the lock so a builder can inject the scoped values it depends on
+	entry.once.Do(func() {
+		buildCtx, ok := scope.ctx.(C)
+		if !ok {
+			buildCtx = callCtx
+		}
+		instance := builder.BuildScoped(buildCtx)
+		entry.instance = instance
+		scope.mu.Lock()
+		scope.built = append(scope.built, instance)
+		scope.mu.Unlock()
+	})
+	instance, _ := entry.instance.(T)
+	return instance
+}
+
+// Close closes the scope's values that implement io.Closer, in reverse build order, and returns the
+// first error. Later InjectScoped calls build unscoped values.
+func (s *Scope) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	built := s.built
+	s.built = nil
+	s.mu.Unlock()
+
+	var firstErr error
+	for i := len(built) - 1; i >= 0; i-- {
+		if closer, ok := built[i].(io.Closer); ok {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,100 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testContext stands in for request.Context, which singleton can't import
+type testContext struct {
+	ctx context.Context
+}
+
+func newTestContext() *testContext {
+	return &testContext{ctx: context.Background()}
+}
+
+func (c *testContext) GetCtx() context.Context    { return c.ctx }
+func (c *testContext) SetCtx(ctx context.Context) { c.ctx = ctx }
+
+type auditTrail struct {
+	ctx    *testContext
+	closed *[]string
+	name   string
+}
+
+func (a *auditTrail) Close() error {
+	*a.closed = append(*a.closed, a.name)
+	return nil
+}
+
+var closedTrails []string
+
+type auditTrailBuilder struct{}
+
+func (auditTrailBuilder) BuildScoped(ctx *testContext) *auditTrail {
+	return &auditTrail{ctx: ctx, closed: &closedTrails, name: "audit"}
+}
+
+// reportBuilder depends on the scoped audit trail
+type reportBuilder struct{}
+
+func (reportBuilder) BuildScoped(ctx *testContext) *auditTrail {
+	InjectScoped[auditTrailBuilder](ctx)
+	return &auditTrail{ctx: ctx, closed: &closedTrails, name: "report"}
+}
+
+type failingCloser struct{}
+
+func (failingCloser) Close() error { return errors.New("flush failed") }
+
+type failingBuilder struct{}
+
+func (failingBuilder) BuildScoped(ctx *testContext) failingCloser { return failingCloser{} }
+
+func TestInjectScoped_SharesValuesWithinScope(t *testing.T) {
+	ctx := newTestContext()
+	scope := BeginScope(ctx)
+	defer scope.Close()
+
+	first := InjectScoped[auditTrailBuilder](ctx)
+	assert.Same(t, first, InjectScoped[auditTrailBuilder](ctx))
+	assert.Equal(t, ctx, first.ctx)
+
+	other := newTestContext()
+	otherScope := BeginScope(other)
+	defer otherScope.Close()
+	assert.NotSame(t, first, InjectScoped[auditTrailBuilder](other))
+}
+
+func TestInjectScoped_WithoutScopeBuildsPerCall(t *testing.T) {
+	ctx := newTestContext()
+	assert.Nil(t, ScopeFrom(ctx))
+	assert.NotSame(t, InjectScoped[auditTrailBuilder](ctx), InjectScoped[auditTrailBuilder](ctx))
+}
+
+func TestScope_CloseDisposesInReverseOrder(t *testing.T) {
+	closedTrails = nil
+	ctx := newTestContext()
+	scope := BeginScope(ctx)
+
+	InjectScoped[reportBuilder](ctx)
+	require.NoError(t, scope.Close())
+	assert.Equal(t, []string{"report", "audit"}, closedTrails)
+
+	// Closing twice disposes nothing more, and a closed scope no longer caches
+	require.NoError(t, scope.Close())
+	assert.Len(t, closedTrails, 2)
+	assert.NotSame(t, InjectScoped[auditTrailBuilder](ctx), InjectScoped[auditTrailBuilder](ctx))
+}
+
+func TestScope_CloseReturnsDisposeError(t *testing.T) {
+	ctx := newTestContext()
+	scope := BeginScope(ctx)
+	InjectScoped[failingBuilder](ctx)
+	assert.EqualError(t, scope.Close(), "flush failed")
+}
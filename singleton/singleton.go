@@ -21,6 +21,9 @@ var (
 )
 
 func Inject[B Builder[T], T any]() T {
+	if impl, ok := overridden[T](); ok {
+		return impl
+	}
 	builderRef := new(B)
 	builder := *builderRef
 	builderType := reflect.TypeOf(builder)
@@ -29,14 +32,14 @@ func Inject[B Builder[T], T any]() T {
 		builder: &genericBuilderWrapper[T]{builder: builder},
 	})
 	entry := entryInterface.(*SingletonEntry)
-	entry.once.Do(func() {
-		entry.instance = entry.builder.Build()
-	})
-	return entry.instance.(T)
+	return entry.get().(T)
 }
 
 // GetInstance retrieves an already initialized singleton instance
 func GetInstance[B Builder[T], T any]() T {
+	if impl, ok := overridden[T](); ok {
+		return impl
+	}
 	builderRef := new(B)
 	builder := *builderRef
 	builderType := reflect.TypeOf(builder)
@@ -56,6 +59,16 @@ func GetInstance[B Builder[T], T any]() T {
 	return entry.instance.(T)
 }
 
+// get builds the instance on first use, recording it for the lifecycle once its dependencies, built
+// from inside Build, have been recorded
+func (e *SingletonEntry) get() any {
+	e.once.Do(func() {
+		e.instance = e.builder.Build()
+		lifecycle.built(e.instance)
+	})
+	return e.instance
+}
+
 type genericBuilderWrapper[T any] struct {
 	builder Builder[T]
 }
@@ -0,0 +1,301 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/orm"
+)
+
+// BatchWriterConfig configures a BatchWriter
+type BatchWriterConfig struct {
+	// Table overrides the table name discovered from the model
+	Table string
+
+	// BatchSize flushes once this many rows are buffered (default 1000)
+	BatchSize int
+
+	// FlushInterval flushes buffered rows periodically (default 1s)
+	FlushInterval time.Duration
+
+	// MaxRetries is the number of retries after a connection error (default 3, negative disables)
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled on each attempt (default 100ms)
+	RetryBackoff time.Duration
+
+	// OnError is called when a batch is dropped after exhausting retries (default logs)
+	OnError func(err error, rows int)
+}
+
+// BatchWriter buffers rows of T and inserts them into ClickHouse in batches
+type BatchWriter[T any] struct {
+	client  *Client
+	config  BatchWriterConfig
+	table   string
+	columns []orm.FieldMetadata
+	insert  string
+
+	mu     sync.Mutex
+	buffer []*T
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBatchWriter creates a writer for T using ORM column metadata and starts its flush loop.
+// The writer is flushed and stopped automatically by Shutdown.
+func NewBatchWriter[T any](client *Client, cfg BatchWriterConfig) (*BatchWriter[T], error) {
+	if client == nil {
+		return nil, fmt.Errorf("clickhouse client is required")
+	}
+
+	metadata := orm.GetMetadata[T]()
+	if metadata == nil {
+		orm.RegisterModel[T]()
+		metadata = orm.GetMetadata[T]()
+	}
+
+	table := cfg.Table
+	if table == "" {
+		table = qualifiedTableName(metadata)
+	}
+	if table == "" {
+		return nil, fmt.Errorf("no table name for %T", *new(T))
+	}
+
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 100 * time.Millisecond
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(err error, rows int) {
+			log.Printf("[ClickHouse] dropped %d rows for %s: %v", rows, table, err)
+		}
+	}
+
+	var columns []orm.FieldMetadata
+	var names []string
+	for _, field := range metadata.Fields {
+		if field.IsAutoIncrement || field.IsReadOnly {
+			continue
+		}
+		columns = append(columns, field)
+		names = append(names, field.Column)
+	}
+
+	w := &BatchWriter[T]{
+		client:  client,
+		config:  cfg,
+		table:   table,
+		columns: columns,
+		insert:  fmt.Sprintf("INSERT INTO %s (%s)", table, strings.Join(names, ", ")),
+		buffer:  make([]*T, 0, cfg.BatchSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go w.flushLoop()
+	OnShutdown(w.Close)
+
+	return w, nil
+}
+
+// Write buffers rows, flushing synchronously once BatchSize is reached
+func (w *BatchWriter[T]) Write(ctx context.Context, rows ...*T) error {
+	w.mu.Lock()
+	w.buffer = append(w.buffer, rows...)
+	var batch []*T
+	if len(w.buffer) >= w.config.BatchSize {
+		batch = w.takeLocked()
+	}
+	w.mu.Unlock()
+
+	if batch == nil {
+		return nil
+	}
+	return w.flushBatch(ctx, batch)
+}
+
+// Flush inserts all buffered rows immediately
+func (w *BatchWriter[T]) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	batch := w.takeLocked()
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return w.flushBatch(ctx, batch)
+}
+
+// Buffered returns the number of rows waiting to be flushed
+func (w *BatchWriter[T]) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.buffer)
+}
+
+// Close stops the flush loop and flushes remaining rows
+func (w *BatchWriter[T]) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+	return w.Flush(ctx)
+}
+
+func (w *BatchWriter[T]) takeLocked() []*T {
+	batch := w.buffer
+	w.buffer = make([]*T, 0, w.config.BatchSize)
+	return batch
+}
+
+func (w *BatchWriter[T]) flushLoop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			// Errors are reported through OnError inside flushBatch
+			_ = w.Flush(context.Background())
+		}
+	}
+}
+
+// flushBatch inserts batch, reporting the outcome through metrics and OnError
+func (w *BatchWriter[T]) flushBatch(ctx context.Context, batch []*T) error {
+	start := time.Now()
+	err := w.insertWithRetry(ctx, batch)
+
+	metrics.RecordClickHouseFlush(ctx, w.table, len(batch), time.Since(start), err == nil)
+
+	if err != nil {
+		err = fmt.Errorf("failed to flush %d rows to %s: %w", len(batch), w.table, err)
+		w.config.OnError(err, len(batch))
+	}
+	return err
+}
+
+// insertWithRetry retries connection errors with exponential backoff
+func (w *BatchWriter[T]) insertWithRetry(ctx context.Context, batch []*T) error {
+	backoff := w.config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := w.insertBatch(ctx, batch)
+		if err == nil || !isRetryable(err) || attempt >= w.config.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (w *BatchWriter[T]) insertBatch(ctx context.Context, batch []*T) error {
+	tx, err := w.client.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, w.insert)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range batch {
+		values, err := w.rowValues(row)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rowValues extracts column values in insert order, resolving driver.Valuer implementations
+func (w *BatchWriter[T]) rowValues(row *T) ([]interface{}, error) {
+	base := unsafe.Pointer(row)
+	values := make([]interface{}, len(w.columns))
+
+	for i, column := range w.columns {
+		field := reflect.NewAt(column.Type, unsafe.Add(base, column.Offset)).Elem()
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			values[i] = nil
+			continue
+		}
+
+		value := field.Interface()
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", column.Column, err)
+			}
+			value = v
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// qualifiedTableName prefixes the database when the model's TableName includes one
+func qualifiedTableName(metadata *orm.ModelMetadata) string {
+	if metadata.Schema != "" && metadata.Schema != "public" {
+		return metadata.Schema + "." + metadata.TableName
+	}
+	return metadata.TableName
+}
+
+// isRetryable reports whether err indicates a dropped connection worth retrying
+func isRetryable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") || strings.Contains(msg, "broken pipe")
+}
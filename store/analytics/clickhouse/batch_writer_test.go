@@ -0,0 +1,48 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	ID        uuid.UUID `orm:"column:id;pk"`
+	Name      string    `orm:"column:name"`
+	Note      *string   `orm:"column:note"`
+	CreatedAt time.Time `orm:"column:created_at"`
+}
+
+func (testEvent) TableName() string {
+	return "analytics.test_events"
+}
+
+func TestBatchWriter_RowValues(t *testing.T) {
+	writer, err := NewBatchWriter[testEvent](&Client{}, BatchWriterConfig{FlushInterval: time.Hour})
+	require.NoError(t, err)
+	defer writer.Close(context.Background())
+
+	assert.Equal(t, "INSERT INTO analytics.test_events (id, name, note, created_at)", writer.insert)
+
+	event := &testEvent{ID: uuid.New(), Name: "signup", CreatedAt: time.Now()}
+	values, err := writer.rowValues(event)
+	require.NoError(t, err)
+	require.Len(t, values, 4)
+	assert.Equal(t, event.ID.String(), values[0])
+	assert.Equal(t, "signup", values[1])
+	assert.Nil(t, values[2])
+	assert.Equal(t, event.CreatedAt, values[3])
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, isRetryable(driver.ErrBadConn))
+	assert.True(t, isRetryable(fmt.Errorf("write: %w", syscall.ECONNRESET)))
+	assert.False(t, isRetryable(fmt.Errorf("code: 60, message: table does not exist")))
+}
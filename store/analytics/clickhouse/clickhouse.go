@@ -0,0 +1,249 @@
+package clickhouse
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	ch "github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/yadunandan004/scaffold/config"
+	"github.com/yadunandan004/scaffold/store/readiness"
+)
+
+var (
+	globalClient  *Client
+	clientMutex   sync.RWMutex
+	shutdownHooks []func(ctx context.Context) error
+)
+
+// Client wraps a ClickHouse connection pool opened through database/sql
+type Client struct {
+	*sql.DB
+	config *Config
+}
+
+// Config contains ClickHouse connection configuration
+type Config struct {
+	Hosts           []string      `yaml:"hosts"`
+	Database        string        `yaml:"database"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Cluster         string        `yaml:"cluster"`
+	TLSEnabled      bool          `yaml:"tls_enabled"`
+	TLSSkipVerify   bool          `yaml:"tls_skip_verify"`
+	Compression     string        `yaml:"compression"`
+	DialTimeout     time.Duration `yaml:"dial_timeout"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// GetConfig resolves ClickHouse configuration from the config file and environment
+func GetConfig(resolver *config.ConfigResolver) *Config {
+	return &Config{
+		Hosts:           resolver.GetStringSlice("clickhouse.hosts", "CLICKHOUSE_HOSTS", []string{"localhost:9000"}),
+		Database:        resolver.GetString("clickhouse.database", "CLICKHOUSE_DATABASE", "default"),
+		User:            resolver.GetString("clickhouse.user", "CLICKHOUSE_USER", "default"),
+		Password:        resolver.GetString("clickhouse.password", "CLICKHOUSE_PASSWORD", ""),
+		Cluster:         resolver.GetString("clickhouse.cluster", "CLICKHOUSE_CLUSTER", ""),
+		TLSEnabled:      resolver.GetBool("clickhouse.tls_enabled", "CLICKHOUSE_TLS_ENABLED", false),
+		TLSSkipVerify:   resolver.GetBool("clickhouse.tls_skip_verify", "CLICKHOUSE_TLS_SKIP_VERIFY", false),
+		Compression:     resolver.GetString("clickhouse.compression", "CLICKHOUSE_COMPRESSION", "lz4"),
+		DialTimeout:     time.Duration(resolver.GetInt("clickhouse.dial_timeout", "CLICKHOUSE_DIAL_TIMEOUT", 10)) * time.Second,
+		MaxOpenConns:    resolver.GetInt("clickhouse.max_open_conns", "CLICKHOUSE_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    resolver.GetInt("clickhouse.max_idle_conns", "CLICKHOUSE_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: time.Duration(resolver.GetInt("clickhouse.conn_max_lifetime", "CLICKHOUSE_CONN_MAX_LIFETIME", 3600)) * time.Second,
+	}
+}
+
+// GetConfigFromEnv resolves ClickHouse configuration from environment variables only
+func GetConfigFromEnv() *Config {
+	return GetConfig(config.NewConfigResolver(""))
+}
+
+// BuildOptions converts Config into clickhouse-go connection options
+func BuildOptions(cfg *Config) (*ch.Options, error) {
+	if len(cfg.Hosts) == 0 {
+		return nil, fmt.Errorf("at least one clickhouse host is required")
+	}
+
+	compression, err := parseCompression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &ch.Options{
+		Addr: cfg.Hosts,
+		Auth: ch.Auth{
+			Database: cfg.Database,
+			Username: cfg.User,
+			Password: cfg.Password,
+		},
+		Compression:      compression,
+		DialTimeout:      cfg.DialTimeout,
+		ConnOpenStrategy: ch.ConnOpenRoundRobin,
+	}
+
+	if cfg.TLSEnabled {
+		opts.TLS = &tls.Config{InsecureSkipVerify: cfg.TLSSkipVerify}
+	}
+
+	return opts, nil
+}
+
+func parseCompression(method string) (*ch.Compression, error) {
+	switch strings.ToLower(method) {
+	case "", "none":
+		return nil, nil
+	case "lz4":
+		return &ch.Compression{Method: ch.CompressionLZ4}, nil
+	case "zstd":
+		return &ch.Compression{Method: ch.CompressionZSTD}, nil
+	case "gzip":
+		return &ch.Compression{Method: ch.CompressionGZIP}, nil
+	default:
+		return nil, fmt.Errorf("unsupported clickhouse compression: %s", method)
+	}
+}
+
+// NewClient opens a connection pool and verifies it with a ping
+func NewClient(cfg *Config) (*Client, error) {
+	opts, err := BuildOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := ch.OpenDB(opts)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping clickhouse: %w", err)
+	}
+
+	return &Client{DB: db, config: cfg}, nil
+}
+
+// Config returns the configuration the client was created with
+func (c *Client) Config() *Config {
+	return c.config
+}
+
+// HealthCheck verifies the ClickHouse connection is alive
+func (c *Client) HealthCheck(ctx context.Context) error {
+	return c.DB.PingContext(ctx)
+}
+
+// Close closes the connection pool
+func (c *Client) Close() error {
+	return c.DB.Close()
+}
+
+// GetClient returns the global ClickHouse client
+func GetClient() *Client {
+	clientMutex.RLock()
+	defer clientMutex.RUnlock()
+	return globalClient
+}
+
+// SetGlobalClient sets the global ClickHouse client
+func SetGlobalClient(client *Client) error {
+	if client == nil {
+		return fmt.Errorf("cannot set nil clickhouse client")
+	}
+
+	clientMutex.Lock()
+	defer clientMutex.Unlock()
+	globalClient = client
+	return nil
+}
+
+// IsConfigured returns whether a global ClickHouse client is set
+func IsConfigured() bool {
+	return GetClient() != nil
+}
+
+// Ping checks if the global ClickHouse connection is alive
+func Ping(ctx context.Context) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("clickhouse client not initialized")
+	}
+	return client.HealthCheck(ctx)
+}
+
+// WaitForReady pings the global client with backoff until ClickHouse answers, ctx ends or timeout passes
+func WaitForReady(ctx context.Context, timeout time.Duration) error {
+	return readiness.Poll(ctx, timeout, "clickhouse", Ping)
+}
+
+// InitFromEnv connects using environment configuration and registers the global client
+func InitFromEnv() error {
+	cfg := GetConfigFromEnv()
+	client, err := NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := SetGlobalClient(client); err != nil {
+		client.Close()
+		return err
+	}
+
+	log.Printf("Connected to ClickHouse at %s (database: %s)", strings.Join(cfg.Hosts, ","), cfg.Database)
+	return nil
+}
+
+// OnShutdown registers fn to run during Shutdown before the connection closes, e.g. flushing buffered writers
+func OnShutdown(fn func(ctx context.Context) error) {
+	clientMutex.Lock()
+	defer clientMutex.Unlock()
+	shutdownHooks = append(shutdownHooks, fn)
+}
+
+// Shutdown runs shutdown hooks and closes the global client, giving up when ctx is done
+func Shutdown(ctx context.Context) error {
+	clientMutex.Lock()
+	hooks := shutdownHooks
+	client := globalClient
+	shutdownHooks = nil
+	globalClient = nil
+	clientMutex.Unlock()
+
+	var firstErr error
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if client == nil {
+		return firstErr
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- client.Close() }()
+
+	select {
+	case err := <-done:
+		if firstErr == nil {
+			firstErr = err
+		}
+	case <-ctx.Done():
+		if firstErr == nil {
+			firstErr = ctx.Err()
+		}
+	}
+
+	return firstErr
+}
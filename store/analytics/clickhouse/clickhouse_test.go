@@ -0,0 +1,34 @@
+package clickhouse
+
+import (
+	"testing"
+	"time"
+
+	ch "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOptions(t *testing.T) {
+	cfg := &Config{
+		Hosts:       []string{"ch-1:9000", "ch-2:9000"},
+		Database:    "analytics",
+		User:        "writer",
+		Compression: "zstd",
+		TLSEnabled:  true,
+		DialTimeout: 5 * time.Second,
+	}
+
+	opts, err := BuildOptions(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Hosts, opts.Addr)
+	assert.Equal(t, "analytics", opts.Auth.Database)
+	assert.Equal(t, ch.CompressionZSTD, opts.Compression.Method)
+	assert.NotNil(t, opts.TLS)
+
+	_, err = BuildOptions(&Config{Hosts: []string{"ch:9000"}, Compression: "snappy"})
+	assert.Error(t, err)
+
+	_, err = BuildOptions(&Config{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+package clickhouse
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultMigrationsTable records applied migrations
+const DefaultMigrationsTable = "schema_migrations"
+
+// OnClusterPlaceholder is replaced with "ON CLUSTER <cluster>" when a cluster is configured and removed otherwise
+const OnClusterPlaceholder = "{on_cluster}"
+
+var (
+	ErrDuplicateMigration = errors.New("duplicate migration version")
+	ErrChecksumMismatch   = errors.New("applied migration has been modified")
+)
+
+// Migration is a versioned set of statements applied in order
+type Migration struct {
+	Version    string
+	Name       string
+	Statements []string
+	Checksum   string
+}
+
+// LoadMigrations reads *.sql files from dir, ordered by filename.
+// Files are named <version>_<name>.sql and may hold several statements separated by semicolons.
+func LoadMigrations(dir string) ([]Migration, error) {
+	return LoadMigrationsFS(os.DirFS(dir), ".")
+}
+
+// LoadMigrationsFS reads migrations from dir within fsys, e.g. an embed.FS
+func LoadMigrationsFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		version, name := parseMigrationName(entry.Name())
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("%w: %s in %s and %s", ErrDuplicateMigration, version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:    version,
+			Name:       name,
+			Statements: splitStatements(string(content)),
+			Checksum:   hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func parseMigrationName(filename string) (version, name string) {
+	base := strings.TrimSuffix(filename, ".sql")
+	if idx := strings.Index(base, "_"); idx > 0 {
+		return base[:idx], base[idx+1:]
+	}
+	return base, base
+}
+
+// splitStatements splits script on semicolons outside quotes and comments, since ClickHouse executes one statement per query
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote byte
+
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		if quote != 0 {
+			current.WriteByte(c)
+			if c == '\\' && i+1 < len(script) {
+				i++
+				current.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			current.WriteByte(c)
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			// Skip line comment
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			current.WriteByte('\n')
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// Migrator applies migrations and records them in a tracking table
+type Migrator struct {
+	client *Client
+	table  string
+}
+
+// NewMigrator creates a migrator using DefaultMigrationsTable
+func NewMigrator(client *Client) *Migrator {
+	return &Migrator{client: client, table: DefaultMigrationsTable}
+}
+
+// WithTable overrides the tracking table name
+func (m *Migrator) WithTable(table string) *Migrator {
+	m.table = table
+	return m
+}
+
+// onCluster substitutes OnClusterPlaceholder for the configured cluster
+func (m *Migrator) onCluster(stmt string) string {
+	clause := ""
+	if cluster := m.cluster(); cluster != "" {
+		clause = "ON CLUSTER " + cluster
+	}
+	return strings.ReplaceAll(stmt, OnClusterPlaceholder, clause)
+}
+
+func (m *Migrator) cluster() string {
+	if m.client.config == nil {
+		return ""
+	}
+	return m.client.config.Cluster
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	// Replicated on clusters so every node sees the same history
+	engine := "MergeTree"
+	if m.cluster() != "" {
+		engine = "ReplicatedMergeTree"
+	}
+
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s %s (
+	version String,
+	name String,
+	checksum String,
+	applied_at DateTime64(3) DEFAULT now64(3)
+) ENGINE = %s ORDER BY version`, m.table, OnClusterPlaceholder, engine)
+
+	if _, err := m.client.ExecContext(ctx, m.onCluster(stmt)); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+// Applied returns the checksum of each applied migration keyed by version
+func (m *Migrator) Applied(ctx context.Context) (map[string]string, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.client.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum FROM %s", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]string)
+	for rows.Next() {
+		var version, checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+// Up applies pending migrations in version order and returns the versions applied.
+// A migration is recorded only after all its statements succeed; DDL is not transactional,
+// so statements in a failed migration should be idempotent (IF NOT EXISTS) to allow a rerun.
+func (m *Migrator) Up(ctx context.Context, migrations []Migration) ([]string, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, migration := range migrations {
+		if checksum, ok := applied[migration.Version]; ok {
+			if checksum != migration.Checksum {
+				return done, fmt.Errorf("%w: %s_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+			}
+			continue
+		}
+
+		for i, stmt := range migration.Statements {
+			if _, err := m.client.ExecContext(ctx, m.onCluster(stmt)); err != nil {
+				return done, fmt.Errorf("migration %s_%s statement %d failed: %w", migration.Version, migration.Name, i+1, err)
+			}
+		}
+
+		insert := fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES (?, ?, ?)", m.table)
+		if _, err := m.client.ExecContext(ctx, insert, migration.Version, migration.Name, migration.Checksum); err != nil {
+			return done, fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
+		}
+
+		log.Printf("[ClickHouse] applied migration %s_%s", migration.Version, migration.Name)
+		done = append(done, migration.Version)
+	}
+
+	return done, nil
+}
+
+// Migrate applies migrations from dir using the global client, intended for service startup
+func Migrate(ctx context.Context, dir string) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("clickhouse client not initialized")
+	}
+
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return err
+	}
+
+	_, err = NewMigrator(client).Up(ctx, migrations)
+	return err
+}
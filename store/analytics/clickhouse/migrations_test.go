@@ -0,0 +1,55 @@
+package clickhouse
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrationsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/002_add_index.sql": {Data: []byte("ALTER TABLE events {on_cluster} ADD INDEX idx_name name TYPE bloom_filter GRANULARITY 1;")},
+		"migrations/001_create_events.sql": {Data: []byte(`
+-- events table; one row per action
+CREATE TABLE IF NOT EXISTS events {on_cluster} (id UUID, name String DEFAULT 'a;b') ENGINE = MergeTree ORDER BY id;
+CREATE TABLE IF NOT EXISTS events_daily {on_cluster} (day Date) ENGINE = MergeTree ORDER BY day;
+`)},
+		"migrations/README.md": {Data: []byte("ignored")},
+	}
+
+	migrations, err := LoadMigrationsFS(fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, "001", migrations[0].Version)
+	assert.Equal(t, "create_events", migrations[0].Name)
+	require.Len(t, migrations[0].Statements, 2)
+	assert.Contains(t, migrations[0].Statements[0], "DEFAULT 'a;b'")
+	assert.NotContains(t, migrations[0].Statements[0], "--")
+	assert.NotEmpty(t, migrations[0].Checksum)
+
+	assert.Equal(t, "002", migrations[1].Version)
+	assert.Len(t, migrations[1].Statements, 1)
+}
+
+func TestLoadMigrationsFS_DuplicateVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_a.sql": {Data: []byte("SELECT 1")},
+		"001_b.sql": {Data: []byte("SELECT 2")},
+	}
+
+	_, err := LoadMigrationsFS(fsys, ".")
+	assert.ErrorIs(t, err, ErrDuplicateMigration)
+}
+
+func TestMigrator_OnCluster(t *testing.T) {
+	stmt := "CREATE TABLE t {on_cluster} (id UInt64) ENGINE = MergeTree ORDER BY id"
+
+	single := NewMigrator(&Client{config: &Config{}})
+	assert.Equal(t, "CREATE TABLE t  (id UInt64) ENGINE = MergeTree ORDER BY id", single.onCluster(stmt))
+
+	clustered := NewMigrator(&Client{config: &Config{Cluster: "analytics"}})
+	assert.Equal(t, "CREATE TABLE t ON CLUSTER analytics (id UInt64) ENGINE = MergeTree ORDER BY id", clustered.onCluster(stmt))
+}
@@ -0,0 +1,56 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yadunandan004/scaffold/orm"
+)
+
+// Select runs query on the global client and scans every row into a T.
+// Columns are matched to fields by name, orm column tag or json tag using the ORM RawScanner.
+func Select[T any](ctx context.Context, query string, args ...interface{}) ([]*T, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("clickhouse client not initialized")
+	}
+	return SelectWith[T](ctx, client, query, args...)
+}
+
+// SelectWith runs Select against a specific client
+func SelectWith[T any](ctx context.Context, client *Client, query string, args ...interface{}) ([]*T, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*T, 0)
+	scanner := &orm.RawScanner{}
+	if err := scanner.ScanRaw(rows, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SelectOne runs query on the global client and scans the first row into a T.
+// Returns sql.ErrNoRows when the query yields no rows.
+func SelectOne[T any](ctx context.Context, query string, args ...interface{}) (*T, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("clickhouse client not initialized")
+	}
+
+	rows, err := client.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result T
+	scanner := &orm.RawScanner{}
+	if err := scanner.ScanRow(rows, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
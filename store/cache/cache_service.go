@@ -2,9 +2,24 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 )
 
+// ErrKeyNotFound is returned by Get when the key is missing or expired
+var ErrKeyNotFound = errors.New("key not found")
+
+// ErrHashNotFound is returned by HGet and HMGet when the hash is missing
+var ErrHashNotFound = errors.New("hash not found")
+
+// ErrLockNotAcquired is returned by Lock when the key is already held by another owner
+var ErrLockNotAcquired = errors.New("lock not acquired")
+
+// ErrLockNotHeld is returned by Unlock when the lock expired or was taken over by another owner
+var ErrLockNotHeld = errors.New("lock not held")
+
 // CacheService defines the interface for cache operations
 type CacheService interface {
 	// Get retrieves a value by key
@@ -42,6 +57,104 @@ type CacheService interface {
 
 	// TTL returns time to live for a key
 	TTL(ctx context.Context, key string) (time.Duration, error)
+
+	// Incr atomically increments the integer value of key by one
+	Incr(ctx context.Context, key string) (int64, error)
+
+	// Decr atomically decrements the integer value of key by one
+	Decr(ctx context.Context, key string) (int64, error)
+
+	// IncrBy atomically increments the integer value of key by delta
+	IncrBy(ctx context.Context, key string, delta int64) (int64, error)
+
+	// SAdd adds members to a set
+	SAdd(ctx context.Context, key string, members ...string) error
+
+	// SMembers returns all members of a set
+	SMembers(ctx context.Context, key string) ([]string, error)
+
+	// SRem removes members from a set
+	SRem(ctx context.Context, key string, members ...string) error
+
+	// ZAdd adds members with scores to a sorted set
+	ZAdd(ctx context.Context, key string, members ...ZMember) error
+
+	// ZRangeByScore returns sorted set members with min <= score <= max, ordered by score
+	ZRangeByScore(ctx context.Context, key string, min, max float64) ([]ZMember, error)
+
+	// Keys returns all keys matching the glob-style pattern
+	Keys(ctx context.Context, pattern string) ([]string, error)
+
+	// Iterate calls fn for each key matching the glob-style pattern, stopping at the first error
+	Iterate(ctx context.Context, pattern string, fn func(key string) error) error
+
+	// DeleteByPrefix removes every key starting with prefix
+	DeleteByPrefix(ctx context.Context, prefix string) error
+
+	// SetWithTags stores a key-value pair and associates the key with each tag
+	SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error
+
+	// InvalidateTag deletes every key associated with tag
+	InvalidateTag(ctx context.Context, tag string) error
+
+	// Publish sends message to all subscribers of channel
+	Publish(ctx context.Context, channel string, message interface{}) error
+
+	// Subscribe listens for messages on the given channels until the subscription is closed
+	Subscribe(ctx context.Context, channels ...string) (Subscription, error)
+
+	// Lock acquires an exclusive lock on key that expires after ttl.
+	// Returns ErrLockNotAcquired if the lock is currently held.
+	Lock(ctx context.Context, key string, ttl time.Duration) (Unlocker, error)
+}
+
+// EscapePattern escapes glob metacharacters so s matches literally in Keys/Iterate patterns
+func EscapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ZMember is a scored member of a sorted set
+type ZMember struct {
+	Score  float64
+	Member string
+}
+
+// Message is a payload received on a subscribed channel
+type Message struct {
+	Channel string
+	Payload string
+}
+
+// Unmarshal decodes the JSON payload into v
+func (m *Message) Unmarshal(v interface{}) error {
+	return json.Unmarshal([]byte(m.Payload), v)
+}
+
+// Subscription delivers messages published to its channels
+type Subscription interface {
+	// Channel returns the stream of received messages; it is closed after Close
+	Channel() <-chan *Message
+
+	// Close unsubscribes from all channels
+	Close() error
+}
+
+// Unlocker releases a lock acquired through CacheService.Lock
+type Unlocker interface {
+	// Unlock releases the lock if it is still held by this owner
+	Unlock(ctx context.Context) error
+
+	// Token returns the fencing token issued with the lock. Tokens increase
+	// monotonically per key, so downstream writes can reject stale holders.
+	Token() int64
 }
 
 // CacheOptions contains options for cache operations
@@ -51,6 +164,18 @@ type CacheOptions struct {
 
 	// CleanupInterval is the interval for cleaning up expired entries (local cache only)
 	CleanupInterval time.Duration
+
+	// MaxEntries bounds the number of key-value entries, evicting least recently used (local cache only, 0 = unbounded)
+	MaxEntries int
+
+	// MaxBytes bounds the total cost of key-value entries, evicting least recently used (local cache only, 0 = unbounded)
+	MaxBytes int64
+
+	// Codec serializes values for byte-oriented backends such as Redis (default JSONCodec)
+	Codec Codec
+
+	// CostFunc returns the cost of an entry counted against MaxBytes; defaults to an approximate size estimate
+	CostFunc func(key string, value interface{}) int64
 }
 
 // DefaultCacheOptions returns default cache options
@@ -58,5 +183,6 @@ func DefaultCacheOptions() *CacheOptions {
 	return &CacheOptions{
 		DefaultExpiration: 5 * time.Minute,
 		CleanupInterval:   10 * time.Minute,
+		Codec:             JSONCodec{},
 	}
 }
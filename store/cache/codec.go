@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec serializes cache values for backends that store bytes
+type Codec interface {
+	// Name identifies the codec, e.g. "json"
+	Name() string
+
+	// Marshal encodes v
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes data into the value pointed to by v
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec encodes values with encoding/json
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string { return "json" }
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// MsgpackCodec encodes values with MessagePack, producing smaller payloads than JSON
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Name() string { return "msgpack" }
+
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// GobCodec encodes values with encoding/gob.
+// Values are encoded as interfaces so they decode back into interface{};
+// concrete types must be registered with gob.Register.
+type GobCodec struct{}
+
+func (GobCodec) Name() string { return "gob" }
+
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	if target, ok := v.(*interface{}); ok {
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(target)
+	}
+
+	var decoded interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	return assign(v, decoded)
+}
+
+// assign stores decoded into the pointer v when the types are compatible
+func assign(v interface{}, decoded interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("gob codec: decode target must be a non-nil pointer, got %T", v)
+	}
+
+	value := reflect.ValueOf(decoded)
+	if !value.IsValid() {
+		target.Elem().SetZero()
+		return nil
+	}
+	if !value.Type().AssignableTo(target.Elem().Type()) {
+		return fmt.Errorf("gob codec: cannot decode %s into %s", value.Type(), target.Elem().Type())
+	}
+
+	target.Elem().Set(value)
+	return nil
+}
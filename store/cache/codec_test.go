@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codecSample struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	gob.Register(codecSample{})
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	codecs := []Codec{JSONCodec{}, MsgpackCodec{}, GobCodec{}}
+
+	for _, codec := range codecs {
+		t.Run(codec.Name(), func(t *testing.T) {
+			data, err := codec.Marshal(codecSample{Name: "sample", Count: 3})
+			require.NoError(t, err)
+
+			var generic interface{}
+			require.NoError(t, codec.Unmarshal(data, &generic))
+			assert.NotNil(t, generic)
+
+			var typed codecSample
+			require.NoError(t, codec.Unmarshal(data, &typed))
+			assert.Equal(t, codecSample{Name: "sample", Count: 3}, typed)
+		})
+	}
+}
+
+func TestGobCodec_TypeMismatch(t *testing.T) {
+	data, err := GobCodec{}.Marshal("text")
+	require.NoError(t, err)
+
+	var n int
+	assert.Error(t, GobCodec{}.Unmarshal(data, &n))
+}
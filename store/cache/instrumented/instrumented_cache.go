@@ -0,0 +1,357 @@
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yadunandan004/scaffold/logger"
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/request"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// Options configures the instrumented cache decorator
+type Options struct {
+	// CacheType labels every metric, e.g. "redis" or "local"
+	CacheType string
+
+	// PrefixSeparator splits the key prefix used as a metric label (default ":")
+	PrefixSeparator string
+
+	// Debug logs every operation at debug level through the logger package, with the request's
+	// correlation fields when ctx carries a request.Context, as gRPC handler contexts do
+	Debug bool
+}
+
+// InstrumentedCache wraps a CacheService and records hit/miss, latency and error metrics per operation and key prefix
+type InstrumentedCache struct {
+	inner   cache.CacheService
+	options Options
+}
+
+// NewInstrumentedCache decorates inner with metrics and optional debug logging
+func NewInstrumentedCache(inner cache.CacheService, options Options) *InstrumentedCache {
+	if options.CacheType == "" {
+		options.CacheType = "unknown"
+	}
+	if options.PrefixSeparator == "" {
+		options.PrefixSeparator = ":"
+	}
+
+	return &InstrumentedCache{
+		inner:   inner,
+		options: options,
+	}
+}
+
+// Unwrap returns the decorated CacheService
+func (c *InstrumentedCache) Unwrap() cache.CacheService {
+	return c.inner
+}
+
+// otherPrefix labels keys without a prefix, so unprefixed keys don't each become a metric series
+const otherPrefix = "other"
+
+func (c *InstrumentedCache) keyPrefix(key string) string {
+	if idx := strings.Index(key, c.options.PrefixSeparator); idx > 0 {
+		return key[:idx]
+	}
+	return otherPrefix
+}
+
+func isMiss(err error) bool {
+	return errors.Is(err, cache.ErrKeyNotFound) || errors.Is(err, cache.ErrHashNotFound)
+}
+
+// observe records latency and failures; misses are not counted as errors
+func (c *InstrumentedCache) observe(ctx context.Context, operation, key string, start time.Time, err error) {
+	duration := time.Since(start)
+	prefix := c.keyPrefix(key)
+	failed := err != nil && !isMiss(err)
+
+	metrics.RecordCacheOperation(ctx, c.options.CacheType, operation, prefix, duration, failed)
+
+	if !c.options.Debug {
+		return
+	}
+	fields := []logger.Field{
+		logger.String("cache_type", c.options.CacheType),
+		logger.String("operation", operation),
+		logger.String("key", key),
+		logger.Duration("duration", duration),
+	}
+	if err != nil {
+		fields = append(fields, logger.Err(err))
+	}
+	logger.LogDebugKV(requestContext(ctx), "cache "+operation, fields...)
+}
+
+// requestContext returns the request.Context ctx carries, or nil when it carries none
+func requestContext(ctx context.Context) request.Context {
+	if reqCtx, ok := request.GetGRPCCtx(ctx); ok {
+		return reqCtx
+	}
+	return nil
+}
+
+// lookup records a hit or miss for read operations
+func (c *InstrumentedCache) lookup(ctx context.Context, operation, key string, err error) {
+	if err != nil && !isMiss(err) {
+		return
+	}
+	metrics.RecordCacheLookup(ctx, c.options.CacheType, operation, c.keyPrefix(key), err == nil)
+}
+
+func firstKey(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// Get retrieves a value by key
+func (c *InstrumentedCache) Get(ctx context.Context, key string) (interface{}, error) {
+	start := time.Now()
+	value, err := c.inner.Get(ctx, key)
+	c.lookup(ctx, "get", key, err)
+	c.observe(ctx, "get", key, start, err)
+	return value, err
+}
+
+// Set stores a key-value pair with optional expiration
+func (c *InstrumentedCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	start := time.Now()
+	err := c.inner.Set(ctx, key, value, expiration)
+	c.observe(ctx, "set", key, start, err)
+	return err
+}
+
+// MGet retrieves multiple values by keys, counting a hit or miss per key
+func (c *InstrumentedCache) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	start := time.Now()
+	values, err := c.inner.MGet(ctx, keys...)
+	if err == nil {
+		for i, key := range keys {
+			if i < len(values) {
+				metrics.RecordCacheLookup(ctx, c.options.CacheType, "mget", c.keyPrefix(key), values[i] != nil)
+			}
+		}
+	}
+	c.observe(ctx, "mget", firstKey(keys), start, err)
+	return values, err
+}
+
+// MSet stores multiple key-value pairs
+func (c *InstrumentedCache) MSet(ctx context.Context, pairs map[string]interface{}, expiration time.Duration) error {
+	start := time.Now()
+	err := c.inner.MSet(ctx, pairs, expiration)
+	var key string
+	for k := range pairs {
+		key = k
+		break
+	}
+	c.observe(ctx, "mset", key, start, err)
+	return err
+}
+
+// HGet retrieves a hash field value
+func (c *InstrumentedCache) HGet(ctx context.Context, key, field string) (interface{}, error) {
+	start := time.Now()
+	value, err := c.inner.HGet(ctx, key, field)
+	c.lookup(ctx, "hget", key, err)
+	c.observe(ctx, "hget", key, start, err)
+	return value, err
+}
+
+// HSet stores a hash field value
+func (c *InstrumentedCache) HSet(ctx context.Context, key, field string, value interface{}) error {
+	start := time.Now()
+	err := c.inner.HSet(ctx, key, field, value)
+	c.observe(ctx, "hset", key, start, err)
+	return err
+}
+
+// HMGet retrieves multiple hash field values
+func (c *InstrumentedCache) HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error) {
+	start := time.Now()
+	values, err := c.inner.HMGet(ctx, key, fields...)
+	c.observe(ctx, "hmget", key, start, err)
+	return values, err
+}
+
+// HMSet stores multiple hash field values
+func (c *InstrumentedCache) HMSet(ctx context.Context, key string, values map[string]interface{}) error {
+	start := time.Now()
+	err := c.inner.HMSet(ctx, key, values)
+	c.observe(ctx, "hmset", key, start, err)
+	return err
+}
+
+// Delete removes one or more keys
+func (c *InstrumentedCache) Delete(ctx context.Context, keys ...string) error {
+	start := time.Now()
+	err := c.inner.Delete(ctx, keys...)
+	c.observe(ctx, "delete", firstKey(keys), start, err)
+	return err
+}
+
+// Exists checks if a key exists
+func (c *InstrumentedCache) Exists(ctx context.Context, key string) (bool, error) {
+	start := time.Now()
+	exists, err := c.inner.Exists(ctx, key)
+	c.observe(ctx, "exists", key, start, err)
+	return exists, err
+}
+
+// Expire sets expiration on a key
+func (c *InstrumentedCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	start := time.Now()
+	err := c.inner.Expire(ctx, key, expiration)
+	c.observe(ctx, "expire", key, start, err)
+	return err
+}
+
+// TTL returns time to live for a key
+func (c *InstrumentedCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	start := time.Now()
+	ttl, err := c.inner.TTL(ctx, key)
+	c.observe(ctx, "ttl", key, start, err)
+	return ttl, err
+}
+
+// Incr atomically increments the integer value of key by one
+func (c *InstrumentedCache) Incr(ctx context.Context, key string) (int64, error) {
+	start := time.Now()
+	n, err := c.inner.Incr(ctx, key)
+	c.observe(ctx, "incr", key, start, err)
+	return n, err
+}
+
+// Decr atomically decrements the integer value of key by one
+func (c *InstrumentedCache) Decr(ctx context.Context, key string) (int64, error) {
+	start := time.Now()
+	n, err := c.inner.Decr(ctx, key)
+	c.observe(ctx, "decr", key, start, err)
+	return n, err
+}
+
+// IncrBy atomically increments the integer value of key by delta
+func (c *InstrumentedCache) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	start := time.Now()
+	n, err := c.inner.IncrBy(ctx, key, delta)
+	c.observe(ctx, "incrby", key, start, err)
+	return n, err
+}
+
+// SAdd adds members to a set
+func (c *InstrumentedCache) SAdd(ctx context.Context, key string, members ...string) error {
+	start := time.Now()
+	err := c.inner.SAdd(ctx, key, members...)
+	c.observe(ctx, "sadd", key, start, err)
+	return err
+}
+
+// SMembers returns all members of a set
+func (c *InstrumentedCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	start := time.Now()
+	members, err := c.inner.SMembers(ctx, key)
+	c.observe(ctx, "smembers", key, start, err)
+	return members, err
+}
+
+// SRem removes members from a set
+func (c *InstrumentedCache) SRem(ctx context.Context, key string, members ...string) error {
+	start := time.Now()
+	err := c.inner.SRem(ctx, key, members...)
+	c.observe(ctx, "srem", key, start, err)
+	return err
+}
+
+// ZAdd adds members with scores to a sorted set
+func (c *InstrumentedCache) ZAdd(ctx context.Context, key string, members ...cache.ZMember) error {
+	start := time.Now()
+	err := c.inner.ZAdd(ctx, key, members...)
+	c.observe(ctx, "zadd", key, start, err)
+	return err
+}
+
+// ZRangeByScore returns sorted set members with min <= score <= max, ordered by score
+func (c *InstrumentedCache) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]cache.ZMember, error) {
+	start := time.Now()
+	members, err := c.inner.ZRangeByScore(ctx, key, min, max)
+	c.observe(ctx, "zrangebyscore", key, start, err)
+	return members, err
+}
+
+// Keys returns all keys matching the glob-style pattern
+func (c *InstrumentedCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	start := time.Now()
+	keys, err := c.inner.Keys(ctx, pattern)
+	c.observe(ctx, "keys", pattern, start, err)
+	return keys, err
+}
+
+// Iterate calls fn for each key matching the glob-style pattern
+func (c *InstrumentedCache) Iterate(ctx context.Context, pattern string, fn func(key string) error) error {
+	start := time.Now()
+	err := c.inner.Iterate(ctx, pattern, fn)
+	c.observe(ctx, "iterate", pattern, start, err)
+	return err
+}
+
+// DeleteByPrefix removes every key starting with prefix
+func (c *InstrumentedCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	start := time.Now()
+	err := c.inner.DeleteByPrefix(ctx, prefix)
+	c.observe(ctx, "delete_by_prefix", prefix, start, err)
+	return err
+}
+
+// SetWithTags stores a key-value pair and associates the key with each tag
+func (c *InstrumentedCache) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	start := time.Now()
+	err := c.inner.SetWithTags(ctx, key, value, expiration, tags...)
+	c.observe(ctx, "set_with_tags", key, start, err)
+	return err
+}
+
+// InvalidateTag deletes every key associated with tag
+func (c *InstrumentedCache) InvalidateTag(ctx context.Context, tag string) error {
+	start := time.Now()
+	err := c.inner.InvalidateTag(ctx, tag)
+	c.observe(ctx, "invalidate_tag", tag, start, err)
+	return err
+}
+
+// Publish sends message to all subscribers of channel
+func (c *InstrumentedCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	start := time.Now()
+	err := c.inner.Publish(ctx, channel, message)
+	c.observe(ctx, "publish", channel, start, err)
+	return err
+}
+
+// Subscribe listens for messages on the given channels until the subscription is closed
+func (c *InstrumentedCache) Subscribe(ctx context.Context, channels ...string) (cache.Subscription, error) {
+	start := time.Now()
+	sub, err := c.inner.Subscribe(ctx, channels...)
+	c.observe(ctx, "subscribe", firstKey(channels), start, err)
+	return sub, err
+}
+
+// Lock acquires an exclusive lock on key that expires after ttl
+func (c *InstrumentedCache) Lock(ctx context.Context, key string, ttl time.Duration) (cache.Unlocker, error) {
+	start := time.Now()
+	unlocker, err := c.inner.Lock(ctx, key, ttl)
+	if errors.Is(err, cache.ErrLockNotAcquired) {
+		// Contention is expected behaviour, not a cache failure
+		c.observe(ctx, "lock", key, start, nil)
+		return nil, err
+	}
+	c.observe(ctx, "lock", key, start, err)
+	return unlocker, err
+}
+
+var _ cache.CacheService = (*InstrumentedCache)(nil)
@@ -0,0 +1,144 @@
+package instrumented
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/logger"
+	"github.com/yadunandan004/scaffold/logger/logwriter"
+	"github.com/yadunandan004/scaffold/metrics"
+	"github.com/yadunandan004/scaffold/store/cache"
+	"github.com/yadunandan004/scaffold/store/cache/local"
+)
+
+// failingCache fails every Get with err
+type failingCache struct {
+	cache.CacheService
+	err error
+}
+
+func (f *failingCache) Get(ctx context.Context, key string) (interface{}, error) {
+	return nil, f.err
+}
+
+// captureWriter records the log entries written to it
+type captureWriter struct {
+	mu      sync.Mutex
+	entries []logwriter.LogEntry
+}
+
+func (w *captureWriter) Write(entry logwriter.LogEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.entries = append(w.entries, entry)
+	return nil
+}
+
+func (w *captureWriter) Flush() error { return nil }
+
+func initMetrics(t *testing.T) {
+	t.Helper()
+	_, err := metrics.InitMetrics(context.Background(), metrics.Config{ServiceName: "instrumented-cache-test"})
+	require.NoError(t, err)
+}
+
+// counter returns the value of the metric series name with the given cache type and key prefix,
+// scraped from the metrics endpoint
+func counter(t *testing.T, name, cacheType, prefix string) float64 {
+	t.Helper()
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	var total float64
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, name+"{") ||
+			!strings.Contains(line, `cache_type="`+cacheType+`"`) || !strings.Contains(line, `key_prefix="`+prefix+`"`) {
+			continue
+		}
+		value, err := strconv.ParseFloat(line[strings.LastIndexByte(line, ' ')+1:], 64)
+		require.NoError(t, err, line)
+		total += value
+	}
+	return total
+}
+
+func newLocal(t *testing.T) cache.CacheService {
+	t.Helper()
+	inner := local.NewLocalCache(cache.DefaultCacheOptions())
+	t.Cleanup(func() { _ = inner.Close() })
+	return inner
+}
+
+func TestInstrumentedCache_CountsHitsAndMisses(t *testing.T) {
+	initMetrics(t)
+	ctx := context.Background()
+	c := NewInstrumentedCache(newLocal(t), Options{CacheType: "hitmiss"})
+
+	require.NoError(t, c.Set(ctx, "users:1", "alice", time.Minute))
+	_, err := c.Get(ctx, "users:1")
+	require.NoError(t, err)
+	_, err = c.Get(ctx, "users:2")
+	assert.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+	assert.Equal(t, 1.0, counter(t, "cache_hits_total", "hitmiss", "users"))
+	assert.Equal(t, 1.0, counter(t, "cache_misses_total", "hitmiss", "users"))
+	assert.Zero(t, counter(t, "cache_errors_total", "hitmiss", "users"), "a miss isn't an error")
+}
+
+func TestInstrumentedCache_CountsErrors(t *testing.T) {
+	initMetrics(t)
+	c := NewInstrumentedCache(&failingCache{err: errors.New("connection refused")}, Options{CacheType: "failing"})
+
+	_, err := c.Get(context.Background(), "users:1")
+	assert.Error(t, err)
+
+	assert.Equal(t, 1.0, counter(t, "cache_errors_total", "failing", "users"))
+	assert.Zero(t, counter(t, "cache_hits_total", "failing", "users"))
+	assert.Zero(t, counter(t, "cache_misses_total", "failing", "users"))
+}
+
+func TestInstrumentedCache_KeyPrefix(t *testing.T) {
+	c := NewInstrumentedCache(nil, Options{})
+	assert.Equal(t, "users", c.keyPrefix("users:1"))
+	assert.Equal(t, "other", c.keyPrefix("session-4f2a"), "keys without a prefix share one label")
+	assert.Equal(t, "other", c.keyPrefix(":1"))
+
+	c = NewInstrumentedCache(nil, Options{PrefixSeparator: "/"})
+	assert.Equal(t, "users", c.keyPrefix("users/1"))
+	assert.Equal(t, "other", c.keyPrefix("users:1"))
+}
+
+func TestInstrumentedCache_DebugLogs(t *testing.T) {
+	capture := &captureWriter{}
+	logger.SetWriter(capture)
+	t.Cleanup(func() { logger.SetWriter(logwriter.NewLocalWriter()) })
+	ctx := context.Background()
+
+	quiet := NewInstrumentedCache(newLocal(t), Options{CacheType: "local"})
+	require.NoError(t, quiet.Set(ctx, "users:1", "alice", time.Minute))
+	assert.Empty(t, capture.entries, "nothing is logged without Debug")
+
+	c := NewInstrumentedCache(newLocal(t), Options{CacheType: "local", Debug: true})
+	_, err := c.Get(ctx, "users:1")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+	require.Len(t, capture.entries, 1)
+	entry := capture.entries[0]
+	assert.Equal(t, logwriter.DebugLevel, entry.Level)
+	assert.Equal(t, "cache get", entry.Message)
+	assert.Equal(t, "users:1", entry.Fields["key"])
+	assert.Equal(t, "local", entry.Fields["cache_type"])
+	assert.Contains(t, entry.Error, cache.ErrKeyNotFound.Error())
+}
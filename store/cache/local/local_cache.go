@@ -1,29 +1,46 @@
 package local
 
 import (
+	"container/list"
 	"context"
 	"errors"
 	"github.com/yadunandan004/scaffold/singleton"
 	"github.com/yadunandan004/scaffold/store/cache"
+	"sort"
 	"sync"
 	"time"
 )
 
-var ErrKeyNotFound = errors.New("key not found")
-var ErrHashNotFound = errors.New("hash not found")
+var ErrKeyNotFound = cache.ErrKeyNotFound
+var ErrHashNotFound = cache.ErrHashNotFound
+var ErrNotInteger = errors.New("value is not an integer")
 
 // LocalCache implements CacheService using an in-memory map
 type LocalCache struct {
 	mu      sync.RWMutex
 	data    map[string]*cacheItem
+	lru     *list.List
+	bytes   int64
+	evicted uint64
 	hashes  map[string]map[string]interface{}
+	sets    map[string]map[string]struct{}
+	zsets   map[string]map[string]float64
+	tags    map[string]map[string]struct{}
+	locks   map[string]*lockEntry
+	fence   int64
 	options *cache.CacheOptions
 	stop    chan bool
+
+	subMu       sync.RWMutex
+	subscribers map[string]map[*localSubscription]struct{}
 }
 
 type cacheItem struct {
+	key        string
 	value      interface{}
 	expiration time.Time
+	cost       int64
+	element    *list.Element
 }
 
 // LocalCacheBuilder implements the builder pattern for dependency injection
@@ -46,9 +63,16 @@ func NewLocalCache(options *cache.CacheOptions) *LocalCache {
 
 	lc := &LocalCache{
 		data:    make(map[string]*cacheItem),
+		lru:     list.New(),
 		hashes:  make(map[string]map[string]interface{}),
+		sets:    make(map[string]map[string]struct{}),
+		zsets:   make(map[string]map[string]float64),
+		tags:    make(map[string]map[string]struct{}),
+		locks:   make(map[string]*lockEntry),
 		options: options,
 		stop:    make(chan bool),
+
+		subscribers: make(map[string]map[*localSubscription]struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -83,15 +107,20 @@ func (c *LocalCache) cleanup() {
 	now := time.Now()
 	for key, item := range c.data {
 		if !item.expiration.IsZero() && now.After(item.expiration) {
-			delete(c.data, key)
+			c.removeItem(key)
+		}
+	}
+	for key, entry := range c.locks {
+		if now.After(entry.expiration) {
+			delete(c.locks, key)
 		}
 	}
 }
 
 // Get retrieves a value by key
 func (c *LocalCache) Get(ctx context.Context, key string) (interface{}, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	item, exists := c.data[key]
 	if !exists {
@@ -102,6 +131,7 @@ func (c *LocalCache) Get(ctx context.Context, key string) (interface{}, error) {
 		return nil, ErrKeyNotFound
 	}
 
+	c.lru.MoveToFront(item.element)
 	return item.value, nil
 }
 
@@ -117,18 +147,46 @@ func (c *LocalCache) Set(ctx context.Context, key string, value interface{}, exp
 		exp = time.Now().Add(c.options.DefaultExpiration)
 	}
 
-	c.data[key] = &cacheItem{
-		value:      value,
-		expiration: exp,
+	c.storeItem(key, value, exp)
+	return nil
+}
+
+// SetWithTags stores a key-value pair and associates the key with each tag
+func (c *LocalCache) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	if err := c.Set(ctx, key, value, expiration); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		if _, exists := c.tags[tag]; !exists {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+
+	return nil
+}
+
+// InvalidateTag deletes every key associated with tag
+func (c *LocalCache) InvalidateTag(ctx context.Context, tag string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.tags[tag] {
+		c.removeItem(key)
 	}
+	delete(c.tags, tag)
 
 	return nil
 }
 
 // MGet retrieves multiple values by keys
 func (c *LocalCache) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	values := make([]interface{}, len(keys))
 	now := time.Now()
@@ -139,6 +197,7 @@ func (c *LocalCache) MGet(ctx context.Context, keys ...string) ([]interface{}, e
 			values[i] = nil
 			continue
 		}
+		c.lru.MoveToFront(item.element)
 		values[i] = item.value
 	}
 
@@ -158,10 +217,7 @@ func (c *LocalCache) MSet(ctx context.Context, pairs map[string]interface{}, exp
 	}
 
 	for key, value := range pairs {
-		c.data[key] = &cacheItem{
-			value:      value,
-			expiration: exp,
-		}
+		c.storeItem(key, value, exp)
 	}
 
 	return nil
@@ -238,8 +294,10 @@ func (c *LocalCache) Delete(ctx context.Context, keys ...string) error {
 	defer c.mu.Unlock()
 
 	for _, key := range keys {
-		delete(c.data, key)
+		c.removeItem(key)
 		delete(c.hashes, key)
+		delete(c.sets, key)
+		delete(c.zsets, key)
 	}
 
 	return nil
@@ -257,7 +315,13 @@ func (c *LocalCache) Exists(ctx context.Context, key string) (bool, error) {
 		return true, nil
 	}
 
-	_, exists := c.hashes[key]
+	if _, exists := c.hashes[key]; exists {
+		return true, nil
+	}
+	if _, exists := c.sets[key]; exists {
+		return true, nil
+	}
+	_, exists := c.zsets[key]
 	return exists, nil
 }
 
@@ -297,6 +361,137 @@ func (c *LocalCache) TTL(ctx context.Context, key string) (time.Duration, error)
 	return ttl, nil
 }
 
+// Incr atomically increments the integer value of key by one
+func (c *LocalCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+// Decr atomically decrements the integer value of key by one
+func (c *LocalCache) Decr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, -1)
+}
+
+// IncrBy atomically increments the integer value of key by delta.
+// A missing or expired key starts from zero with no expiration, matching Redis.
+func (c *LocalCache) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.data[key]
+	if !exists || (!item.expiration.IsZero() && time.Now().After(item.expiration)) {
+		c.storeItem(key, delta, time.Time{})
+		return delta, nil
+	}
+
+	var current int64
+	switch v := item.value.(type) {
+	case int64:
+		current = v
+	case int:
+		current = int64(v)
+	case int32:
+		current = int64(v)
+	default:
+		return 0, ErrNotInteger
+	}
+
+	c.storeItem(key, current+delta, item.expiration)
+	return current + delta, nil
+}
+
+// SAdd adds members to a set
+func (c *LocalCache) SAdd(ctx context.Context, key string, members ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set, exists := c.sets[key]
+	if !exists {
+		set = make(map[string]struct{})
+		c.sets[key] = set
+	}
+
+	for _, member := range members {
+		set[member] = struct{}{}
+	}
+
+	return nil
+}
+
+// SMembers returns all members of a set
+func (c *LocalCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	set := c.sets[key]
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// SRem removes members from a set
+func (c *LocalCache) SRem(ctx context.Context, key string, members ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set, exists := c.sets[key]
+	if !exists {
+		return nil
+	}
+
+	for _, member := range members {
+		delete(set, member)
+	}
+	if len(set) == 0 {
+		delete(c.sets, key)
+	}
+
+	return nil
+}
+
+// ZAdd adds members with scores to a sorted set
+func (c *LocalCache) ZAdd(ctx context.Context, key string, members ...cache.ZMember) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	zset, exists := c.zsets[key]
+	if !exists {
+		zset = make(map[string]float64)
+		c.zsets[key] = zset
+	}
+
+	for _, m := range members {
+		zset[m.Member] = m.Score
+	}
+
+	return nil
+}
+
+// ZRangeByScore returns sorted set members with min <= score <= max, ordered by score
+func (c *LocalCache) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]cache.ZMember, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make([]cache.ZMember, 0)
+	for member, score := range c.zsets[key] {
+		if score >= min && score <= max {
+			results = append(results, cache.ZMember{Score: score, Member: member})
+		}
+	}
+
+	// Ties are ordered lexicographically, as in Redis
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score < results[j].Score
+		}
+		return results[i].Member < results[j].Member
+	})
+
+	return results, nil
+}
+
 // Close stops the cleanup goroutine
 func (c *LocalCache) Close() error {
 	close(c.stop)
@@ -306,4 +501,5 @@ func (c *LocalCache) Close() error {
 // Register the builder with the injector
 func init() {
 	singleton.Inject[LocalCacheBuilder, cache.CacheService]()
+	singleton.Register[LocalCacheBuilder, cache.CacheService]("local")
 }
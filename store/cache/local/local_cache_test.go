@@ -0,0 +1,230 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+func newTestCache(t *testing.T) *LocalCache {
+	c := NewLocalCache(cache.DefaultCacheOptions())
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestLocalCache_Lock(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	first, err := c.Lock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	// Second acquisition must fail while the first is held
+	_, err = c.Lock(ctx, "job", time.Minute)
+	assert.ErrorIs(t, err, cache.ErrLockNotAcquired)
+
+	require.NoError(t, first.Unlock(ctx))
+	assert.ErrorIs(t, first.Unlock(ctx), cache.ErrLockNotHeld)
+
+	// Fencing token increases on re-acquisition
+	second, err := c.Lock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Greater(t, second.Token(), first.Token())
+}
+
+func TestLocalCache_LockExpires(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	stale, err := c.Lock(ctx, "job", 10*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	fresh, err := c.Lock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	// The expired holder must not release the new owner's lock
+	assert.ErrorIs(t, stale.Unlock(ctx), cache.ErrLockNotHeld)
+	assert.NoError(t, fresh.Unlock(ctx))
+}
+
+func TestLocalCache_Counters(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	n, err := c.Incr(ctx, "hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = c.IncrBy(ctx, "hits", 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+
+	n, err = c.Decr(ctx, "hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+
+	require.NoError(t, c.Set(ctx, "name", "scaffold", 0))
+	_, err = c.Incr(ctx, "name")
+	assert.ErrorIs(t, err, ErrNotInteger)
+}
+
+func TestLocalCache_Sets(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.SAdd(ctx, "tags", "a", "b", "c"))
+	require.NoError(t, c.SRem(ctx, "tags", "b"))
+
+	members, err := c.SMembers(ctx, "tags")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "c"}, members)
+}
+
+func TestLocalCache_SortedSets(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.ZAdd(ctx, "board",
+		cache.ZMember{Score: 30, Member: "carol"},
+		cache.ZMember{Score: 10, Member: "alice"},
+		cache.ZMember{Score: 20, Member: "bob"},
+	))
+
+	members, err := c.ZRangeByScore(ctx, "board", 15, 30)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "bob", members[0].Member)
+	assert.Equal(t, "carol", members[1].Member)
+}
+
+func TestLocalCache_PubSub(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	sub, err := c.Subscribe(ctx, "invalidate")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Publish(ctx, "invalidate", map[string]string{"key": "users:1"}))
+	require.NoError(t, c.Publish(ctx, "other", "ignored"))
+
+	select {
+	case msg := <-sub.Channel():
+		var payload map[string]string
+		require.NoError(t, msg.Unmarshal(&payload))
+		assert.Equal(t, "invalidate", msg.Channel)
+		assert.Equal(t, "users:1", payload["key"])
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+
+	require.NoError(t, sub.Close())
+	_, open := <-sub.Channel()
+	assert.False(t, open)
+}
+
+func TestLocalCache_InvalidateTag(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.SetWithTags(ctx, "search:1", "r1", 0, "test_samples"))
+	require.NoError(t, c.SetWithTags(ctx, "search:2", "r2", 0, "test_samples", "other"))
+	require.NoError(t, c.Set(ctx, "untagged", "r3", 0))
+
+	require.NoError(t, c.InvalidateTag(ctx, "test_samples"))
+
+	_, err := c.Get(ctx, "search:1")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = c.Get(ctx, "search:2")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	value, err := c.Get(ctx, "untagged")
+	require.NoError(t, err)
+	assert.Equal(t, "r3", value)
+}
+
+func TestLocalCache_MaxEntriesEvictsLRU(t *testing.T) {
+	opts := cache.DefaultCacheOptions()
+	opts.MaxEntries = 2
+	c := NewLocalCache(opts)
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", "1", 0))
+	require.NoError(t, c.Set(ctx, "b", "2", 0))
+
+	// Touch "a" so "b" becomes least recently used
+	_, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, "c", "3", 0))
+
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = c.Get(ctx, "a")
+	assert.NoError(t, err)
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, uint64(1), stats.Evictions)
+}
+
+func TestLocalCache_MaxBytesWithCostFunc(t *testing.T) {
+	opts := cache.DefaultCacheOptions()
+	opts.MaxBytes = 10
+	opts.CostFunc = func(key string, value interface{}) int64 { return 4 }
+	c := NewLocalCache(opts)
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, c.Set(ctx, key, key, 0))
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(8), stats.Bytes)
+	assert.Equal(t, uint64(1), stats.Evictions)
+
+	require.NoError(t, c.Delete(ctx, "b"))
+	assert.Equal(t, int64(4), c.Stats().Bytes)
+}
+
+func TestLocalCache_KeysAndDeleteByPrefix(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "user:1", "a", 0))
+	require.NoError(t, c.Set(ctx, "user:2", "b", 0))
+	require.NoError(t, c.HSet(ctx, "user:3", "name", "c"))
+	require.NoError(t, c.Set(ctx, "order:1", "d", 0))
+	require.NoError(t, c.Set(ctx, "user*literal", "e", 0))
+
+	keys, err := c.Keys(ctx, "user:*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user:1", "user:2", "user:3"}, keys)
+
+	keys, err = c.Keys(ctx, "user:[12]")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user:1", "user:2"}, keys)
+
+	keys, err = c.Keys(ctx, cache.EscapePattern("user*")+"*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user*literal"}, keys)
+
+	var visited []string
+	require.NoError(t, c.Iterate(ctx, "order:*", func(key string) error {
+		visited = append(visited, key)
+		return c.Delete(ctx, key)
+	}))
+	assert.Equal(t, []string{"order:1"}, visited)
+
+	require.NoError(t, c.DeleteByPrefix(ctx, "user:"))
+	keys, err = c.Keys(ctx, "*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user*literal"}, keys)
+}
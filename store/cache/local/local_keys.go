@@ -0,0 +1,137 @@
+package local
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Keys returns all keys matching the glob-style pattern
+func (c *LocalCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return c.matchingKeys(re), nil
+}
+
+// Iterate calls fn for each key matching pattern over a snapshot, so fn may modify the cache
+func (c *LocalCache) Iterate(ctx context.Context, pattern string, fn func(key string) error) error {
+	keys, err := c.Keys(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteByPrefix removes every key starting with prefix
+func (c *LocalCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.data {
+		if strings.HasPrefix(key, prefix) {
+			c.removeItem(key)
+		}
+	}
+	for key := range c.hashes {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.hashes, key)
+		}
+	}
+	for key := range c.sets {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.sets, key)
+		}
+	}
+	for key := range c.zsets {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.zsets, key)
+		}
+	}
+
+	return nil
+}
+
+func (c *LocalCache) matchingKeys(re *regexp.Regexp) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []string
+	now := time.Now()
+	for key, item := range c.data {
+		if !item.expiration.IsZero() && now.After(item.expiration) {
+			continue
+		}
+		if re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.hashes {
+		if re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.sets {
+		if re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range c.zsets {
+		if re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// compilePattern translates a Redis glob pattern (*, ?, [...], backslash escapes) into a regexp
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			} else {
+				b.WriteString(`\\`)
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + strings.ReplaceAll(class[1:], `\`, `\\`)
+			} else {
+				class = strings.ReplaceAll(class, `\`, `\\`)
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,60 @@
+package local
+
+import (
+	"context"
+	"time"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+type lockEntry struct {
+	token      int64
+	expiration time.Time
+}
+
+// localLock is the Unlocker returned by LocalCache.Lock
+type localLock struct {
+	cache *LocalCache
+	key   string
+	token int64
+}
+
+// Lock acquires an exclusive in-process lock on key.
+// Intended for tests and single-instance deployments.
+func (c *LocalCache) Lock(ctx context.Context, key string, ttl time.Duration) (cache.Unlocker, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, exists := c.locks[key]; exists && time.Now().Before(entry.expiration) {
+		return nil, cache.ErrLockNotAcquired
+	}
+
+	// One counter for every key keeps tokens increasing per key without remembering released keys
+	c.fence++
+	token := c.fence
+	c.locks[key] = &lockEntry{
+		token:      token,
+		expiration: time.Now().Add(ttl),
+	}
+
+	return &localLock{cache: c, key: key, token: token}, nil
+}
+
+// Unlock releases the lock if it is still held by this owner
+func (l *localLock) Unlock(ctx context.Context) error {
+	l.cache.mu.Lock()
+	defer l.cache.mu.Unlock()
+
+	entry, exists := l.cache.locks[l.key]
+	if !exists || entry.token != l.token || time.Now().After(entry.expiration) {
+		return cache.ErrLockNotHeld
+	}
+
+	delete(l.cache.locks, l.key)
+	return nil
+}
+
+// Token returns the fencing token issued with the lock
+func (l *localLock) Token() int64 {
+	return l.token
+}
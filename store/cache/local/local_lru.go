@@ -0,0 +1,113 @@
+package local
+
+import (
+	"time"
+)
+
+// defaultItemCost is charged for values whose size cannot be estimated cheaply
+const defaultItemCost = 64
+
+// Stats describes the current size of the cache and how many entries were evicted
+type Stats struct {
+	Entries   int
+	Bytes     int64
+	Evictions uint64
+}
+
+// Stats returns entry count, total cost and eviction count for key-value entries
+func (c *LocalCache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Stats{
+		Entries:   len(c.data),
+		Bytes:     c.bytes,
+		Evictions: c.evicted,
+	}
+}
+
+// storeItem inserts or replaces key as most recently used, then evicts down to the configured bounds.
+// Callers must hold c.mu.
+func (c *LocalCache) storeItem(key string, value interface{}, expiration time.Time) {
+	cost := c.cost(key, value)
+
+	if item, exists := c.data[key]; exists {
+		c.bytes += cost - item.cost
+		item.value = value
+		item.expiration = expiration
+		item.cost = cost
+		c.lru.MoveToFront(item.element)
+	} else {
+		item := &cacheItem{
+			key:        key,
+			value:      value,
+			expiration: expiration,
+			cost:       cost,
+		}
+		item.element = c.lru.PushFront(item)
+		c.data[key] = item
+		c.bytes += cost
+	}
+
+	c.evict()
+}
+
+// removeItem deletes key from the map and LRU list. Callers must hold c.mu.
+func (c *LocalCache) removeItem(key string) {
+	item, exists := c.data[key]
+	if !exists {
+		return
+	}
+
+	c.lru.Remove(item.element)
+	c.bytes -= item.cost
+	delete(c.data, key)
+}
+
+// evict drops least recently used entries until both MaxEntries and MaxBytes are satisfied.
+// The most recently stored entry is never evicted, even if it alone exceeds MaxBytes.
+func (c *LocalCache) evict() {
+	for c.lru.Len() > 1 && c.overLimit() {
+		oldest := c.lru.Back().Value.(*cacheItem)
+		c.removeItem(oldest.key)
+		c.evicted++
+	}
+}
+
+func (c *LocalCache) overLimit() bool {
+	if c.options.MaxEntries > 0 && len(c.data) > c.options.MaxEntries {
+		return true
+	}
+	return c.options.MaxBytes > 0 && c.bytes > c.options.MaxBytes
+}
+
+func (c *LocalCache) cost(key string, value interface{}) int64 {
+	if c.options.CostFunc != nil {
+		return c.options.CostFunc(key, value)
+	}
+	return estimateCost(key, value)
+}
+
+// estimateCost approximates the memory held by an entry
+func estimateCost(key string, value interface{}) int64 {
+	size := int64(len(key))
+
+	switch v := value.(type) {
+	case string:
+		size += int64(len(v))
+	case []byte:
+		size += int64(len(v))
+	case bool, int8, uint8:
+		size++
+	case int16, uint16:
+		size += 2
+	case int32, uint32, float32:
+		size += 4
+	case int, int64, uint, uint64, float64:
+		size += 8
+	default:
+		size += defaultItemCost
+	}
+
+	return size
+}
@@ -0,0 +1,82 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// localSubscription is an in-process cache.Subscription
+type localSubscription struct {
+	cache    *LocalCache
+	channels []string
+	messages chan *cache.Message
+	once     sync.Once
+}
+
+// Publish delivers the JSON-encoded message to in-process subscribers of channel.
+// Messages are dropped for subscribers whose buffer is full, mirroring Redis PUBSUB semantics
+// where slow consumers lose messages rather than blocking publishers.
+func (c *LocalCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	c.subMu.RLock()
+	defer c.subMu.RUnlock()
+
+	for sub := range c.subscribers[channel] {
+		select {
+		case sub.messages <- &cache.Message{Channel: channel, Payload: string(data)}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Subscribe listens for messages on the given channels
+func (c *LocalCache) Subscribe(ctx context.Context, channels ...string) (cache.Subscription, error) {
+	sub := &localSubscription{
+		cache:    c,
+		channels: channels,
+		messages: make(chan *cache.Message, 100),
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	for _, channel := range channels {
+		if c.subscribers[channel] == nil {
+			c.subscribers[channel] = make(map[*localSubscription]struct{})
+		}
+		c.subscribers[channel][sub] = struct{}{}
+	}
+
+	return sub, nil
+}
+
+// Channel returns the stream of received messages
+func (s *localSubscription) Channel() <-chan *cache.Message {
+	return s.messages
+}
+
+// Close unsubscribes from all channels
+func (s *localSubscription) Close() error {
+	s.once.Do(func() {
+		s.cache.subMu.Lock()
+		defer s.cache.subMu.Unlock()
+
+		for _, channel := range s.channels {
+			delete(s.cache.subscribers[channel], s)
+			if len(s.cache.subscribers[channel]) == 0 {
+				delete(s.cache.subscribers, channel)
+			}
+		}
+		close(s.messages)
+	})
+	return nil
+}
@@ -2,25 +2,32 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/yadunandan004/scaffold/singleton"
 	"github.com/yadunandan004/scaffold/store/cache"
+	"github.com/yadunandan004/scaffold/store/readiness"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// Connection modes selected via RedisConfig.Mode
+const (
+	ModeSingle   = "single"
+	ModeCluster  = "cluster"
+	ModeSentinel = "sentinel"
+)
+
 var (
-	globalClient *redis.Client
+	globalClient redis.UniversalClient
 	clientMutex  sync.RWMutex
 	configured   bool
 )
 
 // SetGlobalClient sets the global Redis client
-func SetGlobalClient(client *redis.Client) {
+func SetGlobalClient(client redis.UniversalClient) {
 	clientMutex.Lock()
 	defer clientMutex.Unlock()
 	globalClient = client
@@ -28,7 +35,7 @@ func SetGlobalClient(client *redis.Client) {
 }
 
 // GetGlobalClient returns the global Redis client
-func GetGlobalClient() *redis.Client {
+func GetGlobalClient() redis.UniversalClient {
 	clientMutex.RLock()
 	defer clientMutex.RUnlock()
 	return globalClient
@@ -52,17 +59,22 @@ func Ping(ctx context.Context) error {
 	return err
 }
 
-var ErrKeyNotFound = errors.New("key not found")
+// WaitForReady pings the global client with backoff until Redis answers, ctx ends or timeout passes
+func WaitForReady(ctx context.Context, timeout time.Duration) error {
+	return readiness.Poll(ctx, timeout, "redis", Ping)
+}
+
+var ErrKeyNotFound = cache.ErrKeyNotFound
 
 // RedisCache implements CacheService using Redis
 type RedisCache struct {
-	client  *redis.Client
+	client  redis.UniversalClient
 	options *cache.CacheOptions
 }
 
 // RedisCacheBuilder implements the builder pattern for dependency injection
 type RedisCacheBuilder struct {
-	client  *redis.Client
+	client  redis.UniversalClient
 	options *cache.CacheOptions
 }
 
@@ -75,20 +87,25 @@ func (b RedisCacheBuilder) Build() cache.CacheService {
 
 // RedisConfig contains Redis connection configuration
 type RedisConfig struct {
-	Host         string
-	Port         int
-	Password     string
-	DB           int
-	MaxRetries   int
-	DialTimeout  time.Duration
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	PoolSize     int
+	Mode             string
+	Host             string
+	Port             int
+	Addrs            []string
+	MasterName       string
+	Password         string
+	SentinelPassword string
+	DB               int
+	MaxRetries       int
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	PoolSize         int
 }
 
 // DefaultRedisConfig returns default Redis configuration
 func DefaultRedisConfig() *RedisConfig {
 	return &RedisConfig{
+		Mode:         ModeSingle,
 		Host:         "localhost",
 		Port:         6379,
 		Password:     "",
@@ -119,8 +136,74 @@ func NewRedisClient(config *RedisConfig) *redis.Client {
 	})
 }
 
+// NewClusterClient creates a Redis Cluster client from config.Addrs
+func NewClusterClient(config *RedisConfig) *redis.ClusterClient {
+	if config == nil {
+		config = DefaultRedisConfig()
+	}
+
+	return redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:        config.addrs(),
+		Password:     config.Password,
+		MaxRetries:   config.MaxRetries,
+		DialTimeout:  config.DialTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+		PoolSize:     config.PoolSize,
+	})
+}
+
+// NewSentinelClient creates a failover client that discovers the master through Sentinel
+func NewSentinelClient(config *RedisConfig) *redis.Client {
+	if config == nil {
+		config = DefaultRedisConfig()
+	}
+
+	return redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:       config.MasterName,
+		SentinelAddrs:    config.addrs(),
+		SentinelPassword: config.SentinelPassword,
+		Password:         config.Password,
+		DB:               config.DB,
+		MaxRetries:       config.MaxRetries,
+		DialTimeout:      config.DialTimeout,
+		ReadTimeout:      config.ReadTimeout,
+		WriteTimeout:     config.WriteTimeout,
+		PoolSize:         config.PoolSize,
+	})
+}
+
+// NewUniversalClient creates a single-node, cluster or sentinel client based on config.Mode
+func NewUniversalClient(config *RedisConfig) (redis.UniversalClient, error) {
+	if config == nil {
+		config = DefaultRedisConfig()
+	}
+
+	switch config.Mode {
+	case "", ModeSingle:
+		return NewRedisClient(config), nil
+	case ModeCluster:
+		return NewClusterClient(config), nil
+	case ModeSentinel:
+		if config.MasterName == "" {
+			return nil, fmt.Errorf("redis sentinel mode requires a master name")
+		}
+		return NewSentinelClient(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported redis mode: %s", config.Mode)
+	}
+}
+
+// addrs returns the configured seed addresses, falling back to Host:Port
+func (config *RedisConfig) addrs() []string {
+	if len(config.Addrs) > 0 {
+		return config.Addrs
+	}
+	return []string{fmt.Sprintf("%s:%d", config.Host, config.Port)}
+}
+
 // NewRedisCache creates a new Redis cache instance
-func NewRedisCache(client *redis.Client, options *cache.CacheOptions) *RedisCache {
+func NewRedisCache(client redis.UniversalClient, options *cache.CacheOptions) *RedisCache {
 	if options == nil {
 		options = cache.DefaultCacheOptions()
 	}
@@ -131,6 +214,19 @@ func NewRedisCache(client *redis.Client, options *cache.CacheOptions) *RedisCach
 	}
 }
 
+// Client returns the underlying client, for atomic operations CacheService doesn't cover such as Lua scripts
+func (c *RedisCache) Client() redis.UniversalClient {
+	return c.client
+}
+
+// codec returns the configured value codec, defaulting to JSON
+func (c *RedisCache) codec() cache.Codec {
+	if c.options.Codec != nil {
+		return c.options.Codec
+	}
+	return cache.JSONCodec{}
+}
+
 // Get retrieves a value by key
 func (c *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
 	val, err := c.client.Get(ctx, key).Result()
@@ -141,10 +237,10 @@ func (c *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
 		return nil, err
 	}
 
-	// Try to unmarshal as JSON first
+	// Try to decode with the configured codec first
 	var result interface{}
-	if err := json.Unmarshal([]byte(val), &result); err != nil {
-		// If not JSON, return as string
+	if err := c.codec().Unmarshal([]byte(val), &result); err != nil {
+		// If not decodable, return as string
 		return val, nil
 	}
 
@@ -153,8 +249,7 @@ func (c *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
 
 // Set stores a key-value pair with optional expiration
 func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	// Marshal value to JSON
-	data, err := json.Marshal(value)
+	data, err := c.codec().Marshal(value)
 	if err != nil {
 		return err
 	}
@@ -180,10 +275,10 @@ func (c *RedisCache) MGet(ctx context.Context, keys ...string) ([]interface{}, e
 			continue
 		}
 
-		// Try to unmarshal as JSON
+		// Try to decode with the configured codec
 		var result interface{}
-		if err := json.Unmarshal([]byte(val.(string)), &result); err != nil {
-			// If not JSON, return as string
+		if err := c.codec().Unmarshal([]byte(val.(string)), &result); err != nil {
+			// If not decodable, return as string
 			results[i] = val
 		} else {
 			results[i] = result
@@ -203,7 +298,7 @@ func (c *RedisCache) MSet(ctx context.Context, pairs map[string]interface{}, exp
 	}
 
 	for key, value := range pairs {
-		data, err := json.Marshal(value)
+		data, err := c.codec().Marshal(value)
 		if err != nil {
 			return err
 		}
@@ -224,10 +319,10 @@ func (c *RedisCache) HGet(ctx context.Context, key, field string) (interface{},
 		return nil, err
 	}
 
-	// Try to unmarshal as JSON
+	// Try to decode with the configured codec
 	var result interface{}
-	if err := json.Unmarshal([]byte(val), &result); err != nil {
-		// If not JSON, return as string
+	if err := c.codec().Unmarshal([]byte(val), &result); err != nil {
+		// If not decodable, return as string
 		return val, nil
 	}
 
@@ -236,7 +331,7 @@ func (c *RedisCache) HGet(ctx context.Context, key, field string) (interface{},
 
 // HSet stores a hash field value
 func (c *RedisCache) HSet(ctx context.Context, key, field string, value interface{}) error {
-	data, err := json.Marshal(value)
+	data, err := c.codec().Marshal(value)
 	if err != nil {
 		return err
 	}
@@ -258,10 +353,10 @@ func (c *RedisCache) HMGet(ctx context.Context, key string, fields ...string) ([
 			continue
 		}
 
-		// Try to unmarshal as JSON
+		// Try to decode with the configured codec
 		var result interface{}
-		if err := json.Unmarshal([]byte(val.(string)), &result); err != nil {
-			// If not JSON, return as string
+		if err := c.codec().Unmarshal([]byte(val.(string)), &result); err != nil {
+			// If not decodable, return as string
 			results[i] = val
 		} else {
 			results[i] = result
@@ -275,7 +370,7 @@ func (c *RedisCache) HMGet(ctx context.Context, key string, fields ...string) ([
 func (c *RedisCache) HMSet(ctx context.Context, key string, values map[string]interface{}) error {
 	data := make(map[string]interface{})
 	for field, value := range values {
-		marshaled, err := json.Marshal(value)
+		marshaled, err := c.codec().Marshal(value)
 		if err != nil {
 			return err
 		}
@@ -322,6 +417,80 @@ func (c *RedisCache) TTL(ctx context.Context, key string) (time.Duration, error)
 	return ttl, nil
 }
 
+// Incr atomically increments the integer value of key by one
+func (c *RedisCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, key).Result()
+}
+
+// Decr atomically decrements the integer value of key by one
+func (c *RedisCache) Decr(ctx context.Context, key string) (int64, error) {
+	return c.client.Decr(ctx, key).Result()
+}
+
+// IncrBy atomically increments the integer value of key by delta
+func (c *RedisCache) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return c.client.IncrBy(ctx, key, delta).Result()
+}
+
+// SAdd adds members to a set
+func (c *RedisCache) SAdd(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	return c.client.SAdd(ctx, key, toInterfaces(members)...).Err()
+}
+
+// SMembers returns all members of a set
+func (c *RedisCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	return c.client.SMembers(ctx, key).Result()
+}
+
+// SRem removes members from a set
+func (c *RedisCache) SRem(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	return c.client.SRem(ctx, key, toInterfaces(members)...).Err()
+}
+
+// ZAdd adds members with scores to a sorted set
+func (c *RedisCache) ZAdd(ctx context.Context, key string, members ...cache.ZMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	zs := make([]redis.Z, len(members))
+	for i, m := range members {
+		zs[i] = redis.Z{Score: m.Score, Member: m.Member}
+	}
+	return c.client.ZAdd(ctx, key, zs...).Err()
+}
+
+// ZRangeByScore returns sorted set members with min <= score <= max, ordered by score
+func (c *RedisCache) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]cache.ZMember, error) {
+	zs, err := c.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatFloat(min, 'f', -1, 64),
+		Max: strconv.FormatFloat(max, 'f', -1, 64),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]cache.ZMember, len(zs))
+	for i, z := range zs {
+		member, _ := z.Member.(string)
+		results[i] = cache.ZMember{Score: z.Score, Member: member}
+	}
+	return results, nil
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
 // Close closes the Redis connection
 func (c *RedisCache) Close() error {
 	return c.client.Close()
@@ -330,4 +499,5 @@ func (c *RedisCache) Close() error {
 // Register the builder with the injector
 func init() {
 	singleton.Inject[RedisCacheBuilder, cache.CacheService]()
+	singleton.Register[RedisCacheBuilder, cache.CacheService]("redis")
 }
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// scanCount is the SCAN COUNT hint per round trip
+const scanCount = 100
+
+// Keys returns all keys matching pattern using SCAN, never KEYS
+func (c *RedisCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+
+	err := c.Iterate(ctx, pattern, func(key string) error {
+		mu.Lock()
+		keys = append(keys, key)
+		mu.Unlock()
+		return nil
+	})
+	return keys, err
+}
+
+// Iterate calls fn for each key matching pattern using SCAN.
+// On a cluster every master is scanned concurrently, so fn must be safe for concurrent use.
+func (c *RedisCache) Iterate(ctx context.Context, pattern string, fn func(key string) error) error {
+	return c.scanPages(ctx, pattern, func(ctx context.Context, node redis.Cmdable, keys []string) error {
+		for _, key := range keys {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByPrefix removes every key starting with prefix, one SCAN page at a time
+func (c *RedisCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	pattern := cache.EscapePattern(prefix) + "*"
+
+	return c.scanPages(ctx, pattern, func(ctx context.Context, node redis.Cmdable, keys []string) error {
+		// Unlink individually so keys in different cluster slots do not fail with CROSSSLOT
+		pipe := node.Pipeline()
+		for _, key := range keys {
+			pipe.Unlink(ctx, key)
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
+
+// scanPages runs SCAN MATCH pattern on every node holding data and hands each page to fn
+func (c *RedisCache) scanPages(ctx context.Context, pattern string, fn func(ctx context.Context, node redis.Cmdable, keys []string) error) error {
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanNode(ctx, node, pattern, fn)
+		})
+	}
+	return scanNode(ctx, c.client, pattern, fn)
+}
+
+func scanNode(ctx context.Context, node redis.Cmdable, pattern string, fn func(ctx context.Context, node redis.Cmdable, keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			if err := fn(ctx, node, keys); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
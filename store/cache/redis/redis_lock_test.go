@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+func newTestCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisCache(client, nil), server
+}
+
+func TestRedisCache_Lock(t *testing.T) {
+	c, server := newTestCache(t)
+	ctx := context.Background()
+
+	first, err := c.Lock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Greater(t, first.Token(), int64(1_000_000_000_000_000))
+
+	_, err = c.Lock(ctx, "job", time.Minute)
+	assert.ErrorIs(t, err, cache.ErrLockNotAcquired)
+
+	require.NoError(t, first.Unlock(ctx))
+	assert.ErrorIs(t, first.Unlock(ctx), cache.ErrLockNotHeld)
+
+	second, err := c.Lock(ctx, "job", 0)
+	require.NoError(t, err)
+	assert.Greater(t, second.Token(), first.Token())
+	assert.Equal(t, time.Duration(0), server.TTL("lock:{job}"))
+	assert.Greater(t, server.TTL("lock:{job}:fence"), 23*time.Hour)
+}
+
+func TestRedisCache_LockExpires(t *testing.T) {
+	c, server := newTestCache(t)
+	ctx := context.Background()
+
+	first, err := c.Lock(ctx, "job", time.Second)
+	require.NoError(t, err)
+	server.FastForward(2 * time.Second)
+
+	second, err := c.Lock(ctx, "job", time.Second)
+	require.NoError(t, err)
+	assert.Greater(t, second.Token(), first.Token())
+	assert.ErrorIs(t, first.Unlock(ctx), cache.ErrLockNotHeld)
+	require.NoError(t, second.Unlock(ctx))
+}
+
+func TestRedisCache_LockFenceRestartsAboveOldTokens(t *testing.T) {
+	c, server := newTestCache(t)
+	ctx := context.Background()
+
+	first, err := c.Lock(ctx, "job", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, first.Unlock(ctx))
+
+	server.FastForward(fenceTTL + 2*time.Second)
+	assert.False(t, server.Exists("lock:{job}:fence"))
+	server.SetTime(time.Now().Add(time.Minute))
+
+	second, err := c.Lock(ctx, "job", time.Second)
+	require.NoError(t, err)
+	assert.Greater(t, second.Token(), first.Token())
+}
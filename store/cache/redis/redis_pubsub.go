@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yadunandan004/scaffold/store/cache"
+)
+
+// redisSubscription adapts a go-redis PubSub to cache.Subscription
+type redisSubscription struct {
+	pubsub   *redis.PubSub
+	messages chan *cache.Message
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Publish sends the JSON-encoded message to all subscribers of channel
+func (c *RedisCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, channel, data).Err()
+}
+
+// Subscribe listens for messages on the given channels using Redis PUBSUB
+func (c *RedisCache) Subscribe(ctx context.Context, channels ...string) (cache.Subscription, error) {
+	pubsub := c.client.Subscribe(ctx, channels...)
+
+	// Wait for confirmation so messages published after Subscribe returns are not missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	sub := &redisSubscription{
+		pubsub:   pubsub,
+		messages: make(chan *cache.Message, 100),
+		done:     make(chan struct{}),
+	}
+	go sub.forward()
+
+	return sub, nil
+}
+
+func (s *redisSubscription) forward() {
+	defer close(s.messages)
+	for msg := range s.pubsub.Channel() {
+		select {
+		case s.messages <- &cache.Message{Channel: msg.Channel, Payload: msg.Payload}:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Channel returns the stream of received messages
+func (s *redisSubscription) Channel() <-chan *cache.Message {
+	return s.messages
+}
+
+// Close unsubscribes from all channels
+func (s *redisSubscription) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return s.pubsub.Close()
+}
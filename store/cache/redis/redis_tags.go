@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+const tagKeyPrefix = "tag:"
+
+// SetWithTags stores a key-value pair and records the key in a Redis set per tag.
+// Tag sets carry no expiration; stale members are harmless and removed on InvalidateTag.
+func (c *RedisCache) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	data, err := c.codec().Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if expiration == 0 && c.options.DefaultExpiration > 0 {
+		expiration = c.options.DefaultExpiration
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, key, data, expiration)
+	for _, tag := range tags {
+		pipe.SAdd(ctx, tagKeyPrefix+tag, key)
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// InvalidateTag deletes every key associated with tag, along with the tag set itself
+func (c *RedisCache) InvalidateTag(ctx context.Context, tag string) error {
+	tagKey := tagKeyPrefix + tag
+
+	keys, err := c.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return err
+	}
+
+	// Delete individually so tagged keys may live in different cluster slots
+	pipe := c.client.Pipeline()
+	for _, key := range append(keys, tagKey) {
+		pipe.Del(ctx, key)
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
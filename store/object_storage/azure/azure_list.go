@@ -0,0 +1,95 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// copyPollInterval is how often Copy checks an asynchronous server-side copy
+const copyPollInterval = 500 * time.Millisecond
+
+// List returns blobs whose names start with prefix
+func (s *AzureStorage) List(ctx context.Context, bucket, prefix string, opts *object_storage.ListOptions) ([]object_storage.ObjectInfo, error) {
+	if opts == nil {
+		opts = &object_storage.ListOptions{}
+	}
+
+	var objects []object_storage.ObjectInfo
+	pager := s.client.NewListBlobsFlatPager(bucket, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s/%s: %w", bucket, prefix, mapError(err))
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || *item.Name <= opts.StartAfter {
+				continue
+			}
+
+			info := object_storage.ObjectInfo{Key: *item.Name}
+			if props := item.Properties; props != nil {
+				if props.ContentLength != nil {
+					info.Size = *props.ContentLength
+				}
+				if props.ETag != nil {
+					info.ETag = string(*props.ETag)
+				}
+				if props.LastModified != nil {
+					info.LastModified = *props.LastModified
+				}
+			}
+
+			objects = append(objects, info)
+			if opts.MaxKeys > 0 && len(objects) >= opts.MaxKeys {
+				return objects, nil
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// Copy starts a server-side copy and waits for it to finish
+func (s *AzureStorage) Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	dst := s.blob(dstBucket, dstKey)
+
+	resp, err := dst.StartCopyFromURL(ctx, s.blob(srcBucket, srcKey).URL(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s/%s to %s/%s: %w", srcBucket, srcKey, dstBucket, dstKey, mapError(err))
+	}
+
+	status := resp.CopyStatus
+	for status != nil && *status == blob.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(copyPollInterval):
+		}
+
+		props, err := dst.GetProperties(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to check copy of %s/%s: %w", dstBucket, dstKey, err)
+		}
+		status = props.CopyStatus
+	}
+
+	if status != nil && *status != blob.CopyStatusTypeSuccess {
+		return fmt.Errorf("copy of %s/%s to %s/%s ended with status %s", srcBucket, srcKey, dstBucket, dstKey, *status)
+	}
+	return nil
+}
+
+// Move copies a blob then deletes the source
+func (s *AzureStorage) Move(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	if err := s.Copy(ctx, srcBucket, srcKey, dstBucket, dstKey); err != nil {
+		return err
+	}
+	return s.Delete(ctx, srcBucket, srcKey)
+}
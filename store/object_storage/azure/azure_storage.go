@@ -0,0 +1,247 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// ProviderName selects this backend via object_storage.Config.Provider
+const ProviderName = "azure"
+
+func init() {
+	object_storage.RegisterProvider(ProviderName, func(ctx context.Context, cfg *object_storage.Config) (object_storage.ObjectStorage, error) {
+		return NewAzureStorage(cfg)
+	})
+}
+
+// AzureStorage implements ObjectStorage using Azure Blob Storage, where buckets map to containers
+type AzureStorage struct {
+	client *azblob.Client
+	config *object_storage.Config
+}
+
+// NewAzureStorage creates a client authenticated with the account shared key.
+// Endpoint defaults to https://<account>.blob.core.windows.net and may point at Azurite.
+func NewAzureStorage(cfg *object_storage.Config) (*AzureStorage, error) {
+	if cfg.AccountName == "" || cfg.AccountKey == "" {
+		return nil, fmt.Errorf("azure storage requires account name and key")
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(cfg.AccountName, cfg.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure credentials: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net/", cfg.AccountName)
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(endpoint, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure blob client: %w", err)
+	}
+
+	return &AzureStorage{client: client, config: cfg}, nil
+}
+
+func (s *AzureStorage) blob(bucket, key string) *blob.Client {
+	return s.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(key)
+}
+
+func isNotFound(err error) bool {
+	return bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound, bloberror.ResourceNotFound)
+}
+
+// mapError converts Azure not-found errors to object_storage.ErrObjectNotFound
+func mapError(err error) error {
+	if isNotFound(err) {
+		return fmt.Errorf("%w: %v", object_storage.ErrObjectNotFound, err)
+	}
+	return err
+}
+
+// deref returns the value p points to, or the zero value when p is nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// Upload uploads data to the specified key
+func (s *AzureStorage) Upload(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.UploadWithOptions(ctx, bucket, key, data, &object_storage.UploadOptions{ContentType: contentType})
+}
+
+// UploadWithOptions uploads data with metadata, tags and caching settings.
+// Blobs are always encrypted at rest; with SSEKMS, KMSKeyID names the encryption scope to use.
+// Checksums are recorded as metadata only, since block blob uploads report no whole-blob digest.
+func (s *AzureStorage) UploadWithOptions(ctx context.Context, bucket, key string, data io.Reader, opts *object_storage.UploadOptions) error {
+	prepared, err := object_storage.PrepareUpload(data, opts)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	defer prepared.Close()
+	opts = prepared.Options
+
+	headers := &blob.HTTPHeaders{}
+	if opts.ContentType != "" {
+		headers.BlobContentType = &opts.ContentType
+	}
+	if opts.CacheControl != "" {
+		headers.BlobCacheControl = &opts.CacheControl
+	}
+
+	uploadOpts := &azblob.UploadStreamOptions{
+		HTTPHeaders: headers,
+		Tags:        opts.Tags,
+	}
+	if len(opts.Metadata) > 0 {
+		uploadOpts.Metadata = make(map[string]*string, len(opts.Metadata))
+		for k, v := range opts.Metadata {
+			uploadOpts.Metadata[k] = to.Ptr(v)
+		}
+	}
+	if opts.ServerSideEncryption == object_storage.SSEKMS && opts.KMSKeyID != "" {
+		uploadOpts.CPKScopeInfo = &blob.CPKScopeInfo{EncryptionScope: &opts.KMSKeyID}
+	}
+
+	if _, err := s.client.UploadStream(ctx, bucket, key, prepared.Body, uploadOpts); err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Head returns the stored attributes of a blob, including its tags
+func (s *AzureStorage) Head(ctx context.Context, bucket, key string) (*object_storage.ObjectMetadata, error) {
+	blobClient := s.blob(bucket, key)
+
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to head %s/%s: %w", bucket, key, mapError(err))
+	}
+
+	meta := &object_storage.ObjectMetadata{
+		Size:                 deref(props.ContentLength),
+		ContentType:          deref(props.ContentType),
+		CacheControl:         deref(props.CacheControl),
+		LastModified:         deref(props.LastModified),
+		ServerSideEncryption: object_storage.SSEProviderManaged,
+		Metadata:             make(map[string]string, len(props.Metadata)),
+	}
+	if props.ETag != nil {
+		meta.ETag = string(*props.ETag)
+	}
+	for k, v := range props.Metadata {
+		meta.Metadata[k] = deref(v)
+	}
+	if props.EncryptionScope != nil {
+		meta.ServerSideEncryption = object_storage.SSEKMS
+		meta.KMSKeyID = *props.EncryptionScope
+	}
+
+	if deref(props.TagCount) > 0 {
+		tags, err := blobClient.GetTags(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tags of %s/%s: %w", bucket, key, mapError(err))
+		}
+		meta.Tags = make(map[string]string, len(tags.BlobTagSet))
+		for _, tag := range tags.BlobTagSet {
+			meta.Tags[deref(tag.Key)] = deref(tag.Value)
+		}
+	}
+
+	return meta, nil
+}
+
+// BatchUpload uploads files concurrently, bounded by Config.MaxConcurrency
+func (s *AzureStorage) BatchUpload(ctx context.Context, uploads []object_storage.BatchUploadInput) *object_storage.BatchUploadResult {
+	return object_storage.RunBatchUpload(ctx, uploads, s.config.MaxConcurrency, func(ctx context.Context, input object_storage.BatchUploadInput) error {
+		return s.Upload(ctx, input.Bucket, input.Key, input.Data, input.ContentType)
+	})
+}
+
+// UploadWithValidation validates the arguments, uploads, and confirms the blob is readable
+func (s *AzureStorage) UploadWithValidation(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	if err := object_storage.ValidateUpload(bucket, key, data, contentType); err != nil {
+		return err
+	}
+	if err := s.Upload(ctx, bucket, key, data, contentType); err != nil {
+		return err
+	}
+
+	if _, err := s.blob(bucket, key).GetProperties(ctx, nil); err != nil {
+		return fmt.Errorf("failed to verify upload %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Download retrieves data from the specified key
+func (s *AzureStorage) Download(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	resp, err := s.client.DownloadStream(ctx, bucket, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s/%s: %w", bucket, key, mapError(err))
+	}
+	return resp.Body, nil
+}
+
+// BatchDownload downloads objects concurrently, bounded by Config.MaxConcurrency
+func (s *AzureStorage) BatchDownload(ctx context.Context, downloads []object_storage.BatchDownloadInput) *object_storage.BatchDownloadResult {
+	return object_storage.RunBatchDownload(ctx, s, downloads, s.config.MaxConcurrency)
+}
+
+// Delete removes the object at the specified key
+func (s *AzureStorage) Delete(ctx context.Context, bucket, key string) error {
+	if _, err := s.client.DeleteBlob(ctx, bucket, key, nil); err != nil {
+		return fmt.Errorf("failed to delete %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Update replaces the object at the specified key
+func (s *AzureStorage) Update(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.Upload(ctx, bucket, key, data, contentType)
+}
+
+// Exists checks if an object exists at the specified key
+func (s *AzureStorage) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := s.blob(bucket, key).GetProperties(ctx, nil)
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetURL returns a read-only SAS URL valid for Config.URLExpiry seconds.
+// Falls back to the unsigned blob URL if signing fails.
+func (s *AzureStorage) GetURL(bucket, key string) string {
+	blobClient := s.blob(bucket, key)
+
+	expiry := time.Duration(s.config.URLExpiry) * time.Second
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+
+	signed, err := blobClient.GetSASURL(sas.BlobPermissions{Read: true}, time.Now().UTC().Add(expiry), nil)
+	if err != nil {
+		return blobClient.URL()
+	}
+	return signed
+}
+
+var _ object_storage.ObjectStorage = (*AzureStorage)(nil)
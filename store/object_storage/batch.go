@@ -0,0 +1,55 @@
+package object_storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxConcurrency bounds parallel uploads in BatchUpload
+const DefaultMaxConcurrency = 10
+
+// RunBatchUpload uploads inputs with at most concurrency uploads in flight.
+// Providers use it to implement BatchUpload consistently.
+func RunBatchUpload(ctx context.Context, uploads []BatchUploadInput, concurrency int, upload func(ctx context.Context, input BatchUploadInput) error) *BatchUploadResult {
+	if concurrency <= 0 {
+		concurrency = DefaultMaxConcurrency
+	}
+
+	result := &BatchUploadResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, input := range uploads {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("%s/%s: %w", input.Bucket, input.Key, ctx.Err()))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(input BatchUploadInput) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := upload(ctx, input)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Errorf("%s/%s: %w", input.Bucket, input.Key, err))
+				return
+			}
+			result.Successful++
+		}(input)
+	}
+
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,45 @@
+package object_storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunBatchUpload(t *testing.T) {
+	uploads := []BatchUploadInput{
+		{Bucket: "b", Key: "a.txt", Data: strings.NewReader("a")},
+		{Bucket: "b", Key: "b.txt", Data: strings.NewReader("b")},
+		{Bucket: "b", Key: "fail.txt", Data: strings.NewReader("c")},
+		{Bucket: "b", Key: "d.txt", Data: strings.NewReader("d")},
+	}
+
+	var inFlight, peak int32
+	result := RunBatchUpload(context.Background(), uploads, 2, func(ctx context.Context, input BatchUploadInput) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if input.Key == "fail.txt" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	assert.Equal(t, 3, result.Successful)
+	assert.Equal(t, 1, result.Failed)
+	assert.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "b/fail.txt")
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+}
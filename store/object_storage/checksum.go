@@ -0,0 +1,225 @@
+package object_storage
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// ChecksumAlgorithm selects the digest computed on upload
+type ChecksumAlgorithm string
+
+const (
+	ChecksumMD5    ChecksumAlgorithm = "md5"
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+)
+
+// checksumMetadataPrefix prefixes the metadata key holding the upload digest, e.g. "checksum_sha256"
+const checksumMetadataPrefix = "checksum_"
+
+var (
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrNoChecksum       = errors.New("no checksum recorded for object")
+)
+
+// NewHash returns a hash for alg
+func NewHash(alg ChecksumAlgorithm) (hash.Hash, error) {
+	switch alg {
+	case ChecksumMD5:
+		return md5.New(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %q", alg)
+	}
+}
+
+// ComputeChecksum returns the hex digest of everything read from r
+func ComputeChecksum(r io.Reader, alg ChecksumAlgorithm) (string, error) {
+	h, err := NewHash(alg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ChecksumMetadataKey is the metadata key under which the digest for alg is stored
+func ChecksumMetadataKey(alg ChecksumAlgorithm) string {
+	return checksumMetadataPrefix + string(alg)
+}
+
+// ChecksumFromMetadata finds a stored digest; keys are matched case-insensitively since some backends change their case
+func ChecksumFromMetadata(metadata map[string]string) (ChecksumAlgorithm, string, bool) {
+	for _, alg := range []ChecksumAlgorithm{ChecksumSHA256, ChecksumMD5} {
+		key := ChecksumMetadataKey(alg)
+		for k, v := range metadata {
+			if strings.EqualFold(k, key) {
+				return alg, v, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// PreparedUpload is an upload body whose digest has been computed ahead of sending
+type PreparedUpload struct {
+	Body     io.Reader
+	Options  *UploadOptions
+	Checksum string
+	cleanup  func()
+}
+
+// PrepareUpload computes opts.Checksum before upload so the digest can be stored as object metadata.
+// Seekable readers are rewound; other readers are spooled to a temporary file removed by Close.
+// Returns ErrChecksumMismatch when opts.ExpectedChecksum is set and differs.
+func PrepareUpload(data io.Reader, opts *UploadOptions) (*PreparedUpload, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	prepared := &PreparedUpload{Body: data, Options: opts}
+	if opts.Checksum == "" {
+		return prepared, nil
+	}
+
+	h, err := NewHash(opts.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	if seeker, ok := data.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(h, seeker); err != nil {
+			return nil, err
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+	} else {
+		spool, err := os.CreateTemp("", "object-upload-*")
+		if err != nil {
+			return nil, err
+		}
+		prepared.cleanup = func() {
+			spool.Close()
+			os.Remove(spool.Name())
+		}
+		if _, err := io.Copy(io.MultiWriter(spool, h), data); err != nil {
+			prepared.Close()
+			return nil, err
+		}
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			prepared.Close()
+			return nil, err
+		}
+		prepared.Body = spool
+	}
+
+	prepared.Checksum = hex.EncodeToString(h.Sum(nil))
+	if opts.ExpectedChecksum != "" && !strings.EqualFold(opts.ExpectedChecksum, prepared.Checksum) {
+		prepared.Close()
+		return nil, fmt.Errorf("%w: expected %s, computed %s", ErrChecksumMismatch, opts.ExpectedChecksum, prepared.Checksum)
+	}
+
+	// Copy so the caller's metadata map is not modified
+	withChecksum := *opts
+	withChecksum.Metadata = make(map[string]string, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		withChecksum.Metadata[k] = v
+	}
+	withChecksum.Metadata[ChecksumMetadataKey(opts.Checksum)] = prepared.Checksum
+	prepared.Options = &withChecksum
+
+	return prepared, nil
+}
+
+// Close removes any temporary spool file
+func (p *PreparedUpload) Close() error {
+	if p.cleanup != nil {
+		p.cleanup()
+		p.cleanup = nil
+	}
+	return nil
+}
+
+// VerifyETag compares an MD5 digest with an ETag that is a plain MD5.
+// Other algorithms and multipart ETags ("<hash>-<parts>") cannot be compared and are accepted.
+func (p *PreparedUpload) VerifyETag(etag string) error {
+	etag = strings.Trim(etag, `"`)
+	if p.Options.Checksum != ChecksumMD5 || p.Checksum == "" || etag == "" || strings.Contains(etag, "-") {
+		return nil
+	}
+	if !strings.EqualFold(etag, p.Checksum) {
+		return fmt.Errorf("%w: stored etag %s, computed %s", ErrChecksumMismatch, etag, p.Checksum)
+	}
+	return nil
+}
+
+// verifyingReader hashes data as it is read and reports a mismatch at EOF
+type verifyingReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+// NewVerifyingReader wraps rc so that reading to EOF returns ErrChecksumMismatch if the content does not match expected
+func NewVerifyingReader(rc io.ReadCloser, alg ChecksumAlgorithm, expected string) (io.ReadCloser, error) {
+	h, err := NewHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{ReadCloser: rc, hash: h, expected: expected}, nil
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if sum := hex.EncodeToString(r.hash.Sum(nil)); !strings.EqualFold(sum, r.expected) {
+			return n, fmt.Errorf("%w: expected %s, computed %s", ErrChecksumMismatch, r.expected, sum)
+		}
+	}
+	return n, err
+}
+
+// DownloadVerified downloads an object through a reader that checks the digest recorded at upload
+func DownloadVerified(ctx context.Context, storage ObjectStorage, bucket, key string) (io.ReadCloser, error) {
+	meta, err := storage.Head(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	alg, expected, ok := ChecksumFromMetadata(meta.Metadata)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNoChecksum, bucket, key)
+	}
+
+	body, err := storage.Download(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyingReader(body, alg, expected)
+}
+
+// VerifyDownload reads the whole object and checks it against the digest recorded at upload
+func VerifyDownload(ctx context.Context, storage ObjectStorage, bucket, key string) error {
+	body, err := DownloadVerified(ctx, storage, bucket, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(io.Discard, body)
+	return err
+}
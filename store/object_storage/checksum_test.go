@@ -0,0 +1,67 @@
+package object_storage
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestPrepareUpload(t *testing.T) {
+	metadata := map[string]string{"owner": "reports"}
+
+	for name, body := range map[string]io.Reader{
+		"seekable": strings.NewReader("hello"),
+		"stream":   io.MultiReader(strings.NewReader("hel"), strings.NewReader("lo")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			prepared, err := PrepareUpload(body, &UploadOptions{Checksum: ChecksumSHA256, Metadata: metadata})
+			require.NoError(t, err)
+			defer prepared.Close()
+
+			assert.Equal(t, helloSHA256, prepared.Checksum)
+			assert.Equal(t, helloSHA256, prepared.Options.Metadata["checksum_sha256"])
+			assert.Equal(t, "reports", prepared.Options.Metadata["owner"])
+			assert.NotContains(t, metadata, "checksum_sha256")
+
+			data, err := io.ReadAll(prepared.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "hello", string(data))
+		})
+	}
+}
+
+func TestPrepareUpload_ExpectedChecksum(t *testing.T) {
+	_, err := PrepareUpload(strings.NewReader("hello"), &UploadOptions{Checksum: ChecksumSHA256, ExpectedChecksum: "deadbeef"})
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	prepared, err := PrepareUpload(strings.NewReader("hello"), &UploadOptions{Checksum: ChecksumSHA256, ExpectedChecksum: strings.ToUpper(helloSHA256)})
+	require.NoError(t, err)
+	prepared.Close()
+}
+
+func TestPreparedUpload_VerifyETag(t *testing.T) {
+	prepared, err := PrepareUpload(strings.NewReader("hello"), &UploadOptions{Checksum: ChecksumMD5})
+	require.NoError(t, err)
+
+	assert.NoError(t, prepared.VerifyETag(`"5d41402abc4b2a76b9719d911017c592"`))
+	assert.NoError(t, prepared.VerifyETag("9b2cf535f27731c974343645a3985328-3"))
+	assert.ErrorIs(t, prepared.VerifyETag("00000000000000000000000000000000"), ErrChecksumMismatch)
+}
+
+func TestNewVerifyingReader(t *testing.T) {
+	reader, err := NewVerifyingReader(io.NopCloser(bytes.NewReader([]byte("hello"))), ChecksumSHA256, helloSHA256)
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	reader, err = NewVerifyingReader(io.NopCloser(bytes.NewReader([]byte("hellO"))), ChecksumSHA256, helloSHA256)
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
@@ -0,0 +1,75 @@
+package object_storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/yadunandan004/scaffold/config"
+)
+
+// Config selects and configures an object storage provider
+type Config struct {
+	Provider        string `yaml:"provider"`
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	UsePathStyle    bool   `yaml:"use_path_style"`
+	RootDir         string `yaml:"root_dir"`
+	PublicURL       string `yaml:"public_url"`
+	ProjectID       string `yaml:"project_id"`
+	CredentialsFile string `yaml:"credentials_file"`
+	AccountName     string `yaml:"account_name"`
+	AccountKey      string `yaml:"account_key"`
+	URLExpiry       int    `yaml:"url_expiry"`
+	MaxConcurrency  int    `yaml:"max_concurrency"`
+}
+
+// ProviderFactory creates an ObjectStorage from configuration
+type ProviderFactory func(ctx context.Context, cfg *Config) (ObjectStorage, error)
+
+var (
+	providers     = make(map[string]ProviderFactory)
+	providerMutex sync.RWMutex
+)
+
+// GetConfig resolves object storage configuration from the config file and environment
+func GetConfig(resolver *config.ConfigResolver) *Config {
+	return &Config{
+		Provider:        resolver.GetString("object_storage.provider", "OBJECT_STORAGE_PROVIDER", ""),
+		Endpoint:        resolver.GetString("object_storage.endpoint", "OBJECT_STORAGE_ENDPOINT", ""),
+		Region:          resolver.GetString("object_storage.region", "OBJECT_STORAGE_REGION", "us-east-1"),
+		AccessKeyID:     resolver.GetString("object_storage.access_key_id", "OBJECT_STORAGE_ACCESS_KEY_ID", ""),
+		SecretAccessKey: resolver.GetString("object_storage.secret_access_key", "OBJECT_STORAGE_SECRET_ACCESS_KEY", ""),
+		UsePathStyle:    resolver.GetBool("object_storage.use_path_style", "OBJECT_STORAGE_USE_PATH_STYLE", false),
+		RootDir:         resolver.GetString("object_storage.root_dir", "OBJECT_STORAGE_ROOT_DIR", ""),
+		PublicURL:       resolver.GetString("object_storage.public_url", "OBJECT_STORAGE_PUBLIC_URL", ""),
+		ProjectID:       resolver.GetString("object_storage.project_id", "OBJECT_STORAGE_PROJECT_ID", ""),
+		CredentialsFile: resolver.GetString("object_storage.credentials_file", "OBJECT_STORAGE_CREDENTIALS_FILE", ""),
+		AccountName:     resolver.GetString("object_storage.account_name", "OBJECT_STORAGE_ACCOUNT_NAME", ""),
+		AccountKey:      resolver.GetString("object_storage.account_key", "OBJECT_STORAGE_ACCOUNT_KEY", ""),
+		URLExpiry:       resolver.GetInt("object_storage.url_expiry", "OBJECT_STORAGE_URL_EXPIRY", 3600),
+		MaxConcurrency:  resolver.GetInt("object_storage.max_concurrency", "OBJECT_STORAGE_MAX_CONCURRENCY", DefaultMaxConcurrency),
+	}
+}
+
+// RegisterProvider makes a provider available to New; providers register themselves in init()
+func RegisterProvider(name string, factory ProviderFactory) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+	providers[name] = factory
+}
+
+// New creates the ObjectStorage selected by cfg.Provider.
+// The provider package must be imported, e.g. _ "github.com/yadunandan004/scaffold/store/object_storage/gcs".
+func New(ctx context.Context, cfg *Config) (ObjectStorage, error) {
+	providerMutex.RLock()
+	factory, ok := providers[cfg.Provider]
+	providerMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown object storage provider: %q", cfg.Provider)
+	}
+	return factory(ctx, cfg)
+}
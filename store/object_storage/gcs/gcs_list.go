@@ -0,0 +1,71 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// List returns objects whose keys start with prefix
+func (s *GCSStorage) List(ctx context.Context, bucket, prefix string, opts *object_storage.ListOptions) ([]object_storage.ObjectInfo, error) {
+	if opts == nil {
+		opts = &object_storage.ListOptions{}
+	}
+
+	query := &storage.Query{Prefix: prefix}
+	if opts.StartAfter != "" {
+		// StartOffset is inclusive, so skip an exact match below
+		query.StartOffset = opts.StartAfter
+	}
+	if err := query.SetAttrSelection([]string{"Name", "Size", "Etag", "Updated"}); err != nil {
+		return nil, err
+	}
+
+	var objects []object_storage.ObjectInfo
+	it := s.client.Bucket(bucket).Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s/%s: %w", bucket, prefix, mapError(err))
+		}
+		if attrs.Name == opts.StartAfter {
+			continue
+		}
+
+		objects = append(objects, object_storage.ObjectInfo{
+			Key:          attrs.Name,
+			Size:         attrs.Size,
+			ETag:         attrs.Etag,
+			LastModified: attrs.Updated,
+		})
+		if opts.MaxKeys > 0 && len(objects) >= opts.MaxKeys {
+			break
+		}
+	}
+
+	return objects, nil
+}
+
+// Copy duplicates an object server-side
+func (s *GCSStorage) Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	if _, err := s.object(dstBucket, dstKey).CopierFrom(s.object(srcBucket, srcKey)).Run(ctx); err != nil {
+		return fmt.Errorf("failed to copy %s/%s to %s/%s: %w", srcBucket, srcKey, dstBucket, dstKey, mapError(err))
+	}
+	return nil
+}
+
+// Move copies an object then deletes the source
+func (s *GCSStorage) Move(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	if err := s.Copy(ctx, srcBucket, srcKey, dstBucket, dstKey); err != nil {
+		return err
+	}
+	return s.Delete(ctx, srcBucket, srcKey)
+}
@@ -0,0 +1,218 @@
+package gcs
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// ProviderName selects this backend via object_storage.Config.Provider
+const ProviderName = "gcs"
+
+const defaultPublicURL = "https://storage.googleapis.com"
+
+func init() {
+	object_storage.RegisterProvider(ProviderName, func(ctx context.Context, cfg *object_storage.Config) (object_storage.ObjectStorage, error) {
+		return NewGCSStorage(ctx, cfg)
+	})
+}
+
+// GCSStorage implements ObjectStorage using Google Cloud Storage
+type GCSStorage struct {
+	client *storage.Client
+	config *object_storage.Config
+}
+
+// NewGCSStorage creates a client using CredentialsFile when set, otherwise Application Default Credentials.
+// Endpoint may point at an emulator such as fake-gcs-server.
+func NewGCSStorage(ctx context.Context, cfg *object_storage.Config) (*GCSStorage, error) {
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+	if cfg.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(cfg.Endpoint))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+
+	return &GCSStorage{client: client, config: cfg}, nil
+}
+
+// Close releases the underlying client
+func (s *GCSStorage) Close() error {
+	return s.client.Close()
+}
+
+func (s *GCSStorage) object(bucket, key string) *storage.ObjectHandle {
+	return s.client.Bucket(bucket).Object(key)
+}
+
+// mapError converts GCS not-found errors to object_storage.ErrObjectNotFound
+func mapError(err error) error {
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return fmt.Errorf("%w: %v", object_storage.ErrObjectNotFound, err)
+	}
+	return err
+}
+
+// Upload uploads data to the specified key
+func (s *GCSStorage) Upload(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.UploadWithOptions(ctx, bucket, key, data, &object_storage.UploadOptions{ContentType: contentType})
+}
+
+// UploadWithOptions uploads data with metadata, caching and encryption settings.
+// GCS has no object tags, so Tags returns object_storage.ErrUnsupported; SSEProviderManaged is the GCS default.
+func (s *GCSStorage) UploadWithOptions(ctx context.Context, bucket, key string, data io.Reader, opts *object_storage.UploadOptions) error {
+	if opts != nil && len(opts.Tags) > 0 {
+		return fmt.Errorf("object tags: %w", object_storage.ErrUnsupported)
+	}
+
+	prepared, err := object_storage.PrepareUpload(data, opts)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	defer prepared.Close()
+	opts = prepared.Options
+
+	writer := s.object(bucket, key).NewWriter(ctx)
+	writer.ContentType = opts.ContentType
+	writer.CacheControl = opts.CacheControl
+	writer.Metadata = opts.Metadata
+	if opts.ServerSideEncryption == object_storage.SSEKMS {
+		writer.KMSKeyName = opts.KMSKeyID
+	}
+
+	if _, err := io.Copy(writer, prepared.Body); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, mapError(err))
+	}
+
+	// GCS reports the MD5 of non-composite objects, which VerifyETag compares for ChecksumMD5
+	if attrs := writer.Attrs(); attrs != nil && len(attrs.MD5) > 0 {
+		if err := prepared.VerifyETag(hex.EncodeToString(attrs.MD5)); err != nil {
+			_ = s.Delete(ctx, bucket, key)
+			return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+		}
+	}
+	return nil
+}
+
+// Head returns the stored attributes of an object
+func (s *GCSStorage) Head(ctx context.Context, bucket, key string) (*object_storage.ObjectMetadata, error) {
+	attrs, err := s.object(bucket, key).Attrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to head %s/%s: %w", bucket, key, mapError(err))
+	}
+
+	meta := &object_storage.ObjectMetadata{
+		Size:                 attrs.Size,
+		ContentType:          attrs.ContentType,
+		ETag:                 attrs.Etag,
+		CacheControl:         attrs.CacheControl,
+		LastModified:         attrs.Updated,
+		Metadata:             attrs.Metadata,
+		ServerSideEncryption: object_storage.SSEProviderManaged,
+	}
+	if attrs.KMSKeyName != "" {
+		meta.ServerSideEncryption = object_storage.SSEKMS
+		meta.KMSKeyID = attrs.KMSKeyName
+	}
+	return meta, nil
+}
+
+// BatchUpload uploads files concurrently, bounded by Config.MaxConcurrency
+func (s *GCSStorage) BatchUpload(ctx context.Context, uploads []object_storage.BatchUploadInput) *object_storage.BatchUploadResult {
+	return object_storage.RunBatchUpload(ctx, uploads, s.config.MaxConcurrency, func(ctx context.Context, input object_storage.BatchUploadInput) error {
+		return s.Upload(ctx, input.Bucket, input.Key, input.Data, input.ContentType)
+	})
+}
+
+// UploadWithValidation validates the arguments, uploads, and confirms the object is readable
+func (s *GCSStorage) UploadWithValidation(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	if err := object_storage.ValidateUpload(bucket, key, data, contentType); err != nil {
+		return err
+	}
+	if err := s.Upload(ctx, bucket, key, data, contentType); err != nil {
+		return err
+	}
+
+	if _, err := s.object(bucket, key).Attrs(ctx); err != nil {
+		return fmt.Errorf("failed to verify upload %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Download retrieves data from the specified key
+func (s *GCSStorage) Download(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	reader, err := s.object(bucket, key).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s/%s: %w", bucket, key, mapError(err))
+	}
+	return reader, nil
+}
+
+// BatchDownload downloads objects concurrently, bounded by Config.MaxConcurrency
+func (s *GCSStorage) BatchDownload(ctx context.Context, downloads []object_storage.BatchDownloadInput) *object_storage.BatchDownloadResult {
+	return object_storage.RunBatchDownload(ctx, s, downloads, s.config.MaxConcurrency)
+}
+
+// Delete removes the object at the specified key
+func (s *GCSStorage) Delete(ctx context.Context, bucket, key string) error {
+	if err := s.object(bucket, key).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Update replaces the object at the specified key
+func (s *GCSStorage) Update(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.Upload(ctx, bucket, key, data, contentType)
+}
+
+// Exists checks if an object exists at the specified key
+func (s *GCSStorage) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := s.object(bucket, key).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, mapError(err)
+	}
+	return true, nil
+}
+
+// GetURL returns the public URL of the object, using Config.PublicURL when set
+func (s *GCSStorage) GetURL(bucket, key string) string {
+	base := s.config.PublicURL
+	if base == "" {
+		base = defaultPublicURL
+	}
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(base, "/"), bucket, escapeKey(key))
+}
+
+// escapeKey escapes each path segment while keeping "/" separators
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+var _ object_storage.ObjectStorage = (*GCSStorage)(nil)
@@ -0,0 +1,126 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// tempFilePrefix marks in-progress uploads, which List skips
+const tempFilePrefix = ".upload-"
+
+// List walks the bucket directory and returns files whose keys start with prefix
+func (s *LocalStorage) List(ctx context.Context, bucket, prefix string, opts *object_storage.ListOptions) ([]object_storage.ObjectInfo, error) {
+	if opts == nil {
+		opts = &object_storage.ListOptions{}
+	}
+
+	bucketDir, err := s.bucketDir(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []object_storage.ObjectInfo
+	err = filepath.WalkDir(bucketDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bucketDir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) || key <= opts.StartAfter {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, object_storage.ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ETag:         etag(info),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s/%s: %w", bucket, prefix, err)
+	}
+
+	// WalkDir orders by path segment, which differs from flat key order when keys contain "/"
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	if opts.MaxKeys > 0 && len(objects) > opts.MaxKeys {
+		objects = objects[:opts.MaxKeys]
+	}
+	return objects, nil
+}
+
+// Copy duplicates a file and its attributes, creating destination directories as needed
+func (s *LocalStorage) Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	src, err := s.Download(ctx, srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	attrs, err := s.readAttributes(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	return s.UploadWithOptions(ctx, dstBucket, dstKey, src, attrs.options())
+}
+
+// Move renames the file and its attributes when possible, falling back to copy and delete
+func (s *LocalStorage) Move(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	srcPath, err := s.path(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	dstPath, err := s.path(dstBucket, dstKey)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(srcPath); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s/%s", object_storage.ErrObjectNotFound, srcBucket, srcKey)
+	}
+
+	attrs, err := s.readAttributes(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(srcPath, dstPath); err == nil {
+		s.removeAttributes(srcBucket, srcKey)
+		return s.writeAttributes(dstBucket, dstKey, attrs)
+	}
+
+	if err := s.Copy(ctx, srcBucket, srcKey, dstBucket, dstKey); err != nil {
+		return err
+	}
+	return s.Delete(ctx, srcBucket, srcKey)
+}
@@ -0,0 +1,139 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// metadataDir holds sidecar attribute files, mirroring bucket/key paths under root
+const metadataDir = ".meta"
+
+// attributes are the upload options persisted alongside each object
+type attributes struct {
+	ContentType          string            `json:"content_type,omitempty"`
+	CacheControl         string            `json:"cache_control,omitempty"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+	ServerSideEncryption string            `json:"server_side_encryption,omitempty"`
+	KMSKeyID             string            `json:"kms_key_id,omitempty"`
+}
+
+func attributesFromOptions(opts *object_storage.UploadOptions) *attributes {
+	return &attributes{
+		ContentType:          opts.ContentType,
+		CacheControl:         opts.CacheControl,
+		Metadata:             opts.Metadata,
+		Tags:                 opts.Tags,
+		ServerSideEncryption: opts.ServerSideEncryption,
+		KMSKeyID:             opts.KMSKeyID,
+	}
+}
+
+func (a *attributes) options() *object_storage.UploadOptions {
+	return &object_storage.UploadOptions{
+		ContentType:          a.ContentType,
+		CacheControl:         a.CacheControl,
+		Metadata:             a.Metadata,
+		Tags:                 a.Tags,
+		ServerSideEncryption: a.ServerSideEncryption,
+		KMSKeyID:             a.KMSKeyID,
+	}
+}
+
+func (s *LocalStorage) attributesPath(bucket, key string) (string, error) {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, metadataDir, rel+".json"), nil
+}
+
+func (s *LocalStorage) writeAttributes(bucket, key string, attrs *attributes) error {
+	path, err := s.attributesPath(bucket, key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, bytes.NewReader(data))
+}
+
+// readAttributes returns the stored attributes, or empty attributes for files written outside LocalStorage
+func (s *LocalStorage) readAttributes(bucket, key string) (*attributes, error) {
+	path, err := s.attributesPath(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &attributes{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := &attributes{}
+	if err := json.Unmarshal(data, attrs); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+func (s *LocalStorage) removeAttributes(bucket, key string) {
+	if path, err := s.attributesPath(bucket, key); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// etag derives a change-detection tag from modification time and size
+func etag(info fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())
+}
+
+// Head returns file size and modification time with the attributes recorded at upload
+func (s *LocalStorage) Head(ctx context.Context, bucket, key string) (*object_storage.ObjectMetadata, error) {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+		return nil, fmt.Errorf("%w: %s/%s", object_storage.ErrObjectNotFound, bucket, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to head %s/%s: %w", bucket, key, err)
+	}
+
+	attrs, err := s.readAttributes(bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to head %s/%s: %w", bucket, key, err)
+	}
+
+	return &object_storage.ObjectMetadata{
+		Size:                 info.Size(),
+		ContentType:          attrs.ContentType,
+		ETag:                 etag(info),
+		CacheControl:         attrs.CacheControl,
+		LastModified:         info.ModTime(),
+		Metadata:             attrs.Metadata,
+		Tags:                 attrs.Tags,
+		ServerSideEncryption: attrs.ServerSideEncryption,
+		KMSKeyID:             attrs.KMSKeyID,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// ProviderName selects this backend via object_storage.Config.Provider
+const ProviderName = "local"
+
+// ErrInvalidPath is returned when a bucket or key would resolve outside the root directory
+var ErrInvalidPath = errors.New("invalid object path")
+
+func init() {
+	object_storage.RegisterProvider(ProviderName, func(ctx context.Context, cfg *object_storage.Config) (object_storage.ObjectStorage, error) {
+		return NewLocalStorage(cfg)
+	})
+}
+
+// LocalStorage implements ObjectStorage on the filesystem for development and tests.
+// Each bucket is a directory under RootDir and each key a file path within it.
+type LocalStorage struct {
+	root   string
+	config *object_storage.Config
+}
+
+// NewLocalStorage creates RootDir if needed and returns a storage rooted there
+func NewLocalStorage(cfg *object_storage.Config) (*LocalStorage, error) {
+	if cfg.RootDir == "" {
+		return nil, fmt.Errorf("local storage requires a root directory")
+	}
+
+	root, err := filepath.Abs(cfg.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create root directory: %w", err)
+	}
+
+	return &LocalStorage{root: root, config: cfg}, nil
+}
+
+// bucketDir resolves bucket to a directory directly under root; names starting with "." are reserved
+func (s *LocalStorage) bucketDir(bucket string) (string, error) {
+	if bucket == "" || strings.HasPrefix(bucket, ".") || strings.ContainsAny(bucket, `/\`) {
+		return "", fmt.Errorf("%w: bucket %q", ErrInvalidPath, bucket)
+	}
+	return filepath.Join(s.root, bucket), nil
+}
+
+// path resolves bucket and key to a file under root, rejecting traversal outside the bucket
+func (s *LocalStorage) path(bucket, key string) (string, error) {
+	bucketDir, err := s.bucketDir(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	full := filepath.Join(bucketDir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(bucketDir, full)
+	if err != nil || key == "" || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s/%s", ErrInvalidPath, bucket, key)
+	}
+	return full, nil
+}
+
+// Upload writes data atomically via a temporary file in the target directory
+func (s *LocalStorage) Upload(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.UploadWithOptions(ctx, bucket, key, data, &object_storage.UploadOptions{ContentType: contentType})
+}
+
+// UploadWithOptions writes data and records its attributes in a sidecar file returned by Head.
+// Encryption settings are recorded but not applied.
+func (s *LocalStorage) UploadWithOptions(ctx context.Context, bucket, key string, data io.Reader, opts *object_storage.UploadOptions) error {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	prepared, err := object_storage.PrepareUpload(data, opts)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	defer prepared.Close()
+	opts = prepared.Options
+
+	if err := writeFile(path, prepared.Body); err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	if err := s.writeAttributes(bucket, key, attributesFromOptions(opts)); err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// writeFile atomically replaces path with the contents of data
+func writeFile(path string, data io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// BatchUpload uploads files concurrently, bounded by Config.MaxConcurrency
+func (s *LocalStorage) BatchUpload(ctx context.Context, uploads []object_storage.BatchUploadInput) *object_storage.BatchUploadResult {
+	return object_storage.RunBatchUpload(ctx, uploads, s.config.MaxConcurrency, func(ctx context.Context, input object_storage.BatchUploadInput) error {
+		return s.Upload(ctx, input.Bucket, input.Key, input.Data, input.ContentType)
+	})
+}
+
+// UploadWithValidation validates the arguments before uploading
+func (s *LocalStorage) UploadWithValidation(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	if err := object_storage.ValidateUpload(bucket, key, data, contentType); err != nil {
+		return err
+	}
+	return s.Upload(ctx, bucket, key, data, contentType)
+}
+
+// Download opens the object for reading
+func (s *LocalStorage) Download(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s/%s", object_storage.ErrObjectNotFound, bucket, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s/%s: %w", bucket, key, err)
+	}
+	return file, nil
+}
+
+// BatchDownload downloads objects concurrently, bounded by Config.MaxConcurrency
+func (s *LocalStorage) BatchDownload(ctx context.Context, downloads []object_storage.BatchDownloadInput) *object_storage.BatchDownloadResult {
+	return object_storage.RunBatchDownload(ctx, s, downloads, s.config.MaxConcurrency)
+}
+
+// Delete removes the object at the specified key
+func (s *LocalStorage) Delete(ctx context.Context, bucket, key string) error {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s/%s", object_storage.ErrObjectNotFound, bucket, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %s/%s: %w", bucket, key, err)
+	}
+	s.removeAttributes(bucket, key)
+	return nil
+}
+
+// Update replaces the object at the specified key
+func (s *LocalStorage) Update(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.Upload(ctx, bucket, key, data, contentType)
+}
+
+// Exists checks if an object exists at the specified key
+func (s *LocalStorage) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// GetURL returns Config.PublicURL/bucket/key when set, otherwise a file:// URL
+func (s *LocalStorage) GetURL(bucket, key string) string {
+	if s.config.PublicURL != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.config.PublicURL, "/"), bucket, key)
+	}
+
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return ""
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+var _ object_storage.ObjectStorage = (*LocalStorage)(nil)
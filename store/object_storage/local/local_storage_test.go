@@ -0,0 +1,202 @@
+package local
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+func newTestStorage(t *testing.T) *LocalStorage {
+	storage, err := NewLocalStorage(&object_storage.Config{RootDir: t.TempDir()})
+	require.NoError(t, err)
+	return storage
+}
+
+func TestLocalStorage_UploadDownloadDelete(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Upload(ctx, "docs", "reports/2024/q1.txt", strings.NewReader("hello"), "text/plain"))
+
+	exists, err := storage.Exists(ctx, "docs", "reports/2024/q1.txt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	reader, err := storage.Download(ctx, "docs", "reports/2024/q1.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, storage.Delete(ctx, "docs", "reports/2024/q1.txt"))
+
+	exists, err = storage.Exists(ctx, "docs", "reports/2024/q1.txt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = storage.Download(ctx, "docs", "reports/2024/q1.txt")
+	assert.ErrorIs(t, err, object_storage.ErrObjectNotFound)
+}
+
+func TestLocalStorage_RejectsTraversal(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct{ bucket, key string }{
+		{"docs", "../escape.txt"},
+		{"docs", "a/../../escape.txt"},
+		{"..", "escape.txt"},
+		{"docs/nested", "file.txt"},
+		{"docs", "."},
+	} {
+		err := storage.Upload(ctx, tc.bucket, tc.key, strings.NewReader("x"), "text/plain")
+		assert.ErrorIs(t, err, ErrInvalidPath, "%s/%s", tc.bucket, tc.key)
+	}
+}
+
+func TestLocalStorage_BatchUpload(t *testing.T) {
+	storage := newTestStorage(t)
+
+	result := storage.BatchUpload(context.Background(), []object_storage.BatchUploadInput{
+		{Bucket: "docs", Key: "a.txt", Data: strings.NewReader("a"), ContentType: "text/plain"},
+		{Bucket: "docs", Key: "b.txt", Data: strings.NewReader("b"), ContentType: "text/plain"},
+		{Bucket: "docs", Key: "../c.txt", Data: strings.NewReader("c"), ContentType: "text/plain"},
+	})
+
+	assert.Equal(t, 2, result.Successful)
+	assert.Equal(t, 1, result.Failed)
+}
+
+func TestLocalStorage_ListCopyMove(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"exports/a.csv", "exports/b.csv", "exports/nested/c.csv", "other.txt"} {
+		require.NoError(t, storage.Upload(ctx, "docs", key, strings.NewReader(key), "text/csv"))
+	}
+
+	objects, err := storage.List(ctx, "docs", "exports/", nil)
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, "exports/a.csv", objects[0].Key)
+	assert.Equal(t, int64(len("exports/a.csv")), objects[0].Size)
+	assert.NotEmpty(t, objects[0].ETag)
+
+	objects, err = storage.List(ctx, "docs", "exports/", &object_storage.ListOptions{StartAfter: "exports/a.csv", MaxKeys: 1})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "exports/b.csv", objects[0].Key)
+
+	objects, err = storage.List(ctx, "missing", "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	require.NoError(t, storage.Copy(ctx, "docs", "other.txt", "archive", "2024/other.txt"))
+	exists, err := storage.Exists(ctx, "docs", "other.txt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, storage.Move(ctx, "docs", "exports/a.csv", "archive", "a.csv"))
+	exists, err = storage.Exists(ctx, "docs", "exports/a.csv")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = storage.Exists(ctx, "archive", "a.csv")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	err = storage.Move(ctx, "docs", "exports/a.csv", "archive", "again.csv")
+	assert.ErrorIs(t, err, object_storage.ErrObjectNotFound)
+}
+
+func TestLocalStorage_UploadWithOptionsAndHead(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	opts := &object_storage.UploadOptions{
+		ContentType:  "application/json",
+		CacheControl: "max-age=60",
+		Metadata:     map[string]string{"owner": "reports"},
+		Tags:         map[string]string{"retention": "short"},
+	}
+	require.NoError(t, storage.UploadWithOptions(ctx, "docs", "report.json", strings.NewReader("{}"), opts))
+
+	meta, err := storage.Head(ctx, "docs", "report.json")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), meta.Size)
+	assert.Equal(t, "application/json", meta.ContentType)
+	assert.Equal(t, "max-age=60", meta.CacheControl)
+	assert.Equal(t, "reports", meta.Metadata["owner"])
+	assert.Equal(t, "short", meta.Tags["retention"])
+
+	require.NoError(t, storage.Copy(ctx, "docs", "report.json", "archive", "report.json"))
+	meta, err = storage.Head(ctx, "archive", "report.json")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", meta.ContentType)
+
+	require.NoError(t, storage.Delete(ctx, "docs", "report.json"))
+	_, err = storage.Head(ctx, "docs", "report.json")
+	assert.ErrorIs(t, err, object_storage.ErrObjectNotFound)
+
+	_, err = storage.Head(ctx, ".meta", "docs/report.json.json")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+}
+
+func TestLocalStorage_VerifyDownload(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	opts := &object_storage.UploadOptions{ContentType: "text/plain", Checksum: object_storage.ChecksumSHA256}
+	require.NoError(t, storage.UploadWithOptions(ctx, "docs", "artifact.txt", strings.NewReader("hello"), opts))
+	assert.NoError(t, object_storage.VerifyDownload(ctx, storage, "docs", "artifact.txt"))
+
+	// Tamper with the file behind the storage's back
+	path, err := storage.path("docs", "artifact.txt")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("hellO"), 0o644))
+	assert.ErrorIs(t, object_storage.VerifyDownload(ctx, storage, "docs", "artifact.txt"), object_storage.ErrChecksumMismatch)
+
+	require.NoError(t, storage.Upload(ctx, "docs", "plain.txt", strings.NewReader("x"), "text/plain"))
+	assert.ErrorIs(t, object_storage.VerifyDownload(ctx, storage, "docs", "plain.txt"), object_storage.ErrNoChecksum)
+}
+
+func TestLocalStorage_BatchDownloadAndSync(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"set/a.txt", "set/nested/b.txt", "other/c.txt"} {
+		require.NoError(t, storage.Upload(ctx, "docs", key, strings.NewReader(key), "text/plain"))
+	}
+
+	var a, missing strings.Builder
+	result := storage.BatchDownload(ctx, []object_storage.BatchDownloadInput{
+		{Bucket: "docs", Key: "set/a.txt", Dest: &a},
+		{Bucket: "docs", Key: "set/missing.txt", Dest: &missing},
+	})
+	assert.Equal(t, 1, result.Successful)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, "set/a.txt", a.String())
+
+	dir := t.TempDir()
+	synced, err := object_storage.SyncPrefixToDir(ctx, storage, "docs", "set/", dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, synced.Downloaded)
+
+	data, err := os.ReadFile(filepath.Join(dir, "nested", "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "set/nested/b.txt", string(data))
+
+	synced, err = object_storage.SyncPrefixToDir(ctx, storage, "docs", "set/", dir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, synced.Downloaded)
+	assert.Equal(t, 2, synced.Skipped)
+}
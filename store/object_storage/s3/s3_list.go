@@ -0,0 +1,72 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// List returns objects whose keys start with prefix, paging through ListObjectsV2
+func (s *S3Storage) List(ctx context.Context, bucket, prefix string, opts *object_storage.ListOptions) ([]object_storage.ObjectInfo, error) {
+	if opts == nil {
+		opts = &object_storage.ListOptions{}
+	}
+
+	input := &awss3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+	if opts.StartAfter != "" {
+		input.StartAfter = aws.String(opts.StartAfter)
+	}
+
+	var objects []object_storage.ObjectInfo
+	paginator := awss3.NewListObjectsV2Paginator(s.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s/%s: %w", bucket, prefix, mapError(err))
+		}
+
+		for _, obj := range page.Contents {
+			objects = append(objects, object_storage.ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         strings.Trim(aws.ToString(obj.ETag), `"`),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+			if opts.MaxKeys > 0 && len(objects) >= opts.MaxKeys {
+				return objects, nil
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// Copy duplicates an object server-side; sources larger than 5GB require a multipart copy and are not supported
+func (s *S3Storage) Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	_, err := s.client.CopyObject(ctx, &awss3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(srcBucket + "/" + url.PathEscape(srcKey)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy %s/%s to %s/%s: %w", srcBucket, srcKey, dstBucket, dstKey, mapError(err))
+	}
+	return nil
+}
+
+// Move copies an object then deletes the source
+func (s *S3Storage) Move(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	if err := s.Copy(ctx, srcBucket, srcKey, dstBucket, dstKey); err != nil {
+		return err
+	}
+	return s.Delete(ctx, srcBucket, srcKey)
+}
@@ -0,0 +1,314 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+// ProviderName selects this backend via object_storage.Config.Provider
+const ProviderName = "s3"
+
+// maxPresignTTL is the longest expiry S3 accepts for SigV4 presigned URLs
+const maxPresignTTL = 7 * 24 * time.Hour
+
+func init() {
+	object_storage.RegisterProvider(ProviderName, func(ctx context.Context, cfg *object_storage.Config) (object_storage.ObjectStorage, error) {
+		return NewS3Storage(ctx, cfg)
+	})
+}
+
+// S3Storage implements ObjectStorage using Amazon S3 or an S3-compatible service such as MinIO
+type S3Storage struct {
+	client   *awss3.Client
+	uploader *manager.Uploader
+	presign  *awss3.PresignClient
+	config   *object_storage.Config
+}
+
+// NewS3Storage creates a client using static keys when configured, otherwise the default AWS credential chain.
+// Set Endpoint and UsePathStyle for MinIO and other S3-compatible services.
+func NewS3Storage(ctx context.Context, cfg *object_storage.Config) (*S3Storage, error) {
+	loadOpts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" {
+		loadOpts = append(loadOpts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+
+	client := awss3.NewFromConfig(awsCfg, func(o *awss3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	return &S3Storage{
+		client:   client,
+		uploader: manager.NewUploader(client),
+		presign:  awss3.NewPresignClient(client),
+		config:   cfg,
+	}, nil
+}
+
+// Client returns the underlying S3 client for operations not covered by ObjectStorage
+func (s *S3Storage) Client() *awss3.Client {
+	return s.client
+}
+
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchBucket":
+			return true
+		}
+	}
+	return false
+}
+
+// mapError converts S3 not-found errors to object_storage.ErrObjectNotFound
+func mapError(err error) error {
+	if isNotFound(err) {
+		return fmt.Errorf("%w: %v", object_storage.ErrObjectNotFound, err)
+	}
+	return err
+}
+
+// Upload streams data to the specified key using multipart upload for large bodies
+func (s *S3Storage) Upload(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.UploadWithOptions(ctx, bucket, key, data, &object_storage.UploadOptions{ContentType: contentType})
+}
+
+// UploadWithOptions uploads data with metadata, tags, caching and encryption settings
+func (s *S3Storage) UploadWithOptions(ctx context.Context, bucket, key string, data io.Reader, opts *object_storage.UploadOptions) error {
+	prepared, err := object_storage.PrepareUpload(data, opts)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	defer prepared.Close()
+	opts = prepared.Options
+
+	input := &awss3.PutObjectInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Body:     prepared.Body,
+		Metadata: opts.Metadata,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if opts.CacheControl != "" {
+		input.CacheControl = aws.String(opts.CacheControl)
+	}
+	if len(opts.Tags) > 0 {
+		input.Tagging = aws.String(encodeTags(opts.Tags))
+	}
+	if opts.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(opts.ServerSideEncryption)
+	}
+	if opts.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(opts.KMSKeyID)
+	}
+	if opts.Checksum == object_storage.ChecksumSHA256 {
+		// S3 validates the SDK-computed x-amz-checksum on receipt
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	}
+
+	out, err := s.uploader.Upload(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, mapError(err))
+	}
+
+	// ETags of KMS-encrypted objects are not MD5 digests
+	if opts.ServerSideEncryption != object_storage.SSEKMS {
+		if err := prepared.VerifyETag(aws.ToString(out.ETag)); err != nil {
+			_ = s.Delete(ctx, bucket, key)
+			return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+		}
+	}
+	return nil
+}
+
+// encodeTags formats tags as the URL query string S3 expects in x-amz-tagging
+func encodeTags(tags map[string]string) string {
+	values := url.Values{}
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// Head returns the stored attributes of an object, including its tags
+func (s *S3Storage) Head(ctx context.Context, bucket, key string) (*object_storage.ObjectMetadata, error) {
+	out, err := s.client.HeadObject(ctx, &awss3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to head %s/%s: %w", bucket, key, mapError(err))
+	}
+
+	meta := &object_storage.ObjectMetadata{
+		Size:                 aws.ToInt64(out.ContentLength),
+		ContentType:          aws.ToString(out.ContentType),
+		ETag:                 strings.Trim(aws.ToString(out.ETag), `"`),
+		CacheControl:         aws.ToString(out.CacheControl),
+		LastModified:         aws.ToTime(out.LastModified),
+		Metadata:             out.Metadata,
+		ServerSideEncryption: string(out.ServerSideEncryption),
+		KMSKeyID:             aws.ToString(out.SSEKMSKeyId),
+	}
+
+	if aws.ToInt32(out.TagCount) > 0 {
+		tagging, err := s.client.GetObjectTagging(ctx, &awss3.GetObjectTaggingInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tags of %s/%s: %w", bucket, key, mapError(err))
+		}
+		meta.Tags = make(map[string]string, len(tagging.TagSet))
+		for _, tag := range tagging.TagSet {
+			meta.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	return meta, nil
+}
+
+// BatchUpload uploads files concurrently, bounded by Config.MaxConcurrency
+func (s *S3Storage) BatchUpload(ctx context.Context, uploads []object_storage.BatchUploadInput) *object_storage.BatchUploadResult {
+	return object_storage.RunBatchUpload(ctx, uploads, s.config.MaxConcurrency, func(ctx context.Context, input object_storage.BatchUploadInput) error {
+		return s.Upload(ctx, input.Bucket, input.Key, input.Data, input.ContentType)
+	})
+}
+
+// UploadWithValidation validates the arguments, uploads, and confirms the object is readable
+func (s *S3Storage) UploadWithValidation(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	if err := object_storage.ValidateUpload(bucket, key, data, contentType); err != nil {
+		return err
+	}
+	if err := s.Upload(ctx, bucket, key, data, contentType); err != nil {
+		return err
+	}
+
+	if _, err := s.client.HeadObject(ctx, &awss3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil {
+		return fmt.Errorf("failed to verify upload %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Download retrieves data from the specified key
+func (s *S3Storage) Download(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &awss3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s/%s: %w", bucket, key, mapError(err))
+	}
+	return out.Body, nil
+}
+
+// BatchDownload downloads objects concurrently, bounded by Config.MaxConcurrency
+func (s *S3Storage) BatchDownload(ctx context.Context, downloads []object_storage.BatchDownloadInput) *object_storage.BatchDownloadResult {
+	return object_storage.RunBatchDownload(ctx, s, downloads, s.config.MaxConcurrency)
+}
+
+// Delete removes the object at the specified key
+func (s *S3Storage) Delete(ctx context.Context, bucket, key string) error {
+	if _, err := s.client.DeleteObject(ctx, &awss3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil {
+		return fmt.Errorf("failed to delete %s/%s: %w", bucket, key, mapError(err))
+	}
+	return nil
+}
+
+// Update replaces the object at the specified key
+func (s *S3Storage) Update(ctx context.Context, bucket, key string, data io.Reader, contentType string) error {
+	return s.Upload(ctx, bucket, key, data, contentType)
+}
+
+// Exists checks if an object exists at the specified key
+func (s *S3Storage) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &awss3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetURL returns the unsigned object URL, using Config.PublicURL when set
+func (s *S3Storage) GetURL(bucket, key string) string {
+	switch {
+	case s.config.PublicURL != "":
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.config.PublicURL, "/"), bucket, key)
+	case s.config.Endpoint != "":
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.config.Endpoint, "/"), bucket, key)
+	default:
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, s.config.Region, key)
+	}
+}
+
+func validatePresignTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > maxPresignTTL {
+		return fmt.Errorf("presign ttl must be between 0 and %s, got %s", maxPresignTTL, ttl)
+	}
+	return nil
+}
+
+// PresignGet returns a URL that downloads the object until ttl elapses
+func (s *S3Storage) PresignGet(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	if err := validatePresignTTL(ttl); err != nil {
+		return "", err
+	}
+
+	req, err := s.presign.PresignGetObject(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, awss3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign get %s/%s: %w", bucket, key, err)
+	}
+	return req.URL, nil
+}
+
+// PresignPut returns a URL that accepts a PUT upload until ttl elapses.
+// The client must send the same Content-Type header, which is part of the signature.
+func (s *S3Storage) PresignPut(ctx context.Context, bucket, key string, ttl time.Duration, contentType string) (string, error) {
+	if err := validatePresignTTL(ttl); err != nil {
+		return "", err
+	}
+
+	input := &awss3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	req, err := s.presign.PresignPutObject(ctx, input, awss3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign put %s/%s: %w", bucket, key, err)
+	}
+	return req.URL, nil
+}
+
+var (
+	_ object_storage.ObjectStorage = (*S3Storage)(nil)
+	_ object_storage.Presigner     = (*S3Storage)(nil)
+)
@@ -0,0 +1,61 @@
+package s3
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yadunandan004/scaffold/store/object_storage"
+)
+
+func newTestStorage(t *testing.T) *S3Storage {
+	storage, err := NewS3Storage(context.Background(), &object_storage.Config{
+		Endpoint:        "http://localhost:9000",
+		Region:          "us-east-1",
+		AccessKeyID:     "test",
+		SecretAccessKey: "secret",
+		UsePathStyle:    true,
+	})
+	require.NoError(t, err)
+	return storage
+}
+
+func TestS3Storage_PresignGet(t *testing.T) {
+	storage := newTestStorage(t)
+
+	signed, err := storage.PresignGet(context.Background(), "docs", "reports/q1.pdf", 15*time.Minute)
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/docs/reports/q1.pdf", u.Path)
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+}
+
+func TestS3Storage_PresignPut(t *testing.T) {
+	storage := newTestStorage(t)
+
+	signed, err := storage.PresignPut(context.Background(), "docs", "upload.png", time.Minute, "image/png")
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Contains(t, u.Query().Get("X-Amz-SignedHeaders"), "content-type")
+
+	_, err = storage.PresignPut(context.Background(), "docs", "upload.png", 8*24*time.Hour, "image/png")
+	assert.Error(t, err)
+}
+
+func TestEncodeTags(t *testing.T) {
+	encoded := encodeTags(map[string]string{"team": "data eng", "tier": "gold"})
+
+	values, err := url.ParseQuery(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "data eng", values.Get("team"))
+	assert.Equal(t, "gold", values.Get("tier"))
+}
@@ -2,9 +2,13 @@ package object_storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"time"
+
+	"github.com/yadunandan004/scaffold/store/readiness"
 )
 
 var (
@@ -12,6 +16,9 @@ var (
 	storageMutex   sync.RWMutex
 )
 
+// ErrObjectNotFound is returned when the requested object does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
 // BatchUploadInput represents a single file in batch upload
 type BatchUploadInput struct {
 	Bucket      string
@@ -27,11 +34,81 @@ type BatchUploadResult struct {
 	Errors     []error
 }
 
+// ErrUnsupported is returned when a backend cannot honour a requested option
+var ErrUnsupported = errors.New("not supported by this storage backend")
+
+// Server-side encryption modes for UploadOptions.ServerSideEncryption
+const (
+	SSEProviderManaged = "AES256"
+	SSEKMS             = "aws:kms"
+)
+
+// BatchDownloadInput represents a single object in batch download
+type BatchDownloadInput struct {
+	Bucket string
+	Key    string
+	Dest   io.Writer
+}
+
+// BatchDownloadResult represents the result of a batch download operation
+type BatchDownloadResult struct {
+	Successful int
+	Failed     int
+	Errors     []error
+}
+
 // ObjectMetadata represents metadata for an object in storage
 type ObjectMetadata struct {
-	Size        int64
-	ContentType string
-	ETag        string
+	Size                 int64
+	ContentType          string
+	ETag                 string
+	CacheControl         string
+	LastModified         time.Time
+	Metadata             map[string]string
+	Tags                 map[string]string
+	ServerSideEncryption string
+	KMSKeyID             string
+}
+
+// UploadOptions carries optional attributes stored with an object
+type UploadOptions struct {
+	ContentType  string
+	CacheControl string
+
+	// Metadata is user-defined key/value metadata returned by Head
+	Metadata map[string]string
+
+	// Tags are object tags usable in lifecycle and access policies
+	Tags map[string]string
+
+	// ServerSideEncryption is SSEProviderManaged or SSEKMS; empty uses the bucket default
+	ServerSideEncryption string
+
+	// KMSKeyID names the customer-managed key when ServerSideEncryption is SSEKMS
+	KMSKeyID string
+
+	// Checksum computes a digest on upload, stores it as metadata and verifies it where the backend allows
+	Checksum ChecksumAlgorithm
+
+	// ExpectedChecksum rejects the upload when the computed hex digest differs
+	ExpectedChecksum string
+}
+
+// ObjectInfo describes an object returned by List
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// ListOptions narrows a List call
+type ListOptions struct {
+	// StartAfter lists only keys lexically after this key
+	StartAfter string
+
+	// MaxKeys limits the number of results; zero lists everything
+	MaxKeys int
 }
 
 // ObjectStorage defines the interface for object storage operations
@@ -39,6 +116,12 @@ type ObjectStorage interface {
 	// Upload uploads data to the specified key
 	Upload(ctx context.Context, bucket, key string, data io.Reader, contentType string) error
 
+	// UploadWithOptions uploads data with metadata, tags, caching and encryption settings
+	UploadWithOptions(ctx context.Context, bucket, key string, data io.Reader, opts *UploadOptions) error
+
+	// Head returns the stored attributes of an object without downloading it
+	Head(ctx context.Context, bucket, key string) (*ObjectMetadata, error)
+
 	BatchUpload(ctx context.Context, uploads []BatchUploadInput) *BatchUploadResult
 
 	UploadWithValidation(ctx context.Context, bucket, key string, data io.Reader, contentType string) error
@@ -46,6 +129,9 @@ type ObjectStorage interface {
 	// Download retrieves data from the specified key
 	Download(ctx context.Context, bucket, key string) (io.ReadCloser, error)
 
+	// BatchDownload writes each object to its Dest concurrently
+	BatchDownload(ctx context.Context, downloads []BatchDownloadInput) *BatchDownloadResult
+
 	// Delete removes the object at the specified key
 	Delete(ctx context.Context, bucket, key string) error
 
@@ -57,6 +143,24 @@ type ObjectStorage interface {
 
 	// GetURL returns a URL for accessing the object
 	GetURL(bucket, key string) string
+
+	// List returns objects whose keys start with prefix, in lexical key order
+	List(ctx context.Context, bucket, prefix string, opts *ListOptions) ([]ObjectInfo, error)
+
+	// Copy duplicates an object, possibly across buckets
+	Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error
+
+	// Move copies an object then deletes the source
+	Move(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error
+}
+
+// Presigner is implemented by backends that can issue time-limited URLs for direct browser access
+type Presigner interface {
+	// PresignGet returns a URL that downloads the object until ttl elapses
+	PresignGet(ctx context.Context, bucket, key string, ttl time.Duration) (string, error)
+
+	// PresignPut returns a URL that accepts a PUT upload with the given content type until ttl elapses
+	PresignPut(ctx context.Context, bucket, key string, ttl time.Duration, contentType string) (string, error)
 }
 
 // SetDefaultStorage sets the default object storage instance
@@ -93,6 +197,13 @@ func Ping(ctx context.Context, storage ObjectStorage) error {
 	return nil
 }
 
+// WaitForReady pings storage with backoff until it answers, ctx ends or timeout passes
+func WaitForReady(ctx context.Context, storage ObjectStorage, timeout time.Duration) error {
+	return readiness.Poll(ctx, timeout, "object storage", func(ctx context.Context) error {
+		return Ping(ctx, storage)
+	})
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) &&
 		(s == substr ||
@@ -0,0 +1,142 @@
+package object_storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RunBatchDownload downloads inputs with at most concurrency downloads in flight.
+// Providers use it to implement BatchDownload consistently.
+func RunBatchDownload(ctx context.Context, storage ObjectStorage, downloads []BatchDownloadInput, concurrency int) *BatchDownloadResult {
+	if concurrency <= 0 {
+		concurrency = DefaultMaxConcurrency
+	}
+
+	result := &BatchDownloadResult{}
+	var mu sync.Mutex
+
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+
+	for _, input := range downloads {
+		group.Go(func() error {
+			err := downloadTo(ctx, storage, input.Bucket, input.Key, input.Dest)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Errorf("%s/%s: %w", input.Bucket, input.Key, err))
+				return nil
+			}
+			result.Successful++
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	return result
+}
+
+func downloadTo(ctx context.Context, storage ObjectStorage, bucket, key string, dest io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	body, err := storage.Download(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(dest, body)
+	return err
+}
+
+// SyncResult summarises a SyncPrefixToDir run
+type SyncResult struct {
+	Downloaded int
+	Skipped    int
+}
+
+// SyncPrefixToDir mirrors every object under prefix into dir, keyed by the path after prefix.
+// Files whose size matches and that are newer than the object are skipped.
+// Downloads run DefaultMaxConcurrency at a time and stop at the first failure.
+func SyncPrefixToDir(ctx context.Context, storage ObjectStorage, bucket, prefix, dir string) (*SyncResult, error) {
+	objects, err := storage.List(ctx, bucket, prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	var mu sync.Mutex
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(DefaultMaxConcurrency)
+
+	for _, object := range objects {
+		rel := strings.TrimPrefix(strings.TrimPrefix(object.Key, prefix), "/")
+		if rel == "" || strings.HasSuffix(rel, "/") {
+			continue
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(rel))
+		if !strings.HasPrefix(target, root+string(filepath.Separator)) {
+			return nil, fmt.Errorf("object key %q escapes sync directory", object.Key)
+		}
+
+		if info, err := os.Stat(target); err == nil && info.Size() == object.Size && !info.ModTime().Before(object.LastModified) {
+			result.Skipped++
+			continue
+		}
+
+		group.Go(func() error {
+			if err := syncObject(groupCtx, storage, bucket, object.Key, target); err != nil {
+				return fmt.Errorf("failed to sync %s/%s: %w", bucket, object.Key, err)
+			}
+			mu.Lock()
+			result.Downloaded++
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// syncObject downloads into a temporary file beside target and renames it into place
+func syncObject(ctx context.Context, storage ObjectStorage, bucket, key, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".sync-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := downloadTo(ctx, storage, bucket, key, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
@@ -0,0 +1,26 @@
+package object_storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidUpload is returned by ValidateUpload for malformed upload requests
+var ErrInvalidUpload = errors.New("invalid upload")
+
+// ValidateUpload checks the arguments shared by every provider's UploadWithValidation
+func ValidateUpload(bucket, key string, data io.Reader, contentType string) error {
+	switch {
+	case bucket == "":
+		return fmt.Errorf("%w: bucket is required", ErrInvalidUpload)
+	case key == "" || strings.HasPrefix(key, "/"):
+		return fmt.Errorf("%w: key must be a non-empty relative path", ErrInvalidUpload)
+	case data == nil:
+		return fmt.Errorf("%w: data is required", ErrInvalidUpload)
+	case contentType == "":
+		return fmt.Errorf("%w: content type is required", ErrInvalidUpload)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RetryOptions controls how connecting retries while the database isn't reachable yet
+type RetryOptions struct {
+	// Attempts is the total number of pings; zero or one tries once
+	Attempts int
+
+	// Backoff is the delay before the second attempt, doubled on each attempt (default 500ms)
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between attempts (default 30s)
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction each delay varies by, so restarted replicas don't retry in step (default 0.2)
+	Jitter float64
+}
+
+// retryOptions returns cfg's connection retry settings
+func (cfg *DatabaseConfig) retryOptions() RetryOptions {
+	return RetryOptions{
+		Attempts:   cfg.ConnectAttempts,
+		Backoff:    cfg.ConnectBackoff,
+		MaxBackoff: cfg.ConnectMaxBackoff,
+	}
+}
+
+// Connect opens a pool for cfg and waits for the database, retrying transient failures per the
+// connect_* settings. With LazyConnect it returns the pool at once and keeps pinging in the background,
+// so statements and the readiness check start succeeding when the database appears.
+func Connect(ctx context.Context, cfg *DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", BuildDSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	if err := configureConnectionPool(db, cfg); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if cfg.LazyConnect {
+		go func() {
+			opts := cfg.retryOptions()
+			opts.Attempts = -1
+			if err := pingWithRetry(ctx, db, opts); err == nil {
+				log.Printf("[Postgres] connected to %s:%d", cfg.Host, cfg.Port)
+			}
+		}()
+		return db, nil
+	}
+
+	if err := pingWithRetry(ctx, db, cfg.retryOptions()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// ConnectWithRetry is SetupConnectionFromDSN with retries while the database isn't reachable
+func ConnectWithRetry(ctx context.Context, dsn string, maxOpenConns, maxIdleConns int, opts RetryOptions) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := pingWithRetry(ctx, db, opts); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(time.Hour)
+	db.SetConnMaxIdleTime(time.Minute * 5)
+
+	return db, nil
+}
+
+// pingWithRetry pings db until it answers, an error isn't transient, or the attempts or ctx run out.
+// Negative Attempts retries until ctx ends.
+func pingWithRetry(ctx context.Context, db *sql.DB, opts RetryOptions) error {
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil || !IsTransientError(err) || (opts.Attempts >= 0 && attempt >= opts.Attempts) {
+			return err
+		}
+
+		delay := retryDelay(opts, attempt)
+		log.Printf("[Postgres] database not reachable (attempt %d): %v; retrying in %s", attempt, err, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the jittered delay after the given failed attempt
+func retryDelay(opts RetryOptions, attempt int) time.Duration {
+	backoff, maxBackoff, jitter := opts.Backoff, opts.MaxBackoff, opts.Jitter
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	if jitter <= 0 {
+		jitter = 0.2
+	}
+
+	delay := backoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	spread := time.Duration(float64(delay) * jitter)
+	return delay - spread/2 + time.Duration(rand.Int63n(int64(spread)+1))
+}
+
+// IsTransientError reports whether err means the database may accept connections shortly: it is
+// unreachable, starting up, shutting down or out of connection slots. Authentication and unknown
+// database errors are not transient.
+func IsTransientError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code.Class() == "08", // connection_exception
+			pqErr.Code == "57P01", // admin_shutdown
+			pqErr.Code == "57P02", // crash_shutdown
+			pqErr.Code == "57P03", // cannot_connect_now
+			pqErr.Code == "53300": // too_many_connections
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
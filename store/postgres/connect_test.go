@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(&pq.Error{Code: "57P03"}), "starting up")
+	assert.True(t, IsTransientError(&pq.Error{Code: "08006"}), "connection failure")
+	assert.True(t, IsTransientError(fmt.Errorf("dial: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})))
+	assert.False(t, IsTransientError(&pq.Error{Code: "28P01"}), "bad password")
+	assert.False(t, IsTransientError(&pq.Error{Code: "3D000"}), "unknown database")
+	assert.False(t, IsTransientError(errors.New("boom")))
+}
+
+func TestRetryDelay(t *testing.T) {
+	opts := RetryOptions{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.2}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		delay := retryDelay(opts, attempt)
+		assert.InDelta(t, float64(want), float64(delay), float64(want)/10+1, "attempt %d", attempt)
+	}
+}
+
+func TestConnectWithRetry_GivesUpAfterAttempts(t *testing.T) {
+	// Nothing listens on port 1, so every ping is refused
+	dsn := "host=127.0.0.1 port=1 user=u dbname=d sslmode=disable connect_timeout=1"
+	start := time.Now()
+	_, err := ConnectWithRetry(context.Background(), dsn, 1, 1, RetryOptions{Attempts: 3, Backoff: 10 * time.Millisecond})
+	require.Error(t, err)
+	assert.True(t, IsTransientError(err))
+	assert.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond, "waited between attempts")
+}
+
+func TestConnect_LazyReturnsImmediately(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db, err := Connect(ctx, &DatabaseConfig{Host: "127.0.0.1", Port: 1, User: "u", DBName: "d", SSLMode: "disable", LazyConnect: true})
+	require.NoError(t, err)
+	defer db.Close()
+	assert.Error(t, db.PingContext(ctx), "unhealthy until the database appears")
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/yadunandan004/scaffold/config"
+	"github.com/yadunandan004/scaffold/store/readiness"
 	"log"
 	"os"
 	"strconv"
@@ -30,15 +31,28 @@ type DB struct {
 }
 
 type DatabaseConfig struct {
-	Host         string `yaml:"host"`
-	Port         int    `yaml:"port"`
-	User         string `yaml:"user"`
-	Password     string `yaml:"password"`
-	DBName       string `yaml:"dbname"`
-	SSLMode      string `yaml:"sslmode"`
-	SearchPath   string `yaml:"search_path"`
-	MaxOpenConns int    `yaml:"max_open_conns"`
-	MaxIdleConns int    `yaml:"max_idle_conns"`
+	Host         string `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port         int    `yaml:"port" env:"DB_PORT" default:"5432"`
+	User         string `yaml:"user" env:"DB_USER"`
+	Password     string `yaml:"password" env:"DB_PASSWORD"`
+	DBName       string `yaml:"name" env:"DB_NAME"`
+	SSLMode      string `yaml:"ssl_mode" env:"DB_SSL_MODE" default:"disable"`
+	SearchPath   string `yaml:"search_path" env:"DB_SEARCH_PATH"`
+	MaxOpenConns int    `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns int    `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"10"`
+	// ConnMaxLifetime and ConnMaxIdleTime take duration strings such as "1h" or "5m"
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"1h"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"5m"`
+	// Schema is this environment's schema for ORM models whose TableName names none; pass it to
+	// orm.SetDefaultSchema at startup
+	Schema string `yaml:"schema" env:"DB_SCHEMA"`
+	// ConnectAttempts is how many times Connect pings a database that isn't reachable yet (default 1)
+	ConnectAttempts int `yaml:"connect_attempts" env:"DB_CONNECT_ATTEMPTS" default:"1"`
+	// ConnectBackoff is the delay before the second attempt, doubled up to ConnectMaxBackoff
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" env:"DB_CONNECT_BACKOFF" default:"500ms"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF" default:"30s"`
+	// LazyConnect makes Connect return without waiting for the database
+	LazyConnect bool `yaml:"lazy_connect" env:"DB_LAZY_CONNECT"`
 }
 
 func BuildDSN(cfg *DatabaseConfig) string {
@@ -65,31 +79,31 @@ func configureConnectionPool(db *sql.DB, cfg *DatabaseConfig) error {
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 
 	// Set maximum lifetime of a connection
-	db.SetConnMaxLifetime(time.Hour)
+	lifetime, idleTime := connTimeouts(cfg)
+	db.SetConnMaxLifetime(lifetime)
 
 	// Set maximum idle time of a connection
-	db.SetConnMaxIdleTime(time.Minute * 5)
+	db.SetConnMaxIdleTime(idleTime)
 
 	return nil
 }
 
-func SetupConnectionFromDSN(dsn string, maxOpenConns, maxIdleConns int) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
+// connTimeouts returns the configured connection lifetimes, using the defaults for a config built
+// by hand rather than read with GetDBConfig
+func connTimeouts(cfg *DatabaseConfig) (lifetime, idleTime time.Duration) {
+	lifetime, idleTime = cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime
+	if lifetime == 0 {
+		lifetime = time.Hour
 	}
-
-	if err := db.Ping(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	if idleTime == 0 {
+		idleTime = 5 * time.Minute
 	}
+	return lifetime, idleTime
+}
 
-	db.SetMaxOpenConns(maxOpenConns)
-	db.SetMaxIdleConns(maxIdleConns)
-	db.SetConnMaxLifetime(time.Hour)
-	db.SetConnMaxIdleTime(time.Minute * 5)
-
-	return db, nil
+// SetupConnectionFromDSN opens a pool for dsn and pings it once; see ConnectWithRetry to wait for the database
+func SetupConnectionFromDSN(dsn string, maxOpenConns, maxIdleConns int) (*sql.DB, error) {
+	return ConnectWithRetry(context.Background(), dsn, maxOpenConns, maxIdleConns, RetryOptions{})
 }
 
 // Close closes the database connection
@@ -148,6 +162,29 @@ func SetGlobalDB(sqlDB *sql.DB, cfg *DatabaseConfig) error {
 	return nil
 }
 
+// SetGlobalReadOnlyDB sets the global read-only database, typically a standby, returned by GetReadOnlyDB.
+// With a config, the pool is sized for read traffic.
+func SetGlobalReadOnlyDB(sqlDB *sql.DB, cfg *DatabaseConfig) error {
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	if sqlDB == nil {
+		return fmt.Errorf("cannot set nil database")
+	}
+	if cfg != nil {
+		if err := configureReadOnlyConnectionPool(sqlDB, cfg); err != nil {
+			return err
+		}
+	}
+
+	globalReadOnlyDB = &DB{
+		DB:     sqlDB,
+		config: cfg,
+	}
+
+	return nil
+}
+
 // GetDSN returns the database connection string (sentinel DSN)
 func GetDSN() string {
 	return GetSentinelDSN()
@@ -164,6 +201,12 @@ func Ping(ctx context.Context) error {
 	return db.DB.PingContext(ctx)
 }
 
+// WaitForReady pings the global database with backoff until it answers, giving up when ctx ends or
+// timeout passes. Use it during bootstrap and in tests rather than sleeping while Postgres starts.
+func WaitForReady(ctx context.Context, timeout time.Duration) error {
+	return readiness.Poll(ctx, timeout, "postgres", Ping)
+}
+
 // configureReadOnlyConnectionPool configures connection pool optimized for read-only operations
 func configureReadOnlyConnectionPool(db *sql.DB, cfg *DatabaseConfig) error {
 	// Read-only connections can have more open connections
@@ -172,11 +215,12 @@ func configureReadOnlyConnectionPool(db *sql.DB, cfg *DatabaseConfig) error {
 	// And more idle connections since they're cheaper
 	db.SetMaxIdleConns(cfg.MaxIdleConns * 2)
 
-	// Set maximum lifetime of a connection
-	db.SetConnMaxLifetime(time.Hour * 2)
+	// Read-only connections live twice as long
+	lifetime, idleTime := connTimeouts(cfg)
+	db.SetConnMaxLifetime(lifetime * 2)
 
 	// Set maximum idle time of a connection
-	db.SetConnMaxIdleTime(time.Minute * 10)
+	db.SetConnMaxIdleTime(idleTime * 2)
 
 	return nil
 }
@@ -188,18 +232,14 @@ func GetReadOnlyDB() *DB {
 	return globalReadOnlyDB
 }
 
+// GetDBConfig reads the database section; fields with invalid values are logged and fall back
+// to the environment or their default
 func GetDBConfig(resolver *config.ConfigResolver) *DatabaseConfig {
-	return &DatabaseConfig{
-		Host:         resolver.GetString("database.host", "DB_HOST", "localhost"),
-		Port:         resolver.GetInt("database.port", "DB_PORT", 5432),
-		User:         resolver.GetString("database.user", "DB_USER", ""),
-		Password:     resolver.GetString("database.password", "DB_PASSWORD", ""),
-		DBName:       resolver.GetString("database.name", "DB_NAME", ""),
-		SSLMode:      resolver.GetString("database.ssl_mode", "DB_SSL_MODE", "disable"),
-		SearchPath:   resolver.GetString("database.search_path", "DB_SEARCH_PATH", ""),
-		MaxOpenConns: resolver.GetInt("database.max_open_conns", "DB_MAX_OPEN_CONNS", 25),
-		MaxIdleConns: resolver.GetInt("database.max_idle_conns", "DB_MAX_IDLE_CONNS", 10),
+	cfg := &DatabaseConfig{}
+	if err := resolver.Unmarshal("database", cfg); err != nil {
+		log.Printf("[Postgres] invalid database config: %v", err)
 	}
+	return cfg
 }
 
 func GetDBConfigFromEnv() *DatabaseConfig {
@@ -723,7 +763,8 @@ func InitSentinelFromEnv() error {
 	}
 
 	dsn := BuildDSN(cfg)
-	db, err := SetupConnectionFromDSN(dsn, cfg.MaxOpenConns, cfg.MaxIdleConns)
+	// Partitions are assigned right away, so the sentinel is waited for even with LazyConnect
+	db, err := ConnectWithRetry(context.Background(), dsn, cfg.MaxOpenConns, cfg.MaxIdleConns, cfg.retryOptions())
 	if err != nil {
 		return fmt.Errorf("failed to connect to sentinel: %w", err)
 	}
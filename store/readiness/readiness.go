@@ -0,0 +1,46 @@
+// Package readiness waits for dependencies to start responding, for service bootstrap and tests
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	initialBackoff = 100 * time.Millisecond
+	maxBackoff     = 2 * time.Second
+)
+
+// Poll calls check until it returns nil, backing off between attempts from 100ms up to 2s with jitter.
+// It gives up when ctx ends or timeout passes, returning the last error; a zero timeout waits on ctx alone.
+func Poll(ctx context.Context, timeout time.Duration, name string, check func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	backoff := initialBackoff
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		// +/-10% so replicas started together don't poll in step
+		delay := backoff - backoff/10 + time.Duration(rand.Int63n(int64(backoff)/5+1))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s not ready after %s: %w", name, time.Since(start).Round(time.Millisecond), err)
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoll_ReturnsOnceReady(t *testing.T) {
+	calls := 0
+	err := Poll(context.Background(), time.Second, "db", func(ctx context.Context) error {
+		if calls++; calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPoll_TimesOutWithLastError(t *testing.T) {
+	refused := errors.New("connection refused")
+	err := Poll(context.Background(), 150*time.Millisecond, "db", func(ctx context.Context) error {
+		return refused
+	})
+	assert.ErrorIs(t, err, refused)
+	assert.ErrorContains(t, err, "db not ready after")
+}
+
+func TestPoll_StopsWhenContextEnds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Poll(ctx, 0, "db", func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}